@@ -0,0 +1,2 @@
+DROP TABLE "post_link_previews";
+DROP TABLE "link_previews";
@@ -0,0 +1,29 @@
+CREATE TABLE "link_previews" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "url" TEXT NOT NULL,
+  "title" TEXT,
+  "description" TEXT,
+  "image_url" TEXT,
+  "site_name" TEXT,
+  "fetched_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_link_previews_id ON "link_previews" ("id");
+
+ALTER TABLE "link_previews"
+ADD CONSTRAINT link_previews_url_unique UNIQUE ("url");
+
+CREATE TABLE "post_link_previews" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "post_id" BIGINT NOT NULL,
+  "link_preview_id" BIGINT NOT NULL
+);
+
+CREATE INDEX idx_post_link_previews_id ON "post_link_previews" ("id");
+CREATE INDEX idx_post_link_previews_link_preview_id ON "post_link_previews" ("link_preview_id");
+
+ALTER TABLE "post_link_previews"
+ADD CONSTRAINT post_link_previews_post_id_unique UNIQUE ("post_id");
+
+ALTER TABLE "post_link_previews" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id");
+ALTER TABLE "post_link_previews" ADD FOREIGN KEY ("link_preview_id") REFERENCES "link_previews" ("id");
@@ -0,0 +1,2 @@
+DROP TABLE "bookmarked_posts";
+DROP TABLE "bookmark_folders";
\ No newline at end of file
@@ -0,0 +1,34 @@
+CREATE TABLE "bookmark_folders" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "name" VARCHAR(50) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_bookmark_folders_id ON "bookmark_folders" ("id");
+CREATE INDEX idx_bookmark_folders_user_id ON "bookmark_folders" ("user_id");
+
+ALTER TABLE "bookmark_folders"
+ADD CONSTRAINT bookmark_folders_user_id_name_unique UNIQUE ("user_id", "name");
+
+ALTER TABLE "bookmark_folders" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE TABLE "bookmarked_posts" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "post_id" BIGINT NOT NULL,
+  "bookmark_folder_id" BIGINT,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_bookmarked_posts_id ON "bookmarked_posts" ("id");
+CREATE INDEX idx_bookmarked_posts_user_id_created_at ON "bookmarked_posts" ("user_id", "created_at");
+CREATE INDEX idx_bookmarked_posts_post_id ON "bookmarked_posts" ("post_id");
+CREATE INDEX idx_bookmarked_posts_bookmark_folder_id ON "bookmarked_posts" ("bookmark_folder_id");
+
+ALTER TABLE "bookmarked_posts"
+ADD CONSTRAINT bookmarked_posts_user_id_post_id_unique UNIQUE ("user_id", "post_id");
+
+ALTER TABLE "bookmarked_posts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "bookmarked_posts" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id");
+ALTER TABLE "bookmarked_posts" ADD FOREIGN KEY ("bookmark_folder_id") REFERENCES "bookmark_folders" ("id") ON DELETE SET NULL;
\ No newline at end of file
@@ -0,0 +1,10 @@
+DROP TABLE "post_reaction_counts";
+
+ALTER TABLE "liked_post_comment_replies"
+DROP COLUMN "reaction_type";
+
+ALTER TABLE "liked_post_comments"
+DROP COLUMN "reaction_type";
+
+ALTER TABLE "liked_posts"
+DROP COLUMN "reaction_type";
\ No newline at end of file
@@ -0,0 +1,28 @@
+ALTER TABLE "liked_posts"
+ADD COLUMN "reaction_type" VARCHAR(15) NOT NULL DEFAULT 'like';
+
+ALTER TABLE "liked_post_comments"
+ADD COLUMN "reaction_type" VARCHAR(15) NOT NULL DEFAULT 'like';
+
+ALTER TABLE "liked_post_comment_replies"
+ADD COLUMN "reaction_type" VARCHAR(15) NOT NULL DEFAULT 'like';
+
+CREATE TABLE "post_reaction_counts" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "post_id" BIGINT NOT NULL,
+  "reaction_type" VARCHAR(15) NOT NULL,
+  "count" INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_post_reaction_counts_id ON "post_reaction_counts" ("id");
+
+ALTER TABLE "post_reaction_counts"
+ADD CONSTRAINT post_reaction_counts_post_id_reaction_type_unique UNIQUE ("post_id", "reaction_type");
+
+ALTER TABLE "post_reaction_counts" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id");
+
+INSERT INTO "post_reaction_counts" ("post_id", "reaction_type", "count")
+SELECT "post_id", 'like', COUNT(*)
+FROM "liked_posts"
+WHERE "post_id" IS NOT NULL
+GROUP BY "post_id";
\ No newline at end of file
@@ -0,0 +1,46 @@
+CREATE TABLE "polls" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "post_id" BIGINT NOT NULL,
+  "allow_multiple" BOOLEAN NOT NULL DEFAULT false,
+  "voter_count" INT NOT NULL DEFAULT 0,
+  "ends_at" TIMESTAMP NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_polls_id ON "polls" ("id");
+
+ALTER TABLE "polls"
+ADD CONSTRAINT polls_post_id_unique UNIQUE ("post_id");
+
+ALTER TABLE "polls" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id");
+
+CREATE TABLE "poll_options" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "poll_id" BIGINT NOT NULL,
+  "option_text" VARCHAR(100) NOT NULL,
+  "position" SMALLINT NOT NULL,
+  "vote_count" INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_poll_options_id ON "poll_options" ("id");
+CREATE INDEX idx_poll_options_poll_id ON "poll_options" ("poll_id");
+
+ALTER TABLE "poll_options" ADD FOREIGN KEY ("poll_id") REFERENCES "polls" ("id");
+
+CREATE TABLE "poll_votes" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "poll_id" BIGINT NOT NULL,
+  "poll_option_id" BIGINT NOT NULL,
+  "user_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_poll_votes_id ON "poll_votes" ("id");
+CREATE INDEX idx_poll_votes_poll_id_user_id ON "poll_votes" ("poll_id", "user_id");
+
+ALTER TABLE "poll_votes"
+ADD CONSTRAINT poll_votes_poll_option_id_user_id_unique UNIQUE ("poll_option_id", "user_id");
+
+ALTER TABLE "poll_votes" ADD FOREIGN KEY ("poll_id") REFERENCES "polls" ("id");
+ALTER TABLE "poll_votes" ADD FOREIGN KEY ("poll_option_id") REFERENCES "poll_options" ("id");
+ALTER TABLE "poll_votes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
\ No newline at end of file
@@ -0,0 +1,11 @@
+DROP TABLE "moderation_actions";
+DROP TABLE "user_account_states";
+DROP TABLE "user_warnings";
+DROP TABLE "hidden_posts";
+DROP TABLE "user_roles";
+
+DROP INDEX idx_reported_posts_resolved_at;
+
+ALTER TABLE "reported_posts" DROP COLUMN "resolved_at";
+ALTER TABLE "reported_posts" DROP COLUMN "created_at";
+ALTER TABLE "reported_posts" ALTER COLUMN "reason" TYPE VARCHAR(15);
\ No newline at end of file
@@ -0,0 +1,81 @@
+ALTER TABLE "reported_posts" ALTER COLUMN "reason" TYPE VARCHAR(50);
+ALTER TABLE "reported_posts" ADD COLUMN "created_at" TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE "reported_posts" ADD COLUMN "resolved_at" TIMESTAMP;
+
+CREATE INDEX idx_reported_posts_resolved_at ON "reported_posts" ("resolved_at");
+
+CREATE TABLE "user_roles" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "role" VARCHAR(20) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_user_roles_id ON "user_roles" ("id");
+
+ALTER TABLE "user_roles"
+ADD CONSTRAINT user_roles_user_id_role_unique UNIQUE ("user_id", "role");
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE TABLE "hidden_posts" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "post_id" BIGINT NOT NULL,
+  "reason" VARCHAR(20) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_hidden_posts_id ON "hidden_posts" ("id");
+
+ALTER TABLE "hidden_posts"
+ADD CONSTRAINT hidden_posts_post_id_unique UNIQUE ("post_id");
+
+ALTER TABLE "hidden_posts" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id");
+
+CREATE TABLE "user_warnings" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "moderator_id" BIGINT NOT NULL,
+  "post_id" BIGINT,
+  "message" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_user_warnings_id ON "user_warnings" ("id");
+CREATE INDEX idx_user_warnings_user_id ON "user_warnings" ("user_id");
+
+ALTER TABLE "user_warnings" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "user_warnings" ADD FOREIGN KEY ("moderator_id") REFERENCES "users" ("id");
+
+CREATE TABLE "user_account_states" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "state" VARCHAR(15) NOT NULL,
+  "reason" TEXT,
+  "expires_at" TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_user_account_states_id ON "user_account_states" ("id");
+
+ALTER TABLE "user_account_states"
+ADD CONSTRAINT user_account_states_user_id_unique UNIQUE ("user_id");
+
+ALTER TABLE "user_account_states" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+-- Post ids are kept without a foreign key so the trail survives post deletion
+CREATE TABLE "moderation_actions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "moderator_id" BIGINT,
+  "post_id" BIGINT,
+  "target_user_id" BIGINT,
+  "action" VARCHAR(20) NOT NULL,
+  "note" TEXT,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_moderation_actions_id ON "moderation_actions" ("id");
+CREATE INDEX idx_moderation_actions_post_id ON "moderation_actions" ("post_id");
+
+ALTER TABLE "moderation_actions" ADD FOREIGN KEY ("moderator_id") REFERENCES "users" ("id");
+ALTER TABLE "moderation_actions" ADD FOREIGN KEY ("target_user_id") REFERENCES "users" ("id");
\ No newline at end of file
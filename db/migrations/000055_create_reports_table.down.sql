@@ -0,0 +1,25 @@
+CREATE TABLE "reported_posts" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT,
+  "post_id" BIGINT,
+  "reason" VARCHAR(50),
+  "message" TEXT,
+  "created_at" TIMESTAMP NOT NULL DEFAULT NOW(),
+  "resolved_at" TIMESTAMP
+);
+
+CREATE INDEX idx_reported_posts_id ON "reported_posts" ("id");
+CREATE INDEX idx_reported_posts_user_id ON "reported_posts" ("user_id");
+CREATE INDEX idx_reported_posts_post_id ON "reported_posts" ("post_id");
+CREATE INDEX idx_reported_posts_reason ON "reported_posts" ("reason");
+CREATE INDEX idx_reported_posts_resolved_at ON "reported_posts" ("resolved_at");
+
+ALTER TABLE "reported_posts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "reported_posts" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id");
+
+INSERT INTO "reported_posts" ("user_id", "post_id", "reason", "message", "created_at", "resolved_at")
+SELECT user_id, target_id, UNNEST(reasons), message, created_at, resolved_at
+FROM "reports"
+WHERE target_type = 'post';
+
+DROP TABLE "reports";
\ No newline at end of file
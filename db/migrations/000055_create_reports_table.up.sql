@@ -0,0 +1,33 @@
+-- Reports target posts, comments, replies or users, so target_id has no foreign key
+CREATE TABLE "reports" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "target_type" VARCHAR(20) NOT NULL,
+  "target_id" BIGINT NOT NULL,
+  "reasons" VARCHAR(50)[] NOT NULL,
+  "message" TEXT,
+  "created_at" TIMESTAMP NOT NULL,
+  "resolved_at" TIMESTAMP
+);
+
+CREATE INDEX idx_reports_id ON "reports" ("id");
+CREATE INDEX idx_reports_target ON "reports" ("target_type", "target_id");
+CREATE INDEX idx_reports_resolved_at ON "reports" ("resolved_at");
+
+ALTER TABLE "reports"
+ADD CONSTRAINT reports_user_id_target_unique UNIQUE ("user_id", "target_type", "target_id");
+
+ALTER TABLE "reports" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+-- reported_posts kept one row per reason, merge them into one report per user and post
+INSERT INTO "reports" ("user_id", "target_type", "target_id", "reasons", "message", "created_at", "resolved_at")
+SELECT user_id, 'post', post_id,
+  COALESCE(ARRAY_AGG(reason ORDER BY id) FILTER (WHERE reason IS NOT NULL), '{}'),
+  MIN(message),
+  MIN(created_at),
+  CASE WHEN BOOL_OR(resolved_at IS NULL) THEN NULL ELSE MAX(resolved_at) END
+FROM "reported_posts"
+WHERE user_id IS NOT NULL AND post_id IS NOT NULL
+GROUP BY user_id, post_id;
+
+DROP TABLE "reported_posts";
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE "user_mutes";
+DROP TABLE "user_blocks";
\ No newline at end of file
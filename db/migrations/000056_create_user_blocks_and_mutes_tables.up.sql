@@ -0,0 +1,30 @@
+CREATE TABLE "user_blocks" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "blocked_user_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_user_blocks_id ON "user_blocks" ("id");
+CREATE INDEX idx_user_blocks_blocked_user_id ON "user_blocks" ("blocked_user_id");
+
+ALTER TABLE "user_blocks"
+ADD CONSTRAINT user_blocks_user_id_blocked_user_id_unique UNIQUE ("user_id", "blocked_user_id");
+
+ALTER TABLE "user_blocks" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "user_blocks" ADD FOREIGN KEY ("blocked_user_id") REFERENCES "users" ("id");
+
+CREATE TABLE "user_mutes" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "muted_user_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_user_mutes_id ON "user_mutes" ("id");
+
+ALTER TABLE "user_mutes"
+ADD CONSTRAINT user_mutes_user_id_muted_user_id_unique UNIQUE ("user_id", "muted_user_id");
+
+ALTER TABLE "user_mutes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "user_mutes" ADD FOREIGN KEY ("muted_user_id") REFERENCES "users" ("id");
\ No newline at end of file
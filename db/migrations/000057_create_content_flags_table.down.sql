@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "content_flags";
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Flags are raised by the content policy, rejected content was never stored so target_id stays empty
+CREATE TABLE "content_flags" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "target_type" VARCHAR(20) NOT NULL,
+  "target_id" BIGINT,
+  "verdict" VARCHAR(10) NOT NULL,
+  "rules" VARCHAR(50)[] NOT NULL,
+  "content" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "resolved_at" TIMESTAMP
+);
+
+CREATE INDEX idx_content_flags_id ON "content_flags" ("id");
+CREATE INDEX idx_content_flags_target ON "content_flags" ("target_type", "target_id");
+CREATE INDEX idx_content_flags_resolved_at ON "content_flags" ("resolved_at");
+
+ALTER TABLE "content_flags" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_account_states_state;
+
+ALTER TABLE "user_account_states" DROP CONSTRAINT IF EXISTS user_account_states_state_check;
+
+ALTER TABLE "user_account_states" DROP COLUMN IF EXISTS "updated_by";
\ No newline at end of file
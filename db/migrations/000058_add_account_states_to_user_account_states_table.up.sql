@@ -0,0 +1,8 @@
+ALTER TABLE "user_account_states" ADD COLUMN "updated_by" BIGINT;
+
+ALTER TABLE "user_account_states" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("id");
+
+ALTER TABLE "user_account_states"
+ADD CONSTRAINT user_account_states_state_check CHECK ("state" IN ('active', 'restricted', 'suspended', 'banned'));
+
+CREATE INDEX idx_user_account_states_state ON "user_account_states" ("state");
\ No newline at end of file
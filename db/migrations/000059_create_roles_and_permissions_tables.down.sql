@@ -0,0 +1,7 @@
+DROP TABLE "admin_audit_logs";
+DROP TABLE "role_permissions";
+DROP TABLE "permissions";
+
+ALTER TABLE "user_roles" DROP CONSTRAINT user_roles_role_fkey;
+
+DROP TABLE "roles";
\ No newline at end of file
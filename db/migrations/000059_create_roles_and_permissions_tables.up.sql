@@ -0,0 +1,80 @@
+CREATE TABLE "roles" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" VARCHAR(20) NOT NULL,
+  "description" TEXT,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_roles_id ON "roles" ("id");
+
+ALTER TABLE "roles"
+ADD CONSTRAINT roles_name_unique UNIQUE ("name");
+
+CREATE TABLE "permissions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" VARCHAR(50) NOT NULL,
+  "description" TEXT,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_permissions_id ON "permissions" ("id");
+
+ALTER TABLE "permissions"
+ADD CONSTRAINT permissions_name_unique UNIQUE ("name");
+
+CREATE TABLE "role_permissions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "role_id" BIGINT NOT NULL,
+  "permission_id" BIGINT NOT NULL
+);
+
+CREATE INDEX idx_role_permissions_id ON "role_permissions" ("id");
+
+ALTER TABLE "role_permissions"
+ADD CONSTRAINT role_permissions_role_id_permission_id_unique UNIQUE ("role_id", "permission_id");
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE CASCADE;
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("permission_id") REFERENCES "permissions" ("id") ON DELETE CASCADE;
+
+INSERT INTO "roles" ("name", "description", "created_at") VALUES
+  ('admin', 'Full access to the admin API', NOW()),
+  ('moderator', 'Handles reports and looks up users', NOW());
+
+-- Roles granted before this table existed are kept
+INSERT INTO "roles" ("name", "created_at")
+SELECT DISTINCT "role", NOW()
+FROM "user_roles"
+ON CONFLICT ("name") DO NOTHING;
+
+ALTER TABLE "user_roles"
+ADD CONSTRAINT user_roles_role_fkey FOREIGN KEY ("role") REFERENCES "roles" ("name");
+
+INSERT INTO "permissions" ("name", "description", "created_at") VALUES
+  ('users:read', 'Look up users', NOW()),
+  ('roles:assign', 'Grant and revoke roles', NOW()),
+  ('catalogs:manage', 'Create, rename and delete catalog items', NOW()),
+  ('reports:manage', 'Act on reports', NOW()),
+  ('audit_logs:read', 'Read the admin audit log', NOW());
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT r.id, p.id
+FROM "roles" r, "permissions" p
+WHERE r.name = 'admin'
+  OR (r.name = 'moderator' AND p.name IN ('users:read', 'reports:manage'));
+
+-- Target ids are kept without a foreign key so the trail survives deletion
+CREATE TABLE "admin_audit_logs" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "actor_id" BIGINT NOT NULL,
+  "action" VARCHAR(50) NOT NULL,
+  "target_type" VARCHAR(30) NOT NULL,
+  "target_id" BIGINT,
+  "details" JSONB NOT NULL DEFAULT '{}',
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_admin_audit_logs_id ON "admin_audit_logs" ("id");
+CREATE INDEX idx_admin_audit_logs_actor_id ON "admin_audit_logs" ("actor_id");
+CREATE INDEX idx_admin_audit_logs_target ON "admin_audit_logs" ("target_type", "target_id");
+
+ALTER TABLE "admin_audit_logs" ADD FOREIGN KEY ("actor_id") REFERENCES "users" ("id");
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP TABLE "catalog_aliases";
+
+ALTER TABLE "job_positions" DROP COLUMN "normalized_name";
+ALTER TABLE "issuing_organizations" DROP COLUMN "normalized_name";
+ALTER TABLE "schools" DROP COLUMN "normalized_name";
+ALTER TABLE "companies" DROP COLUMN "normalized_name";
\ No newline at end of file
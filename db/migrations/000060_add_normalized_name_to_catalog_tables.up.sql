@@ -0,0 +1,43 @@
+ALTER TABLE "companies" ADD COLUMN "normalized_name" TEXT;
+ALTER TABLE "schools" ADD COLUMN "normalized_name" TEXT;
+ALTER TABLE "issuing_organizations" ADD COLUMN "normalized_name" TEXT;
+ALTER TABLE "job_positions" ADD COLUMN "normalized_name" TEXT;
+
+-- Mirrors libs.NormalizeCatalogName for the rows created before this migration
+CREATE FUNCTION pg_temp.normalize_catalog_name(name TEXT) RETURNS TEXT AS $$
+  WITH words AS (
+    SELECT word, ord
+    FROM REGEXP_SPLIT_TO_TABLE(LOWER(name), '[^[:alnum:]]+') WITH ORDINALITY AS w(word, ord)
+    WHERE word != ''
+  )
+  SELECT NULLIF(COALESCE(
+    (SELECT STRING_AGG(word, ' ' ORDER BY ord) FROM words WHERE word NOT IN ('inc', 'incorporated', 'llc', 'ltd', 'limited', 'corp', 'corporation', 'co', 'company', 'plc', 'gmbh', 'pt', 'tbk', 'cv', 'persero')),
+    (SELECT STRING_AGG(word, ' ' ORDER BY ord) FROM words)
+  ), '');
+$$ LANGUAGE SQL;
+
+UPDATE "companies" SET "normalized_name" = pg_temp.normalize_catalog_name("name");
+UPDATE "schools" SET "normalized_name" = pg_temp.normalize_catalog_name("name");
+UPDATE "issuing_organizations" SET "normalized_name" = pg_temp.normalize_catalog_name("name");
+UPDATE "job_positions" SET "normalized_name" = pg_temp.normalize_catalog_name("name");
+
+CREATE INDEX idx_companies_normalized_name ON "companies" ("normalized_name");
+CREATE INDEX idx_schools_normalized_name ON "schools" ("normalized_name");
+CREATE INDEX idx_issuing_organizations_normalized_name ON "issuing_organizations" ("normalized_name");
+CREATE INDEX idx_job_positions_normalized_name ON "job_positions" ("normalized_name");
+
+-- Names of merged catalog items, catalog uses the admin route names (companies, schools, issuing-organizations, job-positions)
+CREATE TABLE "catalog_aliases" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "catalog" VARCHAR(30) NOT NULL,
+  "item_id" BIGINT NOT NULL,
+  "name" TEXT NOT NULL,
+  "normalized_name" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_catalog_aliases_id ON "catalog_aliases" ("id");
+CREATE INDEX idx_catalog_aliases_item ON "catalog_aliases" ("catalog", "item_id");
+
+ALTER TABLE "catalog_aliases"
+ADD CONSTRAINT catalog_aliases_catalog_normalized_name_unique UNIQUE ("catalog", "normalized_name");
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX idx_user_skills_skill_id;
+
+DROP INDEX idx_issuing_organizations_name_trgm;
+DROP INDEX idx_job_positions_name_trgm;
+DROP INDEX idx_skills_name_trgm;
+DROP INDEX idx_companies_name_trgm;
+DROP INDEX idx_schools_name_trgm;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes serve both the prefix (ILIKE) and the fuzzy (%) typeahead matches
+CREATE INDEX idx_schools_name_trgm ON "schools" USING GIN ("name" gin_trgm_ops);
+CREATE INDEX idx_companies_name_trgm ON "companies" USING GIN ("name" gin_trgm_ops);
+CREATE INDEX idx_skills_name_trgm ON "skills" USING GIN ("name" gin_trgm_ops);
+CREATE INDEX idx_job_positions_name_trgm ON "job_positions" USING GIN ("name" gin_trgm_ops);
+CREATE INDEX idx_issuing_organizations_name_trgm ON "issuing_organizations" USING GIN ("name" gin_trgm_ops);
+
+-- Popularity counts the users referencing a skill
+CREATE INDEX idx_user_skills_skill_id ON "user_skills" ("skill_id");
\ No newline at end of file
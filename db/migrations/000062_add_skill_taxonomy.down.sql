@@ -0,0 +1,11 @@
+DROP INDEX idx_catalog_aliases_name_trgm;
+
+DELETE FROM "catalog_aliases" WHERE "catalog" = 'skills';
+
+ALTER TABLE "skills" DROP CONSTRAINT skills_parent_id_fkey;
+
+DROP INDEX idx_skills_normalized_name;
+DROP INDEX idx_skills_parent_id;
+
+ALTER TABLE "skills" DROP COLUMN "normalized_name";
+ALTER TABLE "skills" DROP COLUMN "parent_id";
\ No newline at end of file
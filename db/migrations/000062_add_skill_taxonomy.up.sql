@@ -0,0 +1,15 @@
+ALTER TABLE "skills" ADD COLUMN "parent_id" BIGINT;
+ALTER TABLE "skills" ADD COLUMN "normalized_name" TEXT;
+
+-- Mirrors libs.CatalogMatchKey for skills
+UPDATE "skills" SET "normalized_name" = NULLIF(LOWER(REGEXP_REPLACE(TRIM("name"), '\s+', ' ', 'g')), '');
+
+CREATE INDEX idx_skills_parent_id ON "skills" ("parent_id");
+CREATE INDEX idx_skills_normalized_name ON "skills" ("normalized_name");
+
+ALTER TABLE "skills"
+ADD CONSTRAINT skills_parent_id_fkey
+FOREIGN KEY ("parent_id") REFERENCES "skills" ("id") ON DELETE SET NULL;
+
+-- Skill synonyms ("golang" for "Go") live in catalog_aliases under the skills catalog
+CREATE INDEX idx_catalog_aliases_name_trgm ON "catalog_aliases" USING GIN ("name" gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE "post_hashtags";
+
+DROP INDEX idx_posts_search_vector;
+
+ALTER TABLE "posts" DROP COLUMN "search_vector";
\ No newline at end of file
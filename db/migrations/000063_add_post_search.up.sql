@@ -0,0 +1,27 @@
+-- Posts are written in both Indonesian and English, so each field is indexed under both configurations
+ALTER TABLE "posts" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    SETWEIGHT(TO_TSVECTOR('english', COALESCE("title", '')), 'A') ||
+    SETWEIGHT(TO_TSVECTOR('indonesian', COALESCE("title", '')), 'A') ||
+    SETWEIGHT(TO_TSVECTOR('english', REGEXP_REPLACE(COALESCE("content", ''), '<[^>]*>', ' ', 'g')), 'B') ||
+    SETWEIGHT(TO_TSVECTOR('indonesian', REGEXP_REPLACE(COALESCE("content", ''), '<[^>]*>', ' ', 'g')), 'B')
+) STORED;
+
+CREATE INDEX idx_posts_search_vector ON "posts" USING GIN ("search_vector");
+
+CREATE TABLE "post_hashtags" (
+  "post_id" BIGINT NOT NULL,
+  "hashtag" VARCHAR(50) NOT NULL,
+  PRIMARY KEY ("post_id", "hashtag")
+);
+
+CREATE INDEX idx_post_hashtags_hashtag ON "post_hashtags" ("hashtag");
+
+ALTER TABLE "post_hashtags"
+ADD CONSTRAINT post_hashtags_post_id_fkey
+FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
+
+-- Mirrors libs.ExtractHashtags
+INSERT INTO "post_hashtags" ("post_id", "hashtag")
+SELECT DISTINCT p."id", LOWER(m[1])
+FROM "posts" p,
+    REGEXP_MATCHES(REGEXP_REPLACE(COALESCE(p."content", ''), '<[^>]*>', ' ', 'g'), '(?:^|[^[:alnum:]_&])#([[:alpha:]][[:alnum:]_]{0,49})', 'g') AS m;
\ No newline at end of file
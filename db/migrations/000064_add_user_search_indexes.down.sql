@@ -0,0 +1,2 @@
+DROP INDEX idx_user_details_location_trgm;
+DROP INDEX idx_users_full_name_trgm;
\ No newline at end of file
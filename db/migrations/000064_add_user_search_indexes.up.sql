@@ -0,0 +1,3 @@
+-- People search matches names and locations by substring and similarity
+CREATE INDEX idx_users_full_name_trgm ON "users" USING GIN ("full_name" gin_trgm_ops);
+CREATE INDEX idx_user_details_location_trgm ON "user_details" USING GIN ("location" gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX idx_user_location_type_interests_user_id;
+
+DROP TABLE "saved_talent_searches";
+
+DELETE FROM "permissions" WHERE "name" = 'talent:search';
+DELETE FROM "roles" WHERE "name" = 'recruiter' AND NOT EXISTS (SELECT 1 FROM "user_roles" WHERE "role" = 'recruiter');
\ No newline at end of file
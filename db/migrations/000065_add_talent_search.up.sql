@@ -0,0 +1,31 @@
+INSERT INTO "roles" ("name", "description", "created_at") VALUES
+  ('recruiter', 'Searches candidates who are open to work', NOW())
+ON CONFLICT ("name") DO NOTHING;
+
+INSERT INTO "permissions" ("name", "description", "created_at") VALUES
+  ('talent:search', 'Search candidates and keep saved searches', NOW());
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT r.id, p.id
+FROM "roles" r, "permissions" p
+WHERE r.name IN ('admin', 'recruiter') AND p.name = 'talent:search';
+
+-- Filters are kept as the JSON the search endpoint receives
+CREATE TABLE "saved_talent_searches" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "name" VARCHAR(50) NOT NULL,
+  "filters" JSONB NOT NULL DEFAULT '{}',
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_saved_talent_searches_id ON "saved_talent_searches" ("id");
+CREATE INDEX idx_saved_talent_searches_user_id ON "saved_talent_searches" ("user_id");
+
+ALTER TABLE "saved_talent_searches"
+ADD CONSTRAINT saved_talent_searches_user_id_name_unique UNIQUE ("user_id", "name");
+
+ALTER TABLE "saved_talent_searches" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+-- Dropped together with the location_types table, candidates are matched per user
+CREATE INDEX idx_user_location_type_interests_user_id ON "user_location_type_interests" ("user_id");
\ No newline at end of file
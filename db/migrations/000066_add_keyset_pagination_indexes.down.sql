@@ -0,0 +1,3 @@
+DROP INDEX idx_followings_user_id_id;
+DROP INDEX idx_post_comments_post_id_created_at_id;
+DROP INDEX idx_posts_created_at_id;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Keyset pagination walks these orderings from a cursor instead of counting rows
+CREATE INDEX idx_posts_created_at_id ON "posts" ("created_at" DESC, "id" DESC);
+CREATE INDEX idx_post_comments_post_id_created_at_id ON "post_comments" ("post_id", "created_at" DESC, "id" DESC);
+CREATE INDEX idx_followings_user_id_id ON "followings" ("user_id", "id" DESC);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE "timeline_pull_posts";
+DROP TABLE "timeline_entries";
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- Following feed entries copied to every follower when a post is published
+CREATE TABLE "timeline_entries" (
+  "user_id" BIGINT NOT NULL,
+  "post_id" BIGINT NOT NULL,
+  "author_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("user_id", "post_id")
+);
+
+CREATE INDEX idx_timeline_entries_user_id_created_at ON "timeline_entries" ("user_id", "created_at" DESC, "post_id" DESC);
+CREATE INDEX idx_timeline_entries_post_id ON "timeline_entries" ("post_id");
+
+ALTER TABLE "timeline_entries" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "timeline_entries" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
+ALTER TABLE "timeline_entries" ADD FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Posts of authors with too many followers to fan out, merged into the timelines on read
+CREATE TABLE "timeline_pull_posts" (
+  "post_id" BIGINT PRIMARY KEY,
+  "author_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_timeline_pull_posts_author_id_created_at ON "timeline_pull_posts" ("author_id", "created_at" DESC, "post_id" DESC);
+
+ALTER TABLE "timeline_pull_posts" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
+ALTER TABLE "timeline_pull_posts" ADD FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+INSERT INTO "timeline_entries" ("user_id", "post_id", "author_id", "created_at")
+SELECT f."user_id", p."id", p."user_id", COALESCE(p."created_at", NOW())
+FROM "posts" p
+JOIN "followings" f ON f."follow_user_id" = p."user_id"
+WHERE f."user_id" IS NOT NULL
+ON CONFLICT DO NOTHING;
\ No newline at end of file
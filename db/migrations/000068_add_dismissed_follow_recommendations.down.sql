@@ -0,0 +1 @@
+DROP TABLE "dismissed_follow_recommendations";
\ No newline at end of file
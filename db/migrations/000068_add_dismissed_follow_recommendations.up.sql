@@ -0,0 +1,12 @@
+CREATE TABLE "dismissed_follow_recommendations" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "dismissed_user_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+ALTER TABLE "dismissed_follow_recommendations"
+ADD CONSTRAINT dismissed_follow_recommendations_user_id_dismissed_user_id_unique UNIQUE ("user_id", "dismissed_user_id");
+
+ALTER TABLE "dismissed_follow_recommendations" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "dismissed_follow_recommendations" ADD FOREIGN KEY ("dismissed_user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
\ No newline at end of file
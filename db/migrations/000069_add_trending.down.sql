@@ -0,0 +1,9 @@
+DROP TABLE "trending_topics";
+DROP TABLE "trending_posts";
+
+DROP INDEX idx_post_comments_created_at;
+DROP INDEX idx_reposted_posts_created_at;
+DROP INDEX idx_liked_posts_created_at;
+
+ALTER TABLE "reposted_posts" DROP COLUMN "created_at";
+ALTER TABLE "liked_posts" DROP COLUMN "created_at";
\ No newline at end of file
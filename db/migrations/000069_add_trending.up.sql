@@ -0,0 +1,39 @@
+-- Like and repost times feed the trending velocity, older rows are left without one
+ALTER TABLE "liked_posts" ADD COLUMN "created_at" TIMESTAMP;
+ALTER TABLE "reposted_posts" ADD COLUMN "created_at" TIMESTAMP;
+
+CREATE INDEX idx_liked_posts_created_at ON "liked_posts" ("created_at");
+CREATE INDEX idx_reposted_posts_created_at ON "reposted_posts" ("created_at");
+CREATE INDEX idx_post_comments_created_at ON "post_comments" ("created_at");
+
+-- time_window is one of 1h, 24h or 7d
+CREATE TABLE "trending_posts" (
+  "time_window" VARCHAR(3) NOT NULL,
+  "post_id" BIGINT NOT NULL,
+  "like_count" INT NOT NULL,
+  "comment_count" INT NOT NULL,
+  "repost_count" INT NOT NULL,
+  "score" DOUBLE PRECISION NOT NULL,
+  "computed_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("time_window", "post_id")
+);
+
+CREATE INDEX idx_trending_posts_time_window_score ON "trending_posts" ("time_window", "score" DESC, "post_id" DESC);
+
+ALTER TABLE "trending_posts" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
+
+-- topic_type is hashtag or skill
+CREATE TABLE "trending_topics" (
+  "time_window" VARCHAR(3) NOT NULL,
+  "topic_type" VARCHAR(10) NOT NULL,
+  "name" TEXT NOT NULL,
+  "post_count" INT NOT NULL,
+  "like_count" INT NOT NULL,
+  "comment_count" INT NOT NULL,
+  "repost_count" INT NOT NULL,
+  "score" DOUBLE PRECISION NOT NULL,
+  "computed_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("time_window", "topic_type", "name")
+);
+
+CREATE INDEX idx_trending_topics_time_window_score ON "trending_topics" ("time_window", "score" DESC);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE "post_impressions";
\ No newline at end of file
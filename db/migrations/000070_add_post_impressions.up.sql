@@ -0,0 +1,14 @@
+-- Posts the user scrolled past, reported by the client in batches
+CREATE TABLE "post_impressions" (
+  "user_id" BIGINT NOT NULL,
+  "post_id" BIGINT NOT NULL,
+  "view_count" INT NOT NULL,
+  "first_seen_at" TIMESTAMP NOT NULL,
+  "last_seen_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("user_id", "post_id")
+);
+
+CREATE INDEX idx_post_impressions_post_id ON "post_impressions" ("post_id");
+
+ALTER TABLE "post_impressions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "post_impressions" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
\ No newline at end of file
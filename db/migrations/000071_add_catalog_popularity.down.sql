@@ -0,0 +1 @@
+DROP TABLE "catalog_popularity";
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Users referencing each catalog item, read by the typeahead search and rebuilt by `catalog refresh-popularity`
+CREATE TABLE "catalog_popularity" (
+  "catalog" VARCHAR(30) NOT NULL,
+  "item_id" BIGINT NOT NULL,
+  "user_count" INT NOT NULL,
+  PRIMARY KEY ("catalog", "item_id")
+);
+
+INSERT INTO "catalog_popularity" ("catalog", "item_id", "user_count")
+SELECT 'schools', e.school_id, COUNT(DISTINCT e.user_id)::int FROM educations e WHERE e.school_id IS NOT NULL GROUP BY e.school_id
+UNION ALL
+SELECT 'companies', we.company_id, COUNT(DISTINCT we.user_id)::int FROM work_experiences we WHERE we.company_id IS NOT NULL GROUP BY we.company_id
+UNION ALL
+SELECT 'skills', us.skill_id, COUNT(DISTINCT us.user_id)::int FROM user_skills us WHERE us.skill_id IS NOT NULL GROUP BY us.skill_id
+UNION ALL
+SELECT 'job-positions', uji.job_position_id, COUNT(DISTINCT uji.user_id)::int FROM user_job_interests uji WHERE uji.job_position_id IS NOT NULL GROUP BY uji.job_position_id
+UNION ALL
+SELECT 'issuing-organizations', ce.issuing_organization_id, COUNT(DISTINCT ce.user_id)::int FROM certificates ce WHERE ce.issuing_organization_id IS NOT NULL GROUP BY ce.issuing_organization_id;
\ No newline at end of file
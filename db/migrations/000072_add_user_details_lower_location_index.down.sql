@@ -0,0 +1 @@
+DROP INDEX idx_user_details_lower_location;
\ No newline at end of file
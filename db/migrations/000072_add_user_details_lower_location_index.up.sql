@@ -0,0 +1,2 @@
+-- Follow recommendations match users by location regardless of case
+CREATE INDEX idx_user_details_lower_location ON "user_details" (LOWER("location"));
\ No newline at end of file
@@ -0,0 +1,8 @@
+DELETE FROM "hidden_posts" hp
+USING "hidden_posts" older
+WHERE older."post_id" = hp."post_id" AND older."id" < hp."id";
+
+ALTER TABLE "hidden_posts" DROP CONSTRAINT hidden_posts_post_id_reason_unique;
+
+ALTER TABLE "hidden_posts"
+ADD CONSTRAINT hidden_posts_post_id_unique UNIQUE ("post_id");
\ No newline at end of file
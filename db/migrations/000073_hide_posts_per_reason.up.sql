@@ -0,0 +1,5 @@
+-- A post may be hidden for several reasons at once, each action only lifts its own
+ALTER TABLE "hidden_posts" DROP CONSTRAINT hidden_posts_post_id_unique;
+
+ALTER TABLE "hidden_posts"
+ADD CONSTRAINT hidden_posts_post_id_reason_unique UNIQUE ("post_id", "reason");
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "hidden_post_comment_replies";
+DROP TABLE IF EXISTS "hidden_post_comments";
\ No newline at end of file
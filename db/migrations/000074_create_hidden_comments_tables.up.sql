@@ -0,0 +1,26 @@
+-- Comments and replies hidden by a moderator stay visible to their author only
+CREATE TABLE "hidden_post_comments" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "post_comment_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_hidden_post_comments_id ON "hidden_post_comments" ("id");
+
+ALTER TABLE "hidden_post_comments"
+ADD CONSTRAINT hidden_post_comments_post_comment_id_unique UNIQUE ("post_comment_id");
+
+ALTER TABLE "hidden_post_comments" ADD FOREIGN KEY ("post_comment_id") REFERENCES "post_comments" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "hidden_post_comment_replies" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "post_comment_reply_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_hidden_post_comment_replies_id ON "hidden_post_comment_replies" ("id");
+
+ALTER TABLE "hidden_post_comment_replies"
+ADD CONSTRAINT hidden_post_comment_replies_post_comment_reply_id_unique UNIQUE ("post_comment_reply_id");
+
+ALTER TABLE "hidden_post_comment_replies" ADD FOREIGN KEY ("post_comment_reply_id") REFERENCES "post_comment_replies" ("id") ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS visible_feed_post_for(BIGINT, BIGINT, BIGINT);
+DROP FUNCTION IF EXISTS visible_post_for(BIGINT, BIGINT, BIGINT);
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- Whether the viewer may see the post: hidden posts stay visible to their author, posts of
+-- blocked, blocking, suspended and banned users are never shown. Viewer 0 is nobody, so
+-- every hidden post is left out
+CREATE FUNCTION visible_post_for(viewer_id BIGINT, target_post_id BIGINT, author_id BIGINT) RETURNS BOOLEAN AS $$
+  SELECT (author_id = viewer_id OR NOT EXISTS (SELECT 1 FROM hidden_posts hp WHERE hp.post_id = target_post_id))
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = viewer_id AND ub.blocked_user_id = author_id) OR (ub.user_id = author_id AND ub.blocked_user_id = viewer_id))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = author_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+$$ LANGUAGE SQL STABLE;
+
+-- Feeds and search also leave out the posts of users the viewer muted
+CREATE FUNCTION visible_feed_post_for(viewer_id BIGINT, target_post_id BIGINT, author_id BIGINT) RETURNS BOOLEAN AS $$
+  SELECT visible_post_for(viewer_id, target_post_id, author_id)
+    AND NOT EXISTS (SELECT 1 FROM user_mutes um WHERE um.user_id = viewer_id AND um.muted_user_id = author_id)
+$$ LANGUAGE SQL STABLE;
\ No newline at end of file
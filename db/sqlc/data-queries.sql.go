@@ -10,9 +10,19 @@ import (
 	"database/sql"
 )
 
+const deleteCatalogPopularity = `-- name: DeleteCatalogPopularity :exec
+DELETE FROM catalog_popularity
+`
+
+func (q *Queries) DeleteCatalogPopularity(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteCatalogPopularity)
+	return err
+}
+
 const getCompanies = `-- name: GetCompanies :many
 SELECT id, name, COUNT(*) OVER () AS total_rows
 FROM companies
+ORDER BY name, id
 OFFSET $1
 LIMIT $2
 `
@@ -51,9 +61,52 @@ func (q *Queries) GetCompanies(ctx context.Context, arg GetCompaniesParams) ([]G
 	return items, nil
 }
 
+const getCompaniesByCursor = `-- name: GetCompaniesByCursor :many
+SELECT id, name
+FROM companies
+WHERE $2::bigint = 0 OR (name, id) > ($3::text, $2::bigint)
+ORDER BY name, id
+LIMIT $1
+`
+
+type GetCompaniesByCursorParams struct {
+	Limit      int32
+	CursorID   int64
+	CursorName string
+}
+
+type GetCompaniesByCursorRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetCompaniesByCursor(ctx context.Context, arg GetCompaniesByCursorParams) ([]GetCompaniesByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCompaniesByCursor, arg.Limit, arg.CursorID, arg.CursorName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCompaniesByCursorRow
+	for rows.Next() {
+		var i GetCompaniesByCursorRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getIssuingOrganizations = `-- name: GetIssuingOrganizations :many
 SELECT id, name, COUNT(*) OVER () AS total_rows
 FROM issuing_organizations
+ORDER BY name, id
 OFFSET $1
 LIMIT $2
 `
@@ -92,9 +145,52 @@ func (q *Queries) GetIssuingOrganizations(ctx context.Context, arg GetIssuingOrg
 	return items, nil
 }
 
+const getIssuingOrganizationsByCursor = `-- name: GetIssuingOrganizationsByCursor :many
+SELECT id, name
+FROM issuing_organizations
+WHERE $2::bigint = 0 OR (name, id) > ($3::text, $2::bigint)
+ORDER BY name, id
+LIMIT $1
+`
+
+type GetIssuingOrganizationsByCursorParams struct {
+	Limit      int32
+	CursorID   int64
+	CursorName string
+}
+
+type GetIssuingOrganizationsByCursorRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetIssuingOrganizationsByCursor(ctx context.Context, arg GetIssuingOrganizationsByCursorParams) ([]GetIssuingOrganizationsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getIssuingOrganizationsByCursor, arg.Limit, arg.CursorID, arg.CursorName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetIssuingOrganizationsByCursorRow
+	for rows.Next() {
+		var i GetIssuingOrganizationsByCursorRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getJobPositions = `-- name: GetJobPositions :many
 SELECT id, name, COUNT(id) OVER () AS total_rows
 FROM job_positions
+ORDER BY name, id
 OFFSET $1
 LIMIT $2
 `
@@ -133,9 +229,52 @@ func (q *Queries) GetJobPositions(ctx context.Context, arg GetJobPositionsParams
 	return items, nil
 }
 
+const getJobPositionsByCursor = `-- name: GetJobPositionsByCursor :many
+SELECT id, COALESCE(name, '')::text AS name
+FROM job_positions
+WHERE $2::bigint = 0 OR (COALESCE(name, ''), id) > ($3::text, $2::bigint)
+ORDER BY COALESCE(name, ''), id
+LIMIT $1
+`
+
+type GetJobPositionsByCursorParams struct {
+	Limit      int32
+	CursorID   int64
+	CursorName string
+}
+
+type GetJobPositionsByCursorRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetJobPositionsByCursor(ctx context.Context, arg GetJobPositionsByCursorParams) ([]GetJobPositionsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getJobPositionsByCursor, arg.Limit, arg.CursorID, arg.CursorName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetJobPositionsByCursorRow
+	for rows.Next() {
+		var i GetJobPositionsByCursorRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSchools = `-- name: GetSchools :many
 SELECT id, name, COUNT(*) OVER () AS total_rows
 FROM schools
+ORDER BY name, id
 OFFSET $1
 LIMIT $2
 `
@@ -174,9 +313,96 @@ func (q *Queries) GetSchools(ctx context.Context, arg GetSchoolsParams) ([]GetSc
 	return items, nil
 }
 
+const getSchoolsByCursor = `-- name: GetSchoolsByCursor :many
+SELECT id, name
+FROM schools
+WHERE $2::bigint = 0 OR (name, id) > ($3::text, $2::bigint)
+ORDER BY name, id
+LIMIT $1
+`
+
+type GetSchoolsByCursorParams struct {
+	Limit      int32
+	CursorID   int64
+	CursorName string
+}
+
+type GetSchoolsByCursorRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetSchoolsByCursor(ctx context.Context, arg GetSchoolsByCursorParams) ([]GetSchoolsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSchoolsByCursor, arg.Limit, arg.CursorID, arg.CursorName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSchoolsByCursorRow
+	for rows.Next() {
+		var i GetSchoolsByCursorRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSkillAncestors = `-- name: GetSkillAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT s.id, s.name, s.parent_id, 0 AS depth
+    FROM skills s
+    WHERE s.id = $1::bigint
+    UNION ALL
+    SELECT p.id, p.name, p.parent_id, a.depth + 1
+    FROM skills p
+    JOIN ancestors a ON p.id = a.parent_id
+    WHERE a.depth < 20
+)
+SELECT id, name FROM ancestors
+WHERE depth > 0
+ORDER BY depth DESC
+`
+
+type GetSkillAncestorsRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetSkillAncestors(ctx context.Context, id int64) ([]GetSkillAncestorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSkillAncestors, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSkillAncestorsRow
+	for rows.Next() {
+		var i GetSkillAncestorsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSkills = `-- name: GetSkills :many
 SELECT id, name, COUNT(id) OVER () AS total_rows
 FROM skills
+ORDER BY name, id
 OFFSET $1
 LIMIT $2
 `
@@ -214,3 +440,559 @@ func (q *Queries) GetSkills(ctx context.Context, arg GetSkillsParams) ([]GetSkil
 	}
 	return items, nil
 }
+
+const getSkillsByCursor = `-- name: GetSkillsByCursor :many
+SELECT id, name
+FROM skills
+WHERE $2::bigint = 0 OR (name, id) > ($3::text, $2::bigint)
+ORDER BY name, id
+LIMIT $1
+`
+
+type GetSkillsByCursorParams struct {
+	Limit      int32
+	CursorID   int64
+	CursorName string
+}
+
+type GetSkillsByCursorRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetSkillsByCursor(ctx context.Context, arg GetSkillsByCursorParams) ([]GetSkillsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSkillsByCursor, arg.Limit, arg.CursorID, arg.CursorName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSkillsByCursorRow
+	for rows.Next() {
+		var i GetSkillsByCursorRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertCatalogPopularity = `-- name: InsertCatalogPopularity :exec
+INSERT INTO catalog_popularity (catalog, item_id, user_count)
+SELECT 'schools', e.school_id, COUNT(DISTINCT e.user_id)::int FROM educations e WHERE e.school_id IS NOT NULL GROUP BY e.school_id
+UNION ALL
+SELECT 'companies', we.company_id, COUNT(DISTINCT we.user_id)::int FROM work_experiences we WHERE we.company_id IS NOT NULL GROUP BY we.company_id
+UNION ALL
+SELECT 'skills', us.skill_id, COUNT(DISTINCT us.user_id)::int FROM user_skills us WHERE us.skill_id IS NOT NULL GROUP BY us.skill_id
+UNION ALL
+SELECT 'job-positions', uji.job_position_id, COUNT(DISTINCT uji.user_id)::int FROM user_job_interests uji WHERE uji.job_position_id IS NOT NULL GROUP BY uji.job_position_id
+UNION ALL
+SELECT 'issuing-organizations', ce.issuing_organization_id, COUNT(DISTINCT ce.user_id)::int FROM certificates ce WHERE ce.issuing_organization_id IS NOT NULL GROUP BY ce.issuing_organization_id
+`
+
+func (q *Queries) InsertCatalogPopularity(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, insertCatalogPopularity)
+	return err
+}
+
+const listAllCompanies = `-- name: ListAllCompanies :many
+SELECT id, name FROM companies ORDER BY id
+`
+
+type ListAllCompaniesRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) ListAllCompanies(ctx context.Context) ([]ListAllCompaniesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllCompanies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllCompaniesRow
+	for rows.Next() {
+		var i ListAllCompaniesRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllIssuingOrganizations = `-- name: ListAllIssuingOrganizations :many
+SELECT id, name FROM issuing_organizations ORDER BY id
+`
+
+type ListAllIssuingOrganizationsRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) ListAllIssuingOrganizations(ctx context.Context) ([]ListAllIssuingOrganizationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllIssuingOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllIssuingOrganizationsRow
+	for rows.Next() {
+		var i ListAllIssuingOrganizationsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllJobPositions = `-- name: ListAllJobPositions :many
+SELECT id, name FROM job_positions ORDER BY id
+`
+
+type ListAllJobPositionsRow struct {
+	ID   int64
+	Name sql.NullString
+}
+
+func (q *Queries) ListAllJobPositions(ctx context.Context) ([]ListAllJobPositionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllJobPositions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllJobPositionsRow
+	for rows.Next() {
+		var i ListAllJobPositionsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllSchools = `-- name: ListAllSchools :many
+SELECT id, name FROM schools ORDER BY id
+`
+
+type ListAllSchoolsRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) ListAllSchools(ctx context.Context) ([]ListAllSchoolsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllSchools)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllSchoolsRow
+	for rows.Next() {
+		var i ListAllSchoolsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllSkills = `-- name: ListAllSkills :many
+SELECT id, name FROM skills ORDER BY id
+`
+
+type ListAllSkillsRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) ListAllSkills(ctx context.Context) ([]ListAllSkillsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllSkills)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllSkillsRow
+	for rows.Next() {
+		var i ListAllSkillsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSkillChildren = `-- name: ListSkillChildren :many
+SELECT id, name FROM skills
+WHERE parent_id = $1::bigint
+ORDER BY name, id
+`
+
+type ListSkillChildrenRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) ListSkillChildren(ctx context.Context, parentID int64) ([]ListSkillChildrenRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSkillChildren, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSkillChildrenRow
+	for rows.Next() {
+		var i ListSkillChildrenRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSkillSynonyms = `-- name: ListSkillSynonyms :many
+SELECT id, name FROM catalog_aliases
+WHERE catalog = 'skills' AND item_id = $1::bigint
+ORDER BY name, id
+`
+
+type ListSkillSynonymsRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) ListSkillSynonyms(ctx context.Context, skillID int64) ([]ListSkillSynonymsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSkillSynonyms, skillID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSkillSynonymsRow
+	for rows.Next() {
+		var i ListSkillSynonymsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCompanies = `-- name: SearchCompanies :many
+WITH matched AS (
+    SELECT c.id
+    FROM companies c
+    WHERE c.name ILIKE $1::text OR c.name % $2::text
+    UNION
+    SELECT ca.item_id
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'companies' AND (ca.name ILIKE $1::text OR ca.name % $2::text)
+)
+SELECT c.id, c.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM companies c
+JOIN matched m ON c.id = m.id
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'companies' AND cp.item_id = c.id
+ORDER BY c.name ILIKE $1::text OR EXISTS (
+        SELECT 1 FROM catalog_aliases ca
+        WHERE ca.catalog = 'companies' AND ca.item_id = c.id AND ca.name ILIKE $1::text
+    ) DESC,
+    popularity DESC, similarity(c.name, $2::text) DESC, c.name
+LIMIT $3::int
+`
+
+type SearchCompaniesParams struct {
+	Prefix   string
+	Q        string
+	RowLimit int32
+}
+
+type SearchCompaniesRow struct {
+	ID         int64
+	Name       string
+	Popularity int64
+}
+
+// Merged companies are found by their alias names too
+func (q *Queries) SearchCompanies(ctx context.Context, arg SearchCompaniesParams) ([]SearchCompaniesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchCompanies, arg.Prefix, arg.Q, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchCompaniesRow
+	for rows.Next() {
+		var i SearchCompaniesRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Popularity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchIssuingOrganizations = `-- name: SearchIssuingOrganizations :many
+SELECT io.id, io.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM issuing_organizations io
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'issuing-organizations' AND cp.item_id = io.id
+WHERE io.name ILIKE $1::text OR io.name % $2::text
+ORDER BY io.name ILIKE $1::text DESC, popularity DESC, similarity(io.name, $2::text) DESC, io.name
+LIMIT $3::int
+`
+
+type SearchIssuingOrganizationsParams struct {
+	Prefix   string
+	Q        string
+	RowLimit int32
+}
+
+type SearchIssuingOrganizationsRow struct {
+	ID         int64
+	Name       string
+	Popularity int64
+}
+
+func (q *Queries) SearchIssuingOrganizations(ctx context.Context, arg SearchIssuingOrganizationsParams) ([]SearchIssuingOrganizationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchIssuingOrganizations, arg.Prefix, arg.Q, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchIssuingOrganizationsRow
+	for rows.Next() {
+		var i SearchIssuingOrganizationsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Popularity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchJobPositions = `-- name: SearchJobPositions :many
+SELECT jp.id, jp.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM job_positions jp
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'job-positions' AND cp.item_id = jp.id
+WHERE jp.name ILIKE $1::text OR jp.name % $2::text
+ORDER BY jp.name ILIKE $1::text DESC, popularity DESC, similarity(jp.name, $2::text) DESC, jp.name
+LIMIT $3::int
+`
+
+type SearchJobPositionsParams struct {
+	Prefix   string
+	Q        string
+	RowLimit int32
+}
+
+type SearchJobPositionsRow struct {
+	ID         int64
+	Name       sql.NullString
+	Popularity int64
+}
+
+func (q *Queries) SearchJobPositions(ctx context.Context, arg SearchJobPositionsParams) ([]SearchJobPositionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchJobPositions, arg.Prefix, arg.Q, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchJobPositionsRow
+	for rows.Next() {
+		var i SearchJobPositionsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Popularity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSchools = `-- name: SearchSchools :many
+WITH matched AS (
+    SELECT s.id
+    FROM schools s
+    WHERE s.name ILIKE $1::text OR s.name % $2::text
+    UNION
+    SELECT ca.item_id
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'schools' AND (ca.name ILIKE $1::text OR ca.name % $2::text)
+)
+SELECT s.id, s.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM schools s
+JOIN matched m ON s.id = m.id
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'schools' AND cp.item_id = s.id
+ORDER BY s.name ILIKE $1::text OR EXISTS (
+        SELECT 1 FROM catalog_aliases ca
+        WHERE ca.catalog = 'schools' AND ca.item_id = s.id AND ca.name ILIKE $1::text
+    ) DESC,
+    popularity DESC, similarity(s.name, $2::text) DESC, s.name
+LIMIT $3::int
+`
+
+type SearchSchoolsParams struct {
+	Prefix   string
+	Q        string
+	RowLimit int32
+}
+
+type SearchSchoolsRow struct {
+	ID         int64
+	Name       string
+	Popularity int64
+}
+
+// Merged schools are found by their alias names too
+func (q *Queries) SearchSchools(ctx context.Context, arg SearchSchoolsParams) ([]SearchSchoolsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchSchools, arg.Prefix, arg.Q, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchSchoolsRow
+	for rows.Next() {
+		var i SearchSchoolsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Popularity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSkills = `-- name: SearchSkills :many
+WITH RECURSIVE matched AS (
+    SELECT sk.id, 0 AS depth
+    FROM skills sk
+    WHERE sk.name ILIKE $1::text OR sk.name % $2::text
+    UNION
+    SELECT ca.item_id, 0
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'skills' AND (ca.name ILIKE $1::text OR ca.name % $2::text)
+    UNION
+    SELECT child.id, m.depth + 1
+    FROM skills child
+    JOIN matched m ON child.parent_id = m.id
+    WHERE m.depth < 3
+)
+SELECT sk.id, sk.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM skills sk
+JOIN (SELECT id, MIN(depth) AS depth FROM matched GROUP BY id) m ON sk.id = m.id
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'skills' AND cp.item_id = sk.id
+ORDER BY m.depth,
+    sk.name ILIKE $1::text OR EXISTS (
+        SELECT 1 FROM catalog_aliases ca
+        WHERE ca.catalog = 'skills' AND ca.item_id = sk.id AND ca.name ILIKE $1::text
+    ) DESC,
+    popularity DESC, similarity(sk.name, $2::text) DESC, sk.name
+LIMIT $3::int
+`
+
+type SearchSkillsParams struct {
+	Prefix   string
+	Q        string
+	RowLimit int32
+}
+
+type SearchSkillsRow struct {
+	ID         int64
+	Name       string
+	Popularity int64
+}
+
+// Skills matching by name or synonym come first, then the skills under them
+func (q *Queries) SearchSkills(ctx context.Context, arg SearchSkillsParams) ([]SearchSkillsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchSkills, arg.Prefix, arg.Q, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchSkillsRow
+	for rows.Next() {
+		var i SearchSkillsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Popularity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
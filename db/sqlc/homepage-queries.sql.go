@@ -8,55 +8,497 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
-const getFollowsRecommendationForUserId = `-- name: GetFollowsRecommendationForUserId :many
-SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work, 
-    COUNT(u.id) OVER () AS total_rows
+const countFollowingTimeline = `-- name: CountFollowingTimeline :one
+SELECT (SELECT COUNT(*) FROM timeline_entries te WHERE te.user_id = $1::bigint)
+    + (SELECT COUNT(*) FROM timeline_pull_posts tp JOIN followings f ON f.follow_user_id = tp.author_id WHERE f.user_id = $1::bigint) AS count
+`
+
+func (q *Queries) CountFollowingTimeline(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowingTimeline, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createTrendingPostEvents = `-- name: CreateTrendingPostEvents :exec
+CREATE TEMP TABLE trending_post_events (
+    post_id BIGINT PRIMARY KEY,
+    like_count INT NOT NULL,
+    comment_count INT NOT NULL,
+    repost_count INT NOT NULL,
+    score DOUBLE PRECISION NOT NULL
+) ON COMMIT DROP
+`
+
+// Engagement of one window, shared by the trending posts and topics of a refresh and dropped with its transaction
+func (q *Queries) CreateTrendingPostEvents(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, createTrendingPostEvents)
+	return err
+}
+
+const deleteTrendingPostsByWindow = `-- name: DeleteTrendingPostsByWindow :exec
+DELETE FROM trending_posts
+WHERE time_window = $1::text
+`
+
+func (q *Queries) DeleteTrendingPostsByWindow(ctx context.Context, timeWindow string) error {
+	_, err := q.db.ExecContext(ctx, deleteTrendingPostsByWindow, timeWindow)
+	return err
+}
+
+const deleteTrendingTopicsByWindow = `-- name: DeleteTrendingTopicsByWindow :exec
+DELETE FROM trending_topics
+WHERE time_window = $1::text
+`
+
+func (q *Queries) DeleteTrendingTopicsByWindow(ctx context.Context, timeWindow string) error {
+	_, err := q.db.ExecContext(ctx, deleteTrendingTopicsByWindow, timeWindow)
+	return err
+}
+
+const insertDismissedFollowRecommendation = `-- name: InsertDismissedFollowRecommendation :one
+INSERT INTO dismissed_follow_recommendations (user_id, dismissed_user_id, created_at)
+SELECT $1::bigint, u.id, NOW()
 FROM users u
-JOIN (
-    SELECT DISTINCT f.follow_user_id
-    FROM followings f
-    JOIN followings f2 ON f.user_id = f2.follow_user_id
-    WHERE f2.user_id = $1
-) AS users_reccomendation ON u.id = users_reccomendation.follow_user_id
-WHERE u.id != $1
-ORDER BY u.followers_count DESC
-OFFSET $2
-LIMIT $3
+WHERE u.id = $2::bigint
+ON CONFLICT (user_id, dismissed_user_id) DO UPDATE SET created_at = NOW()
+RETURNING id
 `
 
-type GetFollowsRecommendationForUserIdParams struct {
-	UserID sql.NullInt64
-	Offset int32
-	Limit  int32
+type InsertDismissedFollowRecommendationParams struct {
+	UserID          int64
+	DismissedUserID int64
 }
 
-type GetFollowsRecommendationForUserIdRow struct {
-	ID         int64
-	FullName   string
-	AvatarUrl  sql.NullString
-	Bio        sql.NullString
-	OpenToWork sql.NullBool
-	TotalRows  int64
+func (q *Queries) InsertDismissedFollowRecommendation(ctx context.Context, arg InsertDismissedFollowRecommendationParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertDismissedFollowRecommendation, arg.UserID, arg.DismissedUserID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
-func (q *Queries) GetFollowsRecommendationForUserId(ctx context.Context, arg GetFollowsRecommendationForUserIdParams) ([]GetFollowsRecommendationForUserIdRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFollowsRecommendationForUserId, arg.UserID, arg.Offset, arg.Limit)
+const insertTrendingHashtags = `-- name: InsertTrendingHashtags :exec
+INSERT INTO trending_topics
+(time_window, topic_type, name, post_count, like_count, comment_count, repost_count, score, computed_at)
+SELECT $1::text, 'hashtag', ph.hashtag, COUNT(*)::int, SUM(pe.like_count)::int, SUM(pe.comment_count)::int, SUM(pe.repost_count)::int,
+    SUM(pe.score)::float8, NOW()
+FROM trending_post_events pe
+JOIN post_hashtags ph ON ph.post_id = pe.post_id
+GROUP BY ph.hashtag
+ORDER BY 8 DESC, ph.hashtag
+LIMIT $2::int
+`
+
+type InsertTrendingHashtagsParams struct {
+	TimeWindow string
+	RowLimit   int32
+}
+
+func (q *Queries) InsertTrendingHashtags(ctx context.Context, arg InsertTrendingHashtagsParams) error {
+	_, err := q.db.ExecContext(ctx, insertTrendingHashtags, arg.TimeWindow, arg.RowLimit)
+	return err
+}
+
+const insertTrendingPostEvents = `-- name: InsertTrendingPostEvents :exec
+WITH events AS (
+    SELECT lp.post_id, 1 AS likes, 0 AS comments, 0 AS reposts, lp.created_at
+    FROM liked_posts lp
+    WHERE lp.created_at >= NOW() - MAKE_INTERVAL(secs => $1::int)
+    UNION ALL
+    SELECT pc.post_id, 0, 1, 0, pc.created_at
+    FROM post_comments pc
+    WHERE pc.created_at >= NOW() - MAKE_INTERVAL(secs => $1::int)
+    UNION ALL
+    SELECT rpp.post_id, 0, 0, 1, rpp.created_at
+    FROM reposted_posts rpp
+    WHERE rpp.created_at >= NOW() - MAKE_INTERVAL(secs => $1::int)
+)
+INSERT INTO trending_post_events
+(post_id, like_count, comment_count, repost_count, score)
+SELECT e.post_id, SUM(e.likes)::int, SUM(e.comments)::int, SUM(e.reposts)::int,
+    SUM((e.likes + 2 * e.comments + 3 * e.reposts) * POWER(0.5, EXTRACT(EPOCH FROM NOW() - e.created_at) / ($1::int / 4.0)))::float8
+FROM events e
+JOIN posts p ON p.id = e.post_id
+    WHERE p.visibility = 'public'
+        AND visible_post_for(0, p.id, p.user_id)
+GROUP BY e.post_id
+`
+
+// Posts liked, commented or reposted within the window, comments and reposts weigh more than likes
+// and every event loses half its weight each quarter of the window
+func (q *Queries) InsertTrendingPostEvents(ctx context.Context, windowSeconds int32) error {
+	_, err := q.db.ExecContext(ctx, insertTrendingPostEvents, windowSeconds)
+	return err
+}
+
+const insertTrendingPosts = `-- name: InsertTrendingPosts :exec
+INSERT INTO trending_posts
+(time_window, post_id, like_count, comment_count, repost_count, score, computed_at)
+SELECT $1::text, pe.post_id, pe.like_count, pe.comment_count, pe.repost_count, pe.score, NOW()
+FROM trending_post_events pe
+ORDER BY pe.score DESC, pe.post_id DESC
+LIMIT $2::int
+`
+
+type InsertTrendingPostsParams struct {
+	TimeWindow string
+	RowLimit   int32
+}
+
+func (q *Queries) InsertTrendingPosts(ctx context.Context, arg InsertTrendingPostsParams) error {
+	_, err := q.db.ExecContext(ctx, insertTrendingPosts, arg.TimeWindow, arg.RowLimit)
+	return err
+}
+
+const insertTrendingSkills = `-- name: InsertTrendingSkills :exec
+INSERT INTO trending_topics
+(time_window, topic_type, name, post_count, like_count, comment_count, repost_count, score, computed_at)
+SELECT $1::text, 'skill', ps.name, COUNT(*)::int, SUM(ps.like_count)::int, SUM(ps.comment_count)::int, SUM(ps.repost_count)::int,
+    SUM(ps.score)::float8, NOW()
+FROM (
+    SELECT DISTINCT s.id, s.name, pe.post_id, pe.like_count, pe.comment_count, pe.repost_count, pe.score
+    FROM trending_post_events pe
+    JOIN post_hashtags ph ON ph.post_id = pe.post_id
+    JOIN skills s ON s.normalized_name = ph.hashtag
+        OR s.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'skills' AND ca.normalized_name = ph.hashtag)
+) ps
+GROUP BY ps.id, ps.name
+ORDER BY 8 DESC, ps.name
+LIMIT $2::int
+`
+
+type InsertTrendingSkillsParams struct {
+	TimeWindow string
+	RowLimit   int32
+}
+
+// Skills are matched by hashtags naming the skill or one of its synonyms
+func (q *Queries) InsertTrendingSkills(ctx context.Context, arg InsertTrendingSkillsParams) error {
+	_, err := q.db.ExecContext(ctx, insertTrendingSkills, arg.TimeWindow, arg.RowLimit)
+	return err
+}
+
+const listFollowRecommendationCandidates = `-- name: ListFollowRecommendationCandidates :many
+WITH signals AS (
+    SELECT f.follow_user_id AS user_id, 3.0 AS weight
+    FROM followings vf
+    CROSS JOIN LATERAL (SELECT f.follow_user_id FROM followings f WHERE f.user_id = vf.follow_user_id ORDER BY f.id DESC LIMIT $1::int) f
+    WHERE vf.user_id = $2::bigint
+    UNION ALL
+    SELECT we.user_id, 2.0
+    FROM work_experiences vwe
+    CROSS JOIN LATERAL (SELECT we.user_id FROM work_experiences we WHERE we.company_id = vwe.company_id LIMIT $1::int) we
+    WHERE vwe.user_id = $2::bigint
+    UNION ALL
+    SELECT e.user_id, 1.5
+    FROM educations ve
+    CROSS JOIN LATERAL (SELECT e.user_id FROM educations e WHERE e.school_id = ve.school_id LIMIT $1::int) e
+    WHERE ve.user_id = $2::bigint
+    UNION ALL
+    SELECT us.user_id, 0.3
+    FROM user_skills vus
+    CROSS JOIN LATERAL (SELECT us.user_id FROM user_skills us WHERE us.skill_id = vus.skill_id LIMIT $1::int) us
+    WHERE vus.user_id = $2::bigint
+    UNION ALL
+    SELECT uji.user_id, 0.3
+    FROM user_job_interests vuji
+    CROSS JOIN LATERAL (SELECT uji.user_id FROM user_job_interests uji WHERE uji.job_position_id = vuji.job_position_id LIMIT $1::int) uji
+    WHERE vuji.user_id = $2::bigint
+    UNION ALL
+    SELECT ud.user_id, 0.5
+    FROM user_details vud
+    CROSS JOIN LATERAL (SELECT ud.user_id FROM user_details ud WHERE LOWER(ud.location) = LOWER(vud.location) LIMIT $1::int) ud
+    WHERE vud.user_id = $2::bigint AND vud.location <> ''
+), pool AS (
+    SELECT s.user_id, SUM(s.weight) AS pre_score
+    FROM (
+        SELECT user_id, weight FROM signals
+        UNION ALL
+        (SELECT id, 0 FROM users ORDER BY followers_count DESC NULLS LAST LIMIT $3::int)
+    ) s
+    GROUP BY s.user_id
+), candidates AS (
+    SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+        COALESCE(u.followers_count, 0)::int AS followers_count
+    FROM pool
+    JOIN users u ON u.id = pool.user_id
+    WHERE u.id <> $2::bigint AND u.deleted_at IS NULL
+        AND NOT EXISTS (SELECT 1 FROM followings f WHERE f.user_id = $2::bigint AND f.follow_user_id = u.id)
+        AND NOT EXISTS (SELECT 1 FROM dismissed_follow_recommendations dfr WHERE dfr.user_id = $2::bigint AND dfr.dismissed_user_id = u.id)
+        AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $2::bigint AND ub.blocked_user_id = u.id) OR (ub.user_id = u.id AND ub.blocked_user_id = $2::bigint))
+        AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    ORDER BY pool.pre_score DESC, u.followers_count DESC NULLS LAST, u.id
+    LIMIT $4::int
+)
+SELECT c.id, c.full_name, c.avatar_url, c.bio, c.open_to_work, c.followers_count,
+    (SELECT COUNT(*) FROM followings vf JOIN followings f ON f.user_id = vf.follow_user_id AND f.follow_user_id = c.id WHERE vf.user_id = $2::bigint) AS mutual_followings,
+    COALESCE((SELECT co.name FROM work_experiences we JOIN work_experiences vwe ON vwe.company_id = we.company_id AND vwe.user_id = $2::bigint JOIN companies co ON co.id = we.company_id WHERE we.user_id = c.id ORDER BY co.name LIMIT 1), '')::text AS shared_company,
+    COALESCE((SELECT s.name FROM educations e JOIN educations ve ON ve.school_id = e.school_id AND ve.user_id = $2::bigint JOIN schools s ON s.id = e.school_id WHERE e.user_id = c.id ORDER BY s.name LIMIT 1), '')::text AS shared_school,
+    (SELECT COUNT(DISTINCT us.skill_id) FROM user_skills us JOIN user_skills vus ON vus.skill_id = us.skill_id AND vus.user_id = $2::bigint WHERE us.user_id = c.id) AS shared_skills,
+    (SELECT COUNT(DISTINCT uji.job_position_id) FROM user_job_interests uji JOIN user_job_interests vuji ON vuji.job_position_id = uji.job_position_id AND vuji.user_id = $2::bigint WHERE uji.user_id = c.id) AS shared_job_interests,
+    COALESCE((SELECT ud.location FROM user_details ud JOIN user_details vud ON LOWER(vud.location) = LOWER(ud.location) AND vud.user_id = $2::bigint WHERE ud.user_id = c.id AND ud.location <> '' LIMIT 1), '')::text AS shared_location
+FROM candidates c
+`
+
+type ListFollowRecommendationCandidatesParams struct {
+	SignalLimit  int32
+	UserID       int64
+	PopularLimit int32
+	RowLimit     int32
+}
+
+type ListFollowRecommendationCandidatesRow struct {
+	ID                 int64
+	FullName           string
+	AvatarUrl          sql.NullString
+	Bio                sql.NullString
+	OpenToWork         sql.NullBool
+	FollowersCount     int32
+	MutualFollowings   int64
+	SharedCompany      string
+	SharedSchool       string
+	SharedSkills       int64
+	SharedJobInterests int64
+	SharedLocation     string
+}
+
+// Users related to the viewer by any signal plus the most followed users, ranked by the usecase.
+// Every relation of the viewer adds at most signal_limit users, and the candidates kept are the ones sharing the most with the viewer
+func (q *Queries) ListFollowRecommendationCandidates(ctx context.Context, arg ListFollowRecommendationCandidatesParams) ([]ListFollowRecommendationCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowRecommendationCandidates,
+		arg.SignalLimit,
+		arg.UserID,
+		arg.PopularLimit,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFollowsRecommendationForUserIdRow
+	var items []ListFollowRecommendationCandidatesRow
 	for rows.Next() {
-		var i GetFollowsRecommendationForUserIdRow
+		var i ListFollowRecommendationCandidatesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FullName,
 			&i.AvatarUrl,
 			&i.Bio,
 			&i.OpenToWork,
-			&i.TotalRows,
+			&i.FollowersCount,
+			&i.MutualFollowings,
+			&i.SharedCompany,
+			&i.SharedSchool,
+			&i.SharedSkills,
+			&i.SharedJobInterests,
+			&i.SharedLocation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowingTimeline = `-- name: ListFollowingTimeline :many
+SELECT t.post_id, t.created_at
+FROM (
+    (SELECT te.post_id, te.created_at
+    FROM timeline_entries te
+    WHERE te.user_id = $1::bigint
+    ORDER BY te.created_at DESC, te.post_id DESC
+    LIMIT $2::int + $3::int)
+    UNION ALL
+    (SELECT tp.post_id, tp.created_at
+    FROM timeline_pull_posts tp
+    JOIN followings f ON f.follow_user_id = tp.author_id
+    WHERE f.user_id = $1::bigint
+    ORDER BY tp.created_at DESC, tp.post_id DESC
+    LIMIT $2::int + $3::int)
+) t
+ORDER BY t.created_at DESC, t.post_id DESC
+OFFSET $2::int
+LIMIT $3::int
+`
+
+type ListFollowingTimelineParams struct {
+	UserID    int64
+	RowOffset int32
+	RowLimit  int32
+}
+
+type ListFollowingTimelineRow struct {
+	PostID    int64
+	CreatedAt time.Time
+}
+
+// Both sources are paged on their own index before they are merged
+func (q *Queries) ListFollowingTimeline(ctx context.Context, arg ListFollowingTimelineParams) ([]ListFollowingTimelineRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowingTimeline, arg.UserID, arg.RowOffset, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowingTimelineRow
+	for rows.Next() {
+		var i ListFollowingTimelineRow
+		if err := rows.Scan(&i.PostID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowingTimelineByCursor = `-- name: ListFollowingTimelineByCursor :many
+SELECT t.post_id, t.created_at
+FROM (
+    (SELECT te.post_id, te.created_at
+    FROM timeline_entries te
+    WHERE te.user_id = $1::bigint
+        AND ($2::bigint = 0 OR (te.created_at, te.post_id) < ($3::timestamp, $2::bigint))
+    ORDER BY te.created_at DESC, te.post_id DESC
+    LIMIT $4::int)
+    UNION ALL
+    (SELECT tp.post_id, tp.created_at
+    FROM timeline_pull_posts tp
+    JOIN followings f ON f.follow_user_id = tp.author_id
+    WHERE f.user_id = $1::bigint
+        AND ($2::bigint = 0 OR (tp.created_at, tp.post_id) < ($3::timestamp, $2::bigint))
+    ORDER BY tp.created_at DESC, tp.post_id DESC
+    LIMIT $4::int)
+) t
+ORDER BY t.created_at DESC, t.post_id DESC
+LIMIT $4::int
+`
+
+type ListFollowingTimelineByCursorParams struct {
+	UserID          int64
+	CursorID        int64
+	CursorCreatedAt time.Time
+	RowLimit        int32
+}
+
+type ListFollowingTimelineByCursorRow struct {
+	PostID    int64
+	CreatedAt time.Time
+}
+
+// Both sources are paged on their own index before they are merged
+func (q *Queries) ListFollowingTimelineByCursor(ctx context.Context, arg ListFollowingTimelineByCursorParams) ([]ListFollowingTimelineByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowingTimelineByCursor,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowingTimelineByCursorRow
+	for rows.Next() {
+		var i ListFollowingTimelineByCursorRow
+		if err := rows.Scan(&i.PostID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listForYouCandidates = `-- name: ListForYouCandidates :many
+WITH candidates AS (
+    SELECT p.id, p.user_id, p.created_at,
+        COALESCE(p.like_count, 0)::int AS like_count,
+        COALESCE(p.comment_count, 0)::int AS comment_count,
+        COALESCE(p.repost_count, 0)::int AS repost_count
+    FROM posts p
+    WHERE p.created_at >= NOW() - INTERVAL '7 days' AND p.user_id <> $1::bigint AND p.visibility = 'public'
+        AND NOT EXISTS (SELECT 1 FROM reports rp WHERE rp.target_type = 'post' AND rp.target_id = p.id AND rp.user_id = $1::bigint)
+        AND visible_feed_post_for($1::bigint, p.id, p.user_id)
+    ORDER BY p.created_at DESC
+    LIMIT $2::int
+), authors AS (
+    SELECT a.user_id,
+        EXISTS (SELECT 1 FROM followings f WHERE f.user_id = $1::bigint AND f.follow_user_id = a.user_id) AS is_followed,
+        (SELECT COUNT(*) FROM followings f JOIN followings vf ON vf.follow_user_id = f.user_id AND vf.user_id = $1::bigint WHERE f.follow_user_id = a.user_id) AS mutual_followings,
+        (SELECT COUNT(DISTINCT us.skill_id) FROM user_skills us JOIN user_skills vus ON vus.skill_id = us.skill_id AND vus.user_id = $1::bigint WHERE us.user_id = a.user_id) AS shared_skills,
+        (SELECT COUNT(DISTINCT we.company_id) FROM work_experiences we JOIN work_experiences vwe ON vwe.company_id = we.company_id AND vwe.user_id = $1::bigint WHERE we.user_id = a.user_id) AS shared_companies,
+        (SELECT COUNT(*) FROM liked_posts lp JOIN posts ap ON ap.id = lp.post_id WHERE lp.user_id = $1::bigint AND ap.user_id = a.user_id)
+            + (SELECT COUNT(*) FROM post_comments pc JOIN posts ap ON ap.id = pc.post_id WHERE pc.user_id = $1::bigint AND ap.user_id = a.user_id)
+            + (SELECT COUNT(*) FROM reposted_posts rpp JOIN posts ap ON ap.id = rpp.post_id WHERE rpp.user_id = $1::bigint AND ap.user_id = a.user_id) AS author_interactions
+    FROM (SELECT DISTINCT user_id FROM candidates) a
+)
+SELECT c.id, c.user_id, c.created_at, c.like_count, c.comment_count, c.repost_count,
+    a.is_followed, a.mutual_followings, a.shared_skills, a.shared_companies, a.author_interactions::bigint AS author_interactions
+FROM candidates c
+JOIN authors a ON a.user_id = c.user_id
+`
+
+type ListForYouCandidatesParams struct {
+	UserID   int64
+	RowLimit int32
+}
+
+type ListForYouCandidatesRow struct {
+	ID                 int64
+	UserID             sql.NullInt64
+	CreatedAt          sql.NullTime
+	LikeCount          int32
+	CommentCount       int32
+	RepostCount        int32
+	IsFollowed         bool
+	MutualFollowings   int64
+	SharedSkills       int64
+	SharedCompanies    int64
+	AuthorInteractions int64
+}
+
+// Recent posts with the viewer's relationship to their authors, ranked by the usecase
+func (q *Queries) ListForYouCandidates(ctx context.Context, arg ListForYouCandidatesParams) ([]ListForYouCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listForYouCandidates, arg.UserID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListForYouCandidatesRow
+	for rows.Next() {
+		var i ListForYouCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.IsFollowed,
+			&i.MutualFollowings,
+			&i.SharedSkills,
+			&i.SharedCompanies,
+			&i.AuthorInteractions,
 		); err != nil {
 			return nil, err
 		}
@@ -83,16 +525,21 @@ SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
-LEFT JOIN reported_posts rp ON p.id = rp.post_id AND rp.user_id = $1
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE rp.post_id IS NULL AND p.visibility = 'public'
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
 ORDER BY p.created_at DESC
 OFFSET $2
 LIMIT $3
@@ -124,6 +571,7 @@ type ListNewestPostsRow struct {
 	TotalRows    int64
 	Liked        bool
 	Repost       bool
+	Bookmarked   bool
 }
 
 func (q *Queries) ListNewestPosts(ctx context.Context, arg ListNewestPostsParams) ([]ListNewestPostsRow, error) {
@@ -155,6 +603,115 @@ func (q *Queries) ListNewestPosts(ctx context.Context, arg ListNewestPostsParams
 			&i.TotalRows,
 			&i.Liked,
 			&i.Repost,
+			&i.Bookmarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNewestPostsByCursor = `-- name: ListNewestPostsByCursor :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND ($3::bigint = 0 OR (p.created_at, p.id) < ($4::timestamp, $3::bigint))
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT $2
+`
+
+type ListNewestPostsByCursorParams struct {
+	UserID          sql.NullInt64
+	Limit           int32
+	CursorID        int64
+	CursorCreatedAt time.Time
+}
+
+type ListNewestPostsByCursorRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+	ID_2         sql.NullInt64
+	FullName     sql.NullString
+	AvatarUrl    sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+	ImageUrls    interface{}
+	Liked        bool
+	Repost       bool
+	Bookmarked   bool
+}
+
+func (q *Queries) ListNewestPostsByCursor(ctx context.Context, arg ListNewestPostsByCursorParams) ([]ListNewestPostsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNewestPostsByCursor,
+		arg.UserID,
+		arg.Limit,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNewestPostsByCursorRow
+	for rows.Next() {
+		var i ListNewestPostsByCursorRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Content,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Visibility,
+			&i.ID_2,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.ImageUrls,
+			&i.Liked,
+			&i.Repost,
+			&i.Bookmarked,
 		); err != nil {
 			return nil, err
 		}
@@ -185,16 +742,22 @@ SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
-LEFT JOIN reported_posts rp ON p.id = rp.post_id AND rp.user_id = $1
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE rp.post_id IS NULL AND p.visibility = 'public'
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND NOT EXISTS (SELECT 1 FROM post_impressions pim WHERE pim.user_id = $1 AND pim.post_id = p.id AND pim.first_seen_at < NOW() - INTERVAL '1 hour')
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
 ORDER BY
     recent_post DESC,
     (p.like_count + p.comment_count + p.repost_count) DESC
@@ -229,8 +792,10 @@ type ListPopularPostsRow struct {
 	RecentPost   bool
 	Liked        bool
 	Repost       bool
+	Bookmarked   bool
 }
 
+// Posts first seen over an hour ago, in an earlier session, are left out
 func (q *Queries) ListPopularPosts(ctx context.Context, arg ListPopularPostsParams) ([]ListPopularPostsRow, error) {
 	rows, err := q.db.QueryContext(ctx, listPopularPosts, arg.UserID, arg.Offset, arg.Limit)
 	if err != nil {
@@ -261,6 +826,7 @@ func (q *Queries) ListPopularPosts(ctx context.Context, arg ListPopularPostsPara
 			&i.RecentPost,
 			&i.Liked,
 			&i.Repost,
+			&i.Bookmarked,
 		); err != nil {
 			return nil, err
 		}
@@ -275,11 +841,10 @@ func (q *Queries) ListPopularPosts(ctx context.Context, arg ListPopularPostsPara
 	return items, nil
 }
 
-const listPostsByFollowing = `-- name: ListPostsByFollowing :many
-SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility,
+const listPopularPostsByCursor = `-- name: ListPopularPostsByCursor :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
-	COUNT(p.id) OVER () AS total_rows,
     CASE 
     	WHEN lp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
@@ -287,29 +852,39 @@ SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
-LEFT JOIN users u ON p.user_id = u.id 
-LEFT JOIN reported_posts rp ON p.id = rp.post_id AND rp.user_id = $1
-LEFT JOIN followings f ON p.user_id = f.follow_user_id
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE f.user_id = $1 AND rp.post_id IS NULL
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND NOT EXISTS (SELECT 1 FROM post_impressions pim WHERE pim.user_id = $1 AND pim.post_id = p.id AND pim.first_seen_at < NOW() - INTERVAL '1 hour')
+    AND ($3::bigint = 0 OR (p.created_at >= NOW() - INTERVAL '30 days', COALESCE(p.like_count, 0) + COALESCE(p.comment_count, 0) + COALESCE(p.repost_count, 0), p.id) < ($4::timestamp >= NOW() - INTERVAL '30 days', $5::bigint, $3::bigint))
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
-ORDER BY p.created_at DESC
-OFFSET $2
-LIMIT $3
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY
+    (p.created_at >= NOW() - INTERVAL '30 days') DESC,
+    (COALESCE(p.like_count, 0) + COALESCE(p.comment_count, 0) + COALESCE(p.repost_count, 0)) DESC,
+    p.id DESC
+LIMIT $2
 `
 
-type ListPostsByFollowingParams struct {
-	UserID sql.NullInt64
-	Offset int32
-	Limit  int32
+type ListPopularPostsByCursorParams struct {
+	UserID          sql.NullInt64
+	Limit           int32
+	CursorID        int64
+	CursorCreatedAt time.Time
+	CursorScore     int64
 }
 
-type ListPostsByFollowingRow struct {
+type ListPopularPostsByCursorRow struct {
 	ID           int64
 	UserID       sql.NullInt64
 	Content      sql.NullString
@@ -326,20 +901,27 @@ type ListPostsByFollowingRow struct {
 	Bio          sql.NullString
 	OpenToWork   sql.NullBool
 	ImageUrls    interface{}
-	TotalRows    int64
 	Liked        bool
 	Repost       bool
+	Bookmarked   bool
 }
 
-func (q *Queries) ListPostsByFollowing(ctx context.Context, arg ListPostsByFollowingParams) ([]ListPostsByFollowingRow, error) {
-	rows, err := q.db.QueryContext(ctx, listPostsByFollowing, arg.UserID, arg.Offset, arg.Limit)
+// Posts first seen over an hour ago, in an earlier session, are left out
+func (q *Queries) ListPopularPostsByCursor(ctx context.Context, arg ListPopularPostsByCursorParams) ([]ListPopularPostsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPopularPostsByCursor,
+		arg.UserID,
+		arg.Limit,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.CursorScore,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPostsByFollowingRow
+	var items []ListPopularPostsByCursorRow
 	for rows.Next() {
-		var i ListPostsByFollowingRow
+		var i ListPopularPostsByCursorRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -357,9 +939,360 @@ func (q *Queries) ListPostsByFollowing(ctx context.Context, arg ListPostsByFollo
 			&i.Bio,
 			&i.OpenToWork,
 			&i.ImageUrls,
-			&i.TotalRows,
 			&i.Liked,
 			&i.Repost,
+			&i.Bookmarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByIds = `-- name: ListPostsByIds :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND p.id = ANY($2::bigint[])
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC, p.id DESC
+`
+
+type ListPostsByIdsParams struct {
+	UserID  sql.NullInt64
+	PostIds []int64
+}
+
+type ListPostsByIdsRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+	ID_2         sql.NullInt64
+	FullName     sql.NullString
+	AvatarUrl    sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+	ImageUrls    interface{}
+	Liked        bool
+	Repost       bool
+	Bookmarked   bool
+}
+
+func (q *Queries) ListPostsByIds(ctx context.Context, arg ListPostsByIdsParams) ([]ListPostsByIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByIds, arg.UserID, pq.Array(arg.PostIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPostsByIdsRow
+	for rows.Next() {
+		var i ListPostsByIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Content,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Visibility,
+			&i.ID_2,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.ImageUrls,
+			&i.Liked,
+			&i.Repost,
+			&i.Bookmarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimelinePostsByIds = `-- name: ListTimelinePostsByIds :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rp.target_id IS NULL AND visible_feed_post_for($1, p.id, p.user_id)
+    AND p.id = ANY($2::bigint[])
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC, p.id DESC
+`
+
+type ListTimelinePostsByIdsParams struct {
+	UserID  sql.NullInt64
+	PostIds []int64
+}
+
+type ListTimelinePostsByIdsRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+	ID_2         sql.NullInt64
+	FullName     sql.NullString
+	AvatarUrl    sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+	ImageUrls    interface{}
+	Liked        bool
+	Repost       bool
+	Bookmarked   bool
+}
+
+// Unlike ListPostsByIds it keeps the followers only posts, the ids come from the viewer's own timeline
+func (q *Queries) ListTimelinePostsByIds(ctx context.Context, arg ListTimelinePostsByIdsParams) ([]ListTimelinePostsByIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTimelinePostsByIds, arg.UserID, pq.Array(arg.PostIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTimelinePostsByIdsRow
+	for rows.Next() {
+		var i ListTimelinePostsByIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Content,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Visibility,
+			&i.ID_2,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.ImageUrls,
+			&i.Liked,
+			&i.Repost,
+			&i.Bookmarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrendingPosts = `-- name: ListTrendingPosts :many
+SELECT tp.post_id, tp.score,
+    COUNT(tp.post_id) OVER () AS total_rows
+FROM trending_posts tp
+WHERE tp.time_window = $1::text
+ORDER BY tp.score DESC, tp.post_id DESC
+OFFSET $2::int
+LIMIT $3::int
+`
+
+type ListTrendingPostsParams struct {
+	TimeWindow string
+	RowOffset  int32
+	RowLimit   int32
+}
+
+type ListTrendingPostsRow struct {
+	PostID    int64
+	Score     float64
+	TotalRows int64
+}
+
+func (q *Queries) ListTrendingPosts(ctx context.Context, arg ListTrendingPostsParams) ([]ListTrendingPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrendingPosts, arg.TimeWindow, arg.RowOffset, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrendingPostsRow
+	for rows.Next() {
+		var i ListTrendingPostsRow
+		if err := rows.Scan(&i.PostID, &i.Score, &i.TotalRows); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrendingPostsByCursor = `-- name: ListTrendingPostsByCursor :many
+SELECT tp.post_id, tp.score
+FROM trending_posts tp
+WHERE tp.time_window = $1::text
+    AND ($2::bigint = 0 OR (ROUND(tp.score::numeric * 1000)::bigint, tp.post_id) < ($3::bigint, $2::bigint))
+ORDER BY ROUND(tp.score::numeric * 1000) DESC, tp.post_id DESC
+LIMIT $4::int
+`
+
+type ListTrendingPostsByCursorParams struct {
+	TimeWindow  string
+	CursorID    int64
+	CursorScore int64
+	RowLimit    int32
+}
+
+type ListTrendingPostsByCursorRow struct {
+	PostID int64
+	Score  float64
+}
+
+// Cursor scores keep the precision of libs.TrendingPageCursor
+func (q *Queries) ListTrendingPostsByCursor(ctx context.Context, arg ListTrendingPostsByCursorParams) ([]ListTrendingPostsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrendingPostsByCursor,
+		arg.TimeWindow,
+		arg.CursorID,
+		arg.CursorScore,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrendingPostsByCursorRow
+	for rows.Next() {
+		var i ListTrendingPostsByCursorRow
+		if err := rows.Scan(&i.PostID, &i.Score); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrendingTopics = `-- name: ListTrendingTopics :many
+SELECT tt.topic_type, tt.name, tt.post_count, tt.like_count, tt.comment_count, tt.repost_count, tt.score
+FROM trending_topics tt
+WHERE tt.time_window = $1::text AND ($2::text = '' OR tt.topic_type = $2::text)
+ORDER BY tt.score DESC, tt.name
+LIMIT $3::int
+`
+
+type ListTrendingTopicsParams struct {
+	TimeWindow string
+	TopicType  string
+	RowLimit   int32
+}
+
+type ListTrendingTopicsRow struct {
+	TopicType    string
+	Name         string
+	PostCount    int32
+	LikeCount    int32
+	CommentCount int32
+	RepostCount  int32
+	Score        float64
+}
+
+func (q *Queries) ListTrendingTopics(ctx context.Context, arg ListTrendingTopicsParams) ([]ListTrendingTopicsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrendingTopics, arg.TimeWindow, arg.TopicType, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrendingTopicsRow
+	for rows.Next() {
+		var i ListTrendingTopicsRow
+		if err := rows.Scan(
+			&i.TopicType,
+			&i.Name,
+			&i.PostCount,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -6,8 +6,50 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 )
 
+type AdminAuditLog struct {
+	ID         int64
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   sql.NullInt64
+	Details    json.RawMessage
+	CreatedAt  time.Time
+}
+
+type BookmarkFolder struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type BookmarkedPost struct {
+	ID               int64
+	UserID           int64
+	PostID           int64
+	BookmarkFolderID sql.NullInt64
+	CreatedAt        time.Time
+}
+
+type CatalogAlias struct {
+	ID             int64
+	Catalog        string
+	ItemID         int64
+	Name           string
+	NormalizedName string
+	CreatedAt      time.Time
+}
+
+type CatalogPopularity struct {
+	Catalog   string
+	ItemID    int64
+	UserCount int32
+}
+
 type Certificate struct {
 	ID                    int64
 	UserID                sql.NullInt64
@@ -22,8 +64,28 @@ type Certificate struct {
 }
 
 type Company struct {
-	ID   int64
-	Name string
+	ID             int64
+	Name           string
+	NormalizedName sql.NullString
+}
+
+type ContentFlag struct {
+	ID         int64
+	UserID     int64
+	TargetType string
+	TargetID   sql.NullInt64
+	Verdict    string
+	Rules      []string
+	Content    string
+	CreatedAt  time.Time
+	ResolvedAt sql.NullTime
+}
+
+type DismissedFollowRecommendation struct {
+	ID              int64
+	UserID          int64
+	DismissedUserID int64
+	CreatedAt       time.Time
 }
 
 type Education struct {
@@ -58,32 +120,97 @@ type Following struct {
 	FollowUserID sql.NullInt64
 }
 
+type HiddenPost struct {
+	ID        int64
+	PostID    int64
+	Reason    string
+	CreatedAt time.Time
+}
+
 type IssuingOrganization struct {
-	ID   int64
-	Name string
+	ID             int64
+	Name           string
+	NormalizedName sql.NullString
 }
 
 type JobPosition struct {
-	ID   int64
-	Name sql.NullString
+	ID             int64
+	Name           sql.NullString
+	NormalizedName sql.NullString
 }
 
 type LikedPost struct {
-	ID     int64
-	UserID sql.NullInt64
-	PostID sql.NullInt64
+	ID           int64
+	UserID       sql.NullInt64
+	PostID       sql.NullInt64
+	ReactionType string
+	CreatedAt    sql.NullTime
 }
 
 type LikedPostComment struct {
 	ID            int64
 	UserID        sql.NullInt64
 	PostCommentID sql.NullInt64
+	ReactionType  string
 }
 
 type LikedPostCommentReply struct {
 	ID                 int64
 	UserID             sql.NullInt64
 	PostCommentReplyID sql.NullInt64
+	ReactionType       string
+}
+
+type LinkPreview struct {
+	ID          int64
+	Url         string
+	Title       sql.NullString
+	Description sql.NullString
+	ImageUrl    sql.NullString
+	SiteName    sql.NullString
+	FetchedAt   time.Time
+}
+
+type ModerationAction struct {
+	ID           int64
+	ModeratorID  sql.NullInt64
+	PostID       sql.NullInt64
+	TargetUserID sql.NullInt64
+	Action       string
+	Note         sql.NullString
+	CreatedAt    time.Time
+}
+
+type Permission struct {
+	ID          int64
+	Name        string
+	Description sql.NullString
+	CreatedAt   time.Time
+}
+
+type Poll struct {
+	ID            int64
+	PostID        int64
+	AllowMultiple bool
+	VoterCount    int32
+	EndsAt        time.Time
+	CreatedAt     time.Time
+}
+
+type PollOption struct {
+	ID         int64
+	PollID     int64
+	OptionText string
+	Position   int16
+	VoteCount  int32
+}
+
+type PollVote struct {
+	ID           int64
+	PollID       int64
+	PollOptionID int64
+	UserID       int64
+	CreatedAt    time.Time
 }
 
 type Post struct {
@@ -97,6 +224,7 @@ type Post struct {
 	UpdatedAt    sql.NullTime
 	Title        string
 	Visibility   string
+	SearchVector interface{}
 }
 
 type PostComment struct {
@@ -124,6 +252,11 @@ type PostCommentReply struct {
 	UpdatedAt     sql.NullTime
 }
 
+type PostHashtag struct {
+	PostID  int64
+	Hashtag string
+}
+
 type PostImage struct {
 	ID     int64
 	PostID sql.NullInt64
@@ -131,28 +264,112 @@ type PostImage struct {
 	Index  sql.NullInt16
 }
 
-type ReportedPost struct {
-	ID      int64
-	UserID  sql.NullInt64
-	PostID  sql.NullInt64
-	Reason  sql.NullString
-	Message sql.NullString
+type PostImpression struct {
+	UserID      int64
+	PostID      int64
+	ViewCount   int32
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+type PostLinkPreview struct {
+	ID            int64
+	PostID        int64
+	LinkPreviewID int64
+}
+
+type PostReactionCount struct {
+	ID           int64
+	PostID       int64
+	ReactionType string
+	Count        int32
+}
+
+type Report struct {
+	ID         int64
+	UserID     int64
+	TargetType string
+	TargetID   int64
+	Reasons    []string
+	Message    sql.NullString
+	CreatedAt  time.Time
+	ResolvedAt sql.NullTime
 }
 
 type RepostedPost struct {
-	ID     int64
-	UserID sql.NullInt64
-	PostID sql.NullInt64
+	ID        int64
+	UserID    sql.NullInt64
+	PostID    sql.NullInt64
+	CreatedAt sql.NullTime
+}
+
+type Role struct {
+	ID          int64
+	Name        string
+	Description sql.NullString
+	CreatedAt   time.Time
+}
+
+type RolePermission struct {
+	ID           int64
+	RoleID       int64
+	PermissionID int64
+}
+
+type SavedTalentSearch struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	Filters   json.RawMessage
+	CreatedAt time.Time
 }
 
 type School struct {
-	ID   int64
-	Name string
+	ID             int64
+	Name           string
+	NormalizedName sql.NullString
 }
 
 type Skill struct {
-	ID   int64
-	Name string
+	ID             int64
+	Name           string
+	ParentID       sql.NullInt64
+	NormalizedName sql.NullString
+}
+
+type TimelineEntry struct {
+	UserID    int64
+	PostID    int64
+	AuthorID  int64
+	CreatedAt time.Time
+}
+
+type TimelinePullPost struct {
+	PostID    int64
+	AuthorID  int64
+	CreatedAt time.Time
+}
+
+type TrendingPost struct {
+	TimeWindow   string
+	PostID       int64
+	LikeCount    int32
+	CommentCount int32
+	RepostCount  int32
+	Score        float64
+	ComputedAt   time.Time
+}
+
+type TrendingTopic struct {
+	TimeWindow   string
+	TopicType    string
+	Name         string
+	PostCount    int32
+	LikeCount    int32
+	CommentCount int32
+	RepostCount  int32
+	Score        float64
+	ComputedAt   time.Time
 }
 
 type User struct {
@@ -171,6 +388,23 @@ type User struct {
 	FollowingsCount sql.NullInt32
 }
 
+type UserAccountState struct {
+	ID        int64
+	UserID    int64
+	State     string
+	Reason    sql.NullString
+	ExpiresAt sql.NullTime
+	UpdatedAt time.Time
+	UpdatedBy sql.NullInt64
+}
+
+type UserBlock struct {
+	ID            int64
+	UserID        int64
+	BlockedUserID int64
+	CreatedAt     time.Time
+}
+
 type UserDetail struct {
 	ID              int64
 	UserID          int64
@@ -202,12 +436,26 @@ type UserLocationTypeInterest struct {
 	LocationType sql.NullString
 }
 
+type UserMute struct {
+	ID          int64
+	UserID      int64
+	MutedUserID int64
+	CreatedAt   time.Time
+}
+
 type UserOtp struct {
 	ID     int64
 	UserID sql.NullInt64
 	Otp    sql.NullString
 }
 
+type UserRole struct {
+	ID        int64
+	UserID    int64
+	Role      string
+	CreatedAt time.Time
+}
+
 type UserSkill struct {
 	ID        int64
 	UserID    sql.NullInt64
@@ -222,6 +470,15 @@ type UserSocialLink struct {
 	Platform sql.NullString
 }
 
+type UserWarning struct {
+	ID          int64
+	UserID      int64
+	ModeratorID int64
+	PostID      sql.NullInt64
+	Message     string
+	CreatedAt   time.Time
+}
+
 type WorkExperience struct {
 	ID             int64
 	UserID         sql.NullInt64
@@ -8,10 +8,21 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/lib/pq"
 )
 
+const batchDeleteBookmarkedPostsByPost = `-- name: BatchDeleteBookmarkedPostsByPost :exec
+DELETE FROM bookmarked_posts
+WHERE post_id = $1::bigint
+`
+
+func (q *Queries) BatchDeleteBookmarkedPostsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeleteBookmarkedPostsByPost, postID)
+	return err
+}
+
 const batchDeleteLikedPostByPost = `-- name: BatchDeleteLikedPostByPost :exec
 DELETE FROM liked_posts
 WHERE post_id = $1::bigint
@@ -22,6 +33,96 @@ func (q *Queries) BatchDeleteLikedPostByPost(ctx context.Context, postID int64)
 	return err
 }
 
+const batchDeleteLikedPostCommentRepliesByComment = `-- name: BatchDeleteLikedPostCommentRepliesByComment :exec
+DELETE FROM liked_post_comment_replies
+WHERE post_comment_reply_id IN (
+    SELECT id FROM post_comment_replies
+    WHERE post_comment_id = $1::bigint
+)
+`
+
+func (q *Queries) BatchDeleteLikedPostCommentRepliesByComment(ctx context.Context, postCommentID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeleteLikedPostCommentRepliesByComment, postCommentID)
+	return err
+}
+
+const batchDeleteLikedPostCommentRepliesByPost = `-- name: BatchDeleteLikedPostCommentRepliesByPost :exec
+DELETE FROM liked_post_comment_replies
+WHERE post_comment_reply_id IN (
+    SELECT pcr.id FROM post_comment_replies pcr
+    JOIN post_comments pc ON pc.id = pcr.post_comment_id
+    WHERE pc.post_id = $1::bigint
+)
+`
+
+func (q *Queries) BatchDeleteLikedPostCommentRepliesByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeleteLikedPostCommentRepliesByPost, postID)
+	return err
+}
+
+const batchDeleteLikedPostCommentRepliesByReply = `-- name: BatchDeleteLikedPostCommentRepliesByReply :exec
+DELETE FROM liked_post_comment_replies
+WHERE post_comment_reply_id = $1::bigint
+`
+
+func (q *Queries) BatchDeleteLikedPostCommentRepliesByReply(ctx context.Context, postCommentReplyID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeleteLikedPostCommentRepliesByReply, postCommentReplyID)
+	return err
+}
+
+const batchDeleteLikedPostCommentsByComment = `-- name: BatchDeleteLikedPostCommentsByComment :exec
+DELETE FROM liked_post_comments
+WHERE post_comment_id = $1::bigint
+`
+
+func (q *Queries) BatchDeleteLikedPostCommentsByComment(ctx context.Context, postCommentID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeleteLikedPostCommentsByComment, postCommentID)
+	return err
+}
+
+const batchDeleteLikedPostCommentsByPost = `-- name: BatchDeleteLikedPostCommentsByPost :exec
+DELETE FROM liked_post_comments
+WHERE post_comment_id IN (
+    SELECT id FROM post_comments
+    WHERE post_id = $1::bigint
+)
+`
+
+func (q *Queries) BatchDeleteLikedPostCommentsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeleteLikedPostCommentsByPost, postID)
+	return err
+}
+
+const batchDeletePollOptionsByPost = `-- name: BatchDeletePollOptionsByPost :exec
+DELETE FROM poll_options
+WHERE poll_id IN (SELECT id FROM polls WHERE post_id = $1::bigint)
+`
+
+func (q *Queries) BatchDeletePollOptionsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeletePollOptionsByPost, postID)
+	return err
+}
+
+const batchDeletePollVotesByPost = `-- name: BatchDeletePollVotesByPost :exec
+DELETE FROM poll_votes
+WHERE poll_id IN (SELECT id FROM polls WHERE post_id = $1::bigint)
+`
+
+func (q *Queries) BatchDeletePollVotesByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeletePollVotesByPost, postID)
+	return err
+}
+
+const batchDeletePostCommentRepliesByComment = `-- name: BatchDeletePostCommentRepliesByComment :exec
+DELETE FROM post_comment_replies
+WHERE post_comment_id = $1::bigint
+`
+
+func (q *Queries) BatchDeletePostCommentRepliesByComment(ctx context.Context, postCommentID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeletePostCommentRepliesByComment, postCommentID)
+	return err
+}
+
 const batchDeletePostCommentRepliesByPost = `-- name: BatchDeletePostCommentRepliesByPost :exec
 WITH post_comments AS (
 	SELECT id 
@@ -47,6 +148,16 @@ func (q *Queries) BatchDeletePostCommentsByPost(ctx context.Context, postID int6
 	return err
 }
 
+const batchDeletePostHashtagsByPost = `-- name: BatchDeletePostHashtagsByPost :exec
+DELETE FROM post_hashtags
+WHERE post_id = $1::bigint
+`
+
+func (q *Queries) BatchDeletePostHashtagsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeletePostHashtagsByPost, postID)
+	return err
+}
+
 const batchDeletePostImagesByPost = `-- name: BatchDeletePostImagesByPost :exec
 DELETE FROM post_images
 WHERE post_id = $1::bigint
@@ -57,13 +168,23 @@ func (q *Queries) BatchDeletePostImagesByPost(ctx context.Context, postID int64)
 	return err
 }
 
-const batchDeleteReportedPostsByPost = `-- name: BatchDeleteReportedPostsByPost :exec
-DELETE FROM reported_posts
+const batchDeletePostLinkPreviewsByPost = `-- name: BatchDeletePostLinkPreviewsByPost :exec
+DELETE FROM post_link_previews
+WHERE post_id = $1::bigint
+`
+
+func (q *Queries) BatchDeletePostLinkPreviewsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeletePostLinkPreviewsByPost, postID)
+	return err
+}
+
+const batchDeletePostReactionCountsByPost = `-- name: BatchDeletePostReactionCountsByPost :exec
+DELETE FROM post_reaction_counts
 WHERE post_id = $1::bigint
 `
 
-func (q *Queries) BatchDeleteReportedPostsByPost(ctx context.Context, postID int64) error {
-	_, err := q.db.ExecContext(ctx, batchDeleteReportedPostsByPost, postID)
+func (q *Queries) BatchDeletePostReactionCountsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, batchDeletePostReactionCountsByPost, postID)
 	return err
 }
 
@@ -77,6 +198,81 @@ func (q *Queries) BatchDeleteRepostedPostByPost(ctx context.Context, postID int6
 	return err
 }
 
+const batchInsertPollOptions = `-- name: BatchInsertPollOptions :many
+INSERT INTO poll_options
+	(poll_id, option_text, position)
+SELECT $1::bigint, UNNEST($2::TEXT[]), UNNEST($3::smallint[])
+RETURNING id, poll_id, option_text, position, vote_count
+`
+
+type BatchInsertPollOptionsParams struct {
+	PollID     int64
+	OptionText []string
+	Position   []int16
+}
+
+func (q *Queries) BatchInsertPollOptions(ctx context.Context, arg BatchInsertPollOptionsParams) ([]PollOption, error) {
+	rows, err := q.db.QueryContext(ctx, batchInsertPollOptions, arg.PollID, pq.Array(arg.OptionText), pq.Array(arg.Position))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PollOption
+	for rows.Next() {
+		var i PollOption
+		if err := rows.Scan(
+			&i.ID,
+			&i.PollID,
+			&i.OptionText,
+			&i.Position,
+			&i.VoteCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const batchInsertPollVotes = `-- name: BatchInsertPollVotes :exec
+INSERT INTO poll_votes
+	(poll_id, poll_option_id, user_id, created_at)
+SELECT $1::bigint, UNNEST($2::bigint[]), $3::bigint, NOW()
+`
+
+type BatchInsertPollVotesParams struct {
+	PollID        int64
+	PollOptionIds []int64
+	UserID        int64
+}
+
+func (q *Queries) BatchInsertPollVotes(ctx context.Context, arg BatchInsertPollVotesParams) error {
+	_, err := q.db.ExecContext(ctx, batchInsertPollVotes, arg.PollID, pq.Array(arg.PollOptionIds), arg.UserID)
+	return err
+}
+
+const batchInsertPostHashtags = `-- name: BatchInsertPostHashtags :exec
+INSERT INTO post_hashtags (post_id, hashtag)
+SELECT $1::bigint, UNNEST($2::varchar(50)[])
+ON CONFLICT DO NOTHING
+`
+
+type BatchInsertPostHashtagsParams struct {
+	PostID   int64
+	Hashtags []string
+}
+
+func (q *Queries) BatchInsertPostHashtags(ctx context.Context, arg BatchInsertPostHashtagsParams) error {
+	_, err := q.db.ExecContext(ctx, batchInsertPostHashtags, arg.PostID, pq.Array(arg.Hashtags))
+	return err
+}
+
 const batchInsertPostImages = `-- name: BatchInsertPostImages :many
 INSERT INTO post_images
 	(post_id, url, index)
@@ -118,6 +314,37 @@ func (q *Queries) BatchInsertPostImages(ctx context.Context, arg BatchInsertPost
 	return items, nil
 }
 
+const countOpenPostReporters = `-- name: CountOpenPostReporters :one
+SELECT COUNT(*) AS count
+FROM reports
+WHERE target_type = 'post' AND target_id = $1::bigint AND resolved_at IS NULL
+`
+
+func (q *Queries) CountOpenPostReporters(ctx context.Context, postID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenPostReporters, postID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPollVotesByUser = `-- name: CountPollVotesByUser :one
+SELECT COUNT(*) AS count
+FROM poll_votes
+WHERE poll_id = $1::bigint AND user_id = $2::bigint
+`
+
+type CountPollVotesByUserParams struct {
+	PollID int64
+	UserID int64
+}
+
+func (q *Queries) CountPollVotesByUser(ctx context.Context, arg CountPollVotesByUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPollVotesByUser, arg.PollID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countPostImages = `-- name: CountPostImages :one
 SELECT COUNT(*) AS count
 FROM post_images
@@ -131,24 +358,138 @@ func (q *Queries) CountPostImages(ctx context.Context, postID int64) (int64, err
 	return count, err
 }
 
-const deleteLikedPost = `-- name: DeleteLikedPost :one
-DELETE FROM liked_posts
+const countUserBlocksForPost = `-- name: CountUserBlocksForPost :one
+SELECT COUNT(*) AS count
+FROM posts p
+JOIN user_blocks ub ON (ub.user_id = $1::bigint AND ub.blocked_user_id = p.user_id)
+    OR (ub.user_id = p.user_id AND ub.blocked_user_id = $1::bigint)
+WHERE p.id = $2::bigint
+`
+
+type CountUserBlocksForPostParams struct {
+	UserID int64
+	PostID int64
+}
+
+func (q *Queries) CountUserBlocksForPost(ctx context.Context, arg CountUserBlocksForPostParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserBlocksForPost, arg.UserID, arg.PostID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserBlocksForPostComment = `-- name: CountUserBlocksForPostComment :one
+SELECT COUNT(*) AS count
+FROM post_comments pc
+JOIN posts p ON pc.post_id = p.id
+JOIN user_blocks ub ON (ub.user_id = $1::bigint AND ub.blocked_user_id IN (pc.user_id, p.user_id))
+    OR (ub.user_id IN (pc.user_id, p.user_id) AND ub.blocked_user_id = $1::bigint)
+WHERE pc.id = $2::bigint
+`
+
+type CountUserBlocksForPostCommentParams struct {
+	UserID        int64
+	PostCommentID int64
+}
+
+func (q *Queries) CountUserBlocksForPostComment(ctx context.Context, arg CountUserBlocksForPostCommentParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserBlocksForPostComment, arg.UserID, arg.PostCommentID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserBlocksForPostCommentReply = `-- name: CountUserBlocksForPostCommentReply :one
+SELECT COUNT(*) AS count
+FROM post_comment_replies pcr
+JOIN post_comments pc ON pcr.post_comment_id = pc.id
+JOIN posts p ON pc.post_id = p.id
+JOIN user_blocks ub ON (ub.user_id = $1::bigint AND ub.blocked_user_id IN (pcr.user_id, p.user_id))
+    OR (ub.user_id IN (pcr.user_id, p.user_id) AND ub.blocked_user_id = $1::bigint)
+WHERE pcr.id = $2::bigint
+`
+
+type CountUserBlocksForPostCommentReplyParams struct {
+	UserID             int64
+	PostCommentReplyID int64
+}
+
+func (q *Queries) CountUserBlocksForPostCommentReply(ctx context.Context, arg CountUserBlocksForPostCommentReplyParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserBlocksForPostCommentReply, arg.UserID, arg.PostCommentReplyID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteBookmarkFolder = `-- name: DeleteBookmarkFolder :one
+DELETE FROM bookmark_folders
+WHERE id = $1::bigint AND user_id = $2::bigint
+RETURNING id
+`
+
+type DeleteBookmarkFolderParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeleteBookmarkFolder(ctx context.Context, arg DeleteBookmarkFolderParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, deleteBookmarkFolder, arg.ID, arg.UserID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteBookmarkedPost = `-- name: DeleteBookmarkedPost :one
+DELETE FROM bookmarked_posts
 WHERE user_id = $1::bigint AND post_id = $2::bigint
 RETURNING id
 `
 
-type DeleteLikedPostParams struct {
+type DeleteBookmarkedPostParams struct {
 	UserID int64
 	PostID int64
 }
 
-func (q *Queries) DeleteLikedPost(ctx context.Context, arg DeleteLikedPostParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, deleteLikedPost, arg.UserID, arg.PostID)
+func (q *Queries) DeleteBookmarkedPost(ctx context.Context, arg DeleteBookmarkedPostParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, deleteBookmarkedPost, arg.UserID, arg.PostID)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const deleteHiddenPostByPost = `-- name: DeleteHiddenPostByPost :exec
+DELETE FROM hidden_posts
+WHERE post_id = $1::bigint
+`
+
+func (q *Queries) DeleteHiddenPostByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteHiddenPostByPost, postID)
+	return err
+}
+
+const deleteLikedPost = `-- name: DeleteLikedPost :one
+DELETE FROM liked_posts
+WHERE user_id = $1::bigint AND post_id = $2::bigint
+RETURNING id, reaction_type
+`
+
+type DeleteLikedPostParams struct {
+	UserID int64
+	PostID int64
+}
+
+type DeleteLikedPostRow struct {
+	ID           int64
+	ReactionType string
+}
+
+func (q *Queries) DeleteLikedPost(ctx context.Context, arg DeleteLikedPostParams) (DeleteLikedPostRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteLikedPost, arg.UserID, arg.PostID)
+	var i DeleteLikedPostRow
+	err := row.Scan(&i.ID, &i.ReactionType)
+	return i, err
+}
+
 const deleteLikedPostComment = `-- name: DeleteLikedPostComment :one
 DELETE FROM liked_post_comments
 WHERE user_id = $1::bigint AND post_comment_id = $2::bigint
@@ -185,6 +526,16 @@ func (q *Queries) DeleteLikedPostCommentReplies(ctx context.Context, arg DeleteL
 	return id, err
 }
 
+const deletePollByPost = `-- name: DeletePollByPost :exec
+DELETE FROM polls
+WHERE post_id = $1::bigint
+`
+
+func (q *Queries) DeletePollByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePollByPost, postID)
+	return err
+}
+
 const deletePostById = `-- name: DeletePostById :exec
 DELETE FROM posts
 WHERE id = $1::bigint
@@ -195,6 +546,26 @@ func (q *Queries) DeletePostById(ctx context.Context, id int64) error {
 	return err
 }
 
+const deletePostCommentById = `-- name: DeletePostCommentById :exec
+DELETE FROM post_comments
+WHERE id = $1::bigint
+`
+
+func (q *Queries) DeletePostCommentById(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePostCommentById, id)
+	return err
+}
+
+const deletePostCommentReplyById = `-- name: DeletePostCommentReplyById :exec
+DELETE FROM post_comment_replies
+WHERE id = $1::bigint
+`
+
+func (q *Queries) DeletePostCommentReplyById(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePostCommentReplyById, id)
+	return err
+}
+
 const deleteRepostedPost = `-- name: DeleteRepostedPost :one
 DELETE FROM reposted_posts
 WHERE user_id = $1::bigint AND post_id = $2::bigint
@@ -213,6 +584,44 @@ func (q *Queries) DeleteRepostedPost(ctx context.Context, arg DeleteRepostedPost
 	return id, err
 }
 
+const fanOutPost = `-- name: FanOutPost :exec
+INSERT INTO timeline_entries
+(user_id, post_id, author_id, created_at)
+SELECT f.user_id, p.id, p.user_id, COALESCE(p.created_at, NOW())
+FROM posts p
+JOIN followings f ON f.follow_user_id = p.user_id
+WHERE p.id = $1::bigint
+ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) FanOutPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, fanOutPost, postID)
+	return err
+}
+
+const getBookmarkFolderById = `-- name: GetBookmarkFolderById :one
+SELECT id, user_id, name, created_at FROM bookmark_folders
+WHERE id = $1::bigint AND user_id = $2::bigint
+LIMIT 1
+`
+
+type GetBookmarkFolderByIdParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) GetBookmarkFolderById(ctx context.Context, arg GetBookmarkFolderByIdParams) (BookmarkFolder, error) {
+	row := q.db.QueryRowContext(ctx, getBookmarkFolderById, arg.ID, arg.UserID)
+	var i BookmarkFolder
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getDetailPost = `-- name: GetDetailPost :one
 SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
     pu.id, pu.avatar_url, pu.full_name, pu.bio, pu.open_to_work,
@@ -224,15 +633,20 @@ SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 JOIN users pu ON p.user_id = pu.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $2
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $2
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $2
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE p.id = $1
+WHERE p.id = $1 AND visible_post_for($2, p.id, p.user_id)
 GROUP BY 
-    p.id, pu.id, lp.user_id, rpp.user_id
+    p.id, pu.id, lp.user_id, rpp.user_id, bp.user_id
 `
 
 type GetDetailPostParams struct {
@@ -259,6 +673,7 @@ type GetDetailPostRow struct {
 	ImageUrls    interface{}
 	Liked        bool
 	Repost       bool
+	Bookmarked   bool
 }
 
 func (q *Queries) GetDetailPost(ctx context.Context, arg GetDetailPostParams) (GetDetailPostRow, error) {
@@ -283,27 +698,82 @@ func (q *Queries) GetDetailPost(ctx context.Context, arg GetDetailPostParams) (G
 		&i.ImageUrls,
 		&i.Liked,
 		&i.Repost,
+		&i.Bookmarked,
 	)
 	return i, err
 }
 
-const getPostById = `-- name: GetPostById :one
-SELECT id, user_id, content, like_count, comment_count, repost_count, created_at, updated_at, title, visibility FROM posts
-WHERE id = $1
+const getLinkPreviewByUrl = `-- name: GetLinkPreviewByUrl :one
+SELECT id, url, title, description, image_url, site_name, fetched_at FROM link_previews
+WHERE url = $1::text
 LIMIT 1
 `
 
-func (q *Queries) GetPostById(ctx context.Context, id int64) (Post, error) {
-	row := q.db.QueryRowContext(ctx, getPostById, id)
-	var i Post
+func (q *Queries) GetLinkPreviewByUrl(ctx context.Context, url string) (LinkPreview, error) {
+	row := q.db.QueryRowContext(ctx, getLinkPreviewByUrl, url)
+	var i LinkPreview
 	err := row.Scan(
 		&i.ID,
-		&i.UserID,
-		&i.Content,
-		&i.LikeCount,
-		&i.CommentCount,
-		&i.RepostCount,
-		&i.CreatedAt,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.ImageUrl,
+		&i.SiteName,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const getPollByPostForUpdate = `-- name: GetPollByPostForUpdate :one
+SELECT id, post_id, allow_multiple, voter_count, ends_at, created_at FROM polls
+WHERE post_id = $1::bigint
+FOR UPDATE
+`
+
+func (q *Queries) GetPollByPostForUpdate(ctx context.Context, postID int64) (Poll, error) {
+	row := q.db.QueryRowContext(ctx, getPollByPostForUpdate, postID)
+	var i Poll
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.AllowMultiple,
+		&i.VoterCount,
+		&i.EndsAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPostById = `-- name: GetPostById :one
+SELECT id, user_id, content, like_count, comment_count, repost_count, created_at, updated_at, title, visibility FROM posts
+WHERE id = $1
+LIMIT 1
+`
+
+type GetPostByIdRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+}
+
+func (q *Queries) GetPostById(ctx context.Context, id int64) (GetPostByIdRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostById, id)
+	var i GetPostByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Content,
+		&i.LikeCount,
+		&i.CommentCount,
+		&i.RepostCount,
+		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Title,
 		&i.Visibility,
@@ -311,6 +781,81 @@ func (q *Queries) GetPostById(ctx context.Context, id int64) (Post, error) {
 	return i, err
 }
 
+const getPostCommentById = `-- name: GetPostCommentById :one
+SELECT pc.id, pc.user_id, pc.post_id, pc.content, pc.image_url, pc.like_count, pc.reply_count, pc.is_post_author, pc.created_at, pc.updated_at, p.user_id AS post_user_id
+FROM post_comments pc
+LEFT JOIN posts p ON p.id = pc.post_id
+WHERE pc.id = $1
+`
+
+type GetPostCommentByIdRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	PostID       sql.NullInt64
+	Content      sql.NullString
+	ImageUrl     sql.NullString
+	LikeCount    sql.NullInt32
+	ReplyCount   sql.NullInt32
+	IsPostAuthor sql.NullBool
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	PostUserID   sql.NullInt64
+}
+
+func (q *Queries) GetPostCommentById(ctx context.Context, id int64) (GetPostCommentByIdRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostCommentById, id)
+	var i GetPostCommentByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostID,
+		&i.Content,
+		&i.ImageUrl,
+		&i.LikeCount,
+		&i.ReplyCount,
+		&i.IsPostAuthor,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostUserID,
+	)
+	return i, err
+}
+
+const getPostCommentLikeCount = `-- name: GetPostCommentLikeCount :one
+SELECT id, like_count FROM post_comments
+WHERE id = $1::bigint
+`
+
+type GetPostCommentLikeCountRow struct {
+	ID        int64
+	LikeCount sql.NullInt32
+}
+
+func (q *Queries) GetPostCommentLikeCount(ctx context.Context, id int64) (GetPostCommentLikeCountRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostCommentLikeCount, id)
+	var i GetPostCommentLikeCountRow
+	err := row.Scan(&i.ID, &i.LikeCount)
+	return i, err
+}
+
+const getPostCommentReactionByUser = `-- name: GetPostCommentReactionByUser :one
+SELECT reaction_type FROM liked_post_comments
+WHERE user_id = $1::bigint AND post_comment_id = $2::bigint
+LIMIT 1
+`
+
+type GetPostCommentReactionByUserParams struct {
+	UserID        int64
+	PostCommentID int64
+}
+
+func (q *Queries) GetPostCommentReactionByUser(ctx context.Context, arg GetPostCommentReactionByUserParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getPostCommentReactionByUser, arg.UserID, arg.PostCommentID)
+	var reaction_type string
+	err := row.Scan(&reaction_type)
+	return reaction_type, err
+}
+
 const getPostCommentReplies = `-- name: GetPostCommentReplies :many
 SELECT pcr.id, pcr.user_id, pcr.post_comment_id, pcr.content, pcr.image_url, pcr.like_count, pcr.is_post_author, pcr.created_at, pcr.updated_at, 
     pcr_user.id, pcr_user.avatar_url, pcr_user.full_name, pcr_user.bio, pcr_user.open_to_work,
@@ -319,6 +864,11 @@ FROM post_comment_replies pcr
 LEFT JOIN users pcr_user ON pcr.user_id = pcr_user.id
 LEFT JOIN post_comments pc ON pc.id = pcr.post_comment_id
 WHERE pc.post_id = $1 AND pcr.post_comment_id = $2
+    AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.target_type = 'post_comment_reply' AND r.target_id = pcr.id AND r.user_id = $5)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $5 AND ub.blocked_user_id = pcr.user_id) OR (ub.user_id = pcr.user_id AND ub.blocked_user_id = $5))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = pcr.user_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    AND (pcr.user_id = $5 OR NOT EXISTS (SELECT 1 FROM content_flags cf WHERE cf.target_type = 'post_comment_reply' AND cf.target_id = pcr.id AND cf.verdict = 'hold' AND cf.resolved_at IS NULL))
+    AND (pcr.user_id = $5 OR NOT EXISTS (SELECT 1 FROM hidden_post_comment_replies hpcr WHERE hpcr.post_comment_reply_id = pcr.id))
 ORDER BY pcr.created_at DESC
 OFFSET $3
 LIMIT $4
@@ -329,6 +879,7 @@ type GetPostCommentRepliesParams struct {
 	PostCommentID sql.NullInt64
 	Offset        int32
 	Limit         int32
+	UserID        int64
 }
 
 type GetPostCommentRepliesRow struct {
@@ -355,6 +906,7 @@ func (q *Queries) GetPostCommentReplies(ctx context.Context, arg GetPostCommentR
 		arg.PostCommentID,
 		arg.Offset,
 		arg.Limit,
+		arg.UserID,
 	)
 	if err != nil {
 		return nil, err
@@ -393,6 +945,110 @@ func (q *Queries) GetPostCommentReplies(ctx context.Context, arg GetPostCommentR
 	return items, nil
 }
 
+const getPostCommentReplyById = `-- name: GetPostCommentReplyById :one
+SELECT pcr.id, pcr.user_id, pcr.post_comment_id, pcr.content, pcr.image_url, pcr.like_count, pcr.is_post_author, pcr.created_at, pcr.updated_at, pc.post_id, p.user_id AS post_user_id
+FROM post_comment_replies pcr
+LEFT JOIN post_comments pc ON pc.id = pcr.post_comment_id
+LEFT JOIN posts p ON p.id = pc.post_id
+WHERE pcr.id = $1
+`
+
+type GetPostCommentReplyByIdRow struct {
+	ID            int64
+	UserID        sql.NullInt64
+	PostCommentID sql.NullInt64
+	Content       sql.NullString
+	ImageUrl      sql.NullString
+	LikeCount     sql.NullInt32
+	IsPostAuthor  sql.NullBool
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+	PostID        sql.NullInt64
+	PostUserID    sql.NullInt64
+}
+
+func (q *Queries) GetPostCommentReplyById(ctx context.Context, id int64) (GetPostCommentReplyByIdRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostCommentReplyById, id)
+	var i GetPostCommentReplyByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostCommentID,
+		&i.Content,
+		&i.ImageUrl,
+		&i.LikeCount,
+		&i.IsPostAuthor,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PostID,
+		&i.PostUserID,
+	)
+	return i, err
+}
+
+const getPostCommentReplyImageUrlsByComment = `-- name: GetPostCommentReplyImageUrlsByComment :many
+SELECT image_url FROM post_comment_replies
+WHERE post_comment_id = $1::bigint
+`
+
+func (q *Queries) GetPostCommentReplyImageUrlsByComment(ctx context.Context, postCommentID int64) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, getPostCommentReplyImageUrlsByComment, postCommentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var image_url sql.NullString
+		if err := rows.Scan(&image_url); err != nil {
+			return nil, err
+		}
+		items = append(items, image_url)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostCommentReplyLikeCount = `-- name: GetPostCommentReplyLikeCount :one
+SELECT id, like_count FROM post_comment_replies
+WHERE id = $1::bigint
+`
+
+type GetPostCommentReplyLikeCountRow struct {
+	ID        int64
+	LikeCount sql.NullInt32
+}
+
+func (q *Queries) GetPostCommentReplyLikeCount(ctx context.Context, id int64) (GetPostCommentReplyLikeCountRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostCommentReplyLikeCount, id)
+	var i GetPostCommentReplyLikeCountRow
+	err := row.Scan(&i.ID, &i.LikeCount)
+	return i, err
+}
+
+const getPostCommentReplyReactionByUser = `-- name: GetPostCommentReplyReactionByUser :one
+SELECT reaction_type FROM liked_post_comment_replies
+WHERE user_id = $1::bigint AND post_comment_reply_id = $2::bigint
+LIMIT 1
+`
+
+type GetPostCommentReplyReactionByUserParams struct {
+	UserID             int64
+	PostCommentReplyID int64
+}
+
+func (q *Queries) GetPostCommentReplyReactionByUser(ctx context.Context, arg GetPostCommentReplyReactionByUserParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getPostCommentReplyReactionByUser, arg.UserID, arg.PostCommentReplyID)
+	var reaction_type string
+	err := row.Scan(&reaction_type)
+	return reaction_type, err
+}
+
 const getPostComments = `-- name: GetPostComments :many
 SELECT pc.id, pc.user_id, pc.post_id, pc.content, pc.image_url, pc.like_count, pc.reply_count, pc.is_post_author, pc.created_at, pc.updated_at,
     pcu.id, pcu.avatar_url, pcu.full_name, pcu.bio, pcu.open_to_work,
@@ -400,6 +1056,11 @@ SELECT pc.id, pc.user_id, pc.post_id, pc.content, pc.image_url, pc.like_count, p
 FROM post_comments pc 
 LEFT JOIN users pcu ON pc.user_id = pcu.id
 WHERE pc.post_id = $1
+    AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.target_type = 'post_comment' AND r.target_id = pc.id AND r.user_id = $4)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $4 AND ub.blocked_user_id = pc.user_id) OR (ub.user_id = pc.user_id AND ub.blocked_user_id = $4))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = pc.user_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    AND (pc.user_id = $4 OR NOT EXISTS (SELECT 1 FROM content_flags cf WHERE cf.target_type = 'post_comment' AND cf.target_id = pc.id AND cf.verdict = 'hold' AND cf.resolved_at IS NULL))
+    AND (pc.user_id = $4 OR NOT EXISTS (SELECT 1 FROM hidden_post_comments hpc WHERE hpc.post_comment_id = pc.id))
 ORDER BY pc.created_at DESC
 OFFSET $2
 LIMIT $3
@@ -409,6 +1070,7 @@ type GetPostCommentsParams struct {
 	PostID sql.NullInt64
 	Offset int32
 	Limit  int32
+	UserID int64
 }
 
 type GetPostCommentsRow struct {
@@ -431,7 +1093,12 @@ type GetPostCommentsRow struct {
 }
 
 func (q *Queries) GetPostComments(ctx context.Context, arg GetPostCommentsParams) ([]GetPostCommentsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostComments, arg.PostID, arg.Offset, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostComments,
+		arg.PostID,
+		arg.Offset,
+		arg.Limit,
+		arg.UserID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -470,6 +1137,93 @@ func (q *Queries) GetPostComments(ctx context.Context, arg GetPostCommentsParams
 	return items, nil
 }
 
+const getPostCommentsByCursor = `-- name: GetPostCommentsByCursor :many
+SELECT pc.id, pc.user_id, pc.post_id, pc.content, pc.image_url, pc.like_count, pc.reply_count, pc.is_post_author, pc.created_at, pc.updated_at,
+    pcu.id, pcu.avatar_url, pcu.full_name, pcu.bio, pcu.open_to_work
+FROM post_comments pc 
+LEFT JOIN users pcu ON pc.user_id = pcu.id
+WHERE pc.post_id = $1
+    AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.target_type = 'post_comment' AND r.target_id = pc.id AND r.user_id = $2)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $2 AND ub.blocked_user_id = pc.user_id) OR (ub.user_id = pc.user_id AND ub.blocked_user_id = $2))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = pc.user_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    AND (pc.user_id = $2 OR NOT EXISTS (SELECT 1 FROM content_flags cf WHERE cf.target_type = 'post_comment' AND cf.target_id = pc.id AND cf.verdict = 'hold' AND cf.resolved_at IS NULL))
+    AND (pc.user_id = $2 OR NOT EXISTS (SELECT 1 FROM hidden_post_comments hpc WHERE hpc.post_comment_id = pc.id))
+    AND ($4::bigint = 0 OR (pc.created_at, pc.id) < ($5::timestamp, $4::bigint))
+ORDER BY pc.created_at DESC, pc.id DESC
+LIMIT $3
+`
+
+type GetPostCommentsByCursorParams struct {
+	PostID          sql.NullInt64
+	UserID          int64
+	Limit           int32
+	CursorID        int64
+	CursorCreatedAt time.Time
+}
+
+type GetPostCommentsByCursorRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	PostID       sql.NullInt64
+	Content      sql.NullString
+	ImageUrl     sql.NullString
+	LikeCount    sql.NullInt32
+	ReplyCount   sql.NullInt32
+	IsPostAuthor sql.NullBool
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	ID_2         sql.NullInt64
+	AvatarUrl    sql.NullString
+	FullName     sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+}
+
+func (q *Queries) GetPostCommentsByCursor(ctx context.Context, arg GetPostCommentsByCursorParams) ([]GetPostCommentsByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostCommentsByCursor,
+		arg.PostID,
+		arg.UserID,
+		arg.Limit,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostCommentsByCursorRow
+	for rows.Next() {
+		var i GetPostCommentsByCursorRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PostID,
+			&i.Content,
+			&i.ImageUrl,
+			&i.LikeCount,
+			&i.ReplyCount,
+			&i.IsPostAuthor,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ID_2,
+			&i.AvatarUrl,
+			&i.FullName,
+			&i.Bio,
+			&i.OpenToWork,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPostImagesUrl = `-- name: GetPostImagesUrl :many
 SELECT url FROM post_images
 WHERE post_id = $1::bigint
@@ -498,28 +1252,211 @@ func (q *Queries) GetPostImagesUrl(ctx context.Context, postID int64) ([]sql.Nul
 	return items, nil
 }
 
-const insertLikedPost = `-- name: InsertLikedPost :one
-INSERT INTO liked_posts (user_id, post_id)
-VALUES ($1::bigint, $2::bigint)
-ON CONFLICT (user_id, post_id) DO NOTHING
-RETURNING id
+const getPostLikeCount = `-- name: GetPostLikeCount :one
+SELECT id, like_count FROM posts
+WHERE id = $1::bigint
 `
 
-type InsertLikedPostParams struct {
-	UserID int64
-	PostID int64
+type GetPostLikeCountRow struct {
+	ID        int64
+	LikeCount sql.NullInt32
 }
 
-func (q *Queries) InsertLikedPost(ctx context.Context, arg InsertLikedPostParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertLikedPost, arg.UserID, arg.PostID)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+func (q *Queries) GetPostLikeCount(ctx context.Context, id int64) (GetPostLikeCountRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostLikeCount, id)
+	var i GetPostLikeCountRow
+	err := row.Scan(&i.ID, &i.LikeCount)
+	return i, err
 }
 
-const insertLikedPostCommentReplies = `-- name: InsertLikedPostCommentReplies :one
-INSERT INTO liked_post_comment_replies (user_id, post_comment_reply_id)
-VALUES ($1::bigint, $2::bigint)
+const getPostReactionByUser = `-- name: GetPostReactionByUser :one
+SELECT reaction_type FROM liked_posts
+WHERE user_id = $1::bigint AND post_id = $2::bigint
+LIMIT 1
+`
+
+type GetPostReactionByUserParams struct {
+	UserID int64
+	PostID int64
+}
+
+func (q *Queries) GetPostReactionByUser(ctx context.Context, arg GetPostReactionByUserParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getPostReactionByUser, arg.UserID, arg.PostID)
+	var reaction_type string
+	err := row.Scan(&reaction_type)
+	return reaction_type, err
+}
+
+const getPostViewCounts = `-- name: GetPostViewCounts :one
+SELECT COALESCE(SUM(view_count), 0)::bigint AS view_count, COUNT(*) AS viewer_count
+FROM post_impressions
+WHERE post_id = $1::bigint
+`
+
+type GetPostViewCountsRow struct {
+	ViewCount   int64
+	ViewerCount int64
+}
+
+func (q *Queries) GetPostViewCounts(ctx context.Context, postID int64) (GetPostViewCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostViewCounts, postID)
+	var i GetPostViewCountsRow
+	err := row.Scan(&i.ViewCount, &i.ViewerCount)
+	return i, err
+}
+
+const getUserFollowersCount = `-- name: GetUserFollowersCount :one
+SELECT COALESCE(followers_count, 0)::int FROM users
+WHERE id = $1::bigint
+`
+
+func (q *Queries) GetUserFollowersCount(ctx context.Context, userID int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getUserFollowersCount, userID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const incrementPollOptionVoteCounts = `-- name: IncrementPollOptionVoteCounts :exec
+UPDATE poll_options
+SET vote_count = vote_count + 1
+WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) IncrementPollOptionVoteCounts(ctx context.Context, ids []int64) error {
+	_, err := q.db.ExecContext(ctx, incrementPollOptionVoteCounts, pq.Array(ids))
+	return err
+}
+
+const incrementPollVoterCount = `-- name: IncrementPollVoterCount :exec
+UPDATE polls
+SET voter_count = voter_count + 1
+WHERE id = $1::bigint
+`
+
+func (q *Queries) IncrementPollVoterCount(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, incrementPollVoterCount, id)
+	return err
+}
+
+const insertBookmarkFolder = `-- name: InsertBookmarkFolder :one
+INSERT INTO bookmark_folders (user_id, name, created_at)
+VALUES ($1::bigint, $2::text, NOW())
+ON CONFLICT (user_id, name) DO NOTHING
+RETURNING id, user_id, name, created_at
+`
+
+type InsertBookmarkFolderParams struct {
+	UserID int64
+	Name   string
+}
+
+func (q *Queries) InsertBookmarkFolder(ctx context.Context, arg InsertBookmarkFolderParams) (BookmarkFolder, error) {
+	row := q.db.QueryRowContext(ctx, insertBookmarkFolder, arg.UserID, arg.Name)
+	var i BookmarkFolder
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertBookmarkedPost = `-- name: InsertBookmarkedPost :one
+INSERT INTO bookmarked_posts (user_id, post_id, bookmark_folder_id, created_at)
+VALUES ($1::bigint, $2::bigint, $3, NOW())
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET bookmark_folder_id = EXCLUDED.bookmark_folder_id
+RETURNING id
+`
+
+type InsertBookmarkedPostParams struct {
+	UserID           int64
+	PostID           int64
+	BookmarkFolderID sql.NullInt64
+}
+
+func (q *Queries) InsertBookmarkedPost(ctx context.Context, arg InsertBookmarkedPostParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertBookmarkedPost, arg.UserID, arg.PostID, arg.BookmarkFolderID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertContentFlag = `-- name: InsertContentFlag :exec
+INSERT INTO content_flags (user_id, target_type, target_id, verdict, rules, content, created_at)
+VALUES ($1::bigint, $2::text, NULLIF($3::bigint, 0), $4::text, $5::text[], $6::text, NOW())
+`
+
+type InsertContentFlagParams struct {
+	UserID     int64
+	TargetType string
+	TargetID   int64
+	Verdict    string
+	Rules      []string
+	Content    string
+}
+
+func (q *Queries) InsertContentFlag(ctx context.Context, arg InsertContentFlagParams) error {
+	_, err := q.db.ExecContext(ctx, insertContentFlag,
+		arg.UserID,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Verdict,
+		pq.Array(arg.Rules),
+		arg.Content,
+	)
+	return err
+}
+
+const insertHiddenPost = `-- name: InsertHiddenPost :one
+INSERT INTO hidden_posts (post_id, reason, created_at)
+VALUES ($1::bigint, $2::text, NOW())
+ON CONFLICT (post_id, reason) DO NOTHING
+RETURNING id, post_id, reason, created_at
+`
+
+type InsertHiddenPostParams struct {
+	PostID int64
+	Reason string
+}
+
+func (q *Queries) InsertHiddenPost(ctx context.Context, arg InsertHiddenPostParams) (HiddenPost, error) {
+	row := q.db.QueryRowContext(ctx, insertHiddenPost, arg.PostID, arg.Reason)
+	var i HiddenPost
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertLikedPost = `-- name: InsertLikedPost :one
+INSERT INTO liked_posts (user_id, post_id, reaction_type, created_at)
+VALUES ($1::bigint, $2::bigint, $3::text, NOW())
+ON CONFLICT (user_id, post_id) DO NOTHING
+RETURNING id
+`
+
+type InsertLikedPostParams struct {
+	UserID       int64
+	PostID       int64
+	ReactionType string
+}
+
+func (q *Queries) InsertLikedPost(ctx context.Context, arg InsertLikedPostParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertLikedPost, arg.UserID, arg.PostID, arg.ReactionType)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertLikedPostCommentReplies = `-- name: InsertLikedPostCommentReplies :one
+INSERT INTO liked_post_comment_replies (user_id, post_comment_reply_id, reaction_type)
+VALUES ($1::bigint, $2::bigint, $3::text)
 ON CONFLICT (user_id, post_comment_reply_id) DO NOTHING
 RETURNING id
 `
@@ -527,18 +1464,19 @@ RETURNING id
 type InsertLikedPostCommentRepliesParams struct {
 	UserID             int64
 	PostCommentReplyID int64
+	ReactionType       string
 }
 
 func (q *Queries) InsertLikedPostCommentReplies(ctx context.Context, arg InsertLikedPostCommentRepliesParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertLikedPostCommentReplies, arg.UserID, arg.PostCommentReplyID)
+	row := q.db.QueryRowContext(ctx, insertLikedPostCommentReplies, arg.UserID, arg.PostCommentReplyID, arg.ReactionType)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
 const insertLikedPostComments = `-- name: InsertLikedPostComments :one
-INSERT INTO liked_post_comments (user_id, post_comment_id)
-VALUES ($1::bigint, $2::bigint)
+INSERT INTO liked_post_comments (user_id, post_comment_id, reaction_type)
+VALUES ($1::bigint, $2::bigint, $3::text)
 ON CONFLICT (user_id, post_comment_id) DO NOTHING
 RETURNING id
 `
@@ -546,15 +1484,42 @@ RETURNING id
 type InsertLikedPostCommentsParams struct {
 	UserID        int64
 	PostCommentID int64
+	ReactionType  string
 }
 
 func (q *Queries) InsertLikedPostComments(ctx context.Context, arg InsertLikedPostCommentsParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertLikedPostComments, arg.UserID, arg.PostCommentID)
+	row := q.db.QueryRowContext(ctx, insertLikedPostComments, arg.UserID, arg.PostCommentID, arg.ReactionType)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const insertPoll = `-- name: InsertPoll :one
+INSERT INTO polls (post_id, allow_multiple, ends_at, created_at)
+VALUES ($1::bigint, $2::boolean, $3::timestamp, NOW())
+RETURNING id, post_id, allow_multiple, voter_count, ends_at, created_at
+`
+
+type InsertPollParams struct {
+	PostID        int64
+	AllowMultiple bool
+	EndsAt        time.Time
+}
+
+func (q *Queries) InsertPoll(ctx context.Context, arg InsertPollParams) (Poll, error) {
+	row := q.db.QueryRowContext(ctx, insertPoll, arg.PostID, arg.AllowMultiple, arg.EndsAt)
+	var i Poll
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.AllowMultiple,
+		&i.VoterCount,
+		&i.EndsAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const insertPost = `-- name: InsertPost :one
 INSERT INTO posts
 (user_id, title, content, visibility, created_at, updated_at)
@@ -569,14 +1534,27 @@ type InsertPostParams struct {
 	Visibility string
 }
 
-func (q *Queries) InsertPost(ctx context.Context, arg InsertPostParams) (Post, error) {
+type InsertPostRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+}
+
+func (q *Queries) InsertPost(ctx context.Context, arg InsertPostParams) (InsertPostRow, error) {
 	row := q.db.QueryRowContext(ctx, insertPost,
 		arg.UserID,
 		arg.Title,
 		arg.Content,
 		arg.Visibility,
 	)
-	var i Post
+	var i InsertPostRow
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
@@ -667,41 +1645,46 @@ func (q *Queries) InsertPostCommentReply(ctx context.Context, arg InsertPostComm
 	return i, err
 }
 
-const insertReportedPost = `-- name: InsertReportedPost :one
-INSERT INTO reported_posts
-(user_id, post_id, reason, message)
-SELECT $1::bigint, $2::bigint, UNNEST($3::varchar(15)[]), $4::text
-RETURNING id, user_id, post_id, reason, message
+const insertReport = `-- name: InsertReport :one
+INSERT INTO reports (user_id, target_type, target_id, reasons, message, created_at)
+VALUES ($1::bigint, $2::text, $3::bigint, $4::varchar(50)[], $5::text, NOW())
+ON CONFLICT (user_id, target_type, target_id) DO NOTHING
+RETURNING id, user_id, target_type, target_id, reasons, message, created_at, resolved_at
 `
 
-type InsertReportedPostParams struct {
-	UserID  int64
-	PostID  int64
-	Reason  []string
-	Message string
+type InsertReportParams struct {
+	UserID     int64
+	TargetType string
+	TargetID   int64
+	Reasons    []string
+	Message    string
 }
 
-func (q *Queries) InsertReportedPost(ctx context.Context, arg InsertReportedPostParams) (ReportedPost, error) {
-	row := q.db.QueryRowContext(ctx, insertReportedPost,
+func (q *Queries) InsertReport(ctx context.Context, arg InsertReportParams) (Report, error) {
+	row := q.db.QueryRowContext(ctx, insertReport,
 		arg.UserID,
-		arg.PostID,
-		pq.Array(arg.Reason),
+		arg.TargetType,
+		arg.TargetID,
+		pq.Array(arg.Reasons),
 		arg.Message,
 	)
-	var i ReportedPost
+	var i Report
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.PostID,
-		&i.Reason,
+		&i.TargetType,
+		&i.TargetID,
+		pq.Array(&i.Reasons),
 		&i.Message,
+		&i.CreatedAt,
+		&i.ResolvedAt,
 	)
 	return i, err
 }
 
 const insertRepostedPost = `-- name: InsertRepostedPost :one
-INSERT INTO reposted_posts (user_id, post_id)
-VALUES ($1::bigint, $2::bigint)
+INSERT INTO reposted_posts (user_id, post_id, created_at)
+VALUES ($1::bigint, $2::bigint, NOW())
 ON CONFLICT (user_id, post_id) DO NOTHING
 RETURNING id
 `
@@ -718,76 +1701,139 @@ func (q *Queries) InsertRepostedPost(ctx context.Context, arg InsertRepostedPost
 	return id, err
 }
 
-const listLikedPostsByTargetUser = `-- name: ListLikedPostsByTargetUser :many
+const insertTimelinePullPost = `-- name: InsertTimelinePullPost :exec
+INSERT INTO timeline_pull_posts
+(post_id, author_id, created_at)
+SELECT id, user_id, COALESCE(created_at, NOW())
+FROM posts
+WHERE id = $1::bigint
+ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) InsertTimelinePullPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, insertTimelinePullPost, postID)
+	return err
+}
+
+const listBookmarkFolders = `-- name: ListBookmarkFolders :many
+SELECT bf.id, bf.user_id, bf.name, bf.created_at, COUNT(bp.id) AS bookmark_count
+FROM bookmark_folders bf
+LEFT JOIN bookmarked_posts bp ON bf.id = bp.bookmark_folder_id
+WHERE bf.user_id = $1::bigint
+GROUP BY bf.id
+ORDER BY bf.name ASC
+`
+
+type ListBookmarkFoldersRow struct {
+	ID            int64
+	UserID        int64
+	Name          string
+	CreatedAt     time.Time
+	BookmarkCount int64
+}
+
+func (q *Queries) ListBookmarkFolders(ctx context.Context, userID int64) ([]ListBookmarkFoldersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookmarkFolders, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookmarkFoldersRow
+	for rows.Next() {
+		var i ListBookmarkFoldersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.BookmarkCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookmarkedPosts = `-- name: ListBookmarkedPosts :many
 SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
     CASE 
-    	WHEN lp2.user_id IS NOT NULL THEN TRUE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
   	END AS liked,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
-FROM posts p
+  	END AS repost,
+	bp.bookmark_folder_id
+FROM bookmarked_posts bp
+JOIN posts p ON bp.post_id = p.id
 LEFT JOIN users u ON p.user_id = u.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $3::bigint
-LEFT JOIN liked_posts lp2 ON p.id = lp2.post_id AND lp2.user_id = $4::bigint
-LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $4::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $3::bigint
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE lp.user_id = $3::bigint
+WHERE bp.user_id = $3::bigint AND visible_post_for($3::bigint, p.id, p.user_id)
+	AND ($4::bigint = 0 OR bp.bookmark_folder_id = $4::bigint)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id, lp2.user_id
-ORDER BY p.created_at DESC
+    p.id, u.id, lp.user_id, rpp.user_id, bp.id
+ORDER BY bp.created_at DESC
 OFFSET $1
 LIMIT $2
 `
 
-type ListLikedPostsByTargetUserParams struct {
-	Offset       int32
-	Limit        int32
-	TargetUserID int64
-	UserID       int64
-}
-
-type ListLikedPostsByTargetUserRow struct {
-	ID           int64
-	UserID       sql.NullInt64
-	Content      sql.NullString
-	LikeCount    sql.NullInt32
-	CommentCount sql.NullInt32
-	RepostCount  sql.NullInt32
-	CreatedAt    sql.NullTime
-	UpdatedAt    sql.NullTime
-	Title        string
-	Visibility   string
-	ID_2         sql.NullInt64
-	FullName     sql.NullString
-	AvatarUrl    sql.NullString
-	Bio          sql.NullString
-	OpenToWork   sql.NullBool
-	ImageUrls    interface{}
-	TotalRows    int64
-	Liked        bool
-	Repost       bool
-}
-
-func (q *Queries) ListLikedPostsByTargetUser(ctx context.Context, arg ListLikedPostsByTargetUserParams) ([]ListLikedPostsByTargetUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, listLikedPostsByTargetUser,
+type ListBookmarkedPostsParams struct {
+	Offset           int32
+	Limit            int32
+	UserID           int64
+	BookmarkFolderID int64
+}
+
+type ListBookmarkedPostsRow struct {
+	ID               int64
+	UserID           sql.NullInt64
+	Content          sql.NullString
+	LikeCount        sql.NullInt32
+	CommentCount     sql.NullInt32
+	RepostCount      sql.NullInt32
+	CreatedAt        sql.NullTime
+	UpdatedAt        sql.NullTime
+	Title            string
+	Visibility       string
+	ID_2             sql.NullInt64
+	FullName         sql.NullString
+	AvatarUrl        sql.NullString
+	Bio              sql.NullString
+	OpenToWork       sql.NullBool
+	ImageUrls        interface{}
+	TotalRows        int64
+	Liked            bool
+	Repost           bool
+	BookmarkFolderID sql.NullInt64
+}
+
+func (q *Queries) ListBookmarkedPosts(ctx context.Context, arg ListBookmarkedPostsParams) ([]ListBookmarkedPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookmarkedPosts,
 		arg.Offset,
 		arg.Limit,
-		arg.TargetUserID,
 		arg.UserID,
+		arg.BookmarkFolderID,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListLikedPostsByTargetUserRow
+	var items []ListBookmarkedPostsRow
 	for rows.Next() {
-		var i ListLikedPostsByTargetUserRow
+		var i ListBookmarkedPostsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -808,6 +1854,7 @@ func (q *Queries) ListLikedPostsByTargetUser(ctx context.Context, arg ListLikedP
 			&i.TotalRows,
 			&i.Liked,
 			&i.Repost,
+			&i.BookmarkFolderID,
 		); err != nil {
 			return nil, err
 		}
@@ -822,40 +1869,46 @@ func (q *Queries) ListLikedPostsByTargetUser(ctx context.Context, arg ListLikedP
 	return items, nil
 }
 
-const listNewestPostsByTargetUser = `-- name: ListNewestPostsByTargetUser :many
+const listLikedPostsByTargetUser = `-- name: ListLikedPostsByTargetUser :many
 SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
     CASE 
-    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	WHEN lp2.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
   	END AS liked,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $3::bigint
-LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $3::bigint
+LEFT JOIN liked_posts lp2 ON p.id = lp2.post_id AND lp2.user_id = $4::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $4::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $4::bigint
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE p.user_id = $4::bigint
+WHERE lp.user_id = $3::bigint AND visible_post_for($4::bigint, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, lp2.user_id, bp.user_id
 ORDER BY p.created_at DESC
 OFFSET $1
 LIMIT $2
 `
 
-type ListNewestPostsByTargetUserParams struct {
+type ListLikedPostsByTargetUserParams struct {
 	Offset       int32
 	Limit        int32
-	UserID       int64
 	TargetUserID int64
+	UserID       int64
 }
 
-type ListNewestPostsByTargetUserRow struct {
+type ListLikedPostsByTargetUserRow struct {
 	ID           int64
 	UserID       sql.NullInt64
 	Content      sql.NullString
@@ -875,22 +1928,23 @@ type ListNewestPostsByTargetUserRow struct {
 	TotalRows    int64
 	Liked        bool
 	Repost       bool
+	Bookmarked   bool
 }
 
-func (q *Queries) ListNewestPostsByTargetUser(ctx context.Context, arg ListNewestPostsByTargetUserParams) ([]ListNewestPostsByTargetUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, listNewestPostsByTargetUser,
+func (q *Queries) ListLikedPostsByTargetUser(ctx context.Context, arg ListLikedPostsByTargetUserParams) ([]ListLikedPostsByTargetUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLikedPostsByTargetUser,
 		arg.Offset,
 		arg.Limit,
-		arg.UserID,
 		arg.TargetUserID,
+		arg.UserID,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListNewestPostsByTargetUserRow
+	var items []ListLikedPostsByTargetUserRow
 	for rows.Next() {
-		var i ListNewestPostsByTargetUserRow
+		var i ListLikedPostsByTargetUserRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -911,6 +1965,7 @@ func (q *Queries) ListNewestPostsByTargetUser(ctx context.Context, arg ListNewes
 			&i.TotalRows,
 			&i.Liked,
 			&i.Repost,
+			&i.Bookmarked,
 		); err != nil {
 			return nil, err
 		}
@@ -925,7 +1980,717 @@ func (q *Queries) ListNewestPostsByTargetUser(ctx context.Context, arg ListNewes
 	return items, nil
 }
 
-const listRepostedPostsByTargetUser = `-- name: ListRepostedPostsByTargetUser :many
+const listLinkPreviewsByPostIds = `-- name: ListLinkPreviewsByPostIds :many
+SELECT plp.post_id, lp.url, lp.title, lp.description, lp.image_url, lp.site_name
+FROM post_link_previews plp
+JOIN link_previews lp ON plp.link_preview_id = lp.id
+WHERE plp.post_id = ANY($1::bigint[])
+`
+
+type ListLinkPreviewsByPostIdsRow struct {
+	PostID      int64
+	Url         string
+	Title       sql.NullString
+	Description sql.NullString
+	ImageUrl    sql.NullString
+	SiteName    sql.NullString
+}
+
+func (q *Queries) ListLinkPreviewsByPostIds(ctx context.Context, postIds []int64) ([]ListLinkPreviewsByPostIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLinkPreviewsByPostIds, pq.Array(postIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLinkPreviewsByPostIdsRow
+	for rows.Next() {
+		var i ListLinkPreviewsByPostIdsRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ImageUrl,
+			&i.SiteName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNewestPostsByTargetUser = `-- name: ListNewestPostsByTargetUser :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+	COUNT(p.id) OVER () AS total_rows,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $3::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $3::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $3::bigint
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE p.user_id = $4::bigint AND visible_post_for($3::bigint, p.id, p.user_id)
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC
+OFFSET $1
+LIMIT $2
+`
+
+type ListNewestPostsByTargetUserParams struct {
+	Offset       int32
+	Limit        int32
+	UserID       int64
+	TargetUserID int64
+}
+
+type ListNewestPostsByTargetUserRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+	ID_2         sql.NullInt64
+	FullName     sql.NullString
+	AvatarUrl    sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+	ImageUrls    interface{}
+	TotalRows    int64
+	Liked        bool
+	Repost       bool
+	Bookmarked   bool
+}
+
+func (q *Queries) ListNewestPostsByTargetUser(ctx context.Context, arg ListNewestPostsByTargetUserParams) ([]ListNewestPostsByTargetUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNewestPostsByTargetUser,
+		arg.Offset,
+		arg.Limit,
+		arg.UserID,
+		arg.TargetUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNewestPostsByTargetUserRow
+	for rows.Next() {
+		var i ListNewestPostsByTargetUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Content,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Visibility,
+			&i.ID_2,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.ImageUrls,
+			&i.TotalRows,
+			&i.Liked,
+			&i.Repost,
+			&i.Bookmarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPollOptionIdsByPoll = `-- name: ListPollOptionIdsByPoll :many
+SELECT id FROM poll_options
+WHERE poll_id = $1::bigint
+`
+
+func (q *Queries) ListPollOptionIdsByPoll(ctx context.Context, pollID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listPollOptionIdsByPoll, pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPollOptionsByPostIds = `-- name: ListPollOptionsByPostIds :many
+SELECT po.id, po.poll_id, po.option_text, po.position, po.vote_count FROM poll_options po
+JOIN polls p ON p.id = po.poll_id
+WHERE p.post_id = ANY($1::bigint[])
+ORDER BY po.poll_id, po.position
+`
+
+func (q *Queries) ListPollOptionsByPostIds(ctx context.Context, postIds []int64) ([]PollOption, error) {
+	rows, err := q.db.QueryContext(ctx, listPollOptionsByPostIds, pq.Array(postIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PollOption
+	for rows.Next() {
+		var i PollOption
+		if err := rows.Scan(
+			&i.ID,
+			&i.PollID,
+			&i.OptionText,
+			&i.Position,
+			&i.VoteCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPollsByPostIds = `-- name: ListPollsByPostIds :many
+SELECT id, post_id, allow_multiple, voter_count, ends_at, created_at FROM polls
+WHERE post_id = ANY($1::bigint[])
+`
+
+func (q *Queries) ListPollsByPostIds(ctx context.Context, postIds []int64) ([]Poll, error) {
+	rows, err := q.db.QueryContext(ctx, listPollsByPostIds, pq.Array(postIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Poll
+	for rows.Next() {
+		var i Poll
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.AllowMultiple,
+			&i.VoterCount,
+			&i.EndsAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostReactionCountsByPostIds = `-- name: ListPostReactionCountsByPostIds :many
+SELECT post_id, reaction_type, count
+FROM post_reaction_counts
+WHERE post_id = ANY($1::bigint[]) AND count > 0
+`
+
+type ListPostReactionCountsByPostIdsRow struct {
+	PostID       int64
+	ReactionType string
+	Count        int32
+}
+
+func (q *Queries) ListPostReactionCountsByPostIds(ctx context.Context, postIds []int64) ([]ListPostReactionCountsByPostIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPostReactionCountsByPostIds, pq.Array(postIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPostReactionCountsByPostIdsRow
+	for rows.Next() {
+		var i ListPostReactionCountsByPostIdsRow
+		if err := rows.Scan(&i.PostID, &i.ReactionType, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostReactions = `-- name: ListPostReactions :many
+SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work, lp.reaction_type,
+	COUNT(lp.id) OVER () AS total_rows
+FROM liked_posts lp
+JOIN users u ON lp.user_id = u.id
+WHERE lp.post_id = $3::bigint
+	AND ($4::text = '' OR lp.reaction_type = $4::text)
+ORDER BY lp.id DESC
+OFFSET $1
+LIMIT $2
+`
+
+type ListPostReactionsParams struct {
+	Offset       int32
+	Limit        int32
+	PostID       int64
+	ReactionType string
+}
+
+type ListPostReactionsRow struct {
+	ID           int64
+	FullName     string
+	AvatarUrl    sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+	ReactionType string
+	TotalRows    int64
+}
+
+func (q *Queries) ListPostReactions(ctx context.Context, arg ListPostReactionsParams) ([]ListPostReactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPostReactions,
+		arg.Offset,
+		arg.Limit,
+		arg.PostID,
+		arg.ReactionType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPostReactionsRow
+	for rows.Next() {
+		var i ListPostReactionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.ReactionType,
+			&i.TotalRows,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRepostedPostsByTargetUser = `-- name: ListRepostedPostsByTargetUser :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+	COUNT(p.id) OVER () AS total_rows,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp2.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $3::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $4::bigint
+LEFT JOIN reposted_posts rpp2 ON p.id = rpp2.post_id AND rpp2.user_id = $3::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $3::bigint
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rpp.user_id = $4::bigint AND visible_post_for($3::bigint, p.id, p.user_id)
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, rpp2.user_id, bp.user_id
+ORDER BY p.created_at DESC
+OFFSET $1
+LIMIT $2
+`
+
+type ListRepostedPostsByTargetUserParams struct {
+	Offset       int32
+	Limit        int32
+	UserID       int64
+	TargetUserID int64
+}
+
+type ListRepostedPostsByTargetUserRow struct {
+	ID           int64
+	UserID       sql.NullInt64
+	Content      sql.NullString
+	LikeCount    sql.NullInt32
+	CommentCount sql.NullInt32
+	RepostCount  sql.NullInt32
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        string
+	Visibility   string
+	ID_2         sql.NullInt64
+	FullName     sql.NullString
+	AvatarUrl    sql.NullString
+	Bio          sql.NullString
+	OpenToWork   sql.NullBool
+	ImageUrls    interface{}
+	TotalRows    int64
+	Liked        bool
+	Repost       bool
+	Bookmarked   bool
+}
+
+func (q *Queries) ListRepostedPostsByTargetUser(ctx context.Context, arg ListRepostedPostsByTargetUserParams) ([]ListRepostedPostsByTargetUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRepostedPostsByTargetUser,
+		arg.Offset,
+		arg.Limit,
+		arg.UserID,
+		arg.TargetUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRepostedPostsByTargetUserRow
+	for rows.Next() {
+		var i ListRepostedPostsByTargetUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Content,
+			&i.LikeCount,
+			&i.CommentCount,
+			&i.RepostCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Visibility,
+			&i.ID_2,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.ImageUrls,
+			&i.TotalRows,
+			&i.Liked,
+			&i.Repost,
+			&i.Bookmarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserContentCreatedAtSince = `-- name: ListUserContentCreatedAtSince :many
+SELECT created_at FROM posts WHERE user_id = $1::bigint AND created_at >= $2::timestamp
+UNION ALL
+SELECT created_at FROM post_comments WHERE user_id = $1::bigint AND created_at >= $2::timestamp
+UNION ALL
+SELECT created_at FROM post_comment_replies WHERE user_id = $1::bigint AND created_at >= $2::timestamp
+`
+
+type ListUserContentCreatedAtSinceParams struct {
+	UserID int64
+	Since  time.Time
+}
+
+func (q *Queries) ListUserContentCreatedAtSince(ctx context.Context, arg ListUserContentCreatedAtSinceParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, listUserContentCreatedAtSince, arg.UserID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var created_at sql.NullTime
+		if err := rows.Scan(&created_at); err != nil {
+			return nil, err
+		}
+		items = append(items, created_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserPollVotesByPostIds = `-- name: ListUserPollVotesByPostIds :many
+SELECT pv.poll_id, pv.poll_option_id
+FROM poll_votes pv
+JOIN polls p ON p.id = pv.poll_id
+WHERE pv.user_id = $1::bigint AND p.post_id = ANY($2::bigint[])
+`
+
+type ListUserPollVotesByPostIdsParams struct {
+	UserID  int64
+	PostIds []int64
+}
+
+type ListUserPollVotesByPostIdsRow struct {
+	PollID       int64
+	PollOptionID int64
+}
+
+func (q *Queries) ListUserPollVotesByPostIds(ctx context.Context, arg ListUserPollVotesByPostIdsParams) ([]ListUserPollVotesByPostIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserPollVotesByPostIds, arg.UserID, pq.Array(arg.PostIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserPollVotesByPostIdsRow
+	for rows.Next() {
+		var i ListUserPollVotesByPostIdsRow
+		if err := rows.Scan(&i.PollID, &i.PollOptionID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserReactionsByPostIds = `-- name: ListUserReactionsByPostIds :many
+SELECT post_id, reaction_type
+FROM liked_posts
+WHERE user_id = $1::bigint AND post_id = ANY($2::bigint[])
+`
+
+type ListUserReactionsByPostIdsParams struct {
+	UserID  int64
+	PostIds []int64
+}
+
+type ListUserReactionsByPostIdsRow struct {
+	PostID       sql.NullInt64
+	ReactionType string
+}
+
+func (q *Queries) ListUserReactionsByPostIds(ctx context.Context, arg ListUserReactionsByPostIdsParams) ([]ListUserReactionsByPostIdsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserReactionsByPostIds, arg.UserID, pq.Array(arg.PostIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserReactionsByPostIdsRow
+	for rows.Next() {
+		var i ListUserReactionsByPostIdsRow
+		if err := rows.Scan(&i.PostID, &i.ReactionType); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPostCommentForUpdate = `-- name: LockPostCommentForUpdate :one
+SELECT 1
+FROM post_comments
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPostCommentForUpdate(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, lockPostCommentForUpdate, id)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const lockPostCommentReplyForUpdate = `-- name: LockPostCommentReplyForUpdate :one
+SELECT 1
+FROM post_comment_replies
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPostCommentReplyForUpdate(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, lockPostCommentReplyForUpdate, id)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const lockPostForUpdate = `-- name: LockPostForUpdate :one
+SELECT 1
+FROM posts
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPostForUpdate(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, lockPostForUpdate, id)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const resolveAllReportsByPost = `-- name: ResolveAllReportsByPost :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND ((target_type = 'post' AND target_id = $1::bigint)
+    OR (target_type = 'post_comment' AND target_id IN (SELECT id FROM post_comments WHERE post_id = $1::bigint))
+    OR (target_type = 'post_comment_reply' AND target_id IN (
+        SELECT pcr.id
+        FROM post_comment_replies pcr
+        JOIN post_comments pc ON pcr.post_comment_id = pc.id
+        WHERE pc.post_id = $1::bigint
+    )))
+`
+
+// Reports outlive the deleted content so the report trail stays
+func (q *Queries) ResolveAllReportsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, resolveAllReportsByPost, postID)
+	return err
+}
+
+const resolveContentFlagsByComment = `-- name: ResolveContentFlagsByComment :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND (
+    (target_type = 'post_comment' AND target_id = $1::bigint)
+    OR (target_type = 'post_comment_reply' AND target_id IN (SELECT id FROM post_comment_replies WHERE post_comment_id = $1::bigint))
+)
+`
+
+func (q *Queries) ResolveContentFlagsByComment(ctx context.Context, postCommentID int64) error {
+	_, err := q.db.ExecContext(ctx, resolveContentFlagsByComment, postCommentID)
+	return err
+}
+
+const resolveContentFlagsByPost = `-- name: ResolveContentFlagsByPost :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND (
+    (target_type = 'post' AND target_id = $1::bigint)
+    OR (target_type = 'post_comment' AND target_id IN (SELECT id FROM post_comments WHERE post_id = $1::bigint))
+    OR (target_type = 'post_comment_reply' AND target_id IN (
+        SELECT pcr.id
+        FROM post_comment_replies pcr
+        JOIN post_comments pc ON pcr.post_comment_id = pc.id
+        WHERE pc.post_id = $1::bigint
+    ))
+)
+`
+
+func (q *Queries) ResolveContentFlagsByPost(ctx context.Context, postID int64) error {
+	_, err := q.db.ExecContext(ctx, resolveContentFlagsByPost, postID)
+	return err
+}
+
+const resolveContentFlagsByReply = `-- name: ResolveContentFlagsByReply :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND target_type = 'post_comment_reply' AND target_id = $1::bigint
+`
+
+func (q *Queries) ResolveContentFlagsByReply(ctx context.Context, postCommentReplyID int64) error {
+	_, err := q.db.ExecContext(ctx, resolveContentFlagsByReply, postCommentReplyID)
+	return err
+}
+
+const resolveReportsByComment = `-- name: ResolveReportsByComment :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND ((target_type = 'post_comment' AND target_id = $1::bigint)
+    OR (target_type = 'post_comment_reply' AND target_id IN (SELECT id FROM post_comment_replies WHERE post_comment_id = $1::bigint)))
+`
+
+func (q *Queries) ResolveReportsByComment(ctx context.Context, postCommentID int64) error {
+	_, err := q.db.ExecContext(ctx, resolveReportsByComment, postCommentID)
+	return err
+}
+
+const resolveReportsByReply = `-- name: ResolveReportsByReply :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND target_type = 'post_comment_reply' AND target_id = $1::bigint
+`
+
+func (q *Queries) ResolveReportsByReply(ctx context.Context, postCommentReplyID int64) error {
+	_, err := q.db.ExecContext(ctx, resolveReportsByReply, postCommentReplyID)
+	return err
+}
+
+const searchPosts = `-- name: SearchPosts :many
 SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
@@ -935,66 +2700,97 @@ SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count
     	ELSE FALSE 
   	END AS liked,
 	CASE 
-    	WHEN rpp2.user_id IS NOT NULL THEN TRUE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked,
+	-- Titles are stored as plain text, so they are escaped before being highlighted
+	TS_HEADLINE('english', REPLACE(REPLACE(REPLACE(p.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), sq.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+	TS_HEADLINE('english', REGEXP_REPLACE(COALESCE(p.content, ''), '<[^>]*>', ' ', 'g'), sq.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" ... "')::text AS snippet
 FROM posts p
+CROSS JOIN (
+	SELECT WEBSEARCH_TO_TSQUERY('english', $3::text) || WEBSEARCH_TO_TSQUERY('indonesian', $3::text)
+		|| (CASE WHEN $4::text = '' THEN ''::tsquery ELSE TO_TSQUERY('simple', $4::text) END) AS query
+) sq
 LEFT JOIN users u ON p.user_id = u.id
-LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $3::bigint
-LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $4::bigint
-LEFT JOIN reposted_posts rpp2 ON p.id = rpp2.post_id AND rpp2.user_id = $3::bigint
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $5::bigint
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $5::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $5::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $5::bigint
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE rpp.user_id = $4::bigint
+WHERE p.search_vector @@ sq.query
+	AND ($6::bigint = 0 OR p.user_id = $6::bigint)
+	AND ($7::timestamp IS NULL OR p.created_at >= $7::timestamp)
+	AND ($8::timestamp IS NULL OR p.created_at < $8::timestamp)
+	AND ($9::text = '' OR EXISTS (SELECT 1 FROM post_hashtags ph WHERE ph.post_id = p.id AND ph.hashtag = $9::text))
+	AND rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($5::bigint, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id, rpp2.user_id
-ORDER BY p.created_at DESC
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id, sq.query
+ORDER BY TS_RANK_CD(p.search_vector, sq.query) DESC, p.created_at DESC
 OFFSET $1
 LIMIT $2
 `
 
-type ListRepostedPostsByTargetUserParams struct {
-	Offset       int32
-	Limit        int32
-	UserID       int64
-	TargetUserID int64
-}
-
-type ListRepostedPostsByTargetUserRow struct {
-	ID           int64
-	UserID       sql.NullInt64
-	Content      sql.NullString
-	LikeCount    sql.NullInt32
-	CommentCount sql.NullInt32
-	RepostCount  sql.NullInt32
-	CreatedAt    sql.NullTime
-	UpdatedAt    sql.NullTime
-	Title        string
-	Visibility   string
-	ID_2         sql.NullInt64
-	FullName     sql.NullString
-	AvatarUrl    sql.NullString
-	Bio          sql.NullString
-	OpenToWork   sql.NullBool
-	ImageUrls    interface{}
-	TotalRows    int64
-	Liked        bool
-	Repost       bool
-}
-
-func (q *Queries) ListRepostedPostsByTargetUser(ctx context.Context, arg ListRepostedPostsByTargetUserParams) ([]ListRepostedPostsByTargetUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, listRepostedPostsByTargetUser,
+type SearchPostsParams struct {
+	Offset      int32
+	Limit       int32
+	Query       string
+	PrefixQuery string
+	UserID      int64
+	AuthorID    int64
+	FromDate    sql.NullTime
+	ToDate      sql.NullTime
+	Hashtag     string
+}
+
+type SearchPostsRow struct {
+	ID             int64
+	UserID         sql.NullInt64
+	Content        sql.NullString
+	LikeCount      sql.NullInt32
+	CommentCount   sql.NullInt32
+	RepostCount    sql.NullInt32
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	Title          string
+	Visibility     string
+	ID_2           sql.NullInt64
+	FullName       sql.NullString
+	AvatarUrl      sql.NullString
+	Bio            sql.NullString
+	OpenToWork     sql.NullBool
+	ImageUrls      interface{}
+	TotalRows      int64
+	Liked          bool
+	Repost         bool
+	Bookmarked     bool
+	TitleHighlight string
+	Snippet        string
+}
+
+// Visibility, blocks, reports and mutes are filtered exactly like ListNewestPosts
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
 		arg.Offset,
 		arg.Limit,
+		arg.Query,
+		arg.PrefixQuery,
 		arg.UserID,
-		arg.TargetUserID,
+		arg.AuthorID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Hashtag,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListRepostedPostsByTargetUserRow
+	var items []SearchPostsRow
 	for rows.Next() {
-		var i ListRepostedPostsByTargetUserRow
+		var i SearchPostsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -1015,6 +2811,9 @@ func (q *Queries) ListRepostedPostsByTargetUser(ctx context.Context, arg ListRep
 			&i.TotalRows,
 			&i.Liked,
 			&i.Repost,
+			&i.Bookmarked,
+			&i.TitleHighlight,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -1029,46 +2828,55 @@ func (q *Queries) ListRepostedPostsByTargetUser(ctx context.Context, arg ListRep
 	return items, nil
 }
 
-const lockPostCommentForUpdate = `-- name: LockPostCommentForUpdate :one
-SELECT 1
-FROM posts
-WHERE id = $1
-FOR UPDATE
+const updateLikedPostCommentReactionType = `-- name: UpdateLikedPostCommentReactionType :exec
+UPDATE liked_post_comments
+SET reaction_type = $1::text
+WHERE user_id = $2::bigint AND post_comment_id = $3::bigint
 `
 
-func (q *Queries) LockPostCommentForUpdate(ctx context.Context, id int64) (int32, error) {
-	row := q.db.QueryRowContext(ctx, lockPostCommentForUpdate, id)
-	var column_1 int32
-	err := row.Scan(&column_1)
-	return column_1, err
+type UpdateLikedPostCommentReactionTypeParams struct {
+	ReactionType  string
+	UserID        int64
+	PostCommentID int64
 }
 
-const lockPostCommentReplyForUpdate = `-- name: LockPostCommentReplyForUpdate :one
-SELECT 1
-FROM posts
-WHERE id = $1
-FOR UPDATE
+func (q *Queries) UpdateLikedPostCommentReactionType(ctx context.Context, arg UpdateLikedPostCommentReactionTypeParams) error {
+	_, err := q.db.ExecContext(ctx, updateLikedPostCommentReactionType, arg.ReactionType, arg.UserID, arg.PostCommentID)
+	return err
+}
+
+const updateLikedPostCommentReplyReactionType = `-- name: UpdateLikedPostCommentReplyReactionType :exec
+UPDATE liked_post_comment_replies
+SET reaction_type = $1::text
+WHERE user_id = $2::bigint AND post_comment_reply_id = $3::bigint
 `
 
-func (q *Queries) LockPostCommentReplyForUpdate(ctx context.Context, id int64) (int32, error) {
-	row := q.db.QueryRowContext(ctx, lockPostCommentReplyForUpdate, id)
-	var column_1 int32
-	err := row.Scan(&column_1)
-	return column_1, err
+type UpdateLikedPostCommentReplyReactionTypeParams struct {
+	ReactionType       string
+	UserID             int64
+	PostCommentReplyID int64
 }
 
-const lockPostForUpdate = `-- name: LockPostForUpdate :one
-SELECT 1
-FROM posts
-WHERE id = $1
-FOR UPDATE
+func (q *Queries) UpdateLikedPostCommentReplyReactionType(ctx context.Context, arg UpdateLikedPostCommentReplyReactionTypeParams) error {
+	_, err := q.db.ExecContext(ctx, updateLikedPostCommentReplyReactionType, arg.ReactionType, arg.UserID, arg.PostCommentReplyID)
+	return err
+}
+
+const updateLikedPostReactionType = `-- name: UpdateLikedPostReactionType :exec
+UPDATE liked_posts
+SET reaction_type = $1::text
+WHERE user_id = $2::bigint AND post_id = $3::bigint
 `
 
-func (q *Queries) LockPostForUpdate(ctx context.Context, id int64) (int32, error) {
-	row := q.db.QueryRowContext(ctx, lockPostForUpdate, id)
-	var column_1 int32
-	err := row.Scan(&column_1)
-	return column_1, err
+type UpdateLikedPostReactionTypeParams struct {
+	ReactionType string
+	UserID       int64
+	PostID       int64
+}
+
+func (q *Queries) UpdateLikedPostReactionType(ctx context.Context, arg UpdateLikedPostReactionTypeParams) error {
+	_, err := q.db.ExecContext(ctx, updateLikedPostReactionType, arg.ReactionType, arg.UserID, arg.PostID)
+	return err
 }
 
 const updatePost = `-- name: UpdatePost :exec
@@ -1099,6 +2907,37 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) error {
 	return err
 }
 
+const updatePostComment = `-- name: UpdatePostComment :one
+UPDATE post_comments
+SET content = $1::text,
+    updated_at = NOW()
+WHERE id = $2::bigint
+RETURNING id, user_id, post_id, content, image_url, like_count, reply_count, is_post_author, created_at, updated_at
+`
+
+type UpdatePostCommentParams struct {
+	Content string
+	ID      int64
+}
+
+func (q *Queries) UpdatePostComment(ctx context.Context, arg UpdatePostCommentParams) (PostComment, error) {
+	row := q.db.QueryRowContext(ctx, updatePostComment, arg.Content, arg.ID)
+	var i PostComment
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostID,
+		&i.Content,
+		&i.ImageUrl,
+		&i.LikeCount,
+		&i.ReplyCount,
+		&i.IsPostAuthor,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const updatePostCommentCount = `-- name: UpdatePostCommentCount :one
 UPDATE posts
 SET comment_count = GREATEST(comment_count + $1::smallint, 0),
@@ -1149,6 +2988,36 @@ func (q *Queries) UpdatePostCommentRepliesLikeCount(ctx context.Context, arg Upd
 	return i, err
 }
 
+const updatePostCommentReply = `-- name: UpdatePostCommentReply :one
+UPDATE post_comment_replies
+SET content = $1::text,
+    updated_at = NOW()
+WHERE id = $2::bigint
+RETURNING id, user_id, post_comment_id, content, image_url, like_count, is_post_author, created_at, updated_at
+`
+
+type UpdatePostCommentReplyParams struct {
+	Content string
+	ID      int64
+}
+
+func (q *Queries) UpdatePostCommentReply(ctx context.Context, arg UpdatePostCommentReplyParams) (PostCommentReply, error) {
+	row := q.db.QueryRowContext(ctx, updatePostCommentReply, arg.Content, arg.ID)
+	var i PostCommentReply
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostCommentID,
+		&i.Content,
+		&i.ImageUrl,
+		&i.LikeCount,
+		&i.IsPostAuthor,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const updatePostCommentReplyCount = `-- name: UpdatePostCommentReplyCount :one
 UPDATE post_comments
 SET reply_count = GREATEST(reply_count + $1::smallint, 0),
@@ -1224,6 +3093,24 @@ func (q *Queries) UpdatePostLikeCount(ctx context.Context, arg UpdatePostLikeCou
 	return i, err
 }
 
+const updatePostReactionCount = `-- name: UpdatePostReactionCount :exec
+INSERT INTO post_reaction_counts (post_id, reaction_type, count)
+VALUES ($1::bigint, $2::text, GREATEST($3::int, 0))
+ON CONFLICT (post_id, reaction_type) DO UPDATE
+SET count = GREATEST(post_reaction_counts.count + $3::int, 0)
+`
+
+type UpdatePostReactionCountParams struct {
+	PostID       int64
+	ReactionType string
+	Value        int32
+}
+
+func (q *Queries) UpdatePostReactionCount(ctx context.Context, arg UpdatePostReactionCountParams) error {
+	_, err := q.db.ExecContext(ctx, updatePostReactionCount, arg.PostID, arg.ReactionType, arg.Value)
+	return err
+}
+
 const updatePostRepostCount = `-- name: UpdatePostRepostCount :one
 UPDATE posts
 SET repost_count = GREATEST(repost_count + $1::smallint, 0),
@@ -1248,3 +3135,82 @@ func (q *Queries) UpdatePostRepostCount(ctx context.Context, arg UpdatePostRepos
 	err := row.Scan(&i.ID, &i.RepostCount)
 	return i, err
 }
+
+const upsertLinkPreview = `-- name: UpsertLinkPreview :one
+INSERT INTO link_previews (url, title, description, image_url, site_name, fetched_at)
+VALUES ($1::text, $2::text, $3::text, $4::text, $5::text, NOW())
+ON CONFLICT (url) DO UPDATE
+SET title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    image_url = EXCLUDED.image_url,
+    site_name = EXCLUDED.site_name,
+    fetched_at = NOW()
+RETURNING id, url, title, description, image_url, site_name, fetched_at
+`
+
+type UpsertLinkPreviewParams struct {
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+	SiteName    string
+}
+
+func (q *Queries) UpsertLinkPreview(ctx context.Context, arg UpsertLinkPreviewParams) (LinkPreview, error) {
+	row := q.db.QueryRowContext(ctx, upsertLinkPreview,
+		arg.Url,
+		arg.Title,
+		arg.Description,
+		arg.ImageUrl,
+		arg.SiteName,
+	)
+	var i LinkPreview
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.ImageUrl,
+		&i.SiteName,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const upsertPostImpressions = `-- name: UpsertPostImpressions :exec
+INSERT INTO post_impressions (user_id, post_id, view_count, first_seen_at, last_seen_at)
+SELECT $1::bigint, p.id, 1, NOW(), NOW()
+FROM posts p
+WHERE p.id = ANY($2::bigint[]) AND p.user_id <> $1::bigint
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET view_count = post_impressions.view_count + 1,
+    last_seen_at = NOW()
+`
+
+type UpsertPostImpressionsParams struct {
+	UserID  int64
+	PostIds []int64
+}
+
+// Own and missing posts are skipped
+func (q *Queries) UpsertPostImpressions(ctx context.Context, arg UpsertPostImpressionsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertPostImpressions, arg.UserID, pq.Array(arg.PostIds))
+	return err
+}
+
+const upsertPostLinkPreview = `-- name: UpsertPostLinkPreview :exec
+INSERT INTO post_link_previews (post_id, link_preview_id)
+VALUES ($1::bigint, $2::bigint)
+ON CONFLICT (post_id) DO UPDATE
+SET link_preview_id = EXCLUDED.link_preview_id
+`
+
+type UpsertPostLinkPreviewParams struct {
+	PostID        int64
+	LinkPreviewID int64
+}
+
+func (q *Queries) UpsertPostLinkPreview(ctx context.Context, arg UpsertPostLinkPreviewParams) error {
+	_, err := q.db.ExecContext(ctx, upsertPostLinkPreview, arg.PostID, arg.LinkPreviewID)
+	return err
+}
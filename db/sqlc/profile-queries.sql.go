@@ -13,6 +13,29 @@ import (
 	"github.com/lib/pq"
 )
 
+const backfillTimeline = `-- name: BackfillTimeline :exec
+INSERT INTO timeline_entries
+(user_id, post_id, author_id, created_at)
+SELECT $1::bigint, p.id, p.user_id, COALESCE(p.created_at, NOW())
+FROM posts p
+WHERE p.user_id = $2::bigint AND NOT EXISTS (SELECT 1 FROM timeline_pull_posts tp WHERE tp.post_id = p.id)
+ORDER BY p.created_at DESC NULLS LAST, p.id DESC
+LIMIT $3::int
+ON CONFLICT DO NOTHING
+`
+
+type BackfillTimelineParams struct {
+	UserID   int64
+	AuthorID int64
+	RowLimit int32
+}
+
+// Only the newest posts of the author are copied, older ones stay on the author's profile
+func (q *Queries) BackfillTimeline(ctx context.Context, arg BackfillTimelineParams) error {
+	_, err := q.db.ExecContext(ctx, backfillTimeline, arg.UserID, arg.AuthorID, arg.RowLimit)
+	return err
+}
+
 const batchDeleteUserEmploymentTypeInterests = `-- name: BatchDeleteUserEmploymentTypeInterests :exec
 DELETE FROM user_employment_type_interests
 WHERE user_id = $1::bigint
@@ -95,13 +118,23 @@ func (q *Queries) BatchInsertEducationSkills(ctx context.Context, arg BatchInser
 }
 
 const batchInsertSkills = `-- name: BatchInsertSkills :exec
-INSERT INTO skills (name)
-SELECT unnest($1::text[])
+INSERT INTO skills (name, normalized_name)
+SELECT DISTINCT ON (n.normalized_name) n.name, n.normalized_name
+FROM unnest($1::text[], $2::text[]) AS n(name, normalized_name)
+WHERE NOT EXISTS (SELECT 1 FROM skills s WHERE s.normalized_name = n.normalized_name)
+  AND NOT EXISTS (SELECT 1 FROM catalog_aliases ca WHERE ca.catalog = 'skills' AND ca.normalized_name = n.normalized_name)
+ORDER BY n.normalized_name
 ON CONFLICT (name) DO NOTHING
 `
 
-func (q *Queries) BatchInsertSkills(ctx context.Context, names []string) error {
-	_, err := q.db.ExecContext(ctx, batchInsertSkills, pq.Array(names))
+type BatchInsertSkillsParams struct {
+	Names           []string
+	NormalizedNames []string
+}
+
+// Names matching a skill or a synonym resolve to that skill instead
+func (q *Queries) BatchInsertSkills(ctx context.Context, arg BatchInsertSkillsParams) error {
+	_, err := q.db.ExecContext(ctx, batchInsertSkills, pq.Array(arg.Names), pq.Array(arg.NormalizedNames))
 	return err
 }
 
@@ -151,18 +184,11 @@ func (q *Queries) BatchInsertUserLocationTypeInterests(ctx context.Context, arg
 }
 
 const batchInsertUserMainSkills = `-- name: BatchInsertUserMainSkills :many
-WITH exist_skills AS (
-    SELECT id, name
-    FROM skills
-    WHERE name = ANY($3::text[])
-)
 INSERT INTO user_skills (user_id, skill_id, main_skill)
 SELECT
     $1::bigint,
-    es.id,
-    $2::boolean
-FROM exist_skills es
-WHERE es.name = ANY($3::text[])
+    unnest($2::bigint[]),
+    $3::boolean
 ON CONFLICT (user_id, skill_id) DO UPDATE
 SET main_skill = true
 RETURNING id
@@ -170,15 +196,12 @@ RETURNING id
 
 type BatchInsertUserMainSkillsParams struct {
 	UserID      int64
+	SkillIds    []int64
 	IsMainSkill bool
-	Names       []string
 }
 
-// start get skills id
-// end get skills id
-// start insert user skills if not exist
 func (q *Queries) BatchInsertUserMainSkills(ctx context.Context, arg BatchInsertUserMainSkillsParams) ([]int64, error) {
-	rows, err := q.db.QueryContext(ctx, batchInsertUserMainSkills, arg.UserID, arg.IsMainSkill, pq.Array(arg.Names))
+	rows, err := q.db.QueryContext(ctx, batchInsertUserMainSkills, arg.UserID, pq.Array(arg.SkillIds), arg.IsMainSkill)
 	if err != nil {
 		return nil, err
 	}
@@ -201,35 +224,23 @@ func (q *Queries) BatchInsertUserMainSkills(ctx context.Context, arg BatchInsert
 }
 
 const batchInsertUserSkills = `-- name: BatchInsertUserSkills :many
-
-WITH exist_skills AS (
-    SELECT id, name
-    FROM skills
-    WHERE name = ANY($3::text[])
-)
 INSERT INTO user_skills (user_id, skill_id, main_skill)
 SELECT
     $1::bigint,
-    es.id,
-    $2::boolean
-FROM exist_skills es
-WHERE es.name = ANY($3::text[])
+    unnest($2::bigint[]),
+    $3::boolean
 ON CONFLICT (user_id, skill_id) DO NOTHING
 RETURNING id
 `
 
 type BatchInsertUserSkillsParams struct {
 	UserID      int64
+	SkillIds    []int64
 	IsMainSkill bool
-	Names       []string
 }
 
-// end insert user skills if not exist
-// start get skills id
-// end get skills id
-// start insert user skills if not exist
 func (q *Queries) BatchInsertUserSkills(ctx context.Context, arg BatchInsertUserSkillsParams) ([]int64, error) {
-	rows, err := q.db.QueryContext(ctx, batchInsertUserSkills, arg.UserID, arg.IsMainSkill, pq.Array(arg.Names))
+	rows, err := q.db.QueryContext(ctx, batchInsertUserSkills, arg.UserID, pq.Array(arg.SkillIds), arg.IsMainSkill)
 	if err != nil {
 		return nil, err
 	}
@@ -302,6 +313,25 @@ func (q *Queries) BatchInsertWorkExperienceSkills(ctx context.Context, arg Batch
 	return err
 }
 
+const countUserBlocksBetween = `-- name: CountUserBlocksBetween :one
+SELECT COUNT(*) AS count
+FROM user_blocks
+WHERE (user_id = $1::bigint AND blocked_user_id = $2::bigint)
+    OR (user_id = $2::bigint AND blocked_user_id = $1::bigint)
+`
+
+type CountUserBlocksBetweenParams struct {
+	UserID       int64
+	TargetUserID int64
+}
+
+func (q *Queries) CountUserBlocksBetween(ctx context.Context, arg CountUserBlocksBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserBlocksBetween, arg.UserID, arg.TargetUserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteCertificateById = `-- name: DeleteCertificateById :exec
 DELETE FROM certificates
 WHERE id = $1::bigint AND user_id = $2::bigint
@@ -389,6 +419,57 @@ func (q *Queries) DeleteFollowings(ctx context.Context, arg DeleteFollowingsPara
 	return id, err
 }
 
+const deleteTimelineEntriesByAuthor = `-- name: DeleteTimelineEntriesByAuthor :exec
+DELETE FROM timeline_entries
+WHERE user_id = $1::bigint AND author_id = $2::bigint
+`
+
+type DeleteTimelineEntriesByAuthorParams struct {
+	UserID   int64
+	AuthorID int64
+}
+
+func (q *Queries) DeleteTimelineEntriesByAuthor(ctx context.Context, arg DeleteTimelineEntriesByAuthorParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTimelineEntriesByAuthor, arg.UserID, arg.AuthorID)
+	return err
+}
+
+const deleteUserBlock = `-- name: DeleteUserBlock :one
+DELETE FROM user_blocks
+WHERE user_id = $1::bigint AND blocked_user_id = $2::bigint
+RETURNING id
+`
+
+type DeleteUserBlockParams struct {
+	UserID        int64
+	BlockedUserID int64
+}
+
+func (q *Queries) DeleteUserBlock(ctx context.Context, arg DeleteUserBlockParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserBlock, arg.UserID, arg.BlockedUserID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteUserMute = `-- name: DeleteUserMute :one
+DELETE FROM user_mutes
+WHERE user_id = $1::bigint AND muted_user_id = $2::bigint
+RETURNING id
+`
+
+type DeleteUserMuteParams struct {
+	UserID      int64
+	MutedUserID int64
+}
+
+func (q *Queries) DeleteUserMute(ctx context.Context, arg DeleteUserMuteParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserMute, arg.UserID, arg.MutedUserID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const deleteWorkExperienceById = `-- name: DeleteWorkExperienceById :exec
 DELETE FROM work_experiences
 WHERE id = $1::bigint AND user_id = $2::bigint
@@ -513,6 +594,22 @@ func (q *Queries) GetCertificatesByUserId(ctx context.Context, arg GetCertificat
 	return items, nil
 }
 
+const getCompanyByNormalizedName = `-- name: GetCompanyByNormalizedName :one
+SELECT c.id, c.name, c.normalized_name
+FROM companies c
+WHERE c.normalized_name = $1::text
+  OR c.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'companies' AND ca.normalized_name = $1::text)
+ORDER BY c.id
+LIMIT 1
+`
+
+func (q *Queries) GetCompanyByNormalizedName(ctx context.Context, normalizedName string) (Company, error) {
+	row := q.db.QueryRowContext(ctx, getCompanyByNormalizedName, normalizedName)
+	var i Company
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
+	return i, err
+}
+
 const getEducationById = `-- name: GetEducationById :one
 SELECT id, user_id, school_id, degree, field_of_study, gpa, start_date, finish_date, description, created_at, updated_at FROM educations
 WHERE id = $1::bigint
@@ -621,6 +718,61 @@ func (q *Queries) GetEducationsByUserId(ctx context.Context, arg GetEducationsBy
 	return items, nil
 }
 
+const getFollowedUsersByCursor = `-- name: GetFollowedUsersByCursor :many
+SELECT 
+  f.id AS following_id, u.id, u.full_name ,u.avatar_url ,u.bio ,u.open_to_work
+FROM followings f 
+LEFT JOIN users u ON f.follow_user_id = u.id 
+WHERE f.user_id = $2::bigint AND ($3::bigint = 0 OR f.id < $3::bigint)
+ORDER BY f.id DESC
+LIMIT $1
+`
+
+type GetFollowedUsersByCursorParams struct {
+	Limit    int32
+	UserID   int64
+	CursorID int64
+}
+
+type GetFollowedUsersByCursorRow struct {
+	FollowingID int64
+	ID          sql.NullInt64
+	FullName    sql.NullString
+	AvatarUrl   sql.NullString
+	Bio         sql.NullString
+	OpenToWork  sql.NullBool
+}
+
+func (q *Queries) GetFollowedUsersByCursor(ctx context.Context, arg GetFollowedUsersByCursorParams) ([]GetFollowedUsersByCursorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedUsersByCursor, arg.Limit, arg.UserID, arg.CursorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowedUsersByCursorRow
+	for rows.Next() {
+		var i GetFollowedUsersByCursorRow
+		if err := rows.Scan(
+			&i.FollowingID,
+			&i.ID,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFollowedUsersByUserId = `-- name: GetFollowedUsersByUserId :many
 SELECT 
   u.id, u.full_name ,u.avatar_url ,u.bio ,u.open_to_work,
@@ -677,6 +829,38 @@ func (q *Queries) GetFollowedUsersByUserId(ctx context.Context, arg GetFollowedU
 	return items, nil
 }
 
+const getIssuingOrganizationByNormalizedName = `-- name: GetIssuingOrganizationByNormalizedName :one
+SELECT io.id, io.name, io.normalized_name
+FROM issuing_organizations io
+WHERE io.normalized_name = $1::text
+  OR io.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'issuing-organizations' AND ca.normalized_name = $1::text)
+ORDER BY io.id
+LIMIT 1
+`
+
+func (q *Queries) GetIssuingOrganizationByNormalizedName(ctx context.Context, normalizedName string) (IssuingOrganization, error) {
+	row := q.db.QueryRowContext(ctx, getIssuingOrganizationByNormalizedName, normalizedName)
+	var i IssuingOrganization
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
+	return i, err
+}
+
+const getJobPositionByNormalizedName = `-- name: GetJobPositionByNormalizedName :one
+SELECT jp.id, jp.name, jp.normalized_name
+FROM job_positions jp
+WHERE jp.normalized_name = $1::text
+  OR jp.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'job-positions' AND ca.normalized_name = $1::text)
+ORDER BY jp.id
+LIMIT 1
+`
+
+func (q *Queries) GetJobPositionByNormalizedName(ctx context.Context, normalizedName string) (JobPosition, error) {
+	row := q.db.QueryRowContext(ctx, getJobPositionByNormalizedName, normalizedName)
+	var i JobPosition
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
+	return i, err
+}
+
 const getProfile = `-- name: GetProfile :many
 SELECT users.full_name, users.bio, user_social_links.url, user_social_links.platform, user_skills.main_skill, skills.name, users.followers_count, users.followings_count
 FROM users
@@ -732,6 +916,60 @@ func (q *Queries) GetProfile(ctx context.Context, id int64) ([]GetProfileRow, er
 	return items, nil
 }
 
+const getSchoolByNormalizedName = `-- name: GetSchoolByNormalizedName :one
+SELECT s.id, s.name, s.normalized_name
+FROM schools s
+WHERE s.normalized_name = $1::text
+  OR s.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'schools' AND ca.normalized_name = $1::text)
+ORDER BY s.id
+LIMIT 1
+`
+
+func (q *Queries) GetSchoolByNormalizedName(ctx context.Context, normalizedName string) (School, error) {
+	row := q.db.QueryRowContext(ctx, getSchoolByNormalizedName, normalizedName)
+	var i School
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
+	return i, err
+}
+
+const getSkillIdsByNormalizedNames = `-- name: GetSkillIdsByNormalizedNames :many
+SELECT DISTINCT ON (k.normalized_name) k.skill_id
+FROM (
+    SELECT ca.normalized_name, ca.item_id AS skill_id, 0 AS rank
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'skills' AND ca.normalized_name = ANY($1::text[])
+    UNION ALL
+    SELECT s.normalized_name, s.id, 1
+    FROM skills s
+    WHERE s.normalized_name = ANY($1::text[])
+) k
+ORDER BY k.normalized_name, k.rank, k.skill_id
+`
+
+// Synonyms win over skills sharing the name, then the oldest skill
+func (q *Queries) GetSkillIdsByNormalizedNames(ctx context.Context, normalizedNames []string) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getSkillIdsByNormalizedNames, pq.Array(normalizedNames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var skill_id int64
+		if err := rows.Scan(&skill_id); err != nil {
+			return nil, err
+		}
+		items = append(items, skill_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserAbout = `-- name: GetUserAbout :one
 SELECT ud.id, ud.user_id, ud.about, ud.updated_at, ud.created_at, u.id, u.email, u.full_name
 FROM users u
@@ -880,6 +1118,16 @@ FROM users u
 LEFT JOIN user_details ud ON u.id = ud.user_id
 LEFT JOIN followings f ON u.id = f.follow_user_id AND f.user_id = $1::bigint
 WHERE u.id = $2::bigint
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks ub
+        WHERE (ub.user_id = $1::bigint AND ub.blocked_user_id = u.id)
+            OR (ub.user_id = u.id AND ub.blocked_user_id = $1::bigint)
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_account_states uas
+        WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned')
+            AND (uas.expires_at IS NULL OR uas.expires_at > NOW())
+    )
 LIMIT 1
 `
 
@@ -925,14 +1173,18 @@ func (q *Queries) GetUserProfile(ctx context.Context, arg GetUserProfileParams)
 	return i, err
 }
 
-const getUserSkillIDsByName = `-- name: GetUserSkillIDsByName :many
+const getUserSkillIDsBySkillIds = `-- name: GetUserSkillIDsBySkillIds :many
 SELECT us.id FROM user_skills us
-JOIN skills s ON us.skill_id = s.id
-WHERE s.name = ANY($1::text[])
+WHERE us.user_id = $1::bigint AND us.skill_id = ANY($2::bigint[])
 `
 
-func (q *Queries) GetUserSkillIDsByName(ctx context.Context, name []string) ([]int64, error) {
-	rows, err := q.db.QueryContext(ctx, getUserSkillIDsByName, pq.Array(name))
+type GetUserSkillIDsBySkillIdsParams struct {
+	UserID   int64
+	SkillIds []int64
+}
+
+func (q *Queries) GetUserSkillIDsBySkillIds(ctx context.Context, arg GetUserSkillIDsBySkillIdsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSkillIDsBySkillIds, arg.UserID, pq.Array(arg.SkillIds))
 	if err != nil {
 		return nil, err
 	}
@@ -1205,16 +1457,22 @@ func (q *Queries) InsertCertificate(ctx context.Context, arg InsertCertificatePa
 }
 
 const insertCompany = `-- name: InsertCompany :one
-INSERT INTO companies (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
-RETURNING id, name
+INSERT INTO companies (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
+RETURNING id, name, normalized_name
 `
 
-func (q *Queries) InsertCompany(ctx context.Context, name string) (Company, error) {
-	row := q.db.QueryRowContext(ctx, insertCompany, name)
+type InsertCompanyParams struct {
+	Name           string
+	NormalizedName sql.NullString
+}
+
+func (q *Queries) InsertCompany(ctx context.Context, arg InsertCompanyParams) (Company, error) {
+	row := q.db.QueryRowContext(ctx, insertCompany, arg.Name, arg.NormalizedName)
 	var i Company
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
 	return i, err
 }
 
@@ -1286,44 +1544,110 @@ func (q *Queries) InsertFollowings(ctx context.Context, arg InsertFollowingsPara
 }
 
 const insertIssuingOrganization = `-- name: InsertIssuingOrganization :one
-INSERT INTO issuing_organizations (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
-RETURNING id, name
+INSERT INTO issuing_organizations (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
+RETURNING id, name, normalized_name
 `
 
-func (q *Queries) InsertIssuingOrganization(ctx context.Context, name string) (IssuingOrganization, error) {
-	row := q.db.QueryRowContext(ctx, insertIssuingOrganization, name)
+type InsertIssuingOrganizationParams struct {
+	Name           string
+	NormalizedName sql.NullString
+}
+
+func (q *Queries) InsertIssuingOrganization(ctx context.Context, arg InsertIssuingOrganizationParams) (IssuingOrganization, error) {
+	row := q.db.QueryRowContext(ctx, insertIssuingOrganization, arg.Name, arg.NormalizedName)
 	var i IssuingOrganization
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
 	return i, err
 }
 
 const insertJobPosition = `-- name: InsertJobPosition :one
-INSERT INTO job_positions (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
-RETURNING id, name
+INSERT INTO job_positions (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
+RETURNING id, name, normalized_name
 `
 
-func (q *Queries) InsertJobPosition(ctx context.Context, name sql.NullString) (JobPosition, error) {
-	row := q.db.QueryRowContext(ctx, insertJobPosition, name)
+type InsertJobPositionParams struct {
+	Name           sql.NullString
+	NormalizedName sql.NullString
+}
+
+func (q *Queries) InsertJobPosition(ctx context.Context, arg InsertJobPositionParams) (JobPosition, error) {
+	row := q.db.QueryRowContext(ctx, insertJobPosition, arg.Name, arg.NormalizedName)
 	var i JobPosition
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
 	return i, err
 }
 
 const insertSchool = `-- name: InsertSchool :one
-INSERT INTO schools (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
-RETURNING id, name
+INSERT INTO schools (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
+RETURNING id, name, normalized_name
 `
 
-func (q *Queries) InsertSchool(ctx context.Context, name string) (School, error) {
-	row := q.db.QueryRowContext(ctx, insertSchool, name)
+type InsertSchoolParams struct {
+	Name           string
+	NormalizedName sql.NullString
+}
+
+func (q *Queries) InsertSchool(ctx context.Context, arg InsertSchoolParams) (School, error) {
+	row := q.db.QueryRowContext(ctx, insertSchool, arg.Name, arg.NormalizedName)
 	var i School
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.NormalizedName)
+	return i, err
+}
+
+const insertUserBlock = `-- name: InsertUserBlock :one
+INSERT INTO user_blocks (user_id, blocked_user_id, created_at)
+VALUES ($1::bigint, $2::bigint, NOW())
+ON CONFLICT (user_id, blocked_user_id) DO NOTHING
+RETURNING id, user_id, blocked_user_id, created_at
+`
+
+type InsertUserBlockParams struct {
+	UserID        int64
+	BlockedUserID int64
+}
+
+func (q *Queries) InsertUserBlock(ctx context.Context, arg InsertUserBlockParams) (UserBlock, error) {
+	row := q.db.QueryRowContext(ctx, insertUserBlock, arg.UserID, arg.BlockedUserID)
+	var i UserBlock
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.BlockedUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertUserMute = `-- name: InsertUserMute :one
+INSERT INTO user_mutes (user_id, muted_user_id, created_at)
+VALUES ($1::bigint, $2::bigint, NOW())
+ON CONFLICT (user_id, muted_user_id) DO NOTHING
+RETURNING id, user_id, muted_user_id, created_at
+`
+
+type InsertUserMuteParams struct {
+	UserID      int64
+	MutedUserID int64
+}
+
+func (q *Queries) InsertUserMute(ctx context.Context, arg InsertUserMuteParams) (UserMute, error) {
+	row := q.db.QueryRowContext(ctx, insertUserMute, arg.UserID, arg.MutedUserID)
+	var i UserMute
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MutedUserID,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
@@ -1378,6 +1702,120 @@ func (q *Queries) InsertWorkExperience(ctx context.Context, arg InsertWorkExperi
 	return i, err
 }
 
+const listBlockedUsers = `-- name: ListBlockedUsers :many
+SELECT 
+  u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+  COUNT(*) OVER () AS total_rows
+FROM user_blocks ub
+JOIN users u ON ub.blocked_user_id = u.id
+WHERE ub.user_id = $3::bigint
+ORDER BY ub.created_at DESC
+OFFSET $1
+LIMIT $2
+`
+
+type ListBlockedUsersParams struct {
+	Offset int32
+	Limit  int32
+	UserID int64
+}
+
+type ListBlockedUsersRow struct {
+	ID         int64
+	FullName   string
+	AvatarUrl  sql.NullString
+	Bio        sql.NullString
+	OpenToWork sql.NullBool
+	TotalRows  int64
+}
+
+func (q *Queries) ListBlockedUsers(ctx context.Context, arg ListBlockedUsersParams) ([]ListBlockedUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBlockedUsers, arg.Offset, arg.Limit, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBlockedUsersRow
+	for rows.Next() {
+		var i ListBlockedUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.TotalRows,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMutedUsers = `-- name: ListMutedUsers :many
+SELECT 
+  u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+  COUNT(*) OVER () AS total_rows
+FROM user_mutes um
+JOIN users u ON um.muted_user_id = u.id
+WHERE um.user_id = $3::bigint
+ORDER BY um.created_at DESC
+OFFSET $1
+LIMIT $2
+`
+
+type ListMutedUsersParams struct {
+	Offset int32
+	Limit  int32
+	UserID int64
+}
+
+type ListMutedUsersRow struct {
+	ID         int64
+	FullName   string
+	AvatarUrl  sql.NullString
+	Bio        sql.NullString
+	OpenToWork sql.NullBool
+	TotalRows  int64
+}
+
+func (q *Queries) ListMutedUsers(ctx context.Context, arg ListMutedUsersParams) ([]ListMutedUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMutedUsers, arg.Offset, arg.Limit, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMutedUsersRow
+	for rows.Next() {
+		var i ListMutedUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.AvatarUrl,
+			&i.Bio,
+			&i.OpenToWork,
+			&i.TotalRows,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const lockUserForUpdate = `-- name: LockUserForUpdate :one
 SELECT 1
 FROM users
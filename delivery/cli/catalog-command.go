@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"profiln-be/libs"
+	repository "profiln-be/package/data/repository"
+)
+
+const catalogUsage = `usage:
+  catalog import -catalog <name> -file <path> [-format csv|json] [-dry-run]
+  catalog export -catalog <name> [-format csv|json] [-out <path>]
+  catalog refresh-popularity
+
+catalogs: schools, companies, skills, job-positions, issuing-organizations`
+
+type CatalogCommand struct {
+	repository repository.IDataRepository
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+func NewCatalogCommand(repository repository.IDataRepository, stdout, stderr io.Writer) *CatalogCommand {
+	return &CatalogCommand{
+		repository: repository,
+		stdout:     stdout,
+		stderr:     stderr,
+	}
+}
+
+// Run executes `catalog import`, `catalog export` or `catalog refresh-popularity` and returns the exit code
+func (c *CatalogCommand) Run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(c.stderr, catalogUsage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "import":
+		err = c.importCatalog(args[1:])
+	case "export":
+		err = c.exportCatalog(args[1:])
+	case "refresh-popularity":
+		err = c.refreshPopularity()
+	default:
+		fmt.Fprintln(c.stderr, catalogUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(c.stderr, "error:", err)
+		return 1
+	}
+
+	return 0
+}
+
+func (c *CatalogCommand) importCatalog(args []string) error {
+	flags := flag.NewFlagSet("catalog import", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	catalog := flags.String("catalog", "", "catalog to import into")
+	path := flags.String("file", "", "csv or json file to import")
+	format := flags.String("format", "", "file format, guessed from the file extension when empty")
+	dryRun := flags.Bool("dry-run", false, "print the changes without applying them")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if !libs.IsCatalog(*catalog) {
+		return fmt.Errorf("unknown catalog %q", *catalog)
+	}
+
+	if *path == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*path)), ".")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	names, err := libs.ParseCatalogFile(file, *format)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %w", *path, err)
+	}
+
+	existing, err := c.repository.ListCatalogItems(*catalog)
+	if err != nil {
+		return fmt.Errorf("could not list %s: %w", *catalog, err)
+	}
+
+	diff := libs.DiffCatalogItems(*catalog, existing, names)
+	for _, name := range diff.Inserts {
+		fmt.Fprintf(c.stdout, "+ %s\n", name)
+	}
+	for _, rename := range diff.Renames {
+		fmt.Fprintf(c.stdout, "~ %s -> %s (id %d)\n", rename.From, rename.To, rename.ID)
+	}
+	fmt.Fprintf(c.stdout, "%s: %d to insert, %d to rename, %d unchanged\n", *catalog, len(diff.Inserts), len(diff.Renames), diff.Unchanged)
+
+	if *dryRun || len(diff.Inserts)+len(diff.Renames) == 0 {
+		return nil
+	}
+
+	if err := c.repository.ImportCatalogItems(*catalog, diff); err != nil {
+		return fmt.Errorf("could not import %s: %w", *catalog, err)
+	}
+
+	fmt.Fprintln(c.stdout, "imported")
+	return nil
+}
+
+func (c *CatalogCommand) exportCatalog(args []string) error {
+	flags := flag.NewFlagSet("catalog export", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	catalog := flags.String("catalog", "", "catalog to export")
+	format := flags.String("format", libs.CatalogFormatCSV, "file format, csv or json")
+	path := flags.String("out", "", "file to write, stdout when empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if !libs.IsCatalog(*catalog) {
+		return fmt.Errorf("unknown catalog %q", *catalog)
+	}
+
+	items, err := c.repository.ListCatalogItems(*catalog)
+	if err != nil {
+		return fmt.Errorf("could not list %s: %w", *catalog, err)
+	}
+
+	out := c.stdout
+	if *path != "" {
+		file, err := os.Create(*path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	return libs.WriteCatalogFile(out, *format, items)
+}
+
+// refreshPopularity rebuilds the counts right away, the server also refreshes them periodically
+func (c *CatalogCommand) refreshPopularity() error {
+	if err := c.repository.RefreshCatalogPopularity(context.Background()); err != nil {
+		return fmt.Errorf("could not refresh catalog popularity: %w", err)
+	}
+
+	fmt.Fprintln(c.stdout, "refreshed")
+	return nil
+}
@@ -0,0 +1,561 @@
+package http
+
+import (
+	"net/http"
+	"profiln-be/libs"
+	"profiln-be/model"
+	"profiln-be/package/admin"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+type IAdminController interface {
+	ListUsers(ctx *gin.Context)
+	GetUser(ctx *gin.Context)
+	ListRoles(ctx *gin.Context)
+	AssignRole(ctx *gin.Context)
+	RevokeRole(ctx *gin.Context)
+	InsertCatalogItem(ctx *gin.Context)
+	UpdateCatalogItem(ctx *gin.Context)
+	DeleteCatalogItem(ctx *gin.Context)
+	MergeCatalogItems(ctx *gin.Context)
+	SetSkillParent(ctx *gin.Context)
+	InsertSkillSynonym(ctx *gin.Context)
+	DeleteSkillSynonym(ctx *gin.Context)
+	ListReports(ctx *gin.Context)
+	ActOnReport(ctx *gin.Context)
+	ListAuditLogs(ctx *gin.Context)
+}
+
+type AdminController struct {
+	usecase admin.IAdminUsecase
+}
+
+func NewAdminController(usecase admin.IAdminUsecase) IAdminController {
+	return &AdminController{
+		usecase,
+	}
+}
+
+func (c *AdminController) ListUsers(ctx *gin.Context) {
+	var response model.Response
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListUsers(ctx.Query("search"), pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) GetUser(ctx *gin.Context) {
+	var response model.Response
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("userId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.GetUser(targetUserId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) ListRoles(ctx *gin.Context) {
+	response := c.usecase.ListRoles()
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) AssignRole(ctx *gin.Context) {
+	var (
+		reqBody  model.UserRoleRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("userId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't change your own roles")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.AssignRole(userId, targetUserId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) RevokeRole(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("userId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't change your own roles")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.RevokeRole(userId, targetUserId, ctx.Param("role"))
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) InsertCatalogItem(ctx *gin.Context) {
+	var (
+		reqBody  model.CatalogItemRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.InsertCatalogItem(userId, ctx.Param("catalog"), &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) UpdateCatalogItem(ctx *gin.Context) {
+	var (
+		reqBody  model.CatalogItemRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	itemId, err := strconv.ParseInt(ctx.Param("itemId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.UpdateCatalogItem(userId, ctx.Param("catalog"), itemId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) DeleteCatalogItem(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	itemId, err := strconv.ParseInt(ctx.Param("itemId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DeleteCatalogItem(userId, ctx.Param("catalog"), itemId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) MergeCatalogItems(ctx *gin.Context) {
+	var (
+		reqBody  model.CatalogMergeRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	itemId, err := strconv.ParseInt(ctx.Param("itemId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.MergeCatalogItems(userId, ctx.Param("catalog"), itemId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) SetSkillParent(ctx *gin.Context) {
+	var (
+		reqBody  model.SkillParentRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	skillId, err := strconv.ParseInt(ctx.Param("skillId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.SetSkillParent(userId, skillId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) InsertSkillSynonym(ctx *gin.Context) {
+	var (
+		reqBody  model.SkillSynonymRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	skillId, err := strconv.ParseInt(ctx.Param("skillId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.InsertSkillSynonym(userId, skillId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) DeleteSkillSynonym(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	skillId, err := strconv.ParseInt(ctx.Param("skillId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	synonymId, err := strconv.ParseInt(ctx.Param("synonymId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DeleteSkillSynonym(userId, skillId, synonymId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) ListReports(ctx *gin.Context) {
+	var response model.Response
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListReports(ctx.Query("targetType"), pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) ActOnReport(ctx *gin.Context) {
+	var (
+		reqBody  model.ModerationActionRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	reportId, err := strconv.ParseInt(ctx.Param("reportId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ActOnReport(userId, reportId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *AdminController) ListAuditLogs(ctx *gin.Context) {
+	var (
+		actorId  int64
+		response model.Response
+	)
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	// Actor filter is optional, every admin action is listed when it is empty
+	if actor := ctx.Query("actorId"); actor != "" {
+		actorId, err = strconv.ParseInt(actor, 10, 64)
+		if err != nil || actorId <= 0 {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListAuditLogs(actorId, ctx.Query("targetType"), pagination)
+	ctx.JSON(response.Status.Code, response)
+}
@@ -6,6 +6,7 @@ import (
 	"profiln-be/model"
 	"profiln-be/package/data"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +17,7 @@ type IDataController interface {
 	GetIssuingOrganizations(ctx *gin.Context)
 	GetSkills(ctx *gin.Context)
 	GetJobPositions(ctx *gin.Context)
+	GetSkillTaxonomy(ctx *gin.Context)
 }
 
 type DataController struct {
@@ -31,16 +33,12 @@ func NewDataController(usecase data.IDataUsecase) IDataController {
 func (c *DataController) GetSchools(ctx *gin.Context) {
 	var response model.Response
 
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
+	if ctx.Query("q") != "" {
+		c.searchCatalog(ctx, model.CatalogSchools)
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -48,19 +46,6 @@ func (c *DataController) GetSchools(ctx *gin.Context) {
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
-
-	if page <= 0 || limit <= 0 {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
-		return
-	}
-
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
-	}
 	response = c.usecase.GetSchools(pagination)
 	ctx.JSON(response.Status.Code, response)
 }
@@ -68,16 +53,12 @@ func (c *DataController) GetSchools(ctx *gin.Context) {
 func (c *DataController) GetCompanies(ctx *gin.Context) {
 	var response model.Response
 
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
+	if ctx.Query("q") != "" {
+		c.searchCatalog(ctx, model.CatalogCompanies)
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -85,19 +66,6 @@ func (c *DataController) GetCompanies(ctx *gin.Context) {
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
-
-	if page <= 0 || limit <= 0 {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
-		return
-	}
-
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
-	}
 	response = c.usecase.GetCompanies(pagination)
 	ctx.JSON(response.Status.Code, response)
 }
@@ -105,16 +73,12 @@ func (c *DataController) GetCompanies(ctx *gin.Context) {
 func (c *DataController) GetIssuingOrganizations(ctx *gin.Context) {
 	var response model.Response
 
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
+	if ctx.Query("q") != "" {
+		c.searchCatalog(ctx, model.CatalogIssuingOrganizations)
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -122,19 +86,6 @@ func (c *DataController) GetIssuingOrganizations(ctx *gin.Context) {
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
-
-	if page <= 0 || limit <= 0 {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
-		return
-	}
-
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
-	}
 	response = c.usecase.GetIssuingOrganizations(pagination)
 	ctx.JSON(response.Status.Code, response)
 }
@@ -142,16 +93,12 @@ func (c *DataController) GetIssuingOrganizations(ctx *gin.Context) {
 func (c *DataController) GetSkills(ctx *gin.Context) {
 	var response model.Response
 
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
+	if ctx.Query("q") != "" {
+		c.searchCatalog(ctx, model.CatalogSkills)
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -159,19 +106,6 @@ func (c *DataController) GetSkills(ctx *gin.Context) {
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
-
-	if page <= 0 || limit <= 0 {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
-		return
-	}
-
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
-	}
 	response = c.usecase.GetSkills(pagination)
 	ctx.JSON(response.Status.Code, response)
 }
@@ -179,7 +113,12 @@ func (c *DataController) GetSkills(ctx *gin.Context) {
 func (c *DataController) GetJobPositions(ctx *gin.Context) {
 	var response model.Response
 
-	page, err := strconv.Atoi(ctx.Query("page"))
+	if ctx.Query("q") != "" {
+		c.searchCatalog(ctx, model.CatalogJobPositions)
+		return
+	}
+
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -187,17 +126,36 @@ func (c *DataController) GetJobPositions(ctx *gin.Context) {
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
+	response = c.usecase.GetJobPositions(pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+// searchCatalog serves typeahead requests, the limit is kept small so it can run on every keystroke
+func (c *DataController) GetSkillTaxonomy(ctx *gin.Context) {
+	var response model.Response
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	skillId, err := strconv.ParseInt(ctx.Param("skillId"), 10, 64)
 	if err != nil {
 		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
 
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
 
-	if page <= 0 || limit <= 0 {
+	response = c.usecase.GetSkillTaxonomy(skillId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *DataController) searchCatalog(ctx *gin.Context, catalog string) {
+	var (
+		response model.Response
+		limit    = 10
+		err      error
+	)
+
+	q := strings.TrimSpace(ctx.Query("q"))
+	if q == "" || len(q) > 100 {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
 
@@ -205,10 +163,17 @@ func (c *DataController) GetJobPositions(ctx *gin.Context) {
 		return
 	}
 
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil || limit <= 0 || limit > 20 {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
 	}
-	response = c.usecase.GetJobPositions(pagination)
+
+	response = c.usecase.SearchCatalog(catalog, q, limit)
 	ctx.JSON(response.Status.Code, response)
 }
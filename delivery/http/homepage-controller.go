@@ -14,6 +14,9 @@ import (
 type IHomepageController interface {
 	ListPosts(ctx *gin.Context)
 	ListFollowsRecommendation(ctx *gin.Context)
+	DismissFollowRecommendation(ctx *gin.Context)
+	ListTrendingPosts(ctx *gin.Context)
+	ListTrendingTopics(ctx *gin.Context)
 }
 
 type HomepageController struct {
@@ -33,7 +36,7 @@ func (c *HomepageController) ListPosts(ctx *gin.Context) {
 	userId := int64(userData["id"].(float64))
 	orderBy := ctx.Query("orderBy")
 
-	page, err := strconv.Atoi(ctx.Query("page"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -42,7 +45,26 @@ func (c *HomepageController) ListPosts(ctx *gin.Context) {
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if orderBy != model.FeedOrderNewest && orderBy != model.FeedOrderFollowing && orderBy != model.FeedOrderPopular && orderBy != model.FeedOrderForYou {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination.OrderBy = orderBy
+
+	response = c.usecase.ListPosts(userId, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *HomepageController) ListFollowsRecommendation(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	page, err := strconv.Atoi(ctx.Query("page"))
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -51,7 +73,8 @@ func (c *HomepageController) ListPosts(ctx *gin.Context) {
 		return
 	}
 
-	if orderBy != "newest" && orderBy != "following" && orderBy != "popular" {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
 
@@ -68,22 +91,47 @@ func (c *HomepageController) ListPosts(ctx *gin.Context) {
 	}
 
 	pagination := model.PaginationRequest{
-		Page:    page,
-		Limit:   limit,
-		OrderBy: orderBy,
+		Page:  page,
+		Limit: limit,
 	}
 
-	response = c.usecase.ListPosts(userId, pagination)
+	response = c.usecase.ListFollowsRecommendation(userId, pagination)
 	ctx.JSON(response.Status.Code, response)
 }
 
-func (c *HomepageController) ListFollowsRecommendation(ctx *gin.Context) {
+func (c *HomepageController) DismissFollowRecommendation(ctx *gin.Context) {
 	var response model.Response
 	userData := ctx.MustGet("userData").(jwt.MapClaims)
 	userId := int64(userData["id"].(float64))
 
-	page, err := strconv.Atoi(ctx.Query("page"))
+	dismissedUserId, err := strconv.ParseInt(ctx.Param("userId"), 10, 64)
 	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == dismissedUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't dismiss yourself")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DismissFollowRecommendation(userId, dismissedUserId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *HomepageController) ListTrendingPosts(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	window := ctx.DefaultQuery("window", libs.DefaultTrendingWindow)
+	if _, ok := libs.TrendingWindows[window]; !ok {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
 
@@ -91,7 +139,7 @@ func (c *HomepageController) ListFollowsRecommendation(ctx *gin.Context) {
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -100,7 +148,15 @@ func (c *HomepageController) ListFollowsRecommendation(ctx *gin.Context) {
 		return
 	}
 
-	if page <= 0 || limit <= 0 {
+	response = c.usecase.ListTrendingPosts(userId, window, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *HomepageController) ListTrendingTopics(ctx *gin.Context) {
+	var response model.Response
+
+	window := ctx.DefaultQuery("window", libs.DefaultTrendingWindow)
+	if _, ok := libs.TrendingWindows[window]; !ok {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
 
@@ -108,11 +164,25 @@ func (c *HomepageController) ListFollowsRecommendation(ctx *gin.Context) {
 		return
 	}
 
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
+	// An empty type lists hashtags and skills together
+	topicType := ctx.Query("type")
+	if topicType != "" && topicType != model.TrendingTopicHashtag && topicType != model.TrendingTopicSkill {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
 	}
 
-	response = c.usecase.ListFollowsRecommendation(userId, pagination)
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ListTrendingTopics(window, topicType, limit)
 	ctx.JSON(response.Status.Code, response)
 }
@@ -7,11 +7,20 @@ import (
 	"profiln-be/libs"
 	"profiln-be/model"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
-func Authentication() gin.HandlerFunc {
+// Authentication verifies the token and turns away suspended and banned users
+func Authentication(checker libs.IAccountStateChecker, log *logrus.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		// Route groups share this middleware, only check once per request
+		if _, exists := ctx.Get("userData"); exists {
+			ctx.Next()
+			return
+		}
+
 		var response = model.Response{}
 		header := ctx.Request.Header.Get("Authorization")
 		isHasBearer := strings.HasPrefix(header, "Bearer")
@@ -35,6 +44,28 @@ func Authentication() gin.HandlerFunc {
 			return
 		}
 
+		userId := int64(verifiedToken.(jwt.MapClaims)["id"].(float64))
+
+		accountState, err := checker.GetAccountState(userId)
+		if err != nil {
+			log.Errorf("checker.GetAccountState (user id %d): %v", userId, err)
+
+			status := libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+			response.Status = status
+
+			ctx.AbortWithStatusJSON(status.Code, response)
+			return
+		}
+
+		if accountState.State == model.AccountStateSuspended || accountState.State == model.AccountStateBanned {
+			status := libs.CustomResponse(http.StatusForbidden, "Your account is "+accountState.State)
+			response.Status = status
+			response.Data = accountState
+
+			ctx.AbortWithStatusJSON(status.Code, response)
+			return
+		}
+
 		ctx.Set("userData", verifiedToken)
 		ctx.Next()
 	}
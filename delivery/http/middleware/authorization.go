@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"profiln-be/libs"
+	"profiln-be/model"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type IPermissionChecker interface {
+	HasPermission(userId int64, permission string) (bool, error)
+}
+
+// RequirePermission only lets through users whose roles grant the permission,
+// it must be used after Authentication
+func RequirePermission(checker IPermissionChecker, log *logrus.Logger, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userData := ctx.MustGet("userData").(jwt.MapClaims)
+		userId := int64(userData["id"].(float64))
+
+		allowed, err := checker.HasPermission(userId, permission)
+		if err != nil {
+			log.Errorf("checker.HasPermission (user id %d): %v", userId, err)
+
+			response := model.Response{
+				Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+			}
+			ctx.AbortWithStatusJSON(response.Status.Code, response)
+			return
+		}
+
+		if !allowed {
+			response := model.Response{
+				Status: libs.CustomResponse(http.StatusForbidden, "Forbidden"),
+			}
+			ctx.AbortWithStatusJSON(response.Status.Code, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
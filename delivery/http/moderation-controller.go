@@ -0,0 +1,320 @@
+package http
+
+import (
+	"net/http"
+	"profiln-be/libs"
+	"profiln-be/model"
+	"profiln-be/package/moderation"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+type IModerationController interface {
+	ListReportedContent(ctx *gin.Context)
+	GetReportedPost(ctx *gin.Context)
+	ActOnReportedPost(ctx *gin.Context)
+	ActOnReportedComment(ctx *gin.Context)
+	ActOnReportedReply(ctx *gin.Context)
+	ActOnReportedUser(ctx *gin.Context)
+	ListContentFlags(ctx *gin.Context)
+	ActOnContentFlag(ctx *gin.Context)
+	SetAccountState(ctx *gin.Context)
+}
+
+type ModerationController struct {
+	usecase moderation.IModerationUsecase
+}
+
+func NewModerationController(usecase moderation.IModerationUsecase) IModerationController {
+	return &ModerationController{
+		usecase,
+	}
+}
+
+func (c *ModerationController) ListReportedContent(ctx *gin.Context) {
+	var response model.Response
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListReportedContent(pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ModerationController) GetReportedPost(ctx *gin.Context) {
+	var response model.Response
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.GetReportedPost(postId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ModerationController) ActOnReportedPost(ctx *gin.Context) {
+	var (
+		reqBody  model.ModerationActionRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ActOnReportedPost(userId, postId, &reqBody, nil)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ModerationController) ActOnReportedComment(ctx *gin.Context) {
+	c.actOnReportedContent(ctx, model.ReportTargetPostComment, "commentId")
+}
+
+func (c *ModerationController) ActOnReportedReply(ctx *gin.Context) {
+	c.actOnReportedContent(ctx, model.ReportTargetPostCommentReply, "replyId")
+}
+
+func (c *ModerationController) ActOnReportedUser(ctx *gin.Context) {
+	c.actOnReportedContent(ctx, model.ReportTargetUser, "targetUserId")
+}
+
+// actOnReportedContent handles the actions on reported comments, replies and users,
+// the target id is read from the given route param
+func (c *ModerationController) actOnReportedContent(ctx *gin.Context, targetType, param string) {
+	var (
+		reqBody  model.ModerationActionRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetId, err := strconv.ParseInt(ctx.Param(param), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ActOnReportedContent(userId, targetType, targetId, &reqBody, nil)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ModerationController) ListContentFlags(ctx *gin.Context) {
+	var response model.Response
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListContentFlags(pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ModerationController) ActOnContentFlag(ctx *gin.Context) {
+	var (
+		reqBody  model.ContentFlagActionRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	flagId, err := strconv.ParseInt(ctx.Param("flagId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ActOnContentFlag(userId, flagId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ModerationController) SetAccountState(ctx *gin.Context) {
+	var (
+		reqBody  model.AccountStateRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("targetUserId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't change your own account state")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.SetAccountState(userId, targetUserId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
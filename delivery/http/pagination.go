@@ -0,0 +1,49 @@
+package http
+
+import (
+	"fmt"
+	"profiln-be/libs"
+	"profiln-be/model"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginationQuery reads offset pagination when a page is given, otherwise
+// keyset pagination continuing from the optional cursor
+func paginationQuery(ctx *gin.Context) (model.PaginationRequest, error) {
+	var pagination model.PaginationRequest
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		return pagination, err
+	}
+
+	if limit <= 0 {
+		return pagination, fmt.Errorf("invalid limit %d", limit)
+	}
+	pagination.Limit = limit
+
+	if ctx.Query("page") == "" {
+		if cursor := ctx.Query("cursor"); cursor != "" {
+			pagination.Cursor, err = libs.DecodePageCursor(cursor)
+			if err != nil {
+				return pagination, err
+			}
+		}
+
+		return pagination, nil
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		return pagination, err
+	}
+
+	if page <= 0 {
+		return pagination, fmt.Errorf("invalid page %d", page)
+	}
+	pagination.Page = page
+
+	return pagination, nil
+}
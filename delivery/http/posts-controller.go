@@ -5,6 +5,7 @@ import (
 	"profiln-be/libs"
 	"profiln-be/model"
 	"profiln-be/package/posts"
+	"slices"
 	"strconv"
 
 	"github.com/dgrijalva/jwt-go"
@@ -13,11 +14,16 @@ import (
 
 type IPostsController interface {
 	ReportPost(ctx *gin.Context)
+	ReportPostComment(ctx *gin.Context)
+	ReportPostCommentReply(ctx *gin.Context)
+	ReportUser(ctx *gin.Context)
 	GetDetailPost(ctx *gin.Context)
 	GetPostComments(ctx *gin.Context)
 	GetPostCommentReplies(ctx *gin.Context)
 	LikePost(ctx *gin.Context)
 	UnlikePost(ctx *gin.Context)
+	ReactPost(ctx *gin.Context)
+	ListPostReactions(ctx *gin.Context)
 	ListNewestPostsByTargetUser(ctx *gin.Context)
 	ListLikedPostsByTargetUser(ctx *gin.Context)
 	ListRepostedPostsByTargetUser(ctx *gin.Context)
@@ -31,9 +37,24 @@ type IPostsController interface {
 	InsertPostComment(ctx *gin.Context)
 	LikePostComment(ctx *gin.Context)
 	UnlikePostComment(ctx *gin.Context)
+	ReactPostComment(ctx *gin.Context)
 	InsertPostCommentReply(ctx *gin.Context)
 	LikePostCommentReply(ctx *gin.Context)
 	UnlikePostCommentReply(ctx *gin.Context)
+	ReactPostCommentReply(ctx *gin.Context)
+	UpdatePostComment(ctx *gin.Context)
+	DeletePostComment(ctx *gin.Context)
+	UpdatePostCommentReply(ctx *gin.Context)
+	DeletePostCommentReply(ctx *gin.Context)
+	VotePoll(ctx *gin.Context)
+	BookmarkPost(ctx *gin.Context)
+	UnbookmarkPost(ctx *gin.Context)
+	ListBookmarkedPosts(ctx *gin.Context)
+	InsertBookmarkFolder(ctx *gin.Context)
+	ListBookmarkFolders(ctx *gin.Context)
+	DeleteBookmarkFolder(ctx *gin.Context)
+	RecordPostImpressions(ctx *gin.Context)
+	GetPostViewCounts(ctx *gin.Context)
 }
 
 type PostsController struct {
@@ -48,7 +69,7 @@ func NewPostsController(usecase posts.IPostsUsecase) IPostsController {
 
 func (c *PostsController) ReportPost(ctx *gin.Context) {
 	var (
-		reqBody  model.ReportPost
+		reqBody  model.Report
 		response model.Response
 	)
 	userData := ctx.MustGet("userData").(jwt.MapClaims)
@@ -86,15 +107,15 @@ func (c *PostsController) ReportPost(ctx *gin.Context) {
 		return
 	}
 
-	reqBody.PostId = postId
-
-	response = c.usecase.InsertReportedPost(userId, &reqBody)
+	response = c.usecase.ReportPost(userId, postId, &reqBody)
 	ctx.JSON(response.Status.Code, response)
 }
 
-func (c *PostsController) GetDetailPost(ctx *gin.Context) {
-	var response model.Response
-
+func (c *PostsController) ReportPostComment(ctx *gin.Context) {
+	var (
+		reqBody  model.Report
+		response model.Response
+	)
 	userData := ctx.MustGet("userData").(jwt.MapClaims)
 	userId := int64(userData["id"].(float64))
 
@@ -107,12 +128,49 @@ func (c *PostsController) GetDetailPost(ctx *gin.Context) {
 		return
 	}
 
-	response = c.usecase.GetDetailPost(postId, userId)
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ReportPostComment(userId, postId, postCommentId, &reqBody)
 	ctx.JSON(response.Status.Code, response)
 }
 
-func (c *PostsController) GetPostComments(ctx *gin.Context) {
-	var response model.Response
+func (c *PostsController) ReportPostCommentReply(ctx *gin.Context) {
+	var (
+		reqBody  model.Report
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
 
 	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
 	if err != nil {
@@ -123,44 +181,148 @@ func (c *PostsController) GetPostComments(ctx *gin.Context) {
 		return
 	}
 
-	page, err := strconv.Atoi(ctx.Query("page"))
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
 	if err != nil {
 		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
 
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	postCommentReplyId, err := strconv.ParseInt(ctx.Param("postCommentReplyId"), 10, 64)
 	if err != nil {
 		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
 
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
 
-	if page <= 0 || limit <= 0 {
+	if err := ctx.ShouldBind(&reqBody); err != nil {
 		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
 
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
 
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ReportPostCommentReply(userId, postId, postCommentId, postCommentReplyId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) ReportUser(ctx *gin.Context) {
+	var (
+		reqBody  model.Report
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("targetUserId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ReportUser(userId, targetUserId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) GetDetailPost(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.GetDetailPost(postId, userId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) GetPostComments(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination, err := paginationQuery(ctx)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
 	}
 
-	response = c.usecase.GetPostComments(postId, pagination)
+	response = c.usecase.GetPostComments(userId, postId, pagination)
 	ctx.JSON(response.Status.Code, response)
 }
 
 func (c *PostsController) GetPostCommentReplies(ctx *gin.Context) {
 	var response model.Response
 
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
 	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
 	if err != nil {
 		response.Status =
@@ -210,7 +372,7 @@ func (c *PostsController) GetPostCommentReplies(ctx *gin.Context) {
 		Limit: limit,
 	}
 
-	response = c.usecase.GetPostCommentReplies(postId, postCommentId, pagination)
+	response = c.usecase.GetPostCommentReplies(userId, postId, postCommentId, pagination)
 	ctx.JSON(response.Status.Code, response)
 }
 
@@ -252,6 +414,101 @@ func (c *PostsController) UnlikePost(ctx *gin.Context) {
 	ctx.JSON(response.Status.Code, response)
 }
 
+func (c *PostsController) ReactPost(ctx *gin.Context) {
+	var (
+		reqBody  model.ReactRequest
+		response model.Response
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ReactPost(userId, postId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) ListPostReactions(ctx *gin.Context) {
+	var response model.Response
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	// Empty type lists every reaction
+	reactionType := ctx.Query("type")
+
+	if page <= 0 || limit <= 0 || (reactionType != "" && !slices.Contains(model.ReactionTypes, reactionType)) {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListPostReactions(postId, reactionType, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
 func (c *PostsController) ListNewestPostsByTargetUser(ctx *gin.Context) {
 	var response model.Response
 
@@ -676,16 +933,147 @@ func (c *PostsController) UnlikePostComment(ctx *gin.Context) {
 	ctx.JSON(response.Status.Code, response)
 }
 
-func (c *PostsController) InsertPostCommentReply(ctx *gin.Context) {
+func (c *PostsController) ReactPostComment(ctx *gin.Context) {
 	var (
+		reqBody  model.ReactRequest
 		response model.Response
-		reqBody  model.AddPostCommentReplyReq
 	)
-	fileNames := ctx.MustGet("fileNames").([]string)
+
 	userData := ctx.MustGet("userData").(jwt.MapClaims)
 	userId := int64(userData["id"].(float64))
 
-	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ReactPostComment(userId, postCommentId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) UpdatePostComment(ctx *gin.Context) {
+	var (
+		response model.Response
+		reqBody  model.UpdatePostCommentReq
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	reqBody.ID = postCommentId
+	reqBody.PostId = postId
+	reqBody.UserId = userId
+
+	response = c.usecase.UpdatePostComment(&reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) DeletePostComment(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DeletePostComment(userId, postId, postCommentId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) InsertPostCommentReply(ctx *gin.Context) {
+	var (
+		response model.Response
+		reqBody  model.AddPostCommentReplyReq
+	)
+	fileNames := ctx.MustGet("fileNames").([]string)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
@@ -771,3 +1159,424 @@ func (c *PostsController) UnlikePostCommentReply(ctx *gin.Context) {
 	response = c.usecase.UnlikePostCommentReply(userId, postCommentReplyId)
 	ctx.JSON(response.Status.Code, response)
 }
+
+func (c *PostsController) ReactPostCommentReply(ctx *gin.Context) {
+	var (
+		reqBody  model.ReactRequest
+		response model.Response
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postCommentReplyId, err := strconv.ParseInt(ctx.Param("postCommentReplyId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.ReactPostCommentReply(userId, postCommentReplyId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) UpdatePostCommentReply(ctx *gin.Context) {
+	var (
+		response model.Response
+		reqBody  model.UpdatePostCommentReplyReq
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	postCommentReplyId, err := strconv.ParseInt(ctx.Param("postCommentReplyId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	reqBody.ID = postCommentReplyId
+	reqBody.PostId = postId
+	reqBody.PostCommentId = postCommentId
+	reqBody.UserId = userId
+
+	response = c.usecase.UpdatePostCommentReply(&reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) DeletePostCommentReply(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	postCommentId, err := strconv.ParseInt(ctx.Param("postCommentId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	postCommentReplyId, err := strconv.ParseInt(ctx.Param("postCommentReplyId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DeletePostCommentReply(userId, postId, postCommentId, postCommentReplyId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) VotePoll(ctx *gin.Context) {
+	var (
+		reqBody  model.VotePollRequest
+		response model.Response
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.VotePoll(userId, postId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) BookmarkPost(ctx *gin.Context) {
+	var (
+		reqBody  model.BookmarkPostRequest
+		response model.Response
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	// Request body is optional, only needed to put the bookmark in a folder
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBind(&reqBody); err != nil {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+	}
+
+	response = c.usecase.BookmarkPost(userId, postId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) UnbookmarkPost(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.UnbookmarkPost(userId, postId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) ListBookmarkedPosts(ctx *gin.Context) {
+	var (
+		response         model.Response
+		bookmarkFolderId int64
+		err              error
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	// Folder filter is optional, all bookmarks are listed when it is empty
+	if folderId := ctx.Query("folderId"); folderId != "" {
+		bookmarkFolderId, err = strconv.ParseInt(folderId, 10, 64)
+		if err != nil || bookmarkFolderId <= 0 {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.ListBookmarkedPosts(userId, bookmarkFolderId, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) InsertBookmarkFolder(ctx *gin.Context) {
+	var (
+		reqBody  model.CreateBookmarkFolderRequest
+		response model.Response
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.InsertBookmarkFolder(userId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) ListBookmarkFolders(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	response := c.usecase.ListBookmarkFolders(userId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) DeleteBookmarkFolder(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	bookmarkFolderId, err := strconv.ParseInt(ctx.Param("folderId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DeleteBookmarkFolder(userId, bookmarkFolderId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) RecordPostImpressions(ctx *gin.Context) {
+	var (
+		reqBody  model.PostImpressionsRequest
+		response model.Response
+	)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody) // validate reqBody struct
+	// if there is an error
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.RecordPostImpressions(userId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *PostsController) GetPostViewCounts(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	postId, err := strconv.ParseInt(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.GetPostViewCounts(userId, postId)
+	ctx.JSON(response.Status.Code, response)
+}
@@ -34,6 +34,12 @@ type IProfileController interface {
 	DeleteUserCertificate(ctx *gin.Context)
 	FollowUser(ctx *gin.Context)
 	UnfollowUser(ctx *gin.Context)
+	BlockUser(ctx *gin.Context)
+	UnblockUser(ctx *gin.Context)
+	ListBlockedUsers(ctx *gin.Context)
+	MuteUser(ctx *gin.Context)
+	UnmuteUser(ctx *gin.Context)
+	ListMutedUsers(ctx *gin.Context)
 	InsertUserWorkExperience(ctx *gin.Context)
 	InsertUserEducation(ctx *gin.Context)
 	InsertUserProfile(ctx *gin.Context)
@@ -548,16 +554,7 @@ func (c *ProfileController) GetFollowedUsersByUser(ctx *gin.Context) {
 		return
 	}
 
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
-		return
-	}
-
-	limit, err := strconv.Atoi(ctx.Query("limit"))
+	pagination, err := paginationQuery(ctx)
 	if err != nil {
 		response.Status =
 			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
@@ -565,19 +562,6 @@ func (c *ProfileController) GetFollowedUsersByUser(ctx *gin.Context) {
 		ctx.JSON(response.Status.Code, response)
 		return
 	}
-
-	if page <= 0 || limit <= 0 {
-		response.Status =
-			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
-
-		ctx.JSON(response.Status.Code, response)
-		return
-	}
-
-	pagination := model.PaginationRequest{
-		Page:  page,
-		Limit: limit,
-	}
 	response = c.usecase.GetFollowedUsersByUserId(userId, pagination)
 	ctx.JSON(response.Status.Code, response)
 }
@@ -745,6 +729,188 @@ func (c *ProfileController) UnfollowUser(ctx *gin.Context) {
 	ctx.JSON(response.Status.Code, response)
 }
 
+func (c *ProfileController) BlockUser(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("targetUserId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't block yourself")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.BlockUser(userId, targetUserId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ProfileController) UnblockUser(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("targetUserId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't unblock yourself")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.UnblockUser(userId, targetUserId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ProfileController) ListBlockedUsers(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+	response = c.usecase.ListBlockedUsers(userId, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ProfileController) MuteUser(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("targetUserId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't mute yourself")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.MuteUser(userId, targetUserId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ProfileController) UnmuteUser(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	targetUserId, err := strconv.ParseInt(ctx.Param("targetUserId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if userId == targetUserId {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Can't unmute yourself")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.UnmuteUser(userId, targetUserId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *ProfileController) ListMutedUsers(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+	response = c.usecase.ListMutedUsers(userId, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
 func (c *ProfileController) InsertUserWorkExperience(ctx *gin.Context) {
 	var (
 		response model.Response
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"database/sql"
+	"os"
+	"profiln-be/delivery/http"
+	"profiln-be/delivery/http/middleware"
+	"profiln-be/libs"
+	email "profiln-be/libs/email"
+	"profiln-be/model"
+	"profiln-be/package/admin"
+	repository "profiln-be/package/admin/repository"
+	"profiln-be/package/moderation"
+	moderationRepository "profiln-be/package/moderation/repository"
+	postsRepository "profiln-be/package/posts/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func NewAdminRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
+	smtpSender := os.Getenv("SENDER_NAME")
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	authEmail := os.Getenv("AUTH_EMAIL")
+	authPassword := os.Getenv("AUTH_PASSWORD")
+
+	email := email.NewEmail(smtpPort, smtpSender, smtpHost, authEmail, authPassword, log)
+	googleBucket := libs.NewGoogleBucket(log)
+	moderationRepository := moderationRepository.NewModerationRepository(db)
+	postsRepository := postsRepository.NewPostsRepository(db)
+	moderationUsecase := moderation.NewModerationUsecase(moderationRepository, postsRepository, googleBucket, email, log)
+
+	repository := repository.NewAdminRepository(db)
+	usecase := admin.NewAdminUsecase(repository, moderationUsecase, log)
+	controller := http.NewAdminController(usecase)
+
+	app.Use(middleware.Authentication(moderationRepository, log))
+
+	admin := app.Group("admin")
+	admin.GET("/users", middleware.RequirePermission(repository, log, model.PermissionUsersRead), controller.ListUsers)
+	admin.GET("/users/:userId", middleware.RequirePermission(repository, log, model.PermissionUsersRead), controller.GetUser)
+	admin.POST("/users/:userId/roles", middleware.RequirePermission(repository, log, model.PermissionRolesAssign), controller.AssignRole)
+	admin.DELETE("/users/:userId/roles/:role", middleware.RequirePermission(repository, log, model.PermissionRolesAssign), controller.RevokeRole)
+	admin.GET("/roles", middleware.RequirePermission(repository, log, model.PermissionRolesAssign), controller.ListRoles)
+	admin.POST("/catalogs/:catalog", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.InsertCatalogItem)
+	admin.PUT("/catalogs/:catalog/:itemId", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.UpdateCatalogItem)
+	admin.DELETE("/catalogs/:catalog/:itemId", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.DeleteCatalogItem)
+	admin.POST("/catalogs/:catalog/:itemId/merge", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.MergeCatalogItems)
+	admin.PUT("/skills/:skillId/parent", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.SetSkillParent)
+	admin.POST("/skills/:skillId/synonyms", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.InsertSkillSynonym)
+	admin.DELETE("/skills/:skillId/synonyms/:synonymId", middleware.RequirePermission(repository, log, model.PermissionCatalogsManage), controller.DeleteSkillSynonym)
+	admin.GET("/reports", middleware.RequirePermission(repository, log, model.PermissionReportsManage), controller.ListReports)
+	admin.POST("/reports/:reportId/actions", middleware.RequirePermission(repository, log, model.PermissionReportsManage), controller.ActOnReport)
+	admin.GET("/audit-logs", middleware.RequirePermission(repository, log, model.PermissionAuditLogsRead), controller.ListAuditLogs)
+}
@@ -1,11 +1,15 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"os"
 	"profiln-be/delivery/http"
 	"profiln-be/delivery/http/middleware"
 	"profiln-be/package/data"
 	repository "profiln-be/package/data/repository"
+	moderationRepository "profiln-be/package/moderation/repository"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -16,10 +20,25 @@ func NewDataRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
 	usecase := data.NewDataUsecase(repository, log)
 	controller := http.NewDataController(usecase)
 
-	app.Use(middleware.Authentication())
+	accountStates := moderationRepository.NewModerationRepository(db)
+	app.Use(middleware.Authentication(accountStates, log))
 	app.GET("/schools", controller.GetSchools)
 	app.GET("/companies", controller.GetCompanies)
 	app.GET("/issuing-organizations", controller.GetIssuingOrganizations)
 	app.GET("/skills", controller.GetSkills)
+	app.GET("/skills/:skillId", controller.GetSkillTaxonomy)
 	app.GET("/job-positions", controller.GetJobPositions)
 }
+
+// RefreshCatalogPopularity rebuilds the counts the typeahead search ranks by until ctx is done
+func RefreshCatalogPopularity(ctx context.Context, db *sql.DB, log *logrus.Logger) {
+	repository := repository.NewDataRepository(db)
+	usecase := data.NewDataUsecase(repository, log)
+
+	// Catalog popularity is rebuilt this often
+	refreshInterval, err := time.ParseDuration(os.Getenv("CATALOG_POPULARITY_REFRESH_INTERVAL"))
+	if err != nil || refreshInterval <= 0 {
+		refreshInterval = time.Hour
+	}
+	usecase.RefreshCatalogPopularityEvery(ctx, refreshInterval)
+}
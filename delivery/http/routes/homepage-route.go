@@ -1,11 +1,16 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"os"
 	"profiln-be/delivery/http"
 	"profiln-be/delivery/http/middleware"
+	"profiln-be/libs"
 	"profiln-be/package/homepage"
 	repository "profiln-be/package/homepage/repository"
+	moderationRepository "profiln-be/package/moderation/repository"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -13,10 +18,54 @@ import (
 
 func NewHomepageRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
 	repository := repository.NewHomepageRepository(db)
-	usecase := homepage.NewHomepageUsecase(repository, log)
+	usecase := homepage.NewHomepageUsecase(repository, log, newFeedRanker(), newFollowRecommender())
 	controller := http.NewHomepageController(usecase)
 
-	app.Use(middleware.Authentication())
+	accountStates := moderationRepository.NewModerationRepository(db)
+	app.Use(middleware.Authentication(accountStates, log))
 	app.GET("/posts", controller.ListPosts)
 	app.GET("/users/me/follow-recommendations", controller.ListFollowsRecommendation)
+	app.POST("/users/me/follow-recommendations/:userId/dismiss", controller.DismissFollowRecommendation)
+	app.GET("/trending/posts", controller.ListTrendingPosts)
+	app.GET("/trending/topics", controller.ListTrendingTopics)
+}
+
+// RefreshTrending recomputes the trending posts and topics until ctx is done
+func RefreshTrending(ctx context.Context, db *sql.DB, log *logrus.Logger) {
+	repository := repository.NewHomepageRepository(db)
+	usecase := homepage.NewHomepageUsecase(repository, log, newFeedRanker(), newFollowRecommender())
+
+	// Trending posts and topics are recomputed this often
+	refreshInterval, err := time.ParseDuration(os.Getenv("TRENDING_REFRESH_INTERVAL"))
+	if err != nil || refreshInterval <= 0 {
+		refreshInterval = 5 * time.Minute
+	}
+	usecase.RefreshTrendingEvery(ctx, refreshInterval)
+}
+
+// newFeedRanker builds the for you ranking, follows weigh the most while
+// engagement velocity lets popular posts from strangers surface
+func newFeedRanker() libs.IFeedRanker {
+	return libs.NewFeedRanker(
+		libs.NewFollowScorer("follow", 3),
+		libs.NewSecondDegreeScorer("second_degree", 1.5, 5),
+		libs.NewSharedSkillsScorer("shared_skills", 1, 5),
+		libs.NewSharedCompaniesScorer("shared_companies", 1, 2),
+		libs.NewAuthorAffinityScorer("author_affinity", 2, 10),
+		libs.NewEngagementVelocityScorer("engagement_velocity", 2, 24*time.Hour),
+	)
+}
+
+// newFollowRecommender builds the follow recommendations, mutual follows weigh the most
+// while popularity only orders users the viewer has nothing in common with
+func newFollowRecommender() libs.IFollowRecommender {
+	return libs.NewFollowRecommender(
+		libs.NewMutualFollowsSignal("mutual_follows", 3, 5),
+		libs.NewSharedCompanySignal("shared_company", 2),
+		libs.NewSharedSchoolSignal("shared_school", 1.5),
+		libs.NewSharedSkillsSignal("shared_skills", 1.5, 5),
+		libs.NewSharedJobInterestsSignal("shared_job_interests", 1, 3),
+		libs.NewSharedLocationSignal("shared_location", 0.5),
+		libs.NewPopularitySignal("popularity", 0.25, 1000),
+	)
 }
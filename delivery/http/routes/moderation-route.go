@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"database/sql"
+	"os"
+	"profiln-be/delivery/http"
+	"profiln-be/delivery/http/middleware"
+	"profiln-be/libs"
+	email "profiln-be/libs/email"
+	"profiln-be/model"
+	adminRepository "profiln-be/package/admin/repository"
+	"profiln-be/package/moderation"
+	repository "profiln-be/package/moderation/repository"
+	postsRepository "profiln-be/package/posts/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func NewModerationRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
+	smtpSender := os.Getenv("SENDER_NAME")
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	authEmail := os.Getenv("AUTH_EMAIL")
+	authPassword := os.Getenv("AUTH_PASSWORD")
+
+	email := email.NewEmail(smtpPort, smtpSender, smtpHost, authEmail, authPassword, log)
+	googleBucket := libs.NewGoogleBucket(log)
+	repository := repository.NewModerationRepository(db)
+	postsRepository := postsRepository.NewPostsRepository(db)
+	usecase := moderation.NewModerationUsecase(repository, postsRepository, googleBucket, email, log)
+	controller := http.NewModerationController(usecase)
+
+	permissions := adminRepository.NewAdminRepository(db)
+	app.Use(middleware.Authentication(repository, log))
+
+	moderation := app.Group("moderation", middleware.RequirePermission(permissions, log, model.PermissionReportsManage))
+	moderation.GET("/reports", controller.ListReportedContent)
+	moderation.GET("/reports/posts/:postId", controller.GetReportedPost)
+	moderation.POST("/reports/posts/:postId/actions", controller.ActOnReportedPost)
+	moderation.POST("/reports/comments/:commentId/actions", controller.ActOnReportedComment)
+	moderation.POST("/reports/replies/:replyId/actions", controller.ActOnReportedReply)
+	moderation.POST("/reports/users/:targetUserId/actions", controller.ActOnReportedUser)
+	moderation.GET("/flags", controller.ListContentFlags)
+	moderation.POST("/flags/:flagId/actions", controller.ActOnContentFlag)
+	moderation.PUT("/users/:targetUserId/account-state", controller.SetAccountState)
+}
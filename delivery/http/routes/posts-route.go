@@ -2,52 +2,131 @@ package routes
 
 import (
 	"database/sql"
+	"os"
 	"profiln-be/delivery/http"
 	"profiln-be/delivery/http/middleware"
 	"profiln-be/libs"
+	"profiln-be/model"
+	moderationRepository "profiln-be/package/moderation/repository"
 	"profiln-be/package/posts"
 	repository "profiln-be/package/posts/repository"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-func NewPostsRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
+func NewPostsRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger, background *sync.WaitGroup) {
 	twoMegaBytes := 2 * 1024 * 1024
 	imageFormats := []string{".png", ".jpg"}
 
 	fileSystem := libs.NewFileSystem()
 	googleBucket := libs.NewGoogleBucket(log)
+	linkPreviewFetcher := libs.NewLinkPreviewFetcher()
+
+	// Posts are hidden once this many users reported them
+	reportHideThreshold, err := strconv.Atoi(os.Getenv("REPORT_HIDE_THRESHOLD"))
+	if err != nil || reportHideThreshold < 1 {
+		reportHideThreshold = 5
+	}
+
+	// Posts of authors with at least this many followers are merged into the timelines on read
+	fanOutMaxFollowers, err := strconv.Atoi(os.Getenv("TIMELINE_FANOUT_MAX_FOLLOWERS"))
+	if err != nil || fanOutMaxFollowers < 1 {
+		fanOutMaxFollowers = 5000
+	}
+
+	accountStates := moderationRepository.NewModerationRepository(db)
 	repository := repository.NewPostsRepository(db)
-	usecase := posts.NewPostsUsecase(repository, log, googleBucket, fileSystem)
+	usecase := posts.NewPostsUsecase(repository, log, googleBucket, fileSystem, linkPreviewFetcher, reportHideThreshold, newContentPolicy(), fanOutMaxFollowers, accountStates, background)
 	controller := http.NewPostsController(usecase)
 
-	app.Use(middleware.Authentication())
+	app.Use(middleware.Authentication(accountStates, log))
 
 	app.GET("/users/:userId/posts", controller.ListNewestPostsByTargetUser)
 	app.GET("/users/:userId/posts/like", controller.ListLikedPostsByTargetUser)
 	app.GET("/users/:userId/posts/repost", controller.ListRepostedPostsByTargetUser)
+	app.POST("/users/:targetUserId/report", controller.ReportUser)
 
 	posts := app.Group("posts")
+	posts.POST("/impressions", controller.RecordPostImpressions)
 	posts.POST("/:postId/report", controller.ReportPost)
 	posts.GET("/:postId", controller.GetDetailPost)
 	posts.GET("/:postId/comments", controller.GetPostComments)
 	posts.GET("/:postId/comments/:postCommentId/replies", controller.GetPostCommentReplies)
 	posts.POST("/:postId/like", controller.LikePost)
 	posts.DELETE("/:postId/like", controller.UnlikePost)
+	posts.GET("/:postId/reactions", controller.ListPostReactions)
+	posts.POST("/:postId/reactions", controller.ReactPost)
+	posts.DELETE("/:postId/reactions", controller.UnlikePost)
+	posts.POST("/:postId/bookmark", controller.BookmarkPost)
+	posts.DELETE("/:postId/bookmark", controller.UnbookmarkPost)
+	posts.POST("/:postId/poll/votes", controller.VotePoll)
 	posts.POST("/:postId/repost", controller.RepostPost)
 	posts.POST("/:postId/unrepost", controller.UnrepostPost)
 	posts.POST("/:postId/comments", middleware.ValidateFileUpload(int64(twoMegaBytes), 1, imageFormats, fileSystem, log), controller.InsertPostComment)
+	posts.PATCH("/:postId/comments/:postCommentId", controller.UpdatePostComment)
+	posts.DELETE("/:postId/comments/:postCommentId", controller.DeletePostComment)
+	posts.POST("/:postId/comments/:postCommentId/report", controller.ReportPostComment)
 	posts.POST("/:postId/comments/:postCommentId/like", controller.LikePostComment)
 	posts.DELETE("/:postId/comments/:postCommentId/like", controller.UnlikePostComment)
+	posts.POST("/:postId/comments/:postCommentId/reactions", controller.ReactPostComment)
+	posts.DELETE("/:postId/comments/:postCommentId/reactions", controller.UnlikePostComment)
 	posts.POST("/:postId/comments/:postCommentId/replies", middleware.ValidateFileUpload(int64(twoMegaBytes), 1, imageFormats, fileSystem, log), controller.InsertPostCommentReply)
+	posts.PATCH("/:postId/comments/:postCommentId/replies/:postCommentReplyId", controller.UpdatePostCommentReply)
+	posts.DELETE("/:postId/comments/:postCommentId/replies/:postCommentReplyId", controller.DeletePostCommentReply)
+	posts.POST("/:postId/comments/:postCommentId/replies/:postCommentReplyId/report", controller.ReportPostCommentReply)
 	posts.POST("/:postId/comments/:postCommentId/replies/:postCommentReplyId/like", controller.LikePostCommentReply)
 	posts.DELETE("/:postId/comments/:postCommentId/replies/:postCommentReplyId/like", controller.UnlikePostCommentReply)
+	posts.POST("/:postId/comments/:postCommentId/replies/:postCommentReplyId/reactions", controller.ReactPostCommentReply)
+	posts.DELETE("/:postId/comments/:postCommentId/replies/:postCommentReplyId/reactions", controller.UnlikePostCommentReply)
 
 	myPosts := app.Group("users/me/posts")
 	myPosts.POST("/", controller.InsertPost)
 	myPosts.PATCH("/:postId", controller.UpdatePost)
 	myPosts.DELETE("/:postId", controller.DeletePost)
+	myPosts.GET("/:postId/views", controller.GetPostViewCounts)
 	myPosts.POST("/:postId/upload", middleware.ValidateFileUpload(int64(twoMegaBytes), 10, imageFormats, fileSystem, log), controller.UploadFileForInsertPost)
 	myPosts.PUT("/:postId/upload", middleware.ValidateFileUpload(int64(twoMegaBytes), 10, imageFormats, fileSystem, log), controller.UploadFileForUpdatePost)
+
+	myBookmarks := app.Group("users/me/bookmarks")
+	myBookmarks.GET("/", controller.ListBookmarkedPosts)
+	myBookmarks.GET("/folders", controller.ListBookmarkFolders)
+	myBookmarks.POST("/folders", controller.InsertBookmarkFolder)
+	myBookmarks.DELETE("/folders/:folderId", controller.DeleteBookmarkFolder)
+}
+
+// newContentPolicy builds the content policy from comma separated word and domain lists,
+// authors creating more than the velocity limit within the window are held for review
+func newContentPolicy() libs.IContentPolicy {
+	velocityLimit, err := strconv.Atoi(os.Getenv("CONTENT_POLICY_VELOCITY_LIMIT"))
+	if err != nil {
+		velocityLimit = 10
+	}
+
+	velocityWindow, err := time.ParseDuration(os.Getenv("CONTENT_POLICY_VELOCITY_WINDOW"))
+	if err != nil || velocityWindow <= 0 {
+		velocityWindow = 10 * time.Minute
+	}
+
+	return libs.NewContentPolicy(
+		libs.NewWordListRule("reject_words", splitEnvList("CONTENT_POLICY_REJECT_WORDS"), model.ContentVerdictReject),
+		libs.NewWordListRule("hold_words", splitEnvList("CONTENT_POLICY_HOLD_WORDS"), model.ContentVerdictHold),
+		libs.NewLinkDomainRule("blocked_domains", splitEnvList("CONTENT_POLICY_BLOCKED_DOMAINS"), model.ContentVerdictReject),
+		libs.NewVelocityRule("velocity", velocityLimit, velocityWindow, model.ContentVerdictHold),
+	)
+}
+
+func splitEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
 }
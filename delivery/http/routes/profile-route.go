@@ -5,6 +5,7 @@ import (
 	"profiln-be/delivery/http"
 	"profiln-be/delivery/http/middleware"
 	"profiln-be/libs"
+	moderationRepository "profiln-be/package/moderation/repository"
 	"profiln-be/package/profile"
 	repository "profiln-be/package/profile/repository"
 
@@ -19,11 +20,12 @@ func NewProfileRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
 
 	fileSystem := libs.NewFileSystem()
 	googleBucket := libs.NewGoogleBucket(log)
+	accountStates := moderationRepository.NewModerationRepository(db)
 	repository := repository.NewProfileRepository(db)
-	usecase := profile.NewProfileUsecase(repository, log, googleBucket, fileSystem)
+	usecase := profile.NewProfileUsecase(repository, log, googleBucket, fileSystem, accountStates)
 	controller := http.NewProfileController(usecase)
 
-	app.Use(middleware.Authentication())
+	app.Use(middleware.Authentication(accountStates, log))
 
 	me := app.Group("users/me")
 	me.POST("/skills", controller.InsertUserSkills)
@@ -43,6 +45,8 @@ func NewProfileRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
 	me.POST("/educations", middleware.ValidateFileUpload(int64(twoMegaBytes), 3, imageAndDocumentFormats, fileSystem, log), controller.InsertUserEducation)
 	me.POST("/certificates", controller.InsertUserCertificate)
 	me.GET("/", controller.GetUserBasicInformation)
+	me.GET("/blocks", controller.ListBlockedUsers)
+	me.GET("/mutes", controller.ListMutedUsers)
 
 	users := app.Group("users")
 	users.GET("/:userId/profile", controller.GetUserProfile)
@@ -52,4 +56,8 @@ func NewProfileRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
 	users.GET("/:userId/followings", controller.GetFollowedUsersByUser)
 	users.POST("/:targetUserId/follow", controller.FollowUser)
 	users.DELETE("/:targetUserId/follow", controller.UnfollowUser)
+	users.POST("/:targetUserId/block", controller.BlockUser)
+	users.DELETE("/:targetUserId/block", controller.UnblockUser)
+	users.POST("/:targetUserId/mute", controller.MuteUser)
+	users.DELETE("/:targetUserId/mute", controller.UnmuteUser)
 }
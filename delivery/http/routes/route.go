@@ -3,19 +3,23 @@ package routes
 import (
 	"database/sql"
 	"profiln-be/delivery/http/middleware"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-func NewRoute(app *gin.Engine, db *sql.DB, log *logrus.Logger) {
+func NewRoute(app *gin.Engine, db *sql.DB, log *logrus.Logger, background *sync.WaitGroup) {
 	app.Use(middleware.CORS())
 
 	v1 := app.Group("/api/v1")
 
 	NewAuthRoute(v1, db, log)
 	NewHomepageRoute(v1, db, log)
-	NewPostsRoute(v1, db, log)
+	NewPostsRoute(v1, db, log, background)
 	NewProfileRoute(v1, db, log)
 	NewDataRoute(v1, db, log)
+	NewModerationRoute(v1, db, log)
+	NewAdminRoute(v1, db, log)
+	NewSearchRoute(v1, db, log)
 }
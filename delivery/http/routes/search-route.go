@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"database/sql"
+	"profiln-be/delivery/http"
+	"profiln-be/delivery/http/middleware"
+	"profiln-be/model"
+	adminRepository "profiln-be/package/admin/repository"
+	dataRepository "profiln-be/package/data/repository"
+	moderationRepository "profiln-be/package/moderation/repository"
+	postsRepository "profiln-be/package/posts/repository"
+	"profiln-be/package/search"
+	repository "profiln-be/package/search/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func NewSearchRoute(app *gin.RouterGroup, db *sql.DB, log *logrus.Logger) {
+	repository := repository.NewSearchRepository(db)
+	postsRepository := postsRepository.NewPostsRepository(db)
+	dataRepository := dataRepository.NewDataRepository(db)
+	usecase := search.NewSearchUsecase(repository, postsRepository, dataRepository, log)
+	controller := http.NewSearchController(usecase)
+
+	accountStates := moderationRepository.NewModerationRepository(db)
+	permissions := adminRepository.NewAdminRepository(db)
+	app.Use(middleware.Authentication(accountStates, log))
+
+	search := app.Group("search")
+	search.GET("", controller.Search)
+	search.GET("/more", controller.SearchMore)
+	search.GET("/posts", controller.SearchPosts)
+	search.GET("/users", controller.SearchUsers)
+
+	talent := search.Group("talent", middleware.RequirePermission(permissions, log, model.PermissionTalentSearch))
+	talent.POST("", controller.SearchTalent)
+	talent.GET("/saved", controller.ListSavedTalentSearches)
+	talent.POST("/saved", controller.SaveTalentSearch)
+	talent.GET("/saved/:savedSearchId/results", controller.RunSavedTalentSearch)
+	talent.DELETE("/saved/:savedSearchId", controller.DeleteSavedTalentSearch)
+}
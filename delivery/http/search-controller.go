@@ -0,0 +1,454 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"profiln-be/libs"
+	"profiln-be/model"
+	"profiln-be/package/search"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+type ISearchController interface {
+	Search(ctx *gin.Context)
+	SearchMore(ctx *gin.Context)
+	SearchPosts(ctx *gin.Context)
+	SearchUsers(ctx *gin.Context)
+	SearchTalent(ctx *gin.Context)
+	SaveTalentSearch(ctx *gin.Context)
+	ListSavedTalentSearches(ctx *gin.Context)
+	RunSavedTalentSearch(ctx *gin.Context)
+	DeleteSavedTalentSearch(ctx *gin.Context)
+}
+
+type SearchController struct {
+	usecase search.ISearchUsecase
+}
+
+func NewSearchController(usecase search.ISearchUsecase) ISearchController {
+	return &SearchController{
+		usecase,
+	}
+}
+
+func (c *SearchController) Search(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	q := strings.TrimSpace(ctx.Query("q"))
+	if q == "" || len(q) > 200 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	// limit is the size of each group
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
+	if err != nil || limit <= 0 || limit > 20 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.Search(userId, q, limit)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) SearchMore(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	cursor, err := libs.DecodeSearchCursor(ctx.Query("cursor"))
+	if err != nil || len(cursor.Query) > 200 || cursor.Limit > 20 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.SearchMore(userId, cursor)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) SearchPosts(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" || len(query) > 200 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	filter := model.PostSearchFilter{
+		Query: query,
+	}
+
+	filter.AuthorId, err = optionalQueryId(ctx, "authorId")
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	// Dates are whole days, so "to" includes the posts made on that day
+	if from := ctx.Query("from"); from != "" {
+		fromDate, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+		filter.From = &fromDate
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		toDate, err := time.Parse(time.DateOnly, to)
+		if err != nil || (filter.From != nil && toDate.Before(*filter.From)) {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+		toDate = toDate.AddDate(0, 0, 1)
+		filter.To = &toDate
+	}
+
+	if hashtag := ctx.Query("hashtag"); hashtag != "" {
+		var ok bool
+		filter.Hashtag, ok = libs.NormalizeHashtag(hashtag)
+		if !ok {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.SearchPosts(userId, filter, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) SearchUsers(ctx *gin.Context) {
+	var response model.Response
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	filter := model.UserSearchFilter{
+		Name:     strings.TrimSpace(ctx.Query("q")),
+		Location: strings.TrimSpace(ctx.Query("location")),
+	}
+
+	ids := map[string]*int64{
+		"skillId":       &filter.SkillId,
+		"companyId":     &filter.CompanyId,
+		"schoolId":      &filter.SchoolId,
+		"jobPositionId": &filter.JobPositionId,
+	}
+	for key, id := range ids {
+		*id, err = optionalQueryId(ctx, key)
+		if err != nil {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+	}
+
+	if openToWork := ctx.Query("openToWork"); openToWork != "" {
+		value, err := strconv.ParseBool(openToWork)
+		if err != nil {
+			response.Status =
+				libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+			ctx.JSON(response.Status.Code, response)
+			return
+		}
+		filter.OpenToWork = &value
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.SearchUsers(userId, filter, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) SearchTalent(ctx *gin.Context) {
+	var (
+		reqBody  model.TalentSearchFilter
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.SearchTalent(userId, &reqBody, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) SaveTalentSearch(ctx *gin.Context) {
+	var (
+		reqBody  model.SaveTalentSearchRequest
+		response model.Response
+	)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	if err := ctx.ShouldBind(&reqBody); err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Error parsing request body")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	validationErr := libs.ValidateRequest(reqBody)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+
+		response.Status =
+			libs.CustomResponse(http.StatusUnprocessableEntity, "Validation error")
+		response.Data = errResponse
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.SaveTalentSearch(userId, &reqBody)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) ListSavedTalentSearches(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	response := c.usecase.ListSavedTalentSearches(userId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) RunSavedTalentSearch(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	savedSearchId, err := strconv.ParseInt(ctx.Param("savedSearchId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	if page <= 0 || limit <= 0 {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request query")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	pagination := model.PaginationRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	response = c.usecase.RunSavedTalentSearch(userId, savedSearchId, pagination)
+	ctx.JSON(response.Status.Code, response)
+}
+
+func (c *SearchController) DeleteSavedTalentSearch(ctx *gin.Context) {
+	var response model.Response
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userId := int64(userData["id"].(float64))
+
+	savedSearchId, err := strconv.ParseInt(ctx.Param("savedSearchId"), 10, 64)
+	if err != nil {
+		response.Status =
+			libs.CustomResponse(http.StatusBadRequest, "Invalid request param")
+
+		ctx.JSON(response.Status.Code, response)
+		return
+	}
+
+	response = c.usecase.DeleteSavedTalentSearch(userId, savedSearchId)
+	ctx.JSON(response.Status.Code, response)
+}
+
+// optionalQueryId parses an id filter, a missing filter is 0
+func optionalQueryId(ctx *gin.Context, key string) (int64, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if id <= 0 {
+		return 0, fmt.Errorf("invalid id %d", id)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,34 @@
+package libs
+
+import (
+	"net/http"
+	"profiln-be/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IAccountStateChecker is implemented by the moderation repository, it is shared by
+// the authentication middleware and the usecases restricting suspended and banned users
+type IAccountStateChecker interface {
+	GetAccountState(userId int64) (model.AccountState, error)
+}
+
+// RestrictedResponse is set when the user's account state does not allow publishing or interacting
+func RestrictedResponse(checker IAccountStateChecker, log *logrus.Logger, userId int64) *model.Response {
+	accountState, err := checker.GetAccountState(userId)
+	if err != nil {
+		log.Errorf("checker.GetAccountState (user id: %d): %v", userId, err)
+		return &model.Response{
+			Status: CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if accountState.State != model.AccountStateActive {
+		return &model.Response{
+			Status: CustomResponse(http.StatusForbidden, "Your account is "+accountState.State),
+			Data:   accountState,
+		}
+	}
+
+	return nil
+}
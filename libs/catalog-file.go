@@ -0,0 +1,163 @@
+package libs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"profiln-be/model"
+)
+
+const (
+	CatalogFormatCSV  = "csv"
+	CatalogFormatJSON = "json"
+)
+
+const catalogNameMaxLength = 100
+
+// CatalogMatchKey tells which names are the same catalog entry, skills only ignore case and spacing
+// since normalizing would turn "C++" and "C#" into "c"
+func CatalogMatchKey(catalog, name string) string {
+	if catalog == model.CatalogSkills {
+		return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	}
+
+	return NormalizeCatalogName(name)
+}
+
+// ParseCatalogFile reads the names of a CSV file (the "name" column, else the first one)
+// or a JSON array of names or of objects with a name
+func ParseCatalogFile(r io.Reader, format string) ([]string, error) {
+	var names []string
+
+	switch format {
+	case CatalogFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("could not read csv: %w", err)
+		}
+
+		column := 0
+		if len(records) > 0 {
+			for i, cell := range records[0] {
+				if strings.EqualFold(strings.TrimSpace(cell), "name") {
+					column = i
+					records = records[1:]
+					break
+				}
+			}
+		}
+
+		for _, record := range records {
+			if column < len(record) {
+				names = append(names, record[column])
+			}
+		}
+	case CatalogFormatJSON:
+		var items []json.RawMessage
+		if err := json.NewDecoder(r).Decode(&items); err != nil {
+			return nil, fmt.Errorf("could not read json: %w", err)
+		}
+
+		for i, raw := range items {
+			var name string
+			if err := json.Unmarshal(raw, &name); err != nil {
+				var item model.CatalogItem
+				if err := json.Unmarshal(raw, &item); err != nil {
+					return nil, fmt.Errorf("item %d is neither a name nor an object with a name", i+1)
+				}
+				name = item.Name
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	parsed := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(name) > catalogNameMaxLength {
+			return nil, fmt.Errorf("name %q is longer than %d characters", name, catalogNameMaxLength)
+		}
+
+		parsed = append(parsed, name)
+	}
+
+	return parsed, nil
+}
+
+// WriteCatalogFile writes the items in a format ParseCatalogFile reads back
+func WriteCatalogFile(w io.Writer, format string, items []model.CatalogItem) error {
+	switch format {
+	case CatalogFormatCSV:
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"id", "name"})
+		for _, item := range items {
+			writer.Write([]string{fmt.Sprint(item.ID), item.Name})
+		}
+		writer.Flush()
+
+		return writer.Error()
+	case CatalogFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(items)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// DiffCatalogItems matches the imported names against the existing items, an exact name is unchanged,
+// a name matching an item by CatalogMatchKey renames it and anything else is inserted
+func DiffCatalogItems(catalog string, existing []model.CatalogItem, names []string) model.CatalogImportDiff {
+	var (
+		exact = make(map[string]bool, len(existing))
+		byKey = make(map[string]model.CatalogItem, len(existing))
+		seen  = make(map[string]bool, len(names))
+		diff  = model.CatalogImportDiff{Inserts: []string{}, Renames: []model.CatalogRename{}}
+	)
+
+	for _, item := range existing {
+		exact[item.Name] = true
+
+		key := CatalogMatchKey(catalog, item.Name)
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = item
+		}
+	}
+
+	for _, name := range names {
+		key := CatalogMatchKey(catalog, name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if exact[name] {
+			diff.Unchanged++
+			continue
+		}
+
+		if item, ok := byKey[key]; ok {
+			diff.Renames = append(diff.Renames, model.CatalogRename{ID: item.ID, From: item.Name, To: name})
+			continue
+		}
+
+		diff.Inserts = append(diff.Inserts, name)
+	}
+
+	return diff
+}
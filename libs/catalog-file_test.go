@@ -0,0 +1,127 @@
+package libs
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"profiln-be/model"
+)
+
+func TestParseCatalogFile(t *testing.T) {
+	testCases := []struct {
+		content  string
+		format   string
+		expected []string
+	}{
+		{"name\nGojek\n  Bank   Mandiri \n\nTokopedia\n", CatalogFormatCSV, []string{"Gojek", "Bank Mandiri", "Tokopedia"}},
+		{"id,name\n1,Gojek\n2,\"Astra, Tbk\"\n", CatalogFormatCSV, []string{"Gojek", "Astra, Tbk"}},
+		{"Gojek\nTokopedia\n", CatalogFormatCSV, []string{"Gojek", "Tokopedia"}},
+		{`["Gojek", " Tokopedia "]`, CatalogFormatJSON, []string{"Gojek", "Tokopedia"}},
+		{`[{"id": 1, "name": "Gojek"}, {"name": ""}]`, CatalogFormatJSON, []string{"Gojek"}},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseCatalogFile(strings.NewReader(tc.content), tc.format)
+		if err != nil {
+			t.Fatalf("expected: nil error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Fatalf("expected: %q, got: %q", tc.expected, got)
+		}
+	}
+
+	invalid := []struct {
+		content string
+		format  string
+	}{
+		{`[1, 2]`, CatalogFormatJSON},
+		{`{"name": "Gojek"}`, CatalogFormatJSON},
+		{"name\n" + strings.Repeat("a", 101), CatalogFormatCSV},
+		{"Gojek", "xml"},
+	}
+
+	for _, tc := range invalid {
+		if _, err := ParseCatalogFile(strings.NewReader(tc.content), tc.format); err == nil {
+			t.Fatalf("expected: error for %q, got: nil", tc.content)
+		}
+	}
+}
+
+func TestWriteCatalogFileRoundTrip(t *testing.T) {
+	items := []model.CatalogItem{{ID: 1, Name: "Gojek"}, {ID: 2, Name: "Astra, Tbk"}}
+	expected := []string{"Gojek", "Astra, Tbk"}
+
+	for _, format := range []string{CatalogFormatCSV, CatalogFormatJSON} {
+		var buf bytes.Buffer
+		if err := WriteCatalogFile(&buf, format, items); err != nil {
+			t.Fatalf("expected: nil error, got: %v", err)
+		}
+
+		got, err := ParseCatalogFile(&buf, format)
+		if err != nil {
+			t.Fatalf("expected: nil error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected: %q, got: %q", expected, got)
+		}
+	}
+}
+
+func TestDiffCatalogItems(t *testing.T) {
+	existing := []model.CatalogItem{
+		{ID: 1, Name: "Gojek"},
+		{ID: 2, Name: "PT Telkom Indonesia"},
+		{ID: 3, Name: "telkom indonesia"},
+	}
+	names := []string{"Gojek", "Telkom Indonesia (Persero) Tbk", "Tokopedia", "Tokopedia Inc", "telkom indonesia"}
+
+	got := DiffCatalogItems(model.CatalogCompanies, existing, names)
+	expected := model.CatalogImportDiff{
+		Inserts:   []string{"Tokopedia"},
+		Renames:   []model.CatalogRename{{ID: 2, From: "PT Telkom Indonesia", To: "Telkom Indonesia (Persero) Tbk"}},
+		Unchanged: 1,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %+v, got: %+v", expected, got)
+	}
+
+	// Applying the import leaves nothing to change
+	again := DiffCatalogItems(model.CatalogCompanies, []model.CatalogItem{
+		{ID: 1, Name: "Gojek"},
+		{ID: 2, Name: "Telkom Indonesia (Persero) Tbk"},
+		{ID: 4, Name: "Tokopedia"},
+	}, []string{"Gojek", "Telkom Indonesia (Persero) Tbk", "Tokopedia"})
+	if len(again.Inserts) != 0 || len(again.Renames) != 0 || again.Unchanged != 3 {
+		t.Fatalf("expected: 3 unchanged, got: %+v", again)
+	}
+
+	skills := DiffCatalogItems(model.CatalogSkills, []model.CatalogItem{{ID: 1, Name: "C++"}}, []string{"C#", "c++"})
+	if !reflect.DeepEqual(skills.Inserts, []string{"C#"}) || len(skills.Renames) != 1 {
+		t.Fatalf("expected: C# inserted and C++ renamed, got: %+v", skills)
+	}
+}
+
+func TestCatalogMatchKey(t *testing.T) {
+	testCases := []struct {
+		catalog  string
+		name     string
+		expected string
+	}{
+		{model.CatalogSkills, "  Machine   Learning ", "machine learning"},
+		{model.CatalogSkills, "C++", "c++"},
+		{model.CatalogSkills, "C#", "c#"},
+		{model.CatalogCompanies, "Google, Inc.", "google"},
+	}
+
+	for _, tc := range testCases {
+		got := CatalogMatchKey(tc.catalog, tc.name)
+		if got != tc.expected {
+			t.Fatalf("expected: %s, got: %s", tc.expected, got)
+		}
+	}
+}
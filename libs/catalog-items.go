@@ -0,0 +1,75 @@
+package libs
+
+import (
+	"context"
+	"fmt"
+	db "profiln-be/db/sqlc"
+	"profiln-be/model"
+)
+
+// InsertCatalogItem returns sql.ErrNoRows when an item with the same normalized name exists
+func InsertCatalogItem(ctx context.Context, qtx *db.Queries, catalog, name string) (model.CatalogItem, error) {
+	normalizedName := CatalogMatchKey(catalog, name)
+
+	switch catalog {
+	case model.CatalogSchools:
+		item, err := qtx.InsertCatalogSchool(ctx, db.InsertCatalogSchoolParams{Name: name, NormalizedName: normalizedName})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogCompanies:
+		item, err := qtx.InsertCatalogCompany(ctx, db.InsertCatalogCompanyParams{Name: name, NormalizedName: normalizedName})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogSkills:
+		item, err := qtx.InsertCatalogSkill(ctx, db.InsertCatalogSkillParams{Name: name, NormalizedName: normalizedName})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogJobPositions:
+		item, err := qtx.InsertCatalogJobPosition(ctx, db.InsertCatalogJobPositionParams{Name: name, NormalizedName: normalizedName})
+		return model.CatalogItem{ID: item.ID, Name: item.Name.String}, err
+	case model.CatalogIssuingOrganizations:
+		item, err := qtx.InsertCatalogIssuingOrganization(ctx, db.InsertCatalogIssuingOrganizationParams{Name: name, NormalizedName: normalizedName})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	}
+
+	return model.CatalogItem{}, fmt.Errorf("unknown catalog %q", catalog)
+}
+
+func UpdateCatalogItem(ctx context.Context, qtx *db.Queries, catalog string, itemId int64, name string) (model.CatalogItem, error) {
+	normalizedName := CatalogMatchKey(catalog, name)
+
+	switch catalog {
+	case model.CatalogSchools:
+		item, err := qtx.UpdateCatalogSchool(ctx, db.UpdateCatalogSchoolParams{Name: name, NormalizedName: normalizedName, ID: itemId})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogCompanies:
+		item, err := qtx.UpdateCatalogCompany(ctx, db.UpdateCatalogCompanyParams{Name: name, NormalizedName: normalizedName, ID: itemId})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogSkills:
+		item, err := qtx.UpdateCatalogSkill(ctx, db.UpdateCatalogSkillParams{Name: name, NormalizedName: normalizedName, ID: itemId})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogJobPositions:
+		item, err := qtx.UpdateCatalogJobPosition(ctx, db.UpdateCatalogJobPositionParams{Name: name, NormalizedName: normalizedName, ID: itemId})
+		return model.CatalogItem{ID: item.ID, Name: item.Name.String}, err
+	case model.CatalogIssuingOrganizations:
+		item, err := qtx.UpdateCatalogIssuingOrganization(ctx, db.UpdateCatalogIssuingOrganizationParams{Name: name, NormalizedName: normalizedName, ID: itemId})
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	}
+
+	return model.CatalogItem{}, fmt.Errorf("unknown catalog %q", catalog)
+}
+
+// DeleteCatalogItem returns the number of deleted rows
+func DeleteCatalogItem(ctx context.Context, qtx *db.Queries, catalog string, itemId int64) (int64, error) {
+	switch catalog {
+	case model.CatalogSchools:
+		return qtx.DeleteCatalogSchool(ctx, itemId)
+	case model.CatalogCompanies:
+		return qtx.DeleteCatalogCompany(ctx, itemId)
+	case model.CatalogSkills:
+		return qtx.DeleteCatalogSkill(ctx, itemId)
+	case model.CatalogJobPositions:
+		return qtx.DeleteCatalogJobPosition(ctx, itemId)
+	case model.CatalogIssuingOrganizations:
+		return qtx.DeleteCatalogIssuingOrganization(ctx, itemId)
+	}
+
+	return 0, fmt.Errorf("unknown catalog %q", catalog)
+}
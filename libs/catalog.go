@@ -0,0 +1,67 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"strings"
+	"unicode"
+)
+
+// Catalogs the admin API, the catalog command and the data import work on
+var catalogs = map[string]bool{
+	model.CatalogSchools:              true,
+	model.CatalogCompanies:            true,
+	model.CatalogSkills:               true,
+	model.CatalogJobPositions:         true,
+	model.CatalogIssuingOrganizations: true,
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Legal entity words that don't tell catalog entries apart ("Google LLC" is "Google")
+var catalogLegalWords = map[string]bool{
+	"inc":          true,
+	"incorporated": true,
+	"llc":          true,
+	"ltd":          true,
+	"limited":      true,
+	"corp":         true,
+	"corporation":  true,
+	"co":           true,
+	"company":      true,
+	"plc":          true,
+	"gmbh":         true,
+	"pt":           true,
+	"tbk":          true,
+	"cv":           true,
+	"persero":      true,
+}
+
+func IsCatalog(catalog string) bool {
+	return catalogs[catalog]
+}
+
+// NormalizeCatalogName lowercases the name, drops punctuation and legal entity words,
+// names made only of legal words are kept as they are
+func NormalizeCatalogName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if !catalogLegalWords[word] {
+			kept = append(kept, word)
+		}
+	}
+
+	if len(kept) == 0 {
+		return strings.Join(words, " ")
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// EscapeLikePattern escapes the LIKE wildcards so user input is matched literally
+func EscapeLikePattern(text string) string {
+	return likePatternEscaper.Replace(text)
+}
@@ -0,0 +1,53 @@
+package libs
+
+import "testing"
+
+func TestNormalizeCatalogName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Google", "google"},
+		{"google inc", "google"},
+		{"Google, Inc.", "google"},
+		{"Google LLC", "google"},
+		{"PT Telkom Indonesia (Persero) Tbk", "telkom indonesia"},
+		{"  Universitas   Gadjah-Mada ", "universitas gadjah mada"},
+		{"Co.", "co"},
+		{"!!!", ""},
+	}
+
+	for _, tc := range testCases {
+		got := NormalizeCatalogName(tc.name)
+		if got != tc.expected {
+			t.Fatalf("expected: %s, got: %s", tc.expected, got)
+		}
+	}
+}
+
+func TestIsCatalog(t *testing.T) {
+	testCases := []struct {
+		catalog  string
+		expected bool
+	}{
+		{"schools", true},
+		{"issuing-organizations", true},
+		{"issuing_organizations", false},
+		{"users", false},
+	}
+
+	for _, tc := range testCases {
+		got := IsCatalog(tc.catalog)
+		if got != tc.expected {
+			t.Fatalf("expected: %v, got: %v", tc.expected, got)
+		}
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	expected := `100\% pure\_go \\o/`
+	got := EscapeLikePattern(`100% pure_go \o/`)
+	if got != expected {
+		t.Fatalf("expected: %s, got: %s", expected, got)
+	}
+}
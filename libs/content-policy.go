@@ -0,0 +1,335 @@
+package libs
+
+import (
+	"html"
+	"net/url"
+	"profiln-be/model"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Characters commonly used to disguise letters
+var leetspeakReplacer = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"!", "i",
+	"|", "i",
+	"3", "e",
+	"4", "a",
+	"@", "a",
+	"5", "s",
+	"$", "s",
+	"7", "t",
+	"+", "t",
+	"8", "b",
+	"9", "g",
+)
+
+var (
+	markupTagRegex   = regexp.MustCompile(`<[^>]*>`)
+	contentLinkRegex = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+)
+
+var contentVerdictSeverity = map[string]int{
+	model.ContentVerdictAllow:  0,
+	model.ContentVerdictHold:   1,
+	model.ContentVerdictReject: 2,
+}
+
+type IContentRule interface {
+	Name() string
+	// Check returns the verdict of the rule for the given content
+	Check(input model.ContentPolicyInput) string
+}
+
+type IContentPolicy interface {
+	Evaluate(input model.ContentPolicyInput) model.ContentPolicyResult
+	// Lookback is how far back the author's recent activity is needed by the rules
+	Lookback() time.Duration
+}
+
+type ContentPolicy struct {
+	rules []IContentRule
+}
+
+func NewContentPolicy(rules ...IContentRule) IContentPolicy {
+	return &ContentPolicy{
+		rules: rules,
+	}
+}
+
+// Evaluate runs every rule and returns the strictest verdict along with the rules that raised it
+func (p *ContentPolicy) Evaluate(input model.ContentPolicyInput) model.ContentPolicyResult {
+	result := model.ContentPolicyResult{
+		Verdict: model.ContentVerdictAllow,
+	}
+
+	for _, rule := range p.rules {
+		verdict := rule.Check(input)
+		if verdict == model.ContentVerdictAllow {
+			continue
+		}
+
+		if contentVerdictSeverity[verdict] > contentVerdictSeverity[result.Verdict] {
+			result.Verdict = verdict
+			result.Rules = nil
+		}
+
+		if verdict == result.Verdict {
+			result.Rules = append(result.Rules, rule.Name())
+		}
+	}
+
+	return result
+}
+
+func (p *ContentPolicy) Lookback() time.Duration {
+	var lookback time.Duration
+
+	for _, rule := range p.rules {
+		if windowed, ok := rule.(interface{ Window() time.Duration }); ok && windowed.Window() > lookback {
+			lookback = windowed.Window()
+		}
+	}
+
+	return lookback
+}
+
+type WordListRule struct {
+	name    string
+	verdict string
+	words   map[string]bool
+	// collapsed maps the words with repeated letters collapsed to the length of the shortest listed word
+	collapsed map[string]int
+}
+
+// NewWordListRule keeps the words as written, only lowercased and without leetspeak, so that
+// collapsing repeated letters never turns a listed word into an ordinary one ("ass" into "as")
+func NewWordListRule(name string, words []string, verdict string) IContentRule {
+	normalized := make(map[string]bool)
+	collapsed := make(map[string]int)
+	for _, word := range words {
+		listWords, _ := splitContentWords(word)
+		for _, w := range listWords {
+			normalized[w] = true
+
+			length := len([]rune(w))
+			if n, ok := collapsed[collapseRepeatedLetters(w)]; !ok || length < n {
+				collapsed[collapseRepeatedLetters(w)] = length
+			}
+		}
+	}
+
+	return &WordListRule{
+		name:      name,
+		verdict:   verdict,
+		words:     normalized,
+		collapsed: collapsed,
+	}
+}
+
+func (r *WordListRule) Name() string {
+	return r.name
+}
+
+// Check matches whole words as written, or stretched out with repeated letters ("asss"),
+// spaced out letters may be surrounded by real one letter words
+func (r *WordListRule) Check(input model.ContentPolicyInput) string {
+	words, spacedRuns := splitContentWords(input.Text)
+	for _, word := range words {
+		if r.words[word] {
+			return r.verdict
+		}
+
+		if n, ok := r.collapsed[collapseRepeatedLetters(word)]; ok && len([]rune(word)) >= n {
+			return r.verdict
+		}
+	}
+
+	for _, run := range spacedRuns {
+		collapsed := collapseRepeatedLetters(run)
+		for word := range r.words {
+			if len(word) > 1 && (strings.Contains(run, word) || strings.Contains(collapsed, word)) {
+				return r.verdict
+			}
+		}
+	}
+
+	return model.ContentVerdictAllow
+}
+
+type LinkDomainRule struct {
+	name    string
+	verdict string
+	domains []string
+}
+
+func NewLinkDomainRule(name string, domains []string, verdict string) IContentRule {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+
+	return &LinkDomainRule{
+		name:    name,
+		verdict: verdict,
+		domains: normalized,
+	}
+}
+
+func (r *LinkDomainRule) Name() string {
+	return r.name
+}
+
+// Check also matches subdomains of a blocked domain
+func (r *LinkDomainRule) Check(input model.ContentPolicyInput) string {
+	for _, host := range ExtractLinkHosts(input.Text) {
+		for _, domain := range r.domains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return r.verdict
+			}
+		}
+	}
+
+	return model.ContentVerdictAllow
+}
+
+type VelocityRule struct {
+	name    string
+	verdict string
+	max     int
+	window  time.Duration
+}
+
+// NewVelocityRule creates a rule that triggers once the author already created max items within the window
+func NewVelocityRule(name string, max int, window time.Duration, verdict string) IContentRule {
+	return &VelocityRule{
+		name:    name,
+		verdict: verdict,
+		max:     max,
+		window:  window,
+	}
+}
+
+func (r *VelocityRule) Name() string {
+	return r.name
+}
+
+func (r *VelocityRule) Window() time.Duration {
+	return r.window
+}
+
+func (r *VelocityRule) Check(input model.ContentPolicyInput) string {
+	if r.max <= 0 {
+		return model.ContentVerdictAllow
+	}
+
+	since := input.Now.Add(-r.window)
+	count := 0
+	for _, createdAt := range input.RecentActivity {
+		if !createdAt.Before(since) {
+			count++
+		}
+	}
+
+	if count >= r.max {
+		return r.verdict
+	}
+
+	return model.ContentVerdictAllow
+}
+
+// NormalizeContentWords lowercases the text, undoes leetspeak and collapses repeated letters.
+// Letters that were spaced out are also returned joined as runs ("f r e e" becomes "fre")
+func NormalizeContentWords(text string) (words, spacedRuns []string) {
+	words, spacedRuns = splitContentWords(text)
+	for i, word := range words {
+		words[i] = collapseRepeatedLetters(word)
+	}
+	for i, run := range spacedRuns {
+		spacedRuns[i] = collapseRepeatedLetters(run)
+	}
+
+	return words, spacedRuns
+}
+
+// splitContentWords lowercases the text and undoes leetspeak, spaced out letters are also returned joined as runs
+func splitContentWords(text string) (words, spacedRuns []string) {
+	text = html.UnescapeString(markupTagRegex.ReplaceAllString(text, " "))
+	text = leetspeakReplacer.Replace(strings.ToLower(text))
+
+	tokens := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	var (
+		spaced      strings.Builder
+		spacedCount int
+	)
+
+	flushSpaced := func() {
+		if spacedCount > 1 {
+			spacedRuns = append(spacedRuns, spaced.String())
+		}
+		spaced.Reset()
+		spacedCount = 0
+	}
+
+	for _, token := range tokens {
+		words = append(words, token)
+
+		if len([]rune(token)) == 1 {
+			spaced.WriteString(token)
+			spacedCount++
+			continue
+		}
+
+		flushSpaced()
+	}
+	flushSpaced()
+
+	return words, spacedRuns
+}
+
+func collapseRepeatedLetters(word string) string {
+	var (
+		sb   strings.Builder
+		last rune
+	)
+
+	for i, r := range word {
+		if i > 0 && r == last {
+			continue
+		}
+
+		sb.WriteRune(r)
+		last = r
+	}
+
+	return sb.String()
+}
+
+// ExtractLinkHosts returns the lowercased hosts of the links found in the text
+func ExtractLinkHosts(text string) []string {
+	var hosts []string
+
+	for _, link := range contentLinkRegex.FindAllString(html.UnescapeString(text), -1) {
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+
+		parsedUrl, err := url.Parse(link)
+		if err != nil || parsedUrl.Hostname() == "" {
+			continue
+		}
+
+		hosts = append(hosts, strings.Trim(strings.ToLower(parsedUrl.Hostname()), "."))
+	}
+
+	return hosts
+}
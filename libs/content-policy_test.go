@@ -0,0 +1,115 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeContentWords(t *testing.T) {
+	testCases := []struct {
+		text               string
+		expectedWords      []string
+		expectedSpacedRuns []string
+	}{
+		{"Hello World", []string{"helo", "world"}, nil},
+		{"fr33 m0n3y", []string{"fre", "money"}, nil},
+		{"f r e e  money", []string{"f", "r", "e", "e", "money"}, []string{"fre"}},
+		{"s.p.a.m and <b>$pam</b>", []string{"s", "p", "a", "m", "and", "spam"}, []string{"spam"}},
+		{"spaaaam", []string{"spam"}, nil},
+		{"I am here", []string{"i", "am", "here"}, nil},
+	}
+
+	for _, tc := range testCases {
+		words, spacedRuns := NormalizeContentWords(tc.text)
+		if !reflect.DeepEqual(words, tc.expectedWords) {
+			t.Fatalf("expected: %v, got: %v", tc.expectedWords, words)
+		}
+
+		if !reflect.DeepEqual(spacedRuns, tc.expectedSpacedRuns) {
+			t.Fatalf("expected: %v, got: %v", tc.expectedSpacedRuns, spacedRuns)
+		}
+	}
+}
+
+func TestExtractLinkHosts(t *testing.T) {
+	text := `visit https://Evil.example.com/path, <a href="http://shop.test/?a=1&amp;b=2">shop</a> or www.Other.org`
+
+	expected := []string{"evil.example.com", "shop.test", "www.other.org"}
+	got := ExtractLinkHosts(text)
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+}
+
+func TestWordListRuleKeepsListedWords(t *testing.T) {
+	rule := NewWordListRule("reject_words", []string{"ass", "Hell"}, model.ContentVerdictReject)
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"as good as it gets", model.ContentVerdictAllow},
+		{"a class of its own", model.ContentVerdictAllow},
+		{"hello there", model.ContentVerdictAllow},
+		{"what the hell", model.ContentVerdictReject},
+		{"you 4ss", model.ContentVerdictReject},
+		{"you aaasss", model.ContentVerdictReject},
+		{"you asssss", model.ContentVerdictReject},
+		{"heeeelll no", model.ContentVerdictReject},
+		{"a s s", model.ContentVerdictReject},
+	}
+
+	for _, tc := range testCases {
+		if got := rule.Check(model.ContentPolicyInput{Text: tc.text}); got != tc.expected {
+			t.Fatalf("expected: %s for %q, got: %s", tc.expected, tc.text, got)
+		}
+	}
+}
+
+func TestContentPolicyEvaluate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := NewContentPolicy(
+		NewWordListRule("hold_words", []string{"crypto"}, model.ContentVerdictHold),
+		NewWordListRule("reject_words", []string{"scam"}, model.ContentVerdictReject),
+		NewLinkDomainRule("blocked_domains", []string{"evil.com"}, model.ContentVerdictReject),
+		NewVelocityRule("velocity", 2, 10*time.Minute, model.ContentVerdictHold),
+	)
+
+	testCases := []struct {
+		text          string
+		activity      []time.Time
+		expectedVerd  string
+		expectedRules []string
+	}{
+		{"a normal post", nil, model.ContentVerdictAllow, nil},
+		{"buy cRyPt0 now", nil, model.ContentVerdictHold, []string{"hold_words"}},
+		{"this is a 5 c 4 m", nil, model.ContentVerdictReject, []string{"reject_words"}},
+		{"crypto at https://www.evil.com", nil, model.ContentVerdictReject, []string{"blocked_domains"}},
+		{"see https://notevil.com", nil, model.ContentVerdictAllow, nil},
+		{"a normal post", []time.Time{now.Add(-time.Minute), now.Add(-5 * time.Minute)}, model.ContentVerdictHold, []string{"velocity"}},
+		{"a normal post", []time.Time{now.Add(-time.Minute), now.Add(-time.Hour)}, model.ContentVerdictAllow, nil},
+	}
+
+	for _, tc := range testCases {
+		got := policy.Evaluate(model.ContentPolicyInput{
+			Text:           tc.text,
+			RecentActivity: tc.activity,
+			Now:            now,
+		})
+
+		if got.Verdict != tc.expectedVerd {
+			t.Fatalf("expected: %s, got: %s", tc.expectedVerd, got.Verdict)
+		}
+
+		if !reflect.DeepEqual(got.Rules, tc.expectedRules) {
+			t.Fatalf("expected: %v, got: %v", tc.expectedRules, got.Rules)
+		}
+	}
+
+	if lookback := policy.Lookback(); lookback != 10*time.Minute {
+		t.Fatalf("expected: %v, got: %v", 10*time.Minute, lookback)
+	}
+}
@@ -0,0 +1,201 @@
+package libs
+
+import (
+	"math"
+	"profiln-be/model"
+	"sort"
+	"time"
+)
+
+// Ranked scores are stored in cursors with this precision
+const feedScoreScale = 1000
+
+type IFeedScorer interface {
+	Name() string
+	// Score returns the weighted contribution of the scorer to the candidate's rank
+	Score(candidate model.FeedCandidate, now time.Time) float64
+}
+
+type IFeedRanker interface {
+	Rank(candidates []model.FeedCandidate, now time.Time) []model.FeedCandidate
+}
+
+type FeedRanker struct {
+	scorers []IFeedScorer
+}
+
+func NewFeedRanker(scorers ...IFeedScorer) IFeedRanker {
+	return &FeedRanker{
+		scorers: scorers,
+	}
+}
+
+// Rank sums the scores of every scorer and sorts the candidates by score, newest first on ties
+func (r *FeedRanker) Rank(candidates []model.FeedCandidate, now time.Time) []model.FeedCandidate {
+	ranked := make([]model.FeedCandidate, len(candidates))
+	for i, candidate := range candidates {
+		candidate.Score = 0
+		for _, scorer := range r.scorers {
+			candidate.Score += scorer.Score(candidate, now)
+		}
+		ranked[i] = candidate
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		if !ranked[i].CreatedAt.Equal(ranked[j].CreatedAt) {
+			return ranked[i].CreatedAt.After(ranked[j].CreatedAt)
+		}
+		return ranked[i].PostID > ranked[j].PostID
+	})
+
+	return ranked
+}
+
+type FollowScorer struct {
+	name   string
+	weight float64
+}
+
+// NewFollowScorer creates a scorer that boosts posts of authors the viewer follows
+func NewFollowScorer(name string, weight float64) IFeedScorer {
+	return &FollowScorer{
+		name:   name,
+		weight: weight,
+	}
+}
+
+func (s *FollowScorer) Name() string {
+	return s.name
+}
+
+func (s *FollowScorer) Score(candidate model.FeedCandidate, now time.Time) float64 {
+	if candidate.IsFollowed {
+		return s.weight
+	}
+
+	return 0
+}
+
+type SaturatingScorer struct {
+	name       string
+	weight     float64
+	saturation int64
+	signal     func(candidate model.FeedCandidate) int64
+}
+
+// NewSaturatingScorer creates a scorer that grows linearly with the signal
+// and gives the full weight once the signal reaches saturation
+func NewSaturatingScorer(name string, weight float64, saturation int64, signal func(candidate model.FeedCandidate) int64) IFeedScorer {
+	return &SaturatingScorer{
+		name:       name,
+		weight:     weight,
+		saturation: saturation,
+		signal:     signal,
+	}
+}
+
+func NewSecondDegreeScorer(name string, weight float64, saturation int64) IFeedScorer {
+	return NewSaturatingScorer(name, weight, saturation, func(candidate model.FeedCandidate) int64 {
+		return candidate.MutualFollowings
+	})
+}
+
+func NewSharedSkillsScorer(name string, weight float64, saturation int64) IFeedScorer {
+	return NewSaturatingScorer(name, weight, saturation, func(candidate model.FeedCandidate) int64 {
+		return candidate.SharedSkills
+	})
+}
+
+func NewSharedCompaniesScorer(name string, weight float64, saturation int64) IFeedScorer {
+	return NewSaturatingScorer(name, weight, saturation, func(candidate model.FeedCandidate) int64 {
+		return candidate.SharedCompanies
+	})
+}
+
+func NewAuthorAffinityScorer(name string, weight float64, saturation int64) IFeedScorer {
+	return NewSaturatingScorer(name, weight, saturation, func(candidate model.FeedCandidate) int64 {
+		return candidate.AuthorInteractions
+	})
+}
+
+func (s *SaturatingScorer) Name() string {
+	return s.name
+}
+
+func (s *SaturatingScorer) Score(candidate model.FeedCandidate, now time.Time) float64 {
+	if s.saturation <= 0 {
+		return 0
+	}
+
+	signal := min(max(s.signal(candidate), 0), s.saturation)
+	return s.weight * float64(signal) / float64(s.saturation)
+}
+
+type EngagementVelocityScorer struct {
+	name     string
+	weight   float64
+	halfLife time.Duration
+}
+
+// NewEngagementVelocityScorer creates a scorer for engagement per hour since the post was created,
+// halved every halfLife so older posts need more engagement to keep up
+func NewEngagementVelocityScorer(name string, weight float64, halfLife time.Duration) IFeedScorer {
+	return &EngagementVelocityScorer{
+		name:     name,
+		weight:   weight,
+		halfLife: halfLife,
+	}
+}
+
+func (s *EngagementVelocityScorer) Name() string {
+	return s.name
+}
+
+func (s *EngagementVelocityScorer) Score(candidate model.FeedCandidate, now time.Time) float64 {
+	if s.halfLife <= 0 {
+		return 0
+	}
+
+	// Comments and reposts take more effort than a like
+	engagement := float64(candidate.LikeCount) + 2*float64(candidate.CommentCount) + 3*float64(candidate.RepostCount)
+	ageHours := max(now.Sub(candidate.CreatedAt).Hours(), 0)
+
+	// The offset keeps brand new posts from dividing by almost zero
+	velocity := engagement / (ageHours + 2)
+	decay := math.Pow(0.5, ageHours/s.halfLife.Hours())
+
+	return s.weight * math.Log1p(velocity) * decay
+}
+
+// RankedPageStart returns the index of the first candidate after the cursor. When the cursor's post
+// is no longer ranked, the page continues from the first candidate scored below the cursor
+func RankedPageStart(ranked []model.FeedCandidate, cursor *model.PageCursor) int {
+	if cursor == nil {
+		return 0
+	}
+
+	for i, candidate := range ranked {
+		if candidate.PostID == cursor.ID {
+			return i + 1
+		}
+	}
+
+	for i, candidate := range ranked {
+		if scaledFeedScore(candidate.Score) < cursor.Score {
+			return i
+		}
+	}
+
+	return len(ranked)
+}
+
+func RankedPageCursor(candidate model.FeedCandidate) *model.PageCursor {
+	return &model.PageCursor{Score: scaledFeedScore(candidate.Score), ID: candidate.PostID}
+}
+
+func scaledFeedScore(score float64) int64 {
+	return int64(math.Round(score * feedScoreScale))
+}
@@ -0,0 +1,104 @@
+package libs
+
+import (
+	"math"
+	"profiln-be/model"
+	"testing"
+	"time"
+)
+
+func TestFeedScorers(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	candidate := model.FeedCandidate{
+		PostID:             1,
+		CreatedAt:          now.Add(-22 * time.Hour),
+		LikeCount:          10,
+		CommentCount:       4,
+		RepostCount:        2,
+		IsFollowed:         true,
+		MutualFollowings:   2,
+		SharedSkills:       12,
+		SharedCompanies:    -1,
+		AuthorInteractions: 3,
+	}
+
+	testCases := []struct {
+		scorer   IFeedScorer
+		expected float64
+	}{
+		{NewFollowScorer("follow", 3), 3},
+		{NewSecondDegreeScorer("second_degree", 2, 4), 1},
+		{NewSharedSkillsScorer("shared_skills", 1.5, 5), 1.5},
+		{NewSharedCompaniesScorer("shared_companies", 1, 2), 0},
+		{NewAuthorAffinityScorer("author_affinity", 2, 0), 0},
+		// 24 engagement over 24 hours, halved once
+		{NewEngagementVelocityScorer("velocity", 2, 22*time.Hour), math.Log1p(1)},
+	}
+
+	for _, tc := range testCases {
+		got := tc.scorer.Score(candidate, now)
+		if math.Abs(got-tc.expected) > 1e-9 {
+			t.Fatalf("expected: %v, got: %v (%s)", tc.expected, got, tc.scorer.Name())
+		}
+	}
+
+	candidate.IsFollowed = false
+	if got := NewFollowScorer("follow", 3).Score(candidate, now); got != 0 {
+		t.Fatalf("expected: 0, got: %v", got)
+	}
+}
+
+func TestFeedRankerRank(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ranker := NewFeedRanker(
+		NewFollowScorer("follow", 3),
+		NewAuthorAffinityScorer("author_affinity", 2, 10),
+	)
+
+	candidates := []model.FeedCandidate{
+		{PostID: 1, CreatedAt: now.Add(-time.Hour)},
+		{PostID: 2, CreatedAt: now.Add(-2 * time.Hour), IsFollowed: true},
+		{PostID: 3, CreatedAt: now.Add(-3 * time.Hour), AuthorInteractions: 10},
+		{PostID: 4, CreatedAt: now.Add(-time.Hour)},
+	}
+
+	ranked := ranker.Rank(candidates, now)
+
+	expected := []int64{2, 3, 4, 1}
+	for i, id := range expected {
+		if ranked[i].PostID != id {
+			t.Fatalf("expected: %v, got: %+v", expected, ranked)
+		}
+	}
+
+	if ranked[0].Score != 3 || candidates[1].Score != 0 {
+		t.Fatalf("expected: score 3 on the ranked copy only, got: %v and %v", ranked[0].Score, candidates[1].Score)
+	}
+}
+
+func TestRankedPageStart(t *testing.T) {
+	ranked := []model.FeedCandidate{
+		{PostID: 5, Score: 3},
+		{PostID: 2, Score: 2.5},
+		{PostID: 9, Score: 1},
+	}
+
+	testCases := []struct {
+		cursor   *model.PageCursor
+		expected int
+	}{
+		{nil, 0},
+		{RankedPageCursor(ranked[0]), 1},
+		{RankedPageCursor(ranked[2]), 3},
+		// the cursor's post dropped out of the ranking
+		{&model.PageCursor{Score: 2000, ID: 7}, 2},
+		{&model.PageCursor{Score: 0, ID: 7}, 3},
+	}
+
+	for _, tc := range testCases {
+		got := RankedPageStart(ranked, tc.cursor)
+		if got != tc.expected {
+			t.Fatalf("expected: %d, got: %d (%+v)", tc.expected, got, tc.cursor)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package libs
+
+import (
+	"fmt"
+	"profiln-be/model"
+	"sort"
+)
+
+type IFollowSignal interface {
+	Name() string
+	// Score returns the weighted contribution of the signal to the candidate's rank
+	Score(candidate model.FollowCandidate) float64
+	// Reason explains the signal to the viewer when it scored the candidate
+	Reason(candidate model.FollowCandidate) string
+}
+
+type IFollowRecommender interface {
+	Recommend(candidates []model.FollowCandidate) []model.FollowCandidate
+}
+
+type FollowRecommender struct {
+	signals []IFollowSignal
+}
+
+func NewFollowRecommender(signals ...IFollowSignal) IFollowRecommender {
+	return &FollowRecommender{
+		signals: signals,
+	}
+}
+
+// Recommend sums the scores of every signal and sorts the candidates by score, most followed first on ties.
+// The reason of a candidate comes from the signal that contributed the most
+func (r *FollowRecommender) Recommend(candidates []model.FollowCandidate) []model.FollowCandidate {
+	recommended := make([]model.FollowCandidate, len(candidates))
+	for i, candidate := range candidates {
+		var topScore float64
+		candidate.Score = 0
+		candidate.Reason = ""
+
+		for _, signal := range r.signals {
+			score := signal.Score(candidate)
+			candidate.Score += score
+
+			if score > topScore {
+				topScore = score
+				candidate.Reason = signal.Reason(candidate)
+			}
+		}
+		recommended[i] = candidate
+	}
+
+	sort.SliceStable(recommended, func(i, j int) bool {
+		if recommended[i].Score != recommended[j].Score {
+			return recommended[i].Score > recommended[j].Score
+		}
+		if recommended[i].FollowersCount != recommended[j].FollowersCount {
+			return recommended[i].FollowersCount > recommended[j].FollowersCount
+		}
+		return recommended[i].User.ID < recommended[j].User.ID
+	})
+
+	return recommended
+}
+
+type FollowSignal struct {
+	name       string
+	weight     float64
+	saturation int64
+	signal     func(candidate model.FollowCandidate) int64
+	reason     func(candidate model.FollowCandidate) string
+}
+
+// NewFollowSignal creates a signal that grows linearly with the value of signal
+// and gives the full weight once it reaches saturation
+func NewFollowSignal(name string, weight float64, saturation int64, signal func(candidate model.FollowCandidate) int64, reason func(candidate model.FollowCandidate) string) IFollowSignal {
+	return &FollowSignal{
+		name:       name,
+		weight:     weight,
+		saturation: saturation,
+		signal:     signal,
+		reason:     reason,
+	}
+}
+
+func NewMutualFollowsSignal(name string, weight float64, saturation int64) IFollowSignal {
+	return NewFollowSignal(name, weight, saturation,
+		func(candidate model.FollowCandidate) int64 {
+			return candidate.MutualFollowings
+		},
+		func(candidate model.FollowCandidate) string {
+			return pluralize(candidate.MutualFollowings, "mutual connection")
+		},
+	)
+}
+
+func NewSharedCompanySignal(name string, weight float64) IFollowSignal {
+	return NewFollowSignal(name, weight, 1,
+		func(candidate model.FollowCandidate) int64 {
+			return presence(candidate.SharedCompany)
+		},
+		func(candidate model.FollowCandidate) string {
+			return "Also worked at " + candidate.SharedCompany
+		},
+	)
+}
+
+func NewSharedSchoolSignal(name string, weight float64) IFollowSignal {
+	return NewFollowSignal(name, weight, 1,
+		func(candidate model.FollowCandidate) int64 {
+			return presence(candidate.SharedSchool)
+		},
+		func(candidate model.FollowCandidate) string {
+			return "Also studied at " + candidate.SharedSchool
+		},
+	)
+}
+
+func NewSharedSkillsSignal(name string, weight float64, saturation int64) IFollowSignal {
+	return NewFollowSignal(name, weight, saturation,
+		func(candidate model.FollowCandidate) int64 {
+			return candidate.SharedSkills
+		},
+		func(candidate model.FollowCandidate) string {
+			return pluralize(candidate.SharedSkills, "shared skill")
+		},
+	)
+}
+
+func NewSharedJobInterestsSignal(name string, weight float64, saturation int64) IFollowSignal {
+	return NewFollowSignal(name, weight, saturation,
+		func(candidate model.FollowCandidate) int64 {
+			return candidate.SharedJobInterests
+		},
+		func(candidate model.FollowCandidate) string {
+			return "Interested in the same jobs"
+		},
+	)
+}
+
+func NewSharedLocationSignal(name string, weight float64) IFollowSignal {
+	return NewFollowSignal(name, weight, 1,
+		func(candidate model.FollowCandidate) int64 {
+			return presence(candidate.SharedLocation)
+		},
+		func(candidate model.FollowCandidate) string {
+			return "Also based in " + candidate.SharedLocation
+		},
+	)
+}
+
+// NewPopularitySignal favors most followed users, so new users without any other signal still get recommendations
+func NewPopularitySignal(name string, weight float64, saturation int64) IFollowSignal {
+	return NewFollowSignal(name, weight, saturation,
+		func(candidate model.FollowCandidate) int64 {
+			return int64(candidate.FollowersCount)
+		},
+		func(candidate model.FollowCandidate) string {
+			return "Popular on Profiln"
+		},
+	)
+}
+
+func (s *FollowSignal) Name() string {
+	return s.name
+}
+
+func (s *FollowSignal) Score(candidate model.FollowCandidate) float64 {
+	if s.saturation <= 0 {
+		return 0
+	}
+
+	signal := min(max(s.signal(candidate), 0), s.saturation)
+	return s.weight * float64(signal) / float64(s.saturation)
+}
+
+func (s *FollowSignal) Reason(candidate model.FollowCandidate) string {
+	return s.reason(candidate)
+}
+
+func presence(value string) int64 {
+	if value == "" {
+		return 0
+	}
+
+	return 1
+}
+
+func pluralize(count int64, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,78 @@
+package libs
+
+import (
+	"math"
+	"profiln-be/model"
+	"testing"
+)
+
+func TestFollowSignals(t *testing.T) {
+	candidate := model.FollowCandidate{
+		FollowersCount:     50,
+		MutualFollowings:   3,
+		SharedCompany:      "Acme",
+		SharedSkills:       1,
+		SharedJobInterests: 4,
+	}
+
+	testCases := []struct {
+		signal         IFollowSignal
+		expectedScore  float64
+		expectedReason string
+	}{
+		{NewMutualFollowsSignal("mutual_follows", 3, 6), 1.5, "3 mutual connections"},
+		{NewSharedCompanySignal("shared_company", 2), 2, "Also worked at Acme"},
+		{NewSharedSchoolSignal("shared_school", 2), 0, "Also studied at "},
+		{NewSharedSkillsSignal("shared_skills", 1, 4), 0.25, "1 shared skill"},
+		{NewSharedJobInterestsSignal("shared_job_interests", 1, 2), 1, "Interested in the same jobs"},
+		{NewSharedLocationSignal("shared_location", 1), 0, "Also based in "},
+		{NewPopularitySignal("popularity", 0.5, 100), 0.25, "Popular on Profiln"},
+	}
+
+	for _, tc := range testCases {
+		got := tc.signal.Score(candidate)
+		if math.Abs(got-tc.expectedScore) > 1e-9 {
+			t.Fatalf("expected: %v, got: %v (%s)", tc.expectedScore, got, tc.signal.Name())
+		}
+
+		if reason := tc.signal.Reason(candidate); reason != tc.expectedReason {
+			t.Fatalf("expected: %q, got: %q", tc.expectedReason, reason)
+		}
+	}
+}
+
+func TestFollowRecommenderRecommend(t *testing.T) {
+	recommender := NewFollowRecommender(
+		NewMutualFollowsSignal("mutual_follows", 3, 5),
+		NewSharedCompanySignal("shared_company", 2),
+		NewPopularitySignal("popularity", 0.5, 1000),
+	)
+
+	candidates := []model.FollowCandidate{
+		{User: model.User{ID: 1}, FollowersCount: 10},
+		{User: model.User{ID: 2}, MutualFollowings: 5, SharedCompany: "Acme"},
+		{User: model.User{ID: 3}, SharedCompany: "Acme"},
+		{User: model.User{ID: 4}, FollowersCount: 10},
+	}
+
+	recommended := recommender.Recommend(candidates)
+
+	expected := []struct {
+		id     int64
+		reason string
+	}{
+		{2, "5 mutual connections"},
+		{3, "Also worked at Acme"},
+		{1, "Popular on Profiln"},
+		{4, "Popular on Profiln"},
+	}
+	for i, e := range expected {
+		if recommended[i].User.ID != e.id || recommended[i].Reason != e.reason {
+			t.Fatalf("expected: %+v, got: %+v", expected, recommended)
+		}
+	}
+
+	if candidates[1].Score != 0 || candidates[1].Reason != "" {
+		t.Fatalf("expected: candidates left unchanged, got: %+v", candidates[1])
+	}
+}
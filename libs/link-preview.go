@@ -0,0 +1,269 @@
+package libs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"profiln-be/model"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	linkPreviewTimeout      = 5 * time.Second
+	linkPreviewMaxBodyBytes = 1 << 20
+	linkPreviewMaxRedirects = 3
+)
+
+var errLinkPreviewForbiddenAddress = errors.New("destination address is not allowed")
+
+type ILinkPreviewFetcher interface {
+	Fetch(rawUrl string) (model.LinkPreview, error)
+}
+
+type LinkPreviewFetcher struct {
+	client *http.Client
+}
+
+func NewLinkPreviewFetcher() ILinkPreviewFetcher {
+	return &LinkPreviewFetcher{
+		client: NewSafeHTTPClient(linkPreviewTimeout),
+	}
+}
+
+// NewSafeHTTPClient creates an http client that refuses to connect to
+// loopback, private, link-local and other non public addresses
+func NewSafeHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// Checked on the resolved address to prevent DNS rebinding
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if port != "80" && port != "443" {
+				return errLinkPreviewForbiddenAddress
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || !IsPublicIP(ip) {
+				return errLinkPreviewForbiddenAddress
+			}
+
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= linkPreviewMaxRedirects {
+				return errors.New("too many redirects")
+			}
+
+			return validatePreviewUrl(req.URL)
+		},
+	}
+}
+
+// IsPublicIP reports whether the ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	// Carrier-grade NAT range (100.64.0.0/10)
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+
+	return true
+}
+
+func (f *LinkPreviewFetcher) Fetch(rawUrl string) (model.LinkPreview, error) {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return model.LinkPreview{}, fmt.Errorf("url.Parse: %w", err)
+	}
+
+	if err := validatePreviewUrl(parsedUrl); err != nil {
+		return model.LinkPreview{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), linkPreviewTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedUrl.String(), nil)
+	if err != nil {
+		return model.LinkPreview{}, fmt.Errorf("http.NewRequest: %w", err)
+	}
+	req.Header.Set("User-Agent", "ProfilnBot/1.0 (+link preview)")
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return model.LinkPreview{}, fmt.Errorf("client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return model.LinkPreview{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" {
+		return model.LinkPreview{}, fmt.Errorf("unexpected content type %q", mediaType)
+	}
+
+	preview := ParseOpenGraph(io.LimitReader(resp.Body, linkPreviewMaxBodyBytes))
+	preview.Url = rawUrl
+
+	return preview, nil
+}
+
+// ParseOpenGraph reads OpenGraph metadata from an html document,
+// falling back to the document title and meta description
+func ParseOpenGraph(r io.Reader) model.LinkPreview {
+	var (
+		preview         model.LinkPreview
+		documentTitle   string
+		metaDescription string
+		inTitle         bool
+	)
+
+	tokenizer := html.NewTokenizer(r)
+
+tokenLoop:
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if token.DataAtom == atom.Title {
+				inTitle = true
+				continue
+			}
+
+			// Metadata lives in the head, no need to read the whole body
+			if token.DataAtom == atom.Body {
+				break tokenLoop
+			}
+
+			if token.DataAtom != atom.Meta {
+				continue
+			}
+
+			var property, content string
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "property", "name":
+					property = strings.ToLower(attr.Val)
+				case "content":
+					content = strings.TrimSpace(attr.Val)
+				}
+			}
+
+			switch property {
+			case "og:title":
+				preview.Title = content
+			case "og:description":
+				preview.Description = content
+			case "og:image":
+				preview.ImageUrl = content
+			case "og:site_name":
+				preview.SiteName = content
+			case "description":
+				metaDescription = content
+			}
+		case html.EndTagToken:
+			if token.DataAtom == atom.Title {
+				inTitle = false
+			}
+		case html.TextToken:
+			if inTitle && documentTitle == "" {
+				documentTitle = strings.TrimSpace(token.Data)
+			}
+		}
+	}
+
+	if preview.Title == "" {
+		preview.Title = documentTitle
+	}
+
+	if preview.Description == "" {
+		preview.Description = metaDescription
+	}
+
+	// Only keep images that can be safely rendered by the client
+	if imageUrl, err := url.Parse(preview.ImageUrl); err != nil || imageUrl.Scheme != "https" {
+		preview.ImageUrl = ""
+	}
+
+	return preview
+}
+
+// LinkPreviewFromNullable builds a link preview from nullable query columns,
+// returning nil when the post has no link preview
+func LinkPreviewFromNullable(url, title, description, imageUrl, siteName sql.NullString) *model.LinkPreview {
+	if !url.Valid {
+		return nil
+	}
+
+	return &model.LinkPreview{
+		Url:         url.String,
+		Title:       title.String,
+		Description: description.String,
+		ImageUrl:    imageUrl.String,
+		SiteName:    siteName.String,
+	}
+}
+
+func validatePreviewUrl(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+
+	if u.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+
+	host := u.Hostname()
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return errLinkPreviewForbiddenAddress
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return errLinkPreviewForbiddenAddress
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package libs
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Tags that are kept as is, everything else is stripped and only its text is kept
+var allowedMarkupTags = map[atom.Atom]bool{
+	atom.B:      true,
+	atom.Strong: true,
+	atom.I:      true,
+	atom.Em:     true,
+	atom.Ul:     true,
+	atom.Ol:     true,
+	atom.Li:     true,
+	atom.A:      true,
+	atom.Code:   true,
+	atom.Pre:    true,
+	atom.P:      true,
+	atom.Br:     true,
+}
+
+// Tags whose content must be dropped entirely
+var droppedMarkupTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+}
+
+var (
+	plainLinkRegex = regexp.MustCompile(`https?://[^\s<>"']+`)
+	// A hashtag must not follow a word character, and "&" rules out escaped entities like "&#39;"
+	hashtagRegex     = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&])#(\p{L}[\p{L}\p{N}_]{0,49})`)
+	hashtagNameRegex = regexp.MustCompile(`^\p{L}[\p{L}\p{N}_]{0,49}$`)
+)
+
+// SanitizeMarkup keeps the limited safe markup allowed in posts
+// (bold, italic, lists, links and code) and escapes everything else
+func SanitizeMarkup(content string) string {
+	var (
+		sb          strings.Builder
+		openTags    []atom.Atom
+		droppedTags int
+	)
+
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedMarkupTags[token.DataAtom] {
+				if tokenType == html.StartTagToken {
+					droppedTags++
+				}
+				continue
+			}
+
+			if droppedTags > 0 || !allowedMarkupTags[token.DataAtom] {
+				continue
+			}
+
+			if token.DataAtom == atom.Br {
+				sb.WriteString("<br>")
+				continue
+			}
+
+			if token.DataAtom == atom.A {
+				href := safeLinkHref(token.Attr)
+				if href == "" {
+					continue
+				}
+
+				sb.WriteString(`<a href="`)
+				sb.WriteString(html.EscapeString(href))
+				sb.WriteString(`" rel="nofollow noopener noreferrer" target="_blank">`)
+			} else {
+				sb.WriteString("<" + token.DataAtom.String() + ">")
+			}
+
+			if tokenType == html.StartTagToken {
+				openTags = append(openTags, token.DataAtom)
+			}
+		case html.EndTagToken:
+			if droppedMarkupTags[token.DataAtom] {
+				if droppedTags > 0 {
+					droppedTags--
+				}
+				continue
+			}
+
+			if droppedTags > 0 {
+				continue
+			}
+
+			// Only close tags that were actually opened, in order
+			for i := len(openTags) - 1; i >= 0; i-- {
+				if openTags[i] != token.DataAtom {
+					continue
+				}
+
+				for j := len(openTags) - 1; j >= i; j-- {
+					sb.WriteString("</" + openTags[j].String() + ">")
+				}
+				openTags = openTags[:i]
+				break
+			}
+		case html.TextToken:
+			if droppedTags > 0 {
+				continue
+			}
+
+			sb.WriteString(html.EscapeString(token.Data))
+		}
+	}
+
+	// Close any dangling tags
+	for i := len(openTags) - 1; i >= 0; i-- {
+		sb.WriteString("</" + openTags[i].String() + ">")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// ExtractFirstLink returns the first http(s) link found in the content,
+// either from an anchor tag or from plain text
+func ExtractFirstLink(content string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return ""
+		}
+
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if token.DataAtom != atom.A {
+				continue
+			}
+
+			if href := safeLinkHref(token.Attr); strings.HasPrefix(href, "http") {
+				return href
+			}
+		case html.TextToken:
+			if link := plainLinkRegex.FindString(token.Data); link != "" {
+				return strings.TrimRight(link, ".,;:!?)")
+			}
+		}
+	}
+}
+
+// ExtractHashtags returns the unique lowercased hashtags in the content, in order of appearance
+func ExtractHashtags(content string) []string {
+	var (
+		hashtags []string
+		seen     = make(map[string]bool)
+	)
+
+	text := markupTagRegex.ReplaceAllString(content, " ")
+	for _, match := range hashtagRegex.FindAllStringSubmatch(text, -1) {
+		hashtag := strings.ToLower(match[1])
+		if seen[hashtag] {
+			continue
+		}
+
+		seen[hashtag] = true
+		hashtags = append(hashtags, hashtag)
+	}
+
+	return hashtags
+}
+
+// NormalizeHashtag lowercases a hashtag given with or without its leading "#",
+// it returns false when the hashtag could never be extracted from a post
+func NormalizeHashtag(hashtag string) (string, bool) {
+	hashtag = strings.TrimPrefix(strings.TrimSpace(hashtag), "#")
+	if !hashtagNameRegex.MatchString(hashtag) {
+		return "", false
+	}
+
+	return strings.ToLower(hashtag), true
+}
+
+// safeLinkHref returns the href attribute if it uses an allowed scheme
+func safeLinkHref(attrs []html.Attribute) string {
+	for _, attr := range attrs {
+		if attr.Key != "href" {
+			continue
+		}
+
+		parsedUrl, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil {
+			return ""
+		}
+
+		switch strings.ToLower(parsedUrl.Scheme) {
+		case "http", "https", "mailto":
+			return parsedUrl.String()
+		default:
+			return ""
+		}
+	}
+
+	return ""
+}
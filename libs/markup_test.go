@@ -0,0 +1,91 @@
+package libs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeMarkup(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected string
+	}{
+		{"<b>bold</b> and <i>italic</i>", "<b>bold</b> and <i>italic</i>"},
+		{"<ul><li>one</li><li>two</li></ul>", "<ul><li>one</li><li>two</li></ul>"},
+		{"<code>x := 1</code>", "<code>x := 1</code>"},
+		{"hello<script>alert(1)</script>", "hello"},
+		{`<img src="x" onerror="alert(1)">text`, "text"},
+		{`<b onclick="alert(1)">bold</b>`, "<b>bold</b>"},
+		{`<a href="javascript:alert(1)">link</a>`, "link"},
+		{`<a href="https://example.com">link</a>`, `<a href="https://example.com" rel="nofollow noopener noreferrer" target="_blank">link</a>`},
+		{"<b>unclosed", "<b>unclosed</b>"},
+		{"1 < 2 & 3 > 2", "1 &lt; 2 &amp; 3 &gt; 2"},
+	}
+
+	for _, tc := range testCases {
+		got := SanitizeMarkup(tc.content)
+		if got != tc.expected {
+			t.Fatalf("expected: %q, got: %q", tc.expected, got)
+		}
+	}
+}
+
+func TestExtractFirstLink(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected string
+	}{
+		{"no link here", ""},
+		{"check https://example.com/a, and https://example.org", "https://example.com/a"},
+		{`<a href="https://example.com/post">read</a> http://other.com`, "https://example.com/post"},
+		{`<a href="mailto:me@example.com">mail</a>`, ""},
+	}
+
+	for _, tc := range testCases {
+		got := ExtractFirstLink(tc.content)
+		if got != tc.expected {
+			t.Fatalf("expected: %q, got: %q", tc.expected, got)
+		}
+	}
+}
+
+func TestExtractHashtags(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected []string
+	}{
+		{"no hashtag here", nil},
+		{"#Golang and #golang at #work", []string{"golang", "work"}},
+		{"<p>#hiring</p><b>#remote</b>", []string{"hiring", "remote"}},
+		{"email@x.com#anchor a#b #123 #_x", nil},
+		{"it&#39;s #Kerja_Keras, #karier", []string{"kerja_keras", "karier"}},
+	}
+
+	for _, tc := range testCases {
+		got := ExtractHashtags(tc.content)
+		if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+			t.Fatalf("expected: %v, got: %v", tc.expected, got)
+		}
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	testCases := []struct {
+		hashtag  string
+		expected string
+		ok       bool
+	}{
+		{"#GoLang", "golang", true},
+		{"kerja_keras", "kerja_keras", true},
+		{"#123", "", false},
+		{"two words", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := NormalizeHashtag(tc.hashtag)
+		if got != tc.expected || ok != tc.ok {
+			t.Fatalf("expected: %q %v, got: %q %v", tc.expected, tc.ok, got, ok)
+		}
+	}
+}
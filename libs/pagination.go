@@ -0,0 +1,38 @@
+package libs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"profiln-be/model"
+)
+
+// EncodePageCursor returns an empty cursor when there is no next page
+func EncodePageCursor(cursor *model.PageCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePageCursor(text string) (*model.PageCursor, error) {
+	var cursor model.PageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode cursor: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("could not unmarshal cursor: %w", err)
+	}
+
+	if cursor.ID <= 0 {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return &cursor, nil
+}
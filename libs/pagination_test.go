@@ -0,0 +1,36 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"testing"
+	"time"
+)
+
+func TestPageCursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC)
+	cursor := &model.PageCursor{CreatedAt: &createdAt, Score: 12, ID: 42}
+
+	got, err := DecodePageCursor(EncodePageCursor(cursor))
+	if err != nil {
+		t.Fatalf("expected: nil, got: %v", err)
+	}
+
+	if got.ID != cursor.ID || got.Score != cursor.Score || got.CreatedAt == nil || !got.CreatedAt.Equal(createdAt) {
+		t.Fatalf("expected: %+v, got: %+v", cursor, got)
+	}
+
+	if text := EncodePageCursor(nil); text != "" {
+		t.Fatalf("expected: empty cursor, got: %q", text)
+	}
+
+	invalidCursors := []string{
+		"not base64!",
+		EncodePageCursor(&model.PageCursor{Name: "Go"}),
+	}
+
+	for _, text := range invalidCursors {
+		if _, err := DecodePageCursor(text); err == nil {
+			t.Fatalf("expected: error, got: nil (%q)", text)
+		}
+	}
+}
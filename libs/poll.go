@@ -0,0 +1,20 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"time"
+)
+
+// HidePollResults marks the poll as closed once it has ended and removes the
+// vote counts while the viewer has not voted on a poll that is still open
+func HidePollResults(poll *model.Poll, now time.Time) {
+	poll.IsClosed = !now.Before(poll.EndsAt)
+	if poll.IsClosed || poll.HasVoted {
+		return
+	}
+
+	poll.VoterCount = nil
+	for i := range poll.Options {
+		poll.Options[i].VoteCount = nil
+	}
+}
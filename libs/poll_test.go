@@ -0,0 +1,44 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"testing"
+	"time"
+)
+
+func TestHidePollResults(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		endsAt         time.Time
+		hasVoted       bool
+		expectedClosed bool
+		expectedHidden bool
+	}{
+		{now.Add(time.Hour), false, false, true},
+		{now.Add(time.Hour), true, false, false},
+		{now.Add(-time.Hour), false, true, false},
+		{now, false, true, false},
+	}
+
+	for _, tc := range testCases {
+		voterCount, voteCount := int32(3), int32(2)
+		poll := model.Poll{
+			EndsAt:     tc.endsAt,
+			HasVoted:   tc.hasVoted,
+			VoterCount: &voterCount,
+			Options:    []model.PollOption{{VoteCount: &voteCount}},
+		}
+
+		HidePollResults(&poll, now)
+
+		if poll.IsClosed != tc.expectedClosed {
+			t.Fatalf("expected: %v, got: %v", tc.expectedClosed, poll.IsClosed)
+		}
+
+		hidden := poll.VoterCount == nil && poll.Options[0].VoteCount == nil
+		if hidden != tc.expectedHidden {
+			t.Fatalf("expected: %v, got: %v", tc.expectedHidden, hidden)
+		}
+	}
+}
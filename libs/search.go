@@ -0,0 +1,51 @@
+package libs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"profiln-be/model"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// PrefixTsQuery turns free text into a to_tsquery expression where every word must match
+// and the last one may be incomplete, "golang develo" becomes "golang & develo:*"
+func PrefixTsQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) == 0 {
+		return ""
+	}
+
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
+
+func EncodeSearchCursor(cursor model.SearchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeSearchCursor(text string) (model.SearchCursor, error) {
+	var cursor model.SearchCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return cursor, fmt.Errorf("could not decode cursor: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("could not unmarshal cursor: %w", err)
+	}
+
+	if !slices.Contains(model.SearchGroups, cursor.Group) || cursor.Query == "" || cursor.Offset < 0 || cursor.Limit <= 0 {
+		return cursor, errors.New("invalid cursor")
+	}
+
+	return cursor, nil
+}
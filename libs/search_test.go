@@ -0,0 +1,50 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"testing"
+)
+
+func TestPrefixTsQuery(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"golang develo", "golang & develo:*"},
+		{"  Go!  ", "go:*"},
+		{"c++ & rust | 'x'", "c & rust & x:*"},
+		{"!!!", ""},
+	}
+
+	for _, tc := range testCases {
+		got := PrefixTsQuery(tc.text)
+		if got != tc.expected {
+			t.Fatalf("expected: %q, got: %q", tc.expected, got)
+		}
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	cursor := model.SearchCursor{Group: model.SearchGroupPosts, Query: "golang", Offset: 5, Limit: 5}
+
+	got, err := DecodeSearchCursor(EncodeSearchCursor(cursor))
+	if err != nil {
+		t.Fatalf("expected: nil, got: %v", err)
+	}
+
+	if got != cursor {
+		t.Fatalf("expected: %+v, got: %+v", cursor, got)
+	}
+
+	invalidCursors := []string{
+		"not base64!",
+		EncodeSearchCursor(model.SearchCursor{Group: "jobs", Query: "golang", Limit: 5}),
+		EncodeSearchCursor(model.SearchCursor{Group: model.SearchGroupPeople, Query: "golang", Offset: -1, Limit: 5}),
+	}
+
+	for _, text := range invalidCursors {
+		if _, err := DecodeSearchCursor(text); err == nil {
+			t.Fatalf("expected: error, got: nil (%q)", text)
+		}
+	}
+}
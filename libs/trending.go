@@ -0,0 +1,20 @@
+package libs
+
+import (
+	"profiln-be/model"
+	"time"
+)
+
+// TrendingWindows are the windows trending is computed over, keyed by their name in the api
+var TrendingWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+}
+
+// DefaultTrendingWindow is used when no window is requested
+const DefaultTrendingWindow = "24h"
+
+func TrendingPageCursor(postId int64, score float64) *model.PageCursor {
+	return &model.PageCursor{Score: scaledFeedScore(score), ID: postId}
+}
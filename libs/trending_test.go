@@ -0,0 +1,26 @@
+package libs
+
+import "testing"
+
+func TestTrendingPageCursor(t *testing.T) {
+	testCases := []struct {
+		score    float64
+		expected int64
+	}{
+		{0, 0},
+		{1.5, 1500},
+		{2.00049, 2000},
+		{2.0006, 2001},
+	}
+
+	for _, tc := range testCases {
+		cursor := TrendingPageCursor(7, tc.score)
+		if cursor.Score != tc.expected || cursor.ID != 7 {
+			t.Fatalf("expected: score %d of post 7, got: %+v", tc.expected, cursor)
+		}
+	}
+
+	if _, ok := TrendingWindows[DefaultTrendingWindow]; !ok {
+		t.Fatalf("expected: default window %q to be a trending window, got: none", DefaultTrendingWindow)
+	}
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"profiln-be/config"
+	"profiln-be/delivery/cli"
 	"profiln-be/delivery/http/routes"
+	dataRepository "profiln-be/package/data/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -17,6 +26,14 @@ func main() {
 	db := config.NewDatabase()
 	defer db.Close()
 
+	// `go run . catalog ...` seeds or dumps the reference data instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "catalog" {
+		command := cli.NewCatalogCommand(dataRepository.NewDataRepository(db), os.Stdout, os.Stderr)
+		code := command.Run(os.Args[2:])
+		db.Close()
+		os.Exit(code)
+	}
+
 	log, file := config.NewLogger()
 	defer file.Close()
 
@@ -24,8 +41,41 @@ func main() {
 	app.RedirectTrailingSlash = false
 	app.Use(gin.Logger())
 
-	routes.NewRoute(app, db, log)
+	// Background work started by requests is waited on before the process exits
+	var background sync.WaitGroup
+	routes.NewRoute(app, db, log, &background)
 	port := os.Getenv("PORT")
 
-	log.Fatal(app.Run(fmt.Sprintf(":%s", port)))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Trending and catalog popularity are recomputed in the background until the server shuts down
+	background.Add(2)
+	go func() {
+		defer background.Done()
+		routes.RefreshTrending(ctx, db, log)
+	}()
+	go func() {
+		defer background.Done()
+		routes.RefreshCatalogPopularity(ctx, db, log)
+	}()
+
+	server := &http.Server{Addr: fmt.Sprintf(":%s", port), Handler: app}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("server.Shutdown: %v", err)
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-stopped
+	background.Wait()
 }
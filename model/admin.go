@@ -0,0 +1,135 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Permissions are granted to roles in the role_permissions table
+const (
+	PermissionUsersRead      = "users:read"
+	PermissionRolesAssign    = "roles:assign"
+	PermissionCatalogsManage = "catalogs:manage"
+	PermissionReportsManage  = "reports:manage"
+	PermissionAuditLogsRead  = "audit_logs:read"
+	PermissionTalentSearch   = "talent:search"
+)
+
+// Catalogs managed from the admin API, named after their route
+const (
+	CatalogSchools              = "schools"
+	CatalogCompanies            = "companies"
+	CatalogSkills               = "skills"
+	CatalogJobPositions         = "job-positions"
+	CatalogIssuingOrganizations = "issuing-organizations"
+)
+
+const (
+	AuditActionAssignRole         = "assign_role"
+	AuditActionRevokeRole         = "revoke_role"
+	AuditActionCreateCatalogItem  = "create_catalog_item"
+	AuditActionUpdateCatalogItem  = "update_catalog_item"
+	AuditActionDeleteCatalogItem  = "delete_catalog_item"
+	AuditActionMergeCatalogItems  = "merge_catalog_items"
+	AuditActionSetSkillParent     = "set_skill_parent"
+	AuditActionAddSkillSynonym    = "add_skill_synonym"
+	AuditActionDeleteSkillSynonym = "delete_skill_synonym"
+	AuditActionActOnReport        = "act_on_report"
+	AuditActionActOnContentFlag   = "act_on_content_flag"
+	AuditActionSetAccountState    = "set_account_state"
+)
+
+const (
+	AuditTargetUser        = "user"
+	AuditTargetReport      = "report"
+	AuditTargetContentFlag = "content_flag"
+)
+
+type AdminUser struct {
+	ID            int64        `json:"id"`
+	Email         string       `json:"email"`
+	Fullname      string       `json:"fullname"`
+	AvatarUrl     string       `json:"avatar_url"`
+	VerifiedEmail bool         `json:"verified_email"`
+	CreatedAt     *time.Time   `json:"created_at"`
+	Roles         []string     `json:"roles"`
+	AccountState  AccountState `json:"account_state"`
+}
+
+type Role struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UserRoleRequest struct {
+	Role string `json:"role" validate:"required,max=20"`
+}
+
+type CatalogItem struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type CatalogItemRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CatalogMergeRequest merges the source items into the item of the route
+type CatalogMergeRequest struct {
+	SourceIds []int64 `json:"source_ids" validate:"required,isNotEmptyArray,max=50,dive,min=1"`
+}
+
+// CatalogRename renames an existing item to the spelling of an imported name
+type CatalogRename struct {
+	ID   int64  `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// CatalogImportDiff is what importing a catalog file changes, applied as is or printed on dry runs
+type CatalogImportDiff struct {
+	Inserts   []string        `json:"inserts"`
+	Renames   []CatalogRename `json:"renames"`
+	Unchanged int             `json:"unchanged"`
+}
+
+// SkillParentRequest moves the skill under the parent, a null parent makes it a root
+type SkillParentRequest struct {
+	ParentId *int64 `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+type SkillSynonymRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type AdminReport struct {
+	ID         int64      `json:"id"`
+	Reporter   User       `json:"reporter"`
+	TargetType string     `json:"target_type"`
+	TargetId   int64      `json:"target_id"`
+	Reasons    []string   `json:"reasons"`
+	Message    string     `json:"message"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+type AuditLog struct {
+	ID         int64           `json:"id"`
+	Actor      User            `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   *int64          `json:"target_id"`
+	Details    json.RawMessage `json:"details"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditEntry is an audit log row written by another repository inside its own transaction
+type AuditEntry struct {
+	ActorId    int64
+	Action     string
+	TargetType string
+	TargetId   int64
+	Details    map[string]any
+}
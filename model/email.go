@@ -14,3 +14,10 @@ type OTPEmail struct {
 	DigitFive  string
 	DigitSix   string
 }
+
+type AccountStateEmail struct {
+	Fullname  string
+	State     string
+	Reason    string
+	ExpiresAt string
+}
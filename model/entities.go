@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Response struct {
 	Status Status `json:"status"`
 	Data   any    `json:"data"`
@@ -12,17 +14,30 @@ type Status struct {
 	IsSuccess bool   `json:"is_success"`
 }
 
+// A zero Page asks for keyset pagination, starting after Cursor when it is set
 type PaginationRequest struct {
 	Page    int
 	Limit   int
 	OrderBy string
+	Cursor  *PageCursor
 }
 
+// Totals are only counted for offset pagination
 type PaginationResponse struct {
-	Page             int   `json:"page"`
-	TotalPages       int   `json:"total_pages"`
-	TotalRows        int64 `json:"total_rows"`
-	CurrentRowsCount int   `json:"current_rows_count"`
+	Page             int    `json:"page,omitempty"`
+	TotalPages       *int   `json:"total_pages,omitempty"`
+	TotalRows        *int64 `json:"total_rows,omitempty"`
+	CurrentRowsCount int    `json:"current_rows_count"`
+	NextCursor       string `json:"next_cursor,omitempty"`
+}
+
+// PageCursor is the sort key of the last row of a page, only the fields
+// the list is ordered by are set
+type PageCursor struct {
+	CreatedAt *time.Time `json:"c,omitempty"`
+	Score     int64      `json:"s,omitempty"`
+	Name      string     `json:"n,omitempty"`
+	ID        int64      `json:"i"`
 }
 
 type User struct {
@@ -44,6 +59,20 @@ type Skill struct {
 	Name string `json:"name" validate:"required"`
 }
 
+type SkillSynonym struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// SkillTaxonomy places a skill in the hierarchy, ancestors start from the root ("Backend" for "Go")
+type SkillTaxonomy struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Ancestors []CatalogItem  `json:"ancestors"`
+	Children  []CatalogItem  `json:"children"`
+	Synonyms  []SkillSynonym `json:"synonyms"`
+}
+
 type Company struct {
 	ID   int64  `json:"id" validate:"required"`
 	Name string `json:"name" validate:"required"`
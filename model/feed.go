@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	FeedOrderNewest    = "newest"
+	FeedOrderFollowing = "following"
+	FeedOrderPopular   = "popular"
+	FeedOrderForYou    = "for_you"
+)
+
+// FeedCandidate holds the ranking signals of a post for the viewer
+type FeedCandidate struct {
+	PostID       int64
+	AuthorID     int64
+	CreatedAt    time.Time
+	LikeCount    int32
+	CommentCount int32
+	RepostCount  int32
+	IsFollowed   bool
+	// Users followed by the viewer who also follow the author
+	MutualFollowings int64
+	SharedSkills     int64
+	SharedCompanies  int64
+	// Likes, comments and reposts the viewer gave to the author's posts
+	AuthorInteractions int64
+	Score              float64
+}
@@ -12,3 +12,39 @@ type ListPostsResponse struct {
 	RepostCount  int32     `json:"repost_count"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
+
+// FollowCandidate holds the signals of a user recommended to the viewer
+type FollowCandidate struct {
+	User           User
+	FollowersCount int32
+	// Users followed by the viewer who also follow the candidate
+	MutualFollowings   int64
+	SharedCompany      string
+	SharedSchool       string
+	SharedSkills       int64
+	SharedJobInterests int64
+	// Set when the candidate lives where the viewer does
+	SharedLocation string
+	Score          float64
+	Reason         string
+}
+
+type FollowRecommendation struct {
+	User
+	Reason string `json:"reason"`
+}
+
+const (
+	TrendingTopicHashtag = "hashtag"
+	TrendingTopicSkill   = "skill"
+)
+
+type TrendingTopic struct {
+	Type         string  `json:"type"`
+	Name         string  `json:"name"`
+	PostCount    int32   `json:"post_count"`
+	LikeCount    int32   `json:"like_count"`
+	CommentCount int32   `json:"comment_count"`
+	RepostCount  int32   `json:"repost_count"`
+	Score        float64 `json:"score"`
+}
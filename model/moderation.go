@@ -0,0 +1,158 @@
+package model
+
+import "time"
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+const (
+	ModerationActionDismiss  = "dismiss"
+	ModerationActionHide     = "hide"
+	ModerationActionDelete   = "delete"
+	ModerationActionWarn     = "warn"
+	ModerationActionSuspend  = "suspend"
+	ModerationActionAutoHide = "auto_hide"
+	ModerationActionAutoHold = "auto_hold"
+	ModerationActionApprove  = "approve"
+	ModerationActionRemove   = "remove"
+	ModerationActionRestrict = "restrict"
+	ModerationActionBan      = "ban"
+	ModerationActionRestore  = "restore"
+)
+
+// Restricted users can still sign in but cannot publish or interact,
+// suspended and banned users cannot sign in and their content is hidden
+const (
+	AccountStateActive     = "active"
+	AccountStateRestricted = "restricted"
+	AccountStateSuspended  = "suspended"
+	AccountStateBanned     = "banned"
+)
+
+// Why a post was hidden from everyone but its author
+const (
+	HiddenPostReasonModerator = "moderator"
+	HiddenPostReasonReports   = "reports"
+	// Held by the content policy until a moderator approves it
+	HiddenPostReasonContentPolicy = "content_policy"
+)
+
+const (
+	ContentVerdictAllow  = "allow"
+	ContentVerdictHold   = "hold"
+	ContentVerdictReject = "reject"
+)
+
+const (
+	ReportTargetPost             = "post"
+	ReportTargetPostComment      = "post_comment"
+	ReportTargetPostCommentReply = "post_comment_reply"
+	ReportTargetUser             = "user"
+)
+
+// Report is used for every reportable target, reasons are limited to a fixed catalog
+type Report struct {
+	TargetType string   `json:"target_type"`
+	TargetId   int64    `json:"target_id"`
+	Reason     []string `json:"reason" validate:"required,isNotEmptyArray,max=5,dive,oneof=spam harassment hate_speech violence nudity misinformation scam impersonation fake_profile intellectual_property other"`
+	Message    string   `json:"message" validate:"required"`
+}
+
+type ModerationActionRequest struct {
+	Action string `json:"action" validate:"required,oneof=dismiss hide delete warn suspend"`
+	Note   string `json:"note"`
+	// Only used when suspending, 0 suspends until lifted
+	SuspendDays int `json:"suspend_days" validate:"min=0,max=365"`
+}
+
+// ReportedContentSummary groups the open reports of one post, comment, reply or user
+type ReportedContentSummary struct {
+	TargetType string `json:"target_type"`
+	TargetId   int64  `json:"target_id"`
+	// Post the content belongs to, 0 for reported users
+	PostId int64 `json:"post_id"`
+	// Post title or comment and reply content, empty for reported users
+	Preview        string           `json:"preview"`
+	Author         User             `json:"author"`
+	ReporterCount  int64            `json:"reporter_count"`
+	ReasonCounts   map[string]int64 `json:"reason_counts"`
+	IsHidden       bool             `json:"is_hidden"`
+	LastReportedAt time.Time        `json:"last_reported_at"`
+}
+
+// ReportedContent is the comment, reply or user a moderator acts on
+type ReportedContent struct {
+	TargetType string
+	TargetId   int64
+	// Post of the comment or reply
+	PostId int64
+	// Parent comment of the reply
+	PostCommentId int64
+	AuthorId      int64
+	// Image of the comment or reply, removed from the bucket when it is deleted
+	ImageUrl string
+}
+
+type PostReport struct {
+	ID         int64      `json:"id"`
+	Reporter   User       `json:"reporter"`
+	Reasons    []string   `json:"reasons"`
+	Message    string     `json:"message"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+type AccountState struct {
+	State     string     `json:"state"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type AccountStateRequest struct {
+	State  string `json:"state" validate:"required,oneof=active restricted suspended banned"`
+	Reason string `json:"reason"`
+	// 0 keeps the state until it is changed again
+	Days int `json:"days" validate:"min=0,max=365"`
+}
+
+type ModerationAction struct {
+	ID                int64     `json:"id"`
+	ModeratorId       int64     `json:"moderator_id"`
+	ModeratorFullname string    `json:"moderator_fullname"`
+	TargetUserId      int64     `json:"target_user_id"`
+	Action            string    `json:"action"`
+	Note              string    `json:"note"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type ContentPolicyInput struct {
+	UserId int64
+	Text   string
+	// Creation times of the author's recent posts, comments and replies, empty when editing
+	RecentActivity []time.Time
+	Now            time.Time
+}
+
+type ContentPolicyResult struct {
+	Verdict string   `json:"verdict"`
+	Rules   []string `json:"rules"`
+}
+
+type ContentFlag struct {
+	ID         int64      `json:"id"`
+	Author     User       `json:"author"`
+	TargetType string     `json:"target_type"`
+	TargetId   *int64     `json:"target_id"`
+	Verdict    string     `json:"verdict"`
+	Rules      []string   `json:"rules"`
+	Content    string     `json:"content"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+type ContentFlagActionRequest struct {
+	Action string `json:"action" validate:"required,oneof=approve remove"`
+	Note   string `json:"note"`
+}
@@ -4,17 +4,18 @@ import (
 	"time"
 )
 
-type ReportPost struct {
-	PostId  int64    `json:"post_id"`
-	Reason  []string `json:"reason" validate:"required,isNotEmptyArray"`
-	Message string   `json:"message" validate:"required"`
+type CreatePostRequest struct {
+	UserId     int64              `json:"user_id"`
+	Title      string             `json:"title" form:"title" validate:"required"`
+	Content    string             `json:"content" form:"content"`
+	Visibility string             `json:"visibility" form:"visibility" validate:"required"`
+	Poll       *CreatePollRequest `json:"poll" form:"-"`
 }
 
-type CreatePostRequest struct {
-	UserId     int64  `json:"user_id"`
-	Title      string `json:"title" form:"title" validate:"required"`
-	Content    string `json:"content" form:"content"`
-	Visibility string `json:"visibility" form:"visibility" validate:"required"`
+type CreatePollRequest struct {
+	Options       []string  `json:"options" validate:"min=2,max=4,dive,required,max=100"`
+	EndsAt        time.Time `json:"ends_at" validate:"required,gt"`
+	AllowMultiple bool      `json:"allow_multiple"`
 }
 
 type UpdatePostRequest struct {
@@ -25,18 +26,63 @@ type UpdatePostRequest struct {
 	Visibility string `json:"visibility" form:"visibility" validate:"required"`
 }
 
+const (
+	ReactionLike       = "like"
+	ReactionCelebrate  = "celebrate"
+	ReactionSupport    = "support"
+	ReactionInsightful = "insightful"
+	ReactionFunny      = "funny"
+)
+
+var ReactionTypes = []string{ReactionLike, ReactionCelebrate, ReactionSupport, ReactionInsightful, ReactionFunny}
+
 type Post struct {
-	ID           int64     `json:"id"`
-	User         User      `json:"author"`
-	Title        string    `json:"title"`
-	Content      string    `json:"content"`
-	ImageUrls    []string  `json:"image_urls"`
-	LikeCount    int32     `json:"like_count"`
-	CommentCount int32     `json:"comment_count"`
-	RepostCount  int32     `json:"repost_count"`
-	IsRepost     bool      `json:"is_repost"`
-	IsLiked      bool      `json:"is_liked"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             int64            `json:"id"`
+	User           User             `json:"author"`
+	Title          string           `json:"title"`
+	Content        string           `json:"content"`
+	ImageUrls      []string         `json:"image_urls"`
+	LikeCount      int32            `json:"like_count"`
+	CommentCount   int32            `json:"comment_count"`
+	RepostCount    int32            `json:"repost_count"`
+	IsRepost       bool             `json:"is_repost"`
+	IsLiked        bool             `json:"is_liked"`
+	IsBookmarked   bool             `json:"is_bookmarked"`
+	LinkPreview    *LinkPreview     `json:"link_preview"`
+	ReactionCounts map[string]int32 `json:"reaction_counts"`
+	MyReaction     string           `json:"my_reaction"`
+	Poll           *Poll            `json:"poll"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+type LinkPreview struct {
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageUrl    string `json:"image_url"`
+	SiteName    string `json:"site_name"`
+}
+
+// Poll vote counts are nil while they are hidden from the viewer
+type Poll struct {
+	ID            int64        `json:"id"`
+	AllowMultiple bool         `json:"allow_multiple"`
+	EndsAt        time.Time    `json:"ends_at"`
+	IsClosed      bool         `json:"is_closed"`
+	HasVoted      bool         `json:"has_voted"`
+	VoterCount    *int32       `json:"voter_count"`
+	Options       []PollOption `json:"options"`
+}
+
+type PollOption struct {
+	ID        int64  `json:"id"`
+	Text      string `json:"text"`
+	VoteCount *int32 `json:"vote_count"`
+	IsVoted   bool   `json:"is_voted"`
+}
+
+type VotePollRequest struct {
+	OptionIds []int64 `json:"option_ids" validate:"required,min=1,max=4"`
 }
 
 type PostComment struct {
@@ -77,3 +123,52 @@ type AddPostCommentReplyReq struct {
 	ImageUrl      string `json:"image_url"`
 	IsPostAuthor  bool   `json:"is_post_author"`
 }
+
+type UpdatePostCommentReq struct {
+	ID      int64  `json:"id"`
+	PostId  int64  `json:"post_id"`
+	UserId  int64  `json:"user_id"`
+	Content string `json:"content" form:"content" validate:"required"`
+}
+
+type UpdatePostCommentReplyReq struct {
+	ID            int64  `json:"id"`
+	PostId        int64  `json:"post_id"`
+	PostCommentId int64  `json:"post_comment_id"`
+	UserId        int64  `json:"user_id"`
+	Content       string `json:"content" form:"content" validate:"required"`
+}
+
+type BookmarkPostRequest struct {
+	BookmarkFolderId int64 `json:"bookmark_folder_id" form:"bookmark_folder_id"`
+}
+
+type CreateBookmarkFolderRequest struct {
+	Name string `json:"name" form:"name" validate:"required,max=50"`
+}
+
+type BookmarkFolder struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	BookmarkCount int64     `json:"bookmark_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReactRequest struct {
+	Type string `json:"type" form:"type" validate:"required,oneof=like celebrate support insightful funny"`
+}
+
+type PostReaction struct {
+	User         User   `json:"user"`
+	ReactionType string `json:"reaction_type"`
+}
+
+type PostImpressionsRequest struct {
+	PostIds []int64 `json:"post_ids" validate:"required,min=1,max=100"`
+}
+
+type PostViewCounts struct {
+	PostID      int64 `json:"post_id"`
+	ViewCount   int64 `json:"view_count"`
+	ViewerCount int64 `json:"viewer_count"`
+}
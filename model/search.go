@@ -0,0 +1,119 @@
+package model
+
+import "time"
+
+// Prefix also matches the last word of the query as a prefix, for typeahead
+type PostSearchFilter struct {
+	Query    string
+	Prefix   bool
+	AuthorId int64
+	From     *time.Time
+	To       *time.Time
+	Hashtag  string
+}
+
+// Highlighted fields are HTML escaped with matches wrapped in <mark> tags
+type PostSearchResult struct {
+	Post
+	TitleHighlight string `json:"title_highlight"`
+	Snippet        string `json:"snippet"`
+}
+
+type UserSearchFilter struct {
+	Name          string
+	SkillId       int64
+	CompanyId     int64
+	SchoolId      int64
+	JobPositionId int64
+	Location      string
+	OpenToWork    *bool
+}
+
+type UserSearchResult struct {
+	ID          int64  `json:"id"`
+	Fullname    string `json:"fullname"`
+	AvatarUrl   string `json:"avatar_url"`
+	Bio         string `json:"bio"`
+	OpenToWork  bool   `json:"open_to_work"`
+	Location    string `json:"location"`
+	IsFollowing bool   `json:"is_following"`
+}
+
+// Facet values without a catalog entry, like locations, have no id
+type SearchFacet struct {
+	ID    int64  `json:"id,omitempty"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type UserSearchFacets struct {
+	Skills       []SearchFacet `json:"skills"`
+	Companies    []SearchFacet `json:"companies"`
+	Schools      []SearchFacet `json:"schools"`
+	JobPositions []SearchFacet `json:"job_positions"`
+	Locations    []SearchFacet `json:"locations"`
+	OpenToWork   []SearchFacet `json:"open_to_work"`
+}
+
+// Location and employment types are matched case insensitively against the open to work data
+type TalentSearchFilter struct {
+	JobPositionIds       []int64  `json:"job_position_ids" validate:"max=20,dive,min=1"`
+	SkillIds             []int64  `json:"skill_ids" validate:"max=20,dive,min=1"`
+	LocationTypes        []string `json:"location_types" validate:"max=5,dive,required,max=10"`
+	EmploymentTypes      []string `json:"employment_types" validate:"max=10,dive,required,max=20"`
+	MinYearsOfExperience *float64 `json:"min_years_of_experience" validate:"omitempty,min=0,max=60"`
+	MaxYearsOfExperience *float64 `json:"max_years_of_experience" validate:"omitempty,min=0,max=60"`
+}
+
+type TalentSearchResult struct {
+	ID                int64    `json:"id"`
+	Fullname          string   `json:"fullname"`
+	AvatarUrl         string   `json:"avatar_url"`
+	Bio               string   `json:"bio"`
+	Location          string   `json:"location"`
+	YearsOfExperience float64  `json:"years_of_experience"`
+	JobPositions      []string `json:"job_positions"`
+	LocationTypes     []string `json:"location_types"`
+	EmploymentTypes   []string `json:"employment_types"`
+	MatchedSkills     []string `json:"matched_skills"`
+	Score             float64  `json:"score"`
+}
+
+type SaveTalentSearchRequest struct {
+	Name    string             `json:"name" validate:"required,max=50"`
+	Filters TalentSearchFilter `json:"filters"`
+}
+
+type SavedTalentSearch struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Filters   TalentSearchFilter `json:"filters"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Result groups of the unified search, in the order they are returned
+const (
+	SearchGroupPeople    = "people"
+	SearchGroupPosts     = "posts"
+	SearchGroupCompanies = "companies"
+	SearchGroupSchools   = "schools"
+	SearchGroupSkills    = "skills"
+)
+
+var SearchGroups = []string{SearchGroupPeople, SearchGroupPosts, SearchGroupCompanies, SearchGroupSchools, SearchGroupSkills}
+
+// SearchCursor continues a single group of the unified search
+type SearchCursor struct {
+	Group  string `json:"g"`
+	Query  string `json:"q"`
+	Offset int    `json:"o"`
+	Limit  int    `json:"l"`
+}
+
+type SearchGroup struct {
+	Group      string `json:"group"`
+	Items      any    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	TimedOut   bool   `json:"timed_out"`
+	Partial    bool   `json:"partial"`
+}
@@ -0,0 +1,372 @@
+-- name: CountUserPermissions :one
+SELECT COUNT(*) AS count
+FROM user_roles ur
+JOIN roles r ON ur.role = r.name
+JOIN role_permissions rp ON r.id = rp.role_id
+JOIN permissions p ON rp.permission_id = p.id
+WHERE ur.user_id = @user_id::bigint AND p.name = @permission::text;
+
+-- name: ListAdminUsers :many
+SELECT u.id, u.email, u.full_name, u.avatar_url, u.verified_email, u.created_at,
+    ARRAY(SELECT ur.role FROM user_roles ur WHERE ur.user_id = u.id ORDER BY ur.role)::text[] AS roles,
+    COALESCE(uas.state, 'active')::text AS account_state,
+    COUNT(*) OVER () AS total_rows
+FROM users u
+LEFT JOIN user_account_states uas ON u.id = uas.user_id AND (uas.expires_at IS NULL OR uas.expires_at > NOW())
+WHERE @search::text = '' OR u.email ILIKE '%' || @search::text || '%' OR u.full_name ILIKE '%' || @search::text || '%'
+ORDER BY u.id
+OFFSET $1
+LIMIT $2;
+
+-- name: GetAdminUserById :one
+SELECT u.id, u.email, u.full_name, u.avatar_url, u.verified_email, u.created_at,
+    ARRAY(SELECT ur.role FROM user_roles ur WHERE ur.user_id = u.id ORDER BY ur.role)::text[] AS roles,
+    COALESCE(uas.state, 'active')::text AS account_state, uas.reason, uas.expires_at
+FROM users u
+LEFT JOIN user_account_states uas ON u.id = uas.user_id AND (uas.expires_at IS NULL OR uas.expires_at > NOW())
+WHERE u.id = @id::bigint;
+
+-- name: ListRolesWithPermissions :many
+SELECT r.id, r.name, r.description,
+    COALESCE(ARRAY_AGG(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}')::text[] AS permissions
+FROM roles r
+LEFT JOIN role_permissions rp ON r.id = rp.role_id
+LEFT JOIN permissions p ON rp.permission_id = p.id
+GROUP BY r.id
+ORDER BY r.name;
+
+-- name: CountRolesByName :one
+SELECT COUNT(*) AS count
+FROM roles
+WHERE name = @name::text;
+
+-- name: InsertUserRole :execrows
+INSERT INTO user_roles (user_id, role, created_at)
+VALUES (@user_id::bigint, @role::text, NOW())
+ON CONFLICT (user_id, role) DO NOTHING;
+
+-- name: DeleteUserRole :execrows
+DELETE FROM user_roles
+WHERE user_id = @user_id::bigint AND role = @role::text;
+
+-- name: InsertCatalogSchool :one
+INSERT INTO schools (name, normalized_name)
+VALUES (@name::text, NULLIF(@normalized_name::text, ''))
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateCatalogSchool :one
+UPDATE schools
+SET name = @name::text,
+    normalized_name = NULLIF(@normalized_name::text, '')
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeleteCatalogSchool :execrows
+DELETE FROM schools
+WHERE id = @id::bigint;
+
+-- name: GetCatalogSchoolById :one
+SELECT *
+FROM schools
+WHERE id = @id::bigint;
+
+-- name: InsertCatalogCompany :one
+INSERT INTO companies (name, normalized_name)
+VALUES (@name::text, NULLIF(@normalized_name::text, ''))
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateCatalogCompany :one
+UPDATE companies
+SET name = @name::text,
+    normalized_name = NULLIF(@normalized_name::text, '')
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeleteCatalogCompany :execrows
+DELETE FROM companies
+WHERE id = @id::bigint;
+
+-- name: GetCatalogCompanyById :one
+SELECT *
+FROM companies
+WHERE id = @id::bigint;
+
+-- name: InsertCatalogSkill :one
+INSERT INTO skills (name, normalized_name)
+VALUES (@name::text, NULLIF(@normalized_name::text, ''))
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateCatalogSkill :one
+UPDATE skills
+SET name = @name::text,
+    normalized_name = NULLIF(@normalized_name::text, '')
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeleteCatalogSkill :execrows
+DELETE FROM skills
+WHERE id = @id::bigint;
+
+-- name: GetCatalogSkillById :one
+SELECT *
+FROM skills
+WHERE id = @id::bigint;
+
+-- name: GetSkillByNormalizedName :one
+SELECT *
+FROM skills s
+WHERE s.normalized_name = @normalized_name::text
+  OR s.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'skills' AND ca.normalized_name = @normalized_name::text)
+ORDER BY s.id
+LIMIT 1;
+
+-- name: UpdateSkillParent :one
+UPDATE skills
+SET parent_id = sqlc.narg('parent_id')::bigint
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: GetSkillAncestorIds :many
+WITH RECURSIVE ancestors AS (
+    SELECT s.id, s.parent_id, 0 AS depth
+    FROM skills s
+    WHERE s.id = @id::bigint
+    UNION ALL
+    SELECT p.id, p.parent_id, a.depth + 1
+    FROM skills p
+    JOIN ancestors a ON p.id = a.parent_id
+    WHERE a.depth < 20
+)
+SELECT id FROM ancestors;
+
+-- name: InsertSkillSynonym :one
+INSERT INTO catalog_aliases (catalog, item_id, name, normalized_name, created_at)
+VALUES ('skills', @skill_id::bigint, @name::text, @normalized_name::text, NOW())
+ON CONFLICT (catalog, normalized_name) DO NOTHING
+RETURNING id, name;
+
+-- name: DeleteSkillSynonym :execrows
+DELETE FROM catalog_aliases
+WHERE catalog = 'skills' AND id = @id::bigint AND item_id = @skill_id::bigint;
+
+-- name: InsertCatalogJobPosition :one
+INSERT INTO job_positions (name, normalized_name)
+VALUES (@name::text, NULLIF(@normalized_name::text, ''))
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateCatalogJobPosition :one
+UPDATE job_positions
+SET name = @name::text,
+    normalized_name = NULLIF(@normalized_name::text, '')
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeleteCatalogJobPosition :execrows
+DELETE FROM job_positions
+WHERE id = @id::bigint;
+
+-- name: GetCatalogJobPositionById :one
+SELECT *
+FROM job_positions
+WHERE id = @id::bigint;
+
+-- name: InsertCatalogIssuingOrganization :one
+INSERT INTO issuing_organizations (name, normalized_name)
+VALUES (@name::text, NULLIF(@normalized_name::text, ''))
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateCatalogIssuingOrganization :one
+UPDATE issuing_organizations
+SET name = @name::text,
+    normalized_name = NULLIF(@normalized_name::text, '')
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeleteCatalogIssuingOrganization :execrows
+DELETE FROM issuing_organizations
+WHERE id = @id::bigint;
+
+-- name: GetCatalogIssuingOrganizationById :one
+SELECT *
+FROM issuing_organizations
+WHERE id = @id::bigint;
+
+-- name: MergeWorkExperienceCompanies :exec
+UPDATE work_experiences
+SET company_id = @target_id::bigint
+WHERE company_id = ANY(@source_ids::bigint[]);
+
+-- name: MergeEducationSchools :exec
+UPDATE educations
+SET school_id = @target_id::bigint
+WHERE school_id = ANY(@source_ids::bigint[]);
+
+-- name: MergeCertificateIssuingOrganizations :exec
+UPDATE certificates
+SET issuing_organization_id = @target_id::bigint
+WHERE issuing_organization_id = ANY(@source_ids::bigint[]);
+
+-- name: MergeUserJobInterestJobPositions :exec
+UPDATE user_job_interests
+SET job_position_id = @target_id::bigint
+WHERE job_position_id = ANY(@source_ids::bigint[]);
+
+-- name: DeleteDuplicateUserJobInterests :exec
+DELETE FROM user_job_interests uji
+USING user_job_interests dup
+WHERE uji.job_position_id = @job_position_id::bigint AND dup.job_position_id = uji.job_position_id
+  AND dup.user_id = uji.user_id AND dup.id < uji.id;
+
+-- name: MergeUserSkills :exec
+-- Users having a source skill get the target, main if one of their merged skills was
+INSERT INTO user_skills (user_id, skill_id, main_skill)
+SELECT us.user_id, @target_id::bigint, BOOL_OR(COALESCE(us.main_skill, FALSE))
+FROM user_skills us
+WHERE us.skill_id = ANY(@source_ids::bigint[])
+GROUP BY us.user_id
+ON CONFLICT (user_id, skill_id) DO UPDATE
+SET main_skill = COALESCE(user_skills.main_skill, FALSE) OR EXCLUDED.main_skill;
+
+-- name: MergeEducationSkills :exec
+INSERT INTO education_skills (education_id, user_skill_id)
+SELECT es.education_id, target.id
+FROM education_skills es
+JOIN user_skills source ON es.user_skill_id = source.id
+JOIN user_skills target ON target.user_id = source.user_id AND target.skill_id = @target_id::bigint
+WHERE source.skill_id = ANY(@source_ids::bigint[])
+ON CONFLICT (education_id, user_skill_id) DO NOTHING;
+
+-- name: MergeWorkExperienceSkills :exec
+INSERT INTO work_experience_skills (work_experience_id, user_skill_id)
+SELECT wes.work_experience_id, target.id
+FROM work_experience_skills wes
+JOIN user_skills source ON wes.user_skill_id = source.id
+JOIN user_skills target ON target.user_id = source.user_id AND target.skill_id = @target_id::bigint
+WHERE source.skill_id = ANY(@source_ids::bigint[])
+ON CONFLICT (work_experience_id, user_skill_id) DO NOTHING;
+
+-- name: DeleteEducationSkillsBySkillIds :exec
+DELETE FROM education_skills es
+USING user_skills us
+WHERE es.user_skill_id = us.id AND us.skill_id = ANY(@skill_ids::bigint[]);
+
+-- name: DeleteWorkExperienceSkillsBySkillIds :exec
+DELETE FROM work_experience_skills wes
+USING user_skills us
+WHERE wes.user_skill_id = us.id AND us.skill_id = ANY(@skill_ids::bigint[]);
+
+-- name: DeleteUserSkillsBySkillIds :exec
+DELETE FROM user_skills
+WHERE skill_id = ANY(@skill_ids::bigint[]);
+
+-- name: MoveSkillChildren :exec
+UPDATE skills
+SET parent_id = @target_id::bigint
+WHERE parent_id = ANY(@source_ids::bigint[]) AND id != @target_id::bigint;
+
+-- name: MoveCatalogAliases :exec
+UPDATE catalog_aliases
+SET item_id = @target_id::bigint
+WHERE catalog = @catalog::text AND item_id = ANY(@source_ids::bigint[]);
+
+-- name: InsertCompanyAliases :exec
+INSERT INTO catalog_aliases (catalog, item_id, name, normalized_name, created_at)
+SELECT 'companies', @target_id::bigint, name, normalized_name, NOW()
+FROM companies
+WHERE id = ANY(@source_ids::bigint[]) AND normalized_name IS NOT NULL
+ON CONFLICT (catalog, normalized_name) DO NOTHING;
+
+-- name: InsertSchoolAliases :exec
+INSERT INTO catalog_aliases (catalog, item_id, name, normalized_name, created_at)
+SELECT 'schools', @target_id::bigint, name, normalized_name, NOW()
+FROM schools
+WHERE id = ANY(@source_ids::bigint[]) AND normalized_name IS NOT NULL
+ON CONFLICT (catalog, normalized_name) DO NOTHING;
+
+-- name: InsertIssuingOrganizationAliases :exec
+INSERT INTO catalog_aliases (catalog, item_id, name, normalized_name, created_at)
+SELECT 'issuing-organizations', @target_id::bigint, name, normalized_name, NOW()
+FROM issuing_organizations
+WHERE id = ANY(@source_ids::bigint[]) AND normalized_name IS NOT NULL
+ON CONFLICT (catalog, normalized_name) DO NOTHING;
+
+-- name: InsertJobPositionAliases :exec
+INSERT INTO catalog_aliases (catalog, item_id, name, normalized_name, created_at)
+SELECT 'job-positions', @target_id::bigint, name, normalized_name, NOW()
+FROM job_positions
+WHERE id = ANY(@source_ids::bigint[]) AND name IS NOT NULL AND normalized_name IS NOT NULL
+ON CONFLICT (catalog, normalized_name) DO NOTHING;
+
+-- name: InsertSkillAliases :exec
+INSERT INTO catalog_aliases (catalog, item_id, name, normalized_name, created_at)
+SELECT 'skills', @target_id::bigint, name, normalized_name, NOW()
+FROM skills
+WHERE id = ANY(@source_ids::bigint[]) AND normalized_name IS NOT NULL
+ON CONFLICT (catalog, normalized_name) DO NOTHING;
+
+-- name: DeleteCompaniesByIds :execrows
+DELETE FROM companies
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: DeleteSchoolsByIds :execrows
+DELETE FROM schools
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: DeleteIssuingOrganizationsByIds :execrows
+DELETE FROM issuing_organizations
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: DeleteJobPositionsByIds :execrows
+DELETE FROM job_positions
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: DeleteSkillsByIds :execrows
+DELETE FROM skills
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: DeleteCatalogPopularityByItemIds :exec
+DELETE FROM catalog_popularity
+WHERE catalog = @catalog::text AND item_id = ANY(@item_ids::bigint[]);
+
+-- name: ListOpenReports :many
+SELECT r.id, r.target_type, r.target_id, r.reasons, r.message, r.created_at, r.resolved_at,
+    u.id, u.full_name, u.avatar_url,
+    COUNT(*) OVER () AS total_rows
+FROM reports r
+JOIN users u ON r.user_id = u.id
+WHERE r.resolved_at IS NULL AND (@target_type::text = '' OR r.target_type = @target_type::text)
+ORDER BY r.created_at ASC
+OFFSET $1
+LIMIT $2;
+
+-- name: GetReportById :one
+SELECT r.id, r.target_type, r.target_id, r.reasons, r.message, r.created_at, r.resolved_at,
+    u.id, u.full_name, u.avatar_url
+FROM reports r
+JOIN users u ON r.user_id = u.id
+WHERE r.id = @id::bigint;
+
+-- name: ResolveReportsByTarget :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE target_type = @target_type::text AND target_id = @target_id::bigint AND resolved_at IS NULL;
+
+-- name: InsertAdminAuditLog :exec
+INSERT INTO admin_audit_logs (actor_id, action, target_type, target_id, details, created_at)
+VALUES (@actor_id::bigint, @action::text, @target_type::text, NULLIF(@target_id::bigint, 0), @details::jsonb, NOW());
+
+-- name: ListAdminAuditLogs :many
+SELECT al.id, al.action, al.target_type, al.target_id, al.details, al.created_at,
+    u.id, u.full_name, u.avatar_url,
+    COUNT(*) OVER () AS total_rows
+FROM admin_audit_logs al
+JOIN users u ON al.actor_id = u.id
+WHERE (@actor_id::bigint = 0 OR al.actor_id = @actor_id::bigint) AND (@target_type::text = '' OR al.target_type = @target_type::text)
+ORDER BY al.created_at DESC
+OFFSET $1
+LIMIT $2;
@@ -0,0 +1,828 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "profiln-be/db/sqlc"
+	"profiln-be/libs"
+	"profiln-be/model"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCatalogItemExists = errors.New("catalog item already exists")
+	ErrCatalogItemInUse  = errors.New("catalog item is still in use")
+	ErrSkillCycle        = errors.New("skill would become its own ancestor")
+)
+
+type IAdminRepository interface {
+	HasPermission(userId int64, permission string) (bool, error)
+	ListUsers(search string, offset, limit int32) ([]model.AdminUser, int64, error)
+	GetUserById(userId int64) (model.AdminUser, error)
+	ListRoles() ([]model.Role, error)
+	RoleExists(role string) (bool, error)
+	AssignRole(actorId, userId int64, role string) (bool, error)
+	RevokeRole(actorId, userId int64, role string) (bool, error)
+	InsertCatalogItem(actorId int64, catalog, name string) (model.CatalogItem, error)
+	UpdateCatalogItem(actorId int64, catalog string, itemId int64, name string) (model.CatalogItem, error)
+	DeleteCatalogItem(actorId int64, catalog string, itemId int64) error
+	MergeCatalogItems(actorId int64, catalog string, targetId int64, sourceIds []int64) (model.CatalogItem, error)
+	SetSkillParent(actorId, skillId int64, parentId *int64) (model.CatalogItem, error)
+	InsertSkillSynonym(actorId, skillId int64, name string) (model.SkillSynonym, error)
+	DeleteSkillSynonym(actorId, skillId, synonymId int64) error
+	ListOpenReports(targetType string, offset, limit int32) ([]model.AdminReport, int64, error)
+	GetReportById(reportId int64) (model.AdminReport, error)
+	ListAuditLogs(actorId int64, targetType string, offset, limit int32) ([]model.AuditLog, int64, error)
+}
+
+type AdminRepository struct {
+	dbConn *sql.DB
+	query  *db.Queries
+}
+
+func NewAdminRepository(dbConn *sql.DB) IAdminRepository {
+	return &AdminRepository{
+		dbConn: dbConn,
+		query:  db.New(dbConn),
+	}
+}
+
+func (r *AdminRepository) HasPermission(userId int64, permission string) (bool, error) {
+	count, err := r.query.CountUserPermissions(context.Background(), db.CountUserPermissionsParams{
+		UserID:     userId,
+		Permission: permission,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *AdminRepository) ListUsers(search string, offset, limit int32) ([]model.AdminUser, int64, error) {
+	data, err := r.query.ListAdminUsers(context.Background(), db.ListAdminUsersParams{
+		Offset: offset,
+		Limit:  limit,
+		Search: search,
+	})
+	if err != nil {
+		return []model.AdminUser{}, 0, err
+	}
+
+	var totalRows int64
+	users := make([]model.AdminUser, len(data))
+	for i, v := range data {
+		totalRows = v.TotalRows
+		users[i] = model.AdminUser{
+			ID:            v.ID,
+			Email:         v.Email,
+			Fullname:      v.FullName,
+			AvatarUrl:     v.AvatarUrl.String,
+			VerifiedEmail: v.VerifiedEmail.Bool,
+			Roles:         v.Roles,
+			AccountState: model.AccountState{
+				State: v.AccountState,
+			},
+		}
+
+		if v.CreatedAt.Valid {
+			users[i].CreatedAt = &v.CreatedAt.Time
+		}
+	}
+
+	return users, totalRows, nil
+}
+
+func (r *AdminRepository) GetUserById(userId int64) (model.AdminUser, error) {
+	data, err := r.query.GetAdminUserById(context.Background(), userId)
+	if err != nil {
+		return model.AdminUser{}, err
+	}
+
+	user := model.AdminUser{
+		ID:            data.ID,
+		Email:         data.Email,
+		Fullname:      data.FullName,
+		AvatarUrl:     data.AvatarUrl.String,
+		VerifiedEmail: data.VerifiedEmail.Bool,
+		Roles:         data.Roles,
+		AccountState: model.AccountState{
+			State:  data.AccountState,
+			Reason: data.Reason.String,
+		},
+	}
+
+	if data.CreatedAt.Valid {
+		user.CreatedAt = &data.CreatedAt.Time
+	}
+
+	if data.ExpiresAt.Valid {
+		user.AccountState.ExpiresAt = &data.ExpiresAt.Time
+	}
+
+	return user, nil
+}
+
+func (r *AdminRepository) ListRoles() ([]model.Role, error) {
+	data, err := r.query.ListRolesWithPermissions(context.Background())
+	if err != nil {
+		return []model.Role{}, err
+	}
+
+	roles := make([]model.Role, len(data))
+	for i, v := range data {
+		roles[i] = model.Role{
+			ID:          v.ID,
+			Name:        v.Name,
+			Description: v.Description.String,
+			Permissions: v.Permissions,
+		}
+	}
+
+	return roles, nil
+}
+
+func (r *AdminRepository) RoleExists(role string) (bool, error) {
+	count, err := r.query.CountRolesByName(context.Background(), role)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// AssignRole returns false when the user already has the role
+func (r *AdminRepository) AssignRole(actorId, userId int64, role string) (bool, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	rows, err := qtx.InsertUserRole(ctx, db.InsertUserRoleParams{
+		UserID: userId,
+		Role:   role,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not insert user role: %w", err)
+	}
+
+	if rows == 0 {
+		return false, nil
+	}
+
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionAssignRole, model.AuditTargetUser, userId, map[string]any{"role": role}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// RevokeRole returns false when the user does not have the role
+func (r *AdminRepository) RevokeRole(actorId, userId int64, role string) (bool, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	rows, err := qtx.DeleteUserRole(ctx, db.DeleteUserRoleParams{
+		UserID: userId,
+		Role:   role,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not delete user role: %w", err)
+	}
+
+	if rows == 0 {
+		return false, nil
+	}
+
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionRevokeRole, model.AuditTargetUser, userId, map[string]any{"role": role}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *AdminRepository) InsertCatalogItem(actorId int64, catalog, name string) (model.CatalogItem, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	normalizedName := libs.CatalogMatchKey(catalog, name)
+	_, err = findCatalogItemId(ctx, qtx, catalog, normalizedName)
+	if err == nil {
+		return model.CatalogItem{}, ErrCatalogItemExists
+	} else if err != sql.ErrNoRows {
+		return model.CatalogItem{}, fmt.Errorf("could not find catalog item: %w", err)
+	}
+
+	item, err := libs.InsertCatalogItem(ctx, qtx, catalog, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CatalogItem{}, ErrCatalogItemExists
+	} else if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not insert catalog item: %w", err)
+	}
+
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionCreateCatalogItem, catalog, item.ID, map[string]any{"name": item.Name}); err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *AdminRepository) UpdateCatalogItem(actorId int64, catalog string, itemId int64, name string) (model.CatalogItem, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	normalizedName := libs.CatalogMatchKey(catalog, name)
+	matchingId, err := findCatalogItemId(ctx, qtx, catalog, normalizedName)
+	if err == nil && matchingId != itemId {
+		return model.CatalogItem{}, ErrCatalogItemExists
+	} else if err != nil && err != sql.ErrNoRows {
+		return model.CatalogItem{}, fmt.Errorf("could not find catalog item: %w", err)
+	}
+
+	item, err := libs.UpdateCatalogItem(ctx, qtx, catalog, itemId, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CatalogItem{}, err
+	} else if isPqError(err, "23505") {
+		return model.CatalogItem{}, ErrCatalogItemExists
+	} else if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not update catalog item: %w", err)
+	}
+
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionUpdateCatalogItem, catalog, item.ID, map[string]any{"name": item.Name}); err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return item, nil
+}
+
+// DeleteCatalogItem refuses to delete items still referenced by user data
+func (r *AdminRepository) DeleteCatalogItem(actorId int64, catalog string, itemId int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	rows, err := libs.DeleteCatalogItem(ctx, qtx, catalog, itemId)
+	if isPqError(err, "23503") {
+		return ErrCatalogItemInUse
+	} else if err != nil {
+		return fmt.Errorf("could not delete catalog item: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	err = qtx.DeleteCatalogPopularityByItemIds(ctx, db.DeleteCatalogPopularityByItemIdsParams{
+		Catalog: catalog,
+		ItemIds: []int64{itemId},
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete catalog popularity: %w", err)
+	}
+
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionDeleteCatalogItem, catalog, itemId, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MergeCatalogItems points user data at the target, keeps the source names as aliases
+// of the target and deletes the sources
+func (r *AdminRepository) MergeCatalogItems(actorId int64, catalog string, targetId int64, sourceIds []int64) (model.CatalogItem, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	target, err := getCatalogItemById(ctx, qtx, catalog, targetId)
+	if err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	if err = mergeCatalogItems(ctx, qtx, catalog, targetId, sourceIds); err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	// The target count catches up on the next popularity refresh
+	err = qtx.DeleteCatalogPopularityByItemIds(ctx, db.DeleteCatalogPopularityByItemIdsParams{
+		Catalog: catalog,
+		ItemIds: sourceIds,
+	})
+	if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not delete catalog popularity: %w", err)
+	}
+
+	details := map[string]any{"source_ids": sourceIds}
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionMergeCatalogItems, catalog, targetId, details); err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return target, nil
+}
+
+// SetSkillParent returns sql.ErrNoRows when the skill or the parent doesn't exist
+func (r *AdminRepository) SetSkillParent(actorId, skillId int64, parentId *int64) (model.CatalogItem, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	parent := sql.NullInt64{}
+	if parentId != nil {
+		ancestorIds, err := qtx.GetSkillAncestorIds(ctx, *parentId)
+		if err != nil {
+			return model.CatalogItem{}, fmt.Errorf("could not get skill ancestors: %w", err)
+		}
+
+		if len(ancestorIds) == 0 {
+			return model.CatalogItem{}, sql.ErrNoRows
+		}
+
+		for _, ancestorId := range ancestorIds {
+			if ancestorId == skillId {
+				return model.CatalogItem{}, ErrSkillCycle
+			}
+		}
+
+		parent = sql.NullInt64{Int64: *parentId, Valid: true}
+	}
+
+	skill, err := qtx.UpdateSkillParent(ctx, db.UpdateSkillParentParams{ParentID: parent, ID: skillId})
+	if err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	details := map[string]any{"parent_id": parentId}
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionSetSkillParent, model.CatalogSkills, skillId, details); err != nil {
+		return model.CatalogItem{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CatalogItem{}, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return model.CatalogItem{ID: skill.ID, Name: skill.Name}, nil
+}
+
+// InsertSkillSynonym refuses names already used by a skill or a synonym, those skills are merged instead
+func (r *AdminRepository) InsertSkillSynonym(actorId, skillId int64, name string) (model.SkillSynonym, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return model.SkillSynonym{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	if _, err = qtx.GetCatalogSkillById(ctx, skillId); err != nil {
+		return model.SkillSynonym{}, err
+	}
+
+	normalizedName := libs.CatalogMatchKey(model.CatalogSkills, name)
+	_, err = findCatalogItemId(ctx, qtx, model.CatalogSkills, normalizedName)
+	if err == nil {
+		return model.SkillSynonym{}, ErrCatalogItemExists
+	} else if err != sql.ErrNoRows {
+		return model.SkillSynonym{}, fmt.Errorf("could not find skill: %w", err)
+	}
+
+	synonym, err := qtx.InsertSkillSynonym(ctx, db.InsertSkillSynonymParams{
+		SkillID:        skillId,
+		Name:           name,
+		NormalizedName: normalizedName,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SkillSynonym{}, ErrCatalogItemExists
+	} else if err != nil {
+		return model.SkillSynonym{}, fmt.Errorf("could not insert skill synonym: %w", err)
+	}
+
+	details := map[string]any{"synonym_id": synonym.ID, "name": synonym.Name}
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionAddSkillSynonym, model.CatalogSkills, skillId, details); err != nil {
+		return model.SkillSynonym{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.SkillSynonym{}, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return model.SkillSynonym{ID: synonym.ID, Name: synonym.Name}, nil
+}
+
+func (r *AdminRepository) DeleteSkillSynonym(actorId, skillId, synonymId int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	rows, err := qtx.DeleteSkillSynonym(ctx, db.DeleteSkillSynonymParams{ID: synonymId, SkillID: skillId})
+	if err != nil {
+		return fmt.Errorf("could not delete skill synonym: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	details := map[string]any{"synonym_id": synonymId}
+	if err = insertAuditLog(ctx, qtx, actorId, model.AuditActionDeleteSkillSynonym, model.CatalogSkills, skillId, details); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AdminRepository) ListOpenReports(targetType string, offset, limit int32) ([]model.AdminReport, int64, error) {
+	data, err := r.query.ListOpenReports(context.Background(), db.ListOpenReportsParams{
+		Offset:     offset,
+		Limit:      limit,
+		TargetType: targetType,
+	})
+	if err != nil {
+		return []model.AdminReport{}, 0, err
+	}
+
+	var totalRows int64
+	reports := make([]model.AdminReport, len(data))
+	for i, v := range data {
+		totalRows = v.TotalRows
+		reports[i] = model.AdminReport{
+			ID: v.ID,
+			Reporter: model.User{
+				ID:        v.ID_2,
+				Fullname:  v.FullName,
+				AvatarUrl: v.AvatarUrl.String,
+			},
+			TargetType: v.TargetType,
+			TargetId:   v.TargetID,
+			Reasons:    v.Reasons,
+			Message:    v.Message.String,
+			CreatedAt:  v.CreatedAt,
+		}
+	}
+
+	return reports, totalRows, nil
+}
+
+func (r *AdminRepository) GetReportById(reportId int64) (model.AdminReport, error) {
+	data, err := r.query.GetReportById(context.Background(), reportId)
+	if err != nil {
+		return model.AdminReport{}, err
+	}
+
+	report := model.AdminReport{
+		ID: data.ID,
+		Reporter: model.User{
+			ID:        data.ID_2,
+			Fullname:  data.FullName,
+			AvatarUrl: data.AvatarUrl.String,
+		},
+		TargetType: data.TargetType,
+		TargetId:   data.TargetID,
+		Reasons:    data.Reasons,
+		Message:    data.Message.String,
+		CreatedAt:  data.CreatedAt,
+	}
+
+	if data.ResolvedAt.Valid {
+		report.ResolvedAt = &data.ResolvedAt.Time
+	}
+
+	return report, nil
+}
+
+// InsertAuditEntry writes the audit row with the caller's transaction queries
+func InsertAuditEntry(ctx context.Context, qtx *db.Queries, entry model.AuditEntry) error {
+	return insertAuditLog(ctx, qtx, entry.ActorId, entry.Action, entry.TargetType, entry.TargetId, entry.Details)
+}
+
+func (r *AdminRepository) ListAuditLogs(actorId int64, targetType string, offset, limit int32) ([]model.AuditLog, int64, error) {
+	data, err := r.query.ListAdminAuditLogs(context.Background(), db.ListAdminAuditLogsParams{
+		Offset:     offset,
+		Limit:      limit,
+		ActorID:    actorId,
+		TargetType: targetType,
+	})
+	if err != nil {
+		return []model.AuditLog{}, 0, err
+	}
+
+	var totalRows int64
+	logs := make([]model.AuditLog, len(data))
+	for i, v := range data {
+		totalRows = v.TotalRows
+		logs[i] = model.AuditLog{
+			ID: v.ID,
+			Actor: model.User{
+				ID:        v.ID_2,
+				Fullname:  v.FullName,
+				AvatarUrl: v.AvatarUrl.String,
+			},
+			Action:     v.Action,
+			TargetType: v.TargetType,
+			Details:    v.Details,
+			CreatedAt:  v.CreatedAt,
+		}
+
+		if v.TargetID.Valid {
+			logs[i].TargetId = &v.TargetID.Int64
+		}
+	}
+
+	return logs, totalRows, nil
+}
+
+func insertAuditLog(ctx context.Context, qtx *db.Queries, actorId int64, action, targetType string, targetId int64, details map[string]any) error {
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	detailsJson, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("could not marshal audit log details: %w", err)
+	}
+
+	err = qtx.InsertAdminAuditLog(ctx, db.InsertAdminAuditLogParams{
+		ActorID:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Details:    detailsJson,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert audit log: %w", err)
+	}
+
+	return nil
+}
+
+// findCatalogItemId returns sql.ErrNoRows when nothing matches
+func findCatalogItemId(ctx context.Context, qtx *db.Queries, catalog, normalizedName string) (int64, error) {
+	if normalizedName == "" {
+		return 0, sql.ErrNoRows
+	}
+
+	switch catalog {
+	case model.CatalogSchools:
+		item, err := qtx.GetSchoolByNormalizedName(ctx, normalizedName)
+		return item.ID, err
+	case model.CatalogCompanies:
+		item, err := qtx.GetCompanyByNormalizedName(ctx, normalizedName)
+		return item.ID, err
+	case model.CatalogJobPositions:
+		item, err := qtx.GetJobPositionByNormalizedName(ctx, normalizedName)
+		return item.ID, err
+	case model.CatalogIssuingOrganizations:
+		item, err := qtx.GetIssuingOrganizationByNormalizedName(ctx, normalizedName)
+		return item.ID, err
+	case model.CatalogSkills:
+		item, err := qtx.GetSkillByNormalizedName(ctx, normalizedName)
+		return item.ID, err
+	}
+
+	return 0, sql.ErrNoRows
+}
+
+func getCatalogItemById(ctx context.Context, qtx *db.Queries, catalog string, itemId int64) (model.CatalogItem, error) {
+	switch catalog {
+	case model.CatalogSchools:
+		item, err := qtx.GetCatalogSchoolById(ctx, itemId)
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogCompanies:
+		item, err := qtx.GetCatalogCompanyById(ctx, itemId)
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogJobPositions:
+		item, err := qtx.GetCatalogJobPositionById(ctx, itemId)
+		return model.CatalogItem{ID: item.ID, Name: item.Name.String}, err
+	case model.CatalogIssuingOrganizations:
+		item, err := qtx.GetCatalogIssuingOrganizationById(ctx, itemId)
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	case model.CatalogSkills:
+		item, err := qtx.GetCatalogSkillById(ctx, itemId)
+		return model.CatalogItem{ID: item.ID, Name: item.Name}, err
+	}
+
+	return model.CatalogItem{}, fmt.Errorf("catalog %q can't be merged", catalog)
+}
+
+// mergeCatalogItems returns sql.ErrNoRows when one of the sources doesn't exist
+func mergeCatalogItems(ctx context.Context, qtx *db.Queries, catalog string, targetId int64, sourceIds []int64) error {
+	var (
+		deleted int64
+		err     error
+	)
+
+	switch catalog {
+	case model.CatalogSchools:
+		err = qtx.MergeEducationSchools(ctx, db.MergeEducationSchoolsParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not merge education schools: %w", err)
+		}
+
+		err = qtx.InsertSchoolAliases(ctx, db.InsertSchoolAliasesParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not insert school aliases: %w", err)
+		}
+
+		deleted, err = qtx.DeleteSchoolsByIds(ctx, sourceIds)
+	case model.CatalogCompanies:
+		err = qtx.MergeWorkExperienceCompanies(ctx, db.MergeWorkExperienceCompaniesParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not merge work experience companies: %w", err)
+		}
+
+		err = qtx.InsertCompanyAliases(ctx, db.InsertCompanyAliasesParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not insert company aliases: %w", err)
+		}
+
+		deleted, err = qtx.DeleteCompaniesByIds(ctx, sourceIds)
+	case model.CatalogJobPositions:
+		err = qtx.MergeUserJobInterestJobPositions(ctx, db.MergeUserJobInterestJobPositionsParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not merge user job interest job positions: %w", err)
+		}
+
+		// Users interested in several merged positions would otherwise list the target twice
+		if err = qtx.DeleteDuplicateUserJobInterests(ctx, targetId); err != nil {
+			return fmt.Errorf("could not delete duplicate user job interests: %w", err)
+		}
+
+		err = qtx.InsertJobPositionAliases(ctx, db.InsertJobPositionAliasesParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not insert job position aliases: %w", err)
+		}
+
+		deleted, err = qtx.DeleteJobPositionsByIds(ctx, sourceIds)
+	case model.CatalogIssuingOrganizations:
+		err = qtx.MergeCertificateIssuingOrganizations(ctx, db.MergeCertificateIssuingOrganizationsParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not merge certificate issuing organizations: %w", err)
+		}
+
+		err = qtx.InsertIssuingOrganizationAliases(ctx, db.InsertIssuingOrganizationAliasesParams{TargetID: targetId, SourceIds: sourceIds})
+		if err != nil {
+			return fmt.Errorf("could not insert issuing organization aliases: %w", err)
+		}
+
+		deleted, err = qtx.DeleteIssuingOrganizationsByIds(ctx, sourceIds)
+	case model.CatalogSkills:
+		// A source above the target would leave the target among its own descendants
+		var ancestorIds []int64
+		ancestorIds, err = qtx.GetSkillAncestorIds(ctx, targetId)
+		if err != nil {
+			return fmt.Errorf("could not get skill ancestors: %w", err)
+		}
+
+		for _, ancestorId := range ancestorIds {
+			for _, sourceId := range sourceIds {
+				if ancestorId == sourceId {
+					return ErrSkillCycle
+				}
+			}
+		}
+
+		if err = mergeSkills(ctx, qtx, targetId, sourceIds); err != nil {
+			return err
+		}
+
+		deleted, err = qtx.DeleteSkillsByIds(ctx, sourceIds)
+	default:
+		return fmt.Errorf("catalog %q can't be merged", catalog)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not delete merged catalog items: %w", err)
+	}
+
+	if deleted != int64(len(sourceIds)) {
+		return sql.ErrNoRows
+	}
+
+	err = qtx.MoveCatalogAliases(ctx, db.MoveCatalogAliasesParams{
+		TargetID:  targetId,
+		Catalog:   catalog,
+		SourceIds: sourceIds,
+	})
+	if err != nil {
+		return fmt.Errorf("could not move catalog aliases: %w", err)
+	}
+
+	return nil
+}
+
+// mergeSkills moves the user skills of the sources to the target, along with the educations and
+// work experiences listing them, and hangs the children of the sources under the target
+func mergeSkills(ctx context.Context, qtx *db.Queries, targetId int64, sourceIds []int64) error {
+	if err := qtx.MergeUserSkills(ctx, db.MergeUserSkillsParams{TargetID: targetId, SourceIds: sourceIds}); err != nil {
+		return fmt.Errorf("could not merge user skills: %w", err)
+	}
+
+	if err := qtx.MergeEducationSkills(ctx, db.MergeEducationSkillsParams{TargetID: targetId, SourceIds: sourceIds}); err != nil {
+		return fmt.Errorf("could not merge education skills: %w", err)
+	}
+
+	if err := qtx.MergeWorkExperienceSkills(ctx, db.MergeWorkExperienceSkillsParams{TargetID: targetId, SourceIds: sourceIds}); err != nil {
+		return fmt.Errorf("could not merge work experience skills: %w", err)
+	}
+
+	if err := qtx.DeleteEducationSkillsBySkillIds(ctx, sourceIds); err != nil {
+		return fmt.Errorf("could not delete merged education skills: %w", err)
+	}
+
+	if err := qtx.DeleteWorkExperienceSkillsBySkillIds(ctx, sourceIds); err != nil {
+		return fmt.Errorf("could not delete merged work experience skills: %w", err)
+	}
+
+	if err := qtx.DeleteUserSkillsBySkillIds(ctx, sourceIds); err != nil {
+		return fmt.Errorf("could not delete merged user skills: %w", err)
+	}
+
+	if err := qtx.MoveSkillChildren(ctx, db.MoveSkillChildrenParams{TargetID: targetId, SourceIds: sourceIds}); err != nil {
+		return fmt.Errorf("could not move skill children: %w", err)
+	}
+
+	if err := qtx.InsertSkillAliases(ctx, db.InsertSkillAliasesParams{TargetID: targetId, SourceIds: sourceIds}); err != nil {
+		return fmt.Errorf("could not insert skill aliases: %w", err)
+	}
+
+	return nil
+}
+
+func isPqError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
@@ -0,0 +1,476 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"profiln-be/libs"
+	"profiln-be/model"
+	repository "profiln-be/package/admin/repository"
+	"profiln-be/package/moderation"
+
+	"github.com/sirupsen/logrus"
+)
+
+type IAdminUsecase interface {
+	ListUsers(search string, pagination model.PaginationRequest) (resp model.Response)
+	GetUser(userId int64) (resp model.Response)
+	ListRoles() (resp model.Response)
+	AssignRole(actorId, userId int64, props *model.UserRoleRequest) model.Response
+	RevokeRole(actorId, userId int64, role string) model.Response
+	InsertCatalogItem(actorId int64, catalog string, props *model.CatalogItemRequest) model.Response
+	UpdateCatalogItem(actorId int64, catalog string, itemId int64, props *model.CatalogItemRequest) model.Response
+	DeleteCatalogItem(actorId int64, catalog string, itemId int64) model.Response
+	MergeCatalogItems(actorId int64, catalog string, itemId int64, props *model.CatalogMergeRequest) model.Response
+	SetSkillParent(actorId, skillId int64, props *model.SkillParentRequest) model.Response
+	InsertSkillSynonym(actorId, skillId int64, props *model.SkillSynonymRequest) model.Response
+	DeleteSkillSynonym(actorId, skillId, synonymId int64) model.Response
+	ListReports(targetType string, pagination model.PaginationRequest) (resp model.Response)
+	ActOnReport(actorId, reportId int64, props *model.ModerationActionRequest) model.Response
+	ListAuditLogs(actorId int64, targetType string, pagination model.PaginationRequest) (resp model.Response)
+}
+
+type AdminUsecase struct {
+	repository        repository.IAdminRepository
+	moderationUsecase moderation.IModerationUsecase
+	log               *logrus.Logger
+}
+
+func NewAdminUsecase(repository repository.IAdminRepository, moderationUsecase moderation.IModerationUsecase, log *logrus.Logger) IAdminUsecase {
+	return &AdminUsecase{
+		repository,
+		moderationUsecase,
+		log,
+	}
+}
+
+func (u *AdminUsecase) ListUsers(search string, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListUsers(search, int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListUsers: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get users")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+func (u *AdminUsecase) GetUser(userId int64) (resp model.Response) {
+	user, err := u.repository.GetUserById(userId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetUserById (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get user")
+	resp.Data = user
+	return
+}
+
+func (u *AdminUsecase) ListRoles() (resp model.Response) {
+	roles, err := u.repository.ListRoles()
+	if err != nil {
+		u.log.Errorf("repository.ListRoles: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get roles")
+	resp.Data = roles
+	return
+}
+
+func (u *AdminUsecase) AssignRole(actorId, userId int64, props *model.UserRoleRequest) model.Response {
+	if resp, ok := u.checkRoleTarget(userId, props.Role); !ok {
+		return resp
+	}
+
+	assigned, err := u.repository.AssignRole(actorId, userId, props.Role)
+	if err != nil {
+		u.log.Errorf("repository.AssignRole (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if !assigned {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "User already has the role"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success assign role"),
+	}
+}
+
+func (u *AdminUsecase) RevokeRole(actorId, userId int64, role string) model.Response {
+	if resp, ok := u.checkRoleTarget(userId, role); !ok {
+		return resp
+	}
+
+	revoked, err := u.repository.RevokeRole(actorId, userId, role)
+	if err != nil {
+		u.log.Errorf("repository.RevokeRole (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if !revoked {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "User doesn't have the role"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success revoke role"),
+	}
+}
+
+// checkRoleTarget makes sure both the user and the role exist
+func (u *AdminUsecase) checkRoleTarget(userId int64, role string) (model.Response, bool) {
+	_, err := u.repository.GetUserById(userId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}, false
+	} else if err != nil {
+		u.log.Errorf("repository.GetUserById (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}, false
+	}
+
+	exists, err := u.repository.RoleExists(role)
+	if err != nil {
+		u.log.Errorf("repository.RoleExists (role %s): %v", role, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}, false
+	}
+
+	if !exists {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Role not found"),
+		}, false
+	}
+
+	return model.Response{}, true
+}
+
+func (u *AdminUsecase) InsertCatalogItem(actorId int64, catalog string, props *model.CatalogItemRequest) model.Response {
+	if !libs.IsCatalog(catalog) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Catalog not found"),
+		}
+	}
+
+	item, err := u.repository.InsertCatalogItem(actorId, catalog, props.Name)
+	if errors.Is(err, repository.ErrCatalogItemExists) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Catalog item already exists"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.InsertCatalogItem (catalog %s): %v", catalog, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusCreated, "Success create catalog item"),
+		Data:   item,
+	}
+}
+
+func (u *AdminUsecase) UpdateCatalogItem(actorId int64, catalog string, itemId int64, props *model.CatalogItemRequest) model.Response {
+	if !libs.IsCatalog(catalog) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Catalog not found"),
+		}
+	}
+
+	item, err := u.repository.UpdateCatalogItem(actorId, catalog, itemId, props.Name)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if errors.Is(err, repository.ErrCatalogItemExists) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Catalog item already exists"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.UpdateCatalogItem (catalog %s, item id %d): %v", catalog, itemId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success update catalog item"),
+		Data:   item,
+	}
+}
+
+func (u *AdminUsecase) DeleteCatalogItem(actorId int64, catalog string, itemId int64) model.Response {
+	if !libs.IsCatalog(catalog) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Catalog not found"),
+		}
+	}
+
+	err := u.repository.DeleteCatalogItem(actorId, catalog, itemId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if errors.Is(err, repository.ErrCatalogItemInUse) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Catalog item is still in use"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.DeleteCatalogItem (catalog %s, item id %d): %v", catalog, itemId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success delete catalog item"),
+	}
+}
+
+func (u *AdminUsecase) MergeCatalogItems(actorId int64, catalog string, itemId int64, props *model.CatalogMergeRequest) model.Response {
+	if !libs.IsCatalog(catalog) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Catalog not found"),
+		}
+	}
+
+	var (
+		sourceIds []int64
+		seen      = make(map[int64]bool)
+	)
+	for _, sourceId := range props.SourceIds {
+		if sourceId == itemId {
+			return model.Response{
+				Status: libs.CustomResponse(http.StatusBadRequest, "Can't merge an item into itself"),
+			}
+		}
+
+		if !seen[sourceId] {
+			seen[sourceId] = true
+			sourceIds = append(sourceIds, sourceId)
+		}
+	}
+
+	item, err := u.repository.MergeCatalogItems(actorId, catalog, itemId, sourceIds)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if errors.Is(err, repository.ErrSkillCycle) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Can't merge a skill into one of its descendants"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.MergeCatalogItems (catalog %s, item id %d): %v", catalog, itemId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success merge catalog items"),
+		Data:   item,
+	}
+}
+
+func (u *AdminUsecase) SetSkillParent(actorId, skillId int64, props *model.SkillParentRequest) model.Response {
+	if props.ParentId != nil && *props.ParentId == skillId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Skill can't be its own parent"),
+		}
+	}
+
+	skill, err := u.repository.SetSkillParent(actorId, skillId, props.ParentId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if errors.Is(err, repository.ErrSkillCycle) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Skill can't be placed under one of its descendants"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.SetSkillParent (skill id %d): %v", skillId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success set skill parent"),
+		Data:   skill,
+	}
+}
+
+func (u *AdminUsecase) InsertSkillSynonym(actorId, skillId int64, props *model.SkillSynonymRequest) model.Response {
+	synonym, err := u.repository.InsertSkillSynonym(actorId, skillId, props.Name)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if errors.Is(err, repository.ErrCatalogItemExists) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Name already belongs to a skill, merge the skills instead"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.InsertSkillSynonym (skill id %d): %v", skillId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusCreated, "Success add skill synonym"),
+		Data:   synonym,
+	}
+}
+
+func (u *AdminUsecase) DeleteSkillSynonym(actorId, skillId, synonymId int64) model.Response {
+	err := u.repository.DeleteSkillSynonym(actorId, skillId, synonymId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.DeleteSkillSynonym (skill id %d, synonym id %d): %v", skillId, synonymId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success delete skill synonym"),
+	}
+}
+
+func (u *AdminUsecase) ListReports(targetType string, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListOpenReports(targetType, int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListOpenReports: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get reports")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+// ActOnReport hands the report over to the moderation flow of its target,
+// which writes the audit entry in the same transaction as the action
+func (u *AdminUsecase) ActOnReport(actorId, reportId int64, props *model.ModerationActionRequest) model.Response {
+	report, err := u.repository.GetReportById(reportId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetReportById (report id %d): %v", reportId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if report.ResolvedAt != nil {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Report is already resolved"),
+		}
+	}
+
+	audit := &model.AuditEntry{
+		ActorId:    actorId,
+		Action:     model.AuditActionActOnReport,
+		TargetType: model.AuditTargetReport,
+		TargetId:   report.ID,
+		Details: map[string]any{
+			"action":      props.Action,
+			"target_type": report.TargetType,
+			"target_id":   report.TargetId,
+			"note":        props.Note,
+		},
+	}
+
+	if report.TargetType == model.ReportTargetPost {
+		return u.moderationUsecase.ActOnReportedPost(actorId, report.TargetId, props, audit)
+	}
+
+	return u.moderationUsecase.ActOnReportedContent(actorId, report.TargetType, report.TargetId, props, audit)
+}
+
+func (u *AdminUsecase) ListAuditLogs(actorId int64, targetType string, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListAuditLogs(actorId, targetType, int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListAuditLogs: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get audit logs")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
@@ -1,29 +1,212 @@
 -- name: GetSchools :many
-SELECT *, COUNT(*) OVER () AS total_rows
+SELECT id, name, COUNT(*) OVER () AS total_rows
 FROM schools
+ORDER BY name, id
 OFFSET $1
 LIMIT $2;
 
+-- name: GetSchoolsByCursor :many
+SELECT id, name
+FROM schools
+WHERE @cursor_id::bigint = 0 OR (name, id) > (@cursor_name::text, @cursor_id::bigint)
+ORDER BY name, id
+LIMIT $1;
+
 -- name: GetCompanies :many
-SELECT *, COUNT(*) OVER () AS total_rows
+SELECT id, name, COUNT(*) OVER () AS total_rows
 FROM companies
+ORDER BY name, id
 OFFSET $1
 LIMIT $2;
 
+-- name: GetCompaniesByCursor :many
+SELECT id, name
+FROM companies
+WHERE @cursor_id::bigint = 0 OR (name, id) > (@cursor_name::text, @cursor_id::bigint)
+ORDER BY name, id
+LIMIT $1;
+
 -- name: GetIssuingOrganizations :many
-SELECT *, COUNT(*) OVER () AS total_rows
+SELECT id, name, COUNT(*) OVER () AS total_rows
 FROM issuing_organizations
+ORDER BY name, id
 OFFSET $1
 LIMIT $2;
 
+-- name: GetIssuingOrganizationsByCursor :many
+SELECT id, name
+FROM issuing_organizations
+WHERE @cursor_id::bigint = 0 OR (name, id) > (@cursor_name::text, @cursor_id::bigint)
+ORDER BY name, id
+LIMIT $1;
+
 -- name: GetSkills :many
-SELECT *, COUNT(id) OVER () AS total_rows
+SELECT id, name, COUNT(id) OVER () AS total_rows
 FROM skills
+ORDER BY name, id
 OFFSET $1
 LIMIT $2;
 
+-- name: GetSkillsByCursor :many
+SELECT id, name
+FROM skills
+WHERE @cursor_id::bigint = 0 OR (name, id) > (@cursor_name::text, @cursor_id::bigint)
+ORDER BY name, id
+LIMIT $1;
+
 -- name: GetJobPositions :many
-SELECT *, COUNT(id) OVER () AS total_rows
+SELECT id, name, COUNT(id) OVER () AS total_rows
 FROM job_positions
+ORDER BY name, id
 OFFSET $1
-LIMIT $2;
\ No newline at end of file
+LIMIT $2;
+
+-- name: GetJobPositionsByCursor :many
+SELECT id, COALESCE(name, '')::text AS name
+FROM job_positions
+WHERE @cursor_id::bigint = 0 OR (COALESCE(name, ''), id) > (@cursor_name::text, @cursor_id::bigint)
+ORDER BY COALESCE(name, ''), id
+LIMIT $1;
+
+-- name: SearchSchools :many
+-- Merged schools are found by their alias names too
+WITH matched AS (
+    SELECT s.id
+    FROM schools s
+    WHERE s.name ILIKE @prefix::text OR s.name % @q::text
+    UNION
+    SELECT ca.item_id
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'schools' AND (ca.name ILIKE @prefix::text OR ca.name % @q::text)
+)
+SELECT s.id, s.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM schools s
+JOIN matched m ON s.id = m.id
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'schools' AND cp.item_id = s.id
+ORDER BY s.name ILIKE @prefix::text OR EXISTS (
+        SELECT 1 FROM catalog_aliases ca
+        WHERE ca.catalog = 'schools' AND ca.item_id = s.id AND ca.name ILIKE @prefix::text
+    ) DESC,
+    popularity DESC, similarity(s.name, @q::text) DESC, s.name
+LIMIT @row_limit::int;
+
+-- name: SearchCompanies :many
+-- Merged companies are found by their alias names too
+WITH matched AS (
+    SELECT c.id
+    FROM companies c
+    WHERE c.name ILIKE @prefix::text OR c.name % @q::text
+    UNION
+    SELECT ca.item_id
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'companies' AND (ca.name ILIKE @prefix::text OR ca.name % @q::text)
+)
+SELECT c.id, c.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM companies c
+JOIN matched m ON c.id = m.id
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'companies' AND cp.item_id = c.id
+ORDER BY c.name ILIKE @prefix::text OR EXISTS (
+        SELECT 1 FROM catalog_aliases ca
+        WHERE ca.catalog = 'companies' AND ca.item_id = c.id AND ca.name ILIKE @prefix::text
+    ) DESC,
+    popularity DESC, similarity(c.name, @q::text) DESC, c.name
+LIMIT @row_limit::int;
+
+-- name: SearchIssuingOrganizations :many
+SELECT io.id, io.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM issuing_organizations io
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'issuing-organizations' AND cp.item_id = io.id
+WHERE io.name ILIKE @prefix::text OR io.name % @q::text
+ORDER BY io.name ILIKE @prefix::text DESC, popularity DESC, similarity(io.name, @q::text) DESC, io.name
+LIMIT @row_limit::int;
+
+-- name: SearchSkills :many
+-- Skills matching by name or synonym come first, then the skills under them
+WITH RECURSIVE matched AS (
+    SELECT sk.id, 0 AS depth
+    FROM skills sk
+    WHERE sk.name ILIKE @prefix::text OR sk.name % @q::text
+    UNION
+    SELECT ca.item_id, 0
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'skills' AND (ca.name ILIKE @prefix::text OR ca.name % @q::text)
+    UNION
+    SELECT child.id, m.depth + 1
+    FROM skills child
+    JOIN matched m ON child.parent_id = m.id
+    WHERE m.depth < 3
+)
+SELECT sk.id, sk.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM skills sk
+JOIN (SELECT id, MIN(depth) AS depth FROM matched GROUP BY id) m ON sk.id = m.id
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'skills' AND cp.item_id = sk.id
+ORDER BY m.depth,
+    sk.name ILIKE @prefix::text OR EXISTS (
+        SELECT 1 FROM catalog_aliases ca
+        WHERE ca.catalog = 'skills' AND ca.item_id = sk.id AND ca.name ILIKE @prefix::text
+    ) DESC,
+    popularity DESC, similarity(sk.name, @q::text) DESC, sk.name
+LIMIT @row_limit::int;
+
+-- name: SearchJobPositions :many
+SELECT jp.id, jp.name, COALESCE(cp.user_count, 0)::bigint AS popularity
+FROM job_positions jp
+LEFT JOIN catalog_popularity cp ON cp.catalog = 'job-positions' AND cp.item_id = jp.id
+WHERE jp.name ILIKE @prefix::text OR jp.name % @q::text
+ORDER BY jp.name ILIKE @prefix::text DESC, popularity DESC, similarity(jp.name, @q::text) DESC, jp.name
+LIMIT @row_limit::int;
+
+-- name: DeleteCatalogPopularity :exec
+DELETE FROM catalog_popularity;
+
+-- name: InsertCatalogPopularity :exec
+INSERT INTO catalog_popularity (catalog, item_id, user_count)
+SELECT 'schools', e.school_id, COUNT(DISTINCT e.user_id)::int FROM educations e WHERE e.school_id IS NOT NULL GROUP BY e.school_id
+UNION ALL
+SELECT 'companies', we.company_id, COUNT(DISTINCT we.user_id)::int FROM work_experiences we WHERE we.company_id IS NOT NULL GROUP BY we.company_id
+UNION ALL
+SELECT 'skills', us.skill_id, COUNT(DISTINCT us.user_id)::int FROM user_skills us WHERE us.skill_id IS NOT NULL GROUP BY us.skill_id
+UNION ALL
+SELECT 'job-positions', uji.job_position_id, COUNT(DISTINCT uji.user_id)::int FROM user_job_interests uji WHERE uji.job_position_id IS NOT NULL GROUP BY uji.job_position_id
+UNION ALL
+SELECT 'issuing-organizations', ce.issuing_organization_id, COUNT(DISTINCT ce.user_id)::int FROM certificates ce WHERE ce.issuing_organization_id IS NOT NULL GROUP BY ce.issuing_organization_id;
+
+-- name: ListAllSchools :many
+SELECT id, name FROM schools ORDER BY id;
+
+-- name: ListAllCompanies :many
+SELECT id, name FROM companies ORDER BY id;
+
+-- name: ListAllIssuingOrganizations :many
+SELECT id, name FROM issuing_organizations ORDER BY id;
+
+-- name: ListAllSkills :many
+SELECT id, name FROM skills ORDER BY id;
+
+-- name: ListAllJobPositions :many
+SELECT id, name FROM job_positions ORDER BY id;
+
+-- name: GetSkillAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT s.id, s.name, s.parent_id, 0 AS depth
+    FROM skills s
+    WHERE s.id = @id::bigint
+    UNION ALL
+    SELECT p.id, p.name, p.parent_id, a.depth + 1
+    FROM skills p
+    JOIN ancestors a ON p.id = a.parent_id
+    WHERE a.depth < 20
+)
+SELECT id, name FROM ancestors
+WHERE depth > 0
+ORDER BY depth DESC;
+
+-- name: ListSkillChildren :many
+SELECT id, name FROM skills
+WHERE parent_id = @parent_id::bigint
+ORDER BY name, id;
+
+-- name: ListSkillSynonyms :many
+SELECT id, name FROM catalog_aliases
+WHERE catalog = 'skills' AND item_id = @skill_id::bigint
+ORDER BY name, id;
\ No newline at end of file
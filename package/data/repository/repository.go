@@ -3,7 +3,9 @@ package data
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	db "profiln-be/db/sqlc"
+	"profiln-be/libs"
 	"profiln-be/model"
 )
 
@@ -13,6 +15,13 @@ type IDataRepository interface {
 	GetIssuingOrganizations(offset, limit int32) ([]model.IssuingOrganization, int64, error)
 	GetSkills(offset, limit int32) ([]model.Skill, int64, error)
 	GetJobPositions(offset, limit int32) ([]model.JobPosition, int64, error)
+	SearchCatalog(catalog, q string, limit int32) ([]model.CatalogItem, error)
+	SearchCatalogContext(ctx context.Context, catalog, q string, limit int32) ([]model.CatalogItem, error)
+	GetCatalogByCursor(catalog string, cursor *model.PageCursor, limit int32) ([]model.CatalogItem, *model.PageCursor, error)
+	GetSkillTaxonomy(skillId int64) (model.SkillTaxonomy, error)
+	ListCatalogItems(catalog string) ([]model.CatalogItem, error)
+	ImportCatalogItems(catalog string, diff model.CatalogImportDiff) error
+	RefreshCatalogPopularity(ctx context.Context) error
 }
 
 type DataRepository struct {
@@ -155,3 +164,303 @@ func (r *DataRepository) GetJobPositions(offset, limit int32) ([]model.JobPositi
 
 	return data, count, nil
 }
+
+// SearchCatalog matches names starting with q first, then similar names, most used entries first.
+// Skills also match by synonym and bring the skills under the matches along
+func (r *DataRepository) SearchCatalog(catalog, q string, limit int32) ([]model.CatalogItem, error) {
+	return r.SearchCatalogContext(context.Background(), catalog, q, limit)
+}
+
+// SearchCatalogContext stops the search query when ctx is done
+func (r *DataRepository) SearchCatalogContext(ctx context.Context, catalog, q string, limit int32) ([]model.CatalogItem, error) {
+	var (
+		prefix = libs.EscapeLikePattern(q) + "%"
+		items  []model.CatalogItem
+	)
+
+	switch catalog {
+	case model.CatalogSchools:
+		rows, err := r.query.SearchSchools(ctx, db.SearchSchoolsParams{Prefix: prefix, Q: q, RowLimit: limit})
+		if err != nil {
+			return []model.CatalogItem{}, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogCompanies:
+		rows, err := r.query.SearchCompanies(ctx, db.SearchCompaniesParams{Prefix: prefix, Q: q, RowLimit: limit})
+		if err != nil {
+			return []model.CatalogItem{}, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogSkills:
+		rows, err := r.query.SearchSkills(ctx, db.SearchSkillsParams{Prefix: prefix, Q: q, RowLimit: limit})
+		if err != nil {
+			return []model.CatalogItem{}, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogJobPositions:
+		rows, err := r.query.SearchJobPositions(ctx, db.SearchJobPositionsParams{Prefix: prefix, Q: q, RowLimit: limit})
+		if err != nil {
+			return []model.CatalogItem{}, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name.String})
+		}
+	case model.CatalogIssuingOrganizations:
+		rows, err := r.query.SearchIssuingOrganizations(ctx, db.SearchIssuingOrganizationsParams{Prefix: prefix, Q: q, RowLimit: limit})
+		if err != nil {
+			return []model.CatalogItem{}, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	default:
+		return []model.CatalogItem{}, fmt.Errorf("unknown catalog %q", catalog)
+	}
+
+	if items == nil {
+		items = []model.CatalogItem{}
+	}
+
+	return items, nil
+}
+
+func (r *DataRepository) GetCatalogByCursor(catalog string, cursor *model.PageCursor, limit int32) ([]model.CatalogItem, *model.PageCursor, error) {
+	var (
+		ctx   = context.Background()
+		items []model.CatalogItem
+	)
+
+	var cursorId int64
+	var cursorName string
+	if cursor != nil {
+		cursorId, cursorName = cursor.ID, cursor.Name
+	}
+
+	// fetch one extra row to know whether there is a next page
+	switch catalog {
+	case model.CatalogSchools:
+		rows, err := r.query.GetSchoolsByCursor(ctx, db.GetSchoolsByCursorParams{Limit: limit + 1, CursorID: cursorId, CursorName: cursorName})
+		if err != nil {
+			return []model.CatalogItem{}, nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogCompanies:
+		rows, err := r.query.GetCompaniesByCursor(ctx, db.GetCompaniesByCursorParams{Limit: limit + 1, CursorID: cursorId, CursorName: cursorName})
+		if err != nil {
+			return []model.CatalogItem{}, nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogSkills:
+		rows, err := r.query.GetSkillsByCursor(ctx, db.GetSkillsByCursorParams{Limit: limit + 1, CursorID: cursorId, CursorName: cursorName})
+		if err != nil {
+			return []model.CatalogItem{}, nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogJobPositions:
+		rows, err := r.query.GetJobPositionsByCursor(ctx, db.GetJobPositionsByCursorParams{Limit: limit + 1, CursorID: cursorId, CursorName: cursorName})
+		if err != nil {
+			return []model.CatalogItem{}, nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogIssuingOrganizations:
+		rows, err := r.query.GetIssuingOrganizationsByCursor(ctx, db.GetIssuingOrganizationsByCursorParams{Limit: limit + 1, CursorID: cursorId, CursorName: cursorName})
+		if err != nil {
+			return []model.CatalogItem{}, nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	default:
+		return []model.CatalogItem{}, nil, fmt.Errorf("unknown catalog %q", catalog)
+	}
+
+	if items == nil {
+		items = []model.CatalogItem{}
+	}
+
+	var next *model.PageCursor
+	if len(items) > int(limit) {
+		items = items[:limit]
+		next = &model.PageCursor{Name: items[limit-1].Name, ID: items[limit-1].ID}
+	}
+
+	return items, next, nil
+}
+
+func (r *DataRepository) GetSkillTaxonomy(skillId int64) (model.SkillTaxonomy, error) {
+	ctx := context.Background()
+
+	skill, err := r.query.GetCatalogSkillById(ctx, skillId)
+	if err != nil {
+		return model.SkillTaxonomy{}, err
+	}
+
+	ancestors, err := r.query.GetSkillAncestors(ctx, skillId)
+	if err != nil {
+		return model.SkillTaxonomy{}, fmt.Errorf("could not get skill ancestors: %w", err)
+	}
+
+	children, err := r.query.ListSkillChildren(ctx, skillId)
+	if err != nil {
+		return model.SkillTaxonomy{}, fmt.Errorf("could not list skill children: %w", err)
+	}
+
+	synonyms, err := r.query.ListSkillSynonyms(ctx, skillId)
+	if err != nil {
+		return model.SkillTaxonomy{}, fmt.Errorf("could not list skill synonyms: %w", err)
+	}
+
+	data := model.SkillTaxonomy{
+		ID:        skill.ID,
+		Name:      skill.Name,
+		Ancestors: make([]model.CatalogItem, len(ancestors)),
+		Children:  make([]model.CatalogItem, len(children)),
+		Synonyms:  make([]model.SkillSynonym, len(synonyms)),
+	}
+
+	for i, v := range ancestors {
+		data.Ancestors[i] = model.CatalogItem{ID: v.ID, Name: v.Name}
+	}
+
+	for i, v := range children {
+		data.Children[i] = model.CatalogItem{ID: v.ID, Name: v.Name}
+	}
+
+	for i, v := range synonyms {
+		data.Synonyms[i] = model.SkillSynonym{ID: v.ID, Name: v.Name}
+	}
+
+	return data, nil
+}
+
+func (r *DataRepository) ListCatalogItems(catalog string) ([]model.CatalogItem, error) {
+	var (
+		ctx   = context.Background()
+		items = []model.CatalogItem{}
+	)
+
+	switch catalog {
+	case model.CatalogSchools:
+		rows, err := r.query.ListAllSchools(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogCompanies:
+		rows, err := r.query.ListAllCompanies(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogSkills:
+		rows, err := r.query.ListAllSkills(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	case model.CatalogJobPositions:
+		rows, err := r.query.ListAllJobPositions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name.String})
+		}
+	case model.CatalogIssuingOrganizations:
+		rows, err := r.query.ListAllIssuingOrganizations(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range rows {
+			items = append(items, model.CatalogItem{ID: v.ID, Name: v.Name})
+		}
+	default:
+		return nil, fmt.Errorf("unknown catalog %q", catalog)
+	}
+
+	return items, nil
+}
+
+// ImportCatalogItems applies the whole diff or nothing of it
+func (r *DataRepository) ImportCatalogItems(catalog string, diff model.CatalogImportDiff) error {
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+	ctx := context.Background()
+
+	for _, rename := range diff.Renames {
+		if _, err := libs.UpdateCatalogItem(ctx, qtx, catalog, rename.ID, rename.To); err != nil {
+			return fmt.Errorf("could not rename %q to %q: %w", rename.From, rename.To, err)
+		}
+	}
+
+	for _, name := range diff.Inserts {
+		// Inserting a name that already exists is a no-op, the import stays idempotent
+		if _, err := libs.InsertCatalogItem(ctx, qtx, catalog, name); err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("could not insert %q: %w", name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RefreshCatalogPopularity rebuilds the user counts the typeahead search ranks by,
+// rows of deleted items go away with the rebuild
+func (r *DataRepository) RefreshCatalogPopularity(ctx context.Context) error {
+	tx, err := r.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	if err := qtx.DeleteCatalogPopularity(ctx); err != nil {
+		return fmt.Errorf("could not delete catalog popularity: %w", err)
+	}
+
+	if err := qtx.InsertCatalogPopularity(ctx); err != nil {
+		return fmt.Errorf("could not insert catalog popularity: %w", err)
+	}
+
+	return tx.Commit()
+}
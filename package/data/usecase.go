@@ -1,10 +1,13 @@
 package data
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
 	"profiln-be/libs"
 	"profiln-be/model"
 	repository "profiln-be/package/data/repository"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -15,6 +18,9 @@ type IDataUsecase interface {
 	GetIssuingOrganizations(pagination model.PaginationRequest) model.Response
 	GetSkills(pagination model.PaginationRequest) model.Response
 	GetJobPositions(pagination model.PaginationRequest) model.Response
+	SearchCatalog(catalog, q string, limit int) model.Response
+	GetSkillTaxonomy(skillId int64) model.Response
+	RefreshCatalogPopularityEvery(ctx context.Context, interval time.Duration)
 }
 
 type DataUsecase struct {
@@ -30,6 +36,10 @@ func NewDataUsecase(repository repository.IDataRepository, log *logrus.Logger) I
 }
 
 func (u *DataUsecase) GetSchools(pagination model.PaginationRequest) model.Response {
+	if pagination.Page == 0 {
+		return u.getCatalogByCursor(model.CatalogSchools, "schools", pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 
 	data, totalRows, err := u.repository.GetSchools(int32(offset), int32(pagination.Limit))
@@ -44,8 +54,8 @@ func (u *DataUsecase) GetSchools(pagination model.PaginationRequest) model.Respo
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -59,6 +69,10 @@ func (u *DataUsecase) GetSchools(pagination model.PaginationRequest) model.Respo
 }
 
 func (u *DataUsecase) GetCompanies(pagination model.PaginationRequest) model.Response {
+	if pagination.Page == 0 {
+		return u.getCatalogByCursor(model.CatalogCompanies, "companies", pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 
 	data, totalRows, err := u.repository.GetCompanies(int32(offset), int32(pagination.Limit))
@@ -73,8 +87,8 @@ func (u *DataUsecase) GetCompanies(pagination model.PaginationRequest) model.Res
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -88,6 +102,10 @@ func (u *DataUsecase) GetCompanies(pagination model.PaginationRequest) model.Res
 }
 
 func (u *DataUsecase) GetIssuingOrganizations(pagination model.PaginationRequest) model.Response {
+	if pagination.Page == 0 {
+		return u.getCatalogByCursor(model.CatalogIssuingOrganizations, "issuing organizations", pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 
 	data, totalRows, err := u.repository.GetIssuingOrganizations(int32(offset), int32(pagination.Limit))
@@ -102,8 +120,8 @@ func (u *DataUsecase) GetIssuingOrganizations(pagination model.PaginationRequest
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -117,6 +135,10 @@ func (u *DataUsecase) GetIssuingOrganizations(pagination model.PaginationRequest
 }
 
 func (u *DataUsecase) GetSkills(pagination model.PaginationRequest) model.Response {
+	if pagination.Page == 0 {
+		return u.getCatalogByCursor(model.CatalogSkills, "skills", pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 
 	data, totalRows, err := u.repository.GetSkills(int32(offset), int32(pagination.Limit))
@@ -131,8 +153,8 @@ func (u *DataUsecase) GetSkills(pagination model.PaginationRequest) model.Respon
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -146,6 +168,10 @@ func (u *DataUsecase) GetSkills(pagination model.PaginationRequest) model.Respon
 }
 
 func (u *DataUsecase) GetJobPositions(pagination model.PaginationRequest) model.Response {
+	if pagination.Page == 0 {
+		return u.getCatalogByCursor(model.CatalogJobPositions, "job positions", pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 
 	data, totalRows, err := u.repository.GetJobPositions(int32(offset), int32(pagination.Limit))
@@ -160,8 +186,8 @@ func (u *DataUsecase) GetJobPositions(pagination model.PaginationRequest) model.
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -173,3 +199,82 @@ func (u *DataUsecase) GetJobPositions(pagination model.PaginationRequest) model.
 		},
 	}
 }
+
+func (u *DataUsecase) getCatalogByCursor(catalog, label string, pagination model.PaginationRequest) model.Response {
+	data, next, err := u.repository.GetCatalogByCursor(catalog, pagination.Cursor, int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.GetCatalogByCursor(%s): %v", catalog, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	paginate := model.PaginationResponse{
+		CurrentRowsCount: len(data),
+		NextCursor:       libs.EncodePageCursor(next),
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success fetch "+label),
+		Data: map[string]any{
+			"pagination": paginate,
+			"data":       data,
+		},
+	}
+}
+
+func (u *DataUsecase) SearchCatalog(catalog, q string, limit int) model.Response {
+	data, err := u.repository.SearchCatalog(catalog, q, int32(limit))
+	if err != nil {
+		u.log.Errorf("repository.SearchCatalog (catalog %s): %v", catalog, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success search catalog"),
+		Data:   data,
+	}
+}
+
+func (u *DataUsecase) GetSkillTaxonomy(skillId int64) model.Response {
+	data, err := u.repository.GetSkillTaxonomy(skillId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetSkillTaxonomy (skill id %d): %v", skillId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success get skill taxonomy"),
+		Data:   data,
+	}
+}
+
+// RefreshCatalogPopularityEvery rebuilds the catalog popularity right away and then once per interval,
+// it returns once ctx is done
+func (u *DataUsecase) RefreshCatalogPopularityEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.repository.RefreshCatalogPopularity(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			u.log.Errorf("repository.RefreshCatalogPopularity: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
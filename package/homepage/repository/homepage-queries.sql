@@ -1,5 +1,5 @@
 -- name: ListNewestPosts :many
-SELECT p.*, 
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
@@ -10,25 +10,29 @@ SELECT p.*,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
-LEFT JOIN reported_posts rp ON p.id = rp.post_id AND rp.user_id = $1
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE rp.post_id IS NULL AND p.visibility = 'public'
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
 ORDER BY p.created_at DESC
 OFFSET $2
 LIMIT $3;
 
--- name: ListPostsByFollowing :many
-SELECT p.*,
+-- name: ListNewestPostsByCursor :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
-	COUNT(p.id) OVER () AS total_rows,
     CASE 
     	WHEN lp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
@@ -36,23 +40,75 @@ SELECT p.*,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
-LEFT JOIN users u ON p.user_id = u.id 
-LEFT JOIN reported_posts rp ON p.id = rp.post_id AND rp.user_id = $1
-LEFT JOIN followings f ON p.user_id = f.follow_user_id
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE f.user_id = $1 AND rp.post_id IS NULL
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND (@cursor_id::bigint = 0 OR (p.created_at, p.id) < (@cursor_created_at::timestamp, @cursor_id::bigint))
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
-ORDER BY p.created_at DESC
-OFFSET $2
-LIMIT $3;
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT $2;
+
+-- name: ListFollowingTimeline :many
+-- Both sources are paged on their own index before they are merged
+SELECT t.post_id, t.created_at
+FROM (
+    (SELECT te.post_id, te.created_at
+    FROM timeline_entries te
+    WHERE te.user_id = @user_id::bigint
+    ORDER BY te.created_at DESC, te.post_id DESC
+    LIMIT @row_offset::int + @row_limit::int)
+    UNION ALL
+    (SELECT tp.post_id, tp.created_at
+    FROM timeline_pull_posts tp
+    JOIN followings f ON f.follow_user_id = tp.author_id
+    WHERE f.user_id = @user_id::bigint
+    ORDER BY tp.created_at DESC, tp.post_id DESC
+    LIMIT @row_offset::int + @row_limit::int)
+) t
+ORDER BY t.created_at DESC, t.post_id DESC
+OFFSET @row_offset::int
+LIMIT @row_limit::int;
+
+-- name: ListFollowingTimelineByCursor :many
+-- Both sources are paged on their own index before they are merged
+SELECT t.post_id, t.created_at
+FROM (
+    (SELECT te.post_id, te.created_at
+    FROM timeline_entries te
+    WHERE te.user_id = @user_id::bigint
+        AND (@cursor_id::bigint = 0 OR (te.created_at, te.post_id) < (@cursor_created_at::timestamp, @cursor_id::bigint))
+    ORDER BY te.created_at DESC, te.post_id DESC
+    LIMIT @row_limit::int)
+    UNION ALL
+    (SELECT tp.post_id, tp.created_at
+    FROM timeline_pull_posts tp
+    JOIN followings f ON f.follow_user_id = tp.author_id
+    WHERE f.user_id = @user_id::bigint
+        AND (@cursor_id::bigint = 0 OR (tp.created_at, tp.post_id) < (@cursor_created_at::timestamp, @cursor_id::bigint))
+    ORDER BY tp.created_at DESC, tp.post_id DESC
+    LIMIT @row_limit::int)
+) t
+ORDER BY t.created_at DESC, t.post_id DESC
+LIMIT @row_limit::int;
+
+-- name: CountFollowingTimeline :one
+SELECT (SELECT COUNT(*) FROM timeline_entries te WHERE te.user_id = @user_id::bigint)
+    + (SELECT COUNT(*) FROM timeline_pull_posts tp JOIN followings f ON f.follow_user_id = tp.author_id WHERE f.user_id = @user_id::bigint) AS count;
 
 -- name: ListPopularPosts :many
-SELECT p.*, 
+-- Posts first seen over an hour ago, in an earlier session, are left out
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
@@ -67,33 +123,323 @@ SELECT p.*,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
-LEFT JOIN reported_posts rp ON p.id = rp.post_id AND rp.user_id = $1
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE rp.post_id IS NULL AND p.visibility = 'public'
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND NOT EXISTS (SELECT 1 FROM post_impressions pim WHERE pim.user_id = $1 AND pim.post_id = p.id AND pim.first_seen_at < NOW() - INTERVAL '1 hour')
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
 ORDER BY
     recent_post DESC,
     (p.like_count + p.comment_count + p.repost_count) DESC
 OFFSET $2
 LIMIT $3;
 
--- name: GetFollowsRecommendationForUserId :many
-SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work, 
-    COUNT(u.id) OVER () AS total_rows
+-- name: ListPopularPostsByCursor :many
+-- Posts first seen over an hour ago, in an earlier session, are left out
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND NOT EXISTS (SELECT 1 FROM post_impressions pim WHERE pim.user_id = $1 AND pim.post_id = p.id AND pim.first_seen_at < NOW() - INTERVAL '1 hour')
+    AND (@cursor_id::bigint = 0 OR (p.created_at >= NOW() - INTERVAL '30 days', COALESCE(p.like_count, 0) + COALESCE(p.comment_count, 0) + COALESCE(p.repost_count, 0), p.id) < (@cursor_created_at::timestamp >= NOW() - INTERVAL '30 days', @cursor_score::bigint, @cursor_id::bigint))
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY
+    (p.created_at >= NOW() - INTERVAL '30 days') DESC,
+    (COALESCE(p.like_count, 0) + COALESCE(p.comment_count, 0) + COALESCE(p.repost_count, 0)) DESC,
+    p.id DESC
+LIMIT $2;
+
+-- name: ListPostsByIds :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for($1, p.id, p.user_id)
+    AND p.id = ANY(@post_ids::bigint[])
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC, p.id DESC;
+
+-- name: ListTimelinePostsByIds :many
+-- Unlike ListPostsByIds it keeps the followers only posts, the ids come from the viewer's own timeline
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
+FROM posts p
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = $1
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $1
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $1
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $1
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE rp.target_id IS NULL AND visible_feed_post_for($1, p.id, p.user_id)
+    AND p.id = ANY(@post_ids::bigint[])
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
+ORDER BY p.created_at DESC, p.id DESC;
+
+-- name: ListForYouCandidates :many
+-- Recent posts with the viewer's relationship to their authors, ranked by the usecase
+WITH candidates AS (
+    SELECT p.id, p.user_id, p.created_at,
+        COALESCE(p.like_count, 0)::int AS like_count,
+        COALESCE(p.comment_count, 0)::int AS comment_count,
+        COALESCE(p.repost_count, 0)::int AS repost_count
+    FROM posts p
+    WHERE p.created_at >= NOW() - INTERVAL '7 days' AND p.user_id <> @user_id::bigint AND p.visibility = 'public'
+        AND NOT EXISTS (SELECT 1 FROM reports rp WHERE rp.target_type = 'post' AND rp.target_id = p.id AND rp.user_id = @user_id::bigint)
+        AND visible_feed_post_for(@user_id::bigint, p.id, p.user_id)
+    ORDER BY p.created_at DESC
+    LIMIT @row_limit::int
+), authors AS (
+    SELECT a.user_id,
+        EXISTS (SELECT 1 FROM followings f WHERE f.user_id = @user_id::bigint AND f.follow_user_id = a.user_id) AS is_followed,
+        (SELECT COUNT(*) FROM followings f JOIN followings vf ON vf.follow_user_id = f.user_id AND vf.user_id = @user_id::bigint WHERE f.follow_user_id = a.user_id) AS mutual_followings,
+        (SELECT COUNT(DISTINCT us.skill_id) FROM user_skills us JOIN user_skills vus ON vus.skill_id = us.skill_id AND vus.user_id = @user_id::bigint WHERE us.user_id = a.user_id) AS shared_skills,
+        (SELECT COUNT(DISTINCT we.company_id) FROM work_experiences we JOIN work_experiences vwe ON vwe.company_id = we.company_id AND vwe.user_id = @user_id::bigint WHERE we.user_id = a.user_id) AS shared_companies,
+        (SELECT COUNT(*) FROM liked_posts lp JOIN posts ap ON ap.id = lp.post_id WHERE lp.user_id = @user_id::bigint AND ap.user_id = a.user_id)
+            + (SELECT COUNT(*) FROM post_comments pc JOIN posts ap ON ap.id = pc.post_id WHERE pc.user_id = @user_id::bigint AND ap.user_id = a.user_id)
+            + (SELECT COUNT(*) FROM reposted_posts rpp JOIN posts ap ON ap.id = rpp.post_id WHERE rpp.user_id = @user_id::bigint AND ap.user_id = a.user_id) AS author_interactions
+    FROM (SELECT DISTINCT user_id FROM candidates) a
+)
+SELECT c.id, c.user_id, c.created_at, c.like_count, c.comment_count, c.repost_count,
+    a.is_followed, a.mutual_followings, a.shared_skills, a.shared_companies, a.author_interactions::bigint AS author_interactions
+FROM candidates c
+JOIN authors a ON a.user_id = c.user_id;
+
+-- name: ListFollowRecommendationCandidates :many
+-- Users related to the viewer by any signal plus the most followed users, ranked by the usecase.
+-- Every relation of the viewer adds at most signal_limit users, and the candidates kept are the ones sharing the most with the viewer
+WITH signals AS (
+    SELECT f.follow_user_id AS user_id, 3.0 AS weight
+    FROM followings vf
+    CROSS JOIN LATERAL (SELECT f.follow_user_id FROM followings f WHERE f.user_id = vf.follow_user_id ORDER BY f.id DESC LIMIT @signal_limit::int) f
+    WHERE vf.user_id = @user_id::bigint
+    UNION ALL
+    SELECT we.user_id, 2.0
+    FROM work_experiences vwe
+    CROSS JOIN LATERAL (SELECT we.user_id FROM work_experiences we WHERE we.company_id = vwe.company_id LIMIT @signal_limit::int) we
+    WHERE vwe.user_id = @user_id::bigint
+    UNION ALL
+    SELECT e.user_id, 1.5
+    FROM educations ve
+    CROSS JOIN LATERAL (SELECT e.user_id FROM educations e WHERE e.school_id = ve.school_id LIMIT @signal_limit::int) e
+    WHERE ve.user_id = @user_id::bigint
+    UNION ALL
+    SELECT us.user_id, 0.3
+    FROM user_skills vus
+    CROSS JOIN LATERAL (SELECT us.user_id FROM user_skills us WHERE us.skill_id = vus.skill_id LIMIT @signal_limit::int) us
+    WHERE vus.user_id = @user_id::bigint
+    UNION ALL
+    SELECT uji.user_id, 0.3
+    FROM user_job_interests vuji
+    CROSS JOIN LATERAL (SELECT uji.user_id FROM user_job_interests uji WHERE uji.job_position_id = vuji.job_position_id LIMIT @signal_limit::int) uji
+    WHERE vuji.user_id = @user_id::bigint
+    UNION ALL
+    SELECT ud.user_id, 0.5
+    FROM user_details vud
+    CROSS JOIN LATERAL (SELECT ud.user_id FROM user_details ud WHERE LOWER(ud.location) = LOWER(vud.location) LIMIT @signal_limit::int) ud
+    WHERE vud.user_id = @user_id::bigint AND vud.location <> ''
+), pool AS (
+    SELECT s.user_id, SUM(s.weight) AS pre_score
+    FROM (
+        SELECT user_id, weight FROM signals
+        UNION ALL
+        (SELECT id, 0 FROM users ORDER BY followers_count DESC NULLS LAST LIMIT @popular_limit::int)
+    ) s
+    GROUP BY s.user_id
+), candidates AS (
+    SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+        COALESCE(u.followers_count, 0)::int AS followers_count
+    FROM pool
+    JOIN users u ON u.id = pool.user_id
+    WHERE u.id <> @user_id::bigint AND u.deleted_at IS NULL
+        AND NOT EXISTS (SELECT 1 FROM followings f WHERE f.user_id = @user_id::bigint AND f.follow_user_id = u.id)
+        AND NOT EXISTS (SELECT 1 FROM dismissed_follow_recommendations dfr WHERE dfr.user_id = @user_id::bigint AND dfr.dismissed_user_id = u.id)
+        AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = @user_id::bigint AND ub.blocked_user_id = u.id) OR (ub.user_id = u.id AND ub.blocked_user_id = @user_id::bigint))
+        AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    ORDER BY pool.pre_score DESC, u.followers_count DESC NULLS LAST, u.id
+    LIMIT @row_limit::int
+)
+SELECT c.id, c.full_name, c.avatar_url, c.bio, c.open_to_work, c.followers_count,
+    (SELECT COUNT(*) FROM followings vf JOIN followings f ON f.user_id = vf.follow_user_id AND f.follow_user_id = c.id WHERE vf.user_id = @user_id::bigint) AS mutual_followings,
+    COALESCE((SELECT co.name FROM work_experiences we JOIN work_experiences vwe ON vwe.company_id = we.company_id AND vwe.user_id = @user_id::bigint JOIN companies co ON co.id = we.company_id WHERE we.user_id = c.id ORDER BY co.name LIMIT 1), '')::text AS shared_company,
+    COALESCE((SELECT s.name FROM educations e JOIN educations ve ON ve.school_id = e.school_id AND ve.user_id = @user_id::bigint JOIN schools s ON s.id = e.school_id WHERE e.user_id = c.id ORDER BY s.name LIMIT 1), '')::text AS shared_school,
+    (SELECT COUNT(DISTINCT us.skill_id) FROM user_skills us JOIN user_skills vus ON vus.skill_id = us.skill_id AND vus.user_id = @user_id::bigint WHERE us.user_id = c.id) AS shared_skills,
+    (SELECT COUNT(DISTINCT uji.job_position_id) FROM user_job_interests uji JOIN user_job_interests vuji ON vuji.job_position_id = uji.job_position_id AND vuji.user_id = @user_id::bigint WHERE uji.user_id = c.id) AS shared_job_interests,
+    COALESCE((SELECT ud.location FROM user_details ud JOIN user_details vud ON LOWER(vud.location) = LOWER(ud.location) AND vud.user_id = @user_id::bigint WHERE ud.user_id = c.id AND ud.location <> '' LIMIT 1), '')::text AS shared_location
+FROM candidates c;
+
+-- name: InsertDismissedFollowRecommendation :one
+INSERT INTO dismissed_follow_recommendations (user_id, dismissed_user_id, created_at)
+SELECT @user_id::bigint, u.id, NOW()
 FROM users u
-JOIN (
-    SELECT DISTINCT f.follow_user_id
-    FROM followings f
-    JOIN followings f2 ON f.user_id = f2.follow_user_id
-    WHERE f2.user_id = $1
-) AS users_reccomendation ON u.id = users_reccomendation.follow_user_id
-WHERE u.id != $1
-ORDER BY u.followers_count DESC
-OFFSET $2
-LIMIT $3;
\ No newline at end of file
+WHERE u.id = @dismissed_user_id::bigint
+ON CONFLICT (user_id, dismissed_user_id) DO UPDATE SET created_at = NOW()
+RETURNING id;
+
+-- name: DeleteTrendingPostsByWindow :exec
+DELETE FROM trending_posts
+WHERE time_window = @time_window::text;
+
+-- name: CreateTrendingPostEvents :exec
+-- Engagement of one window, shared by the trending posts and topics of a refresh and dropped with its transaction
+CREATE TEMP TABLE trending_post_events (
+    post_id BIGINT PRIMARY KEY,
+    like_count INT NOT NULL,
+    comment_count INT NOT NULL,
+    repost_count INT NOT NULL,
+    score DOUBLE PRECISION NOT NULL
+) ON COMMIT DROP;
+
+-- name: InsertTrendingPostEvents :exec
+-- Posts liked, commented or reposted within the window, comments and reposts weigh more than likes
+-- and every event loses half its weight each quarter of the window
+WITH events AS (
+    SELECT lp.post_id, 1 AS likes, 0 AS comments, 0 AS reposts, lp.created_at
+    FROM liked_posts lp
+    WHERE lp.created_at >= NOW() - MAKE_INTERVAL(secs => @window_seconds::int)
+    UNION ALL
+    SELECT pc.post_id, 0, 1, 0, pc.created_at
+    FROM post_comments pc
+    WHERE pc.created_at >= NOW() - MAKE_INTERVAL(secs => @window_seconds::int)
+    UNION ALL
+    SELECT rpp.post_id, 0, 0, 1, rpp.created_at
+    FROM reposted_posts rpp
+    WHERE rpp.created_at >= NOW() - MAKE_INTERVAL(secs => @window_seconds::int)
+)
+INSERT INTO trending_post_events
+(post_id, like_count, comment_count, repost_count, score)
+SELECT e.post_id, SUM(e.likes)::int, SUM(e.comments)::int, SUM(e.reposts)::int,
+    SUM((e.likes + 2 * e.comments + 3 * e.reposts) * POWER(0.5, EXTRACT(EPOCH FROM NOW() - e.created_at) / (@window_seconds::int / 4.0)))::float8
+FROM events e
+JOIN posts p ON p.id = e.post_id
+    WHERE p.visibility = 'public'
+        AND visible_post_for(0, p.id, p.user_id)
+GROUP BY e.post_id;
+
+-- name: InsertTrendingPosts :exec
+INSERT INTO trending_posts
+(time_window, post_id, like_count, comment_count, repost_count, score, computed_at)
+SELECT @time_window::text, pe.post_id, pe.like_count, pe.comment_count, pe.repost_count, pe.score, NOW()
+FROM trending_post_events pe
+ORDER BY pe.score DESC, pe.post_id DESC
+LIMIT @row_limit::int;
+
+-- name: DeleteTrendingTopicsByWindow :exec
+DELETE FROM trending_topics
+WHERE time_window = @time_window::text;
+
+-- name: InsertTrendingHashtags :exec
+INSERT INTO trending_topics
+(time_window, topic_type, name, post_count, like_count, comment_count, repost_count, score, computed_at)
+SELECT @time_window::text, 'hashtag', ph.hashtag, COUNT(*)::int, SUM(pe.like_count)::int, SUM(pe.comment_count)::int, SUM(pe.repost_count)::int,
+    SUM(pe.score)::float8, NOW()
+FROM trending_post_events pe
+JOIN post_hashtags ph ON ph.post_id = pe.post_id
+GROUP BY ph.hashtag
+ORDER BY 8 DESC, ph.hashtag
+LIMIT @row_limit::int;
+
+-- name: InsertTrendingSkills :exec
+-- Skills are matched by hashtags naming the skill or one of its synonyms
+INSERT INTO trending_topics
+(time_window, topic_type, name, post_count, like_count, comment_count, repost_count, score, computed_at)
+SELECT @time_window::text, 'skill', ps.name, COUNT(*)::int, SUM(ps.like_count)::int, SUM(ps.comment_count)::int, SUM(ps.repost_count)::int,
+    SUM(ps.score)::float8, NOW()
+FROM (
+    SELECT DISTINCT s.id, s.name, pe.post_id, pe.like_count, pe.comment_count, pe.repost_count, pe.score
+    FROM trending_post_events pe
+    JOIN post_hashtags ph ON ph.post_id = pe.post_id
+    JOIN skills s ON s.normalized_name = ph.hashtag
+        OR s.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'skills' AND ca.normalized_name = ph.hashtag)
+) ps
+GROUP BY ps.id, ps.name
+ORDER BY 8 DESC, ps.name
+LIMIT @row_limit::int;
+
+-- name: ListTrendingPosts :many
+SELECT tp.post_id, tp.score,
+    COUNT(tp.post_id) OVER () AS total_rows
+FROM trending_posts tp
+WHERE tp.time_window = @time_window::text
+ORDER BY tp.score DESC, tp.post_id DESC
+OFFSET @row_offset::int
+LIMIT @row_limit::int;
+
+-- name: ListTrendingPostsByCursor :many
+-- Cursor scores keep the precision of libs.TrendingPageCursor
+SELECT tp.post_id, tp.score
+FROM trending_posts tp
+WHERE tp.time_window = @time_window::text
+    AND (@cursor_id::bigint = 0 OR (ROUND(tp.score::numeric * 1000)::bigint, tp.post_id) < (@cursor_score::bigint, @cursor_id::bigint))
+ORDER BY ROUND(tp.score::numeric * 1000) DESC, tp.post_id DESC
+LIMIT @row_limit::int;
+
+-- name: ListTrendingTopics :many
+SELECT tt.topic_type, tt.name, tt.post_count, tt.like_count, tt.comment_count, tt.repost_count, tt.score
+FROM trending_topics tt
+WHERE tt.time_window = @time_window::text AND (@topic_type::text = '' OR tt.topic_type = @topic_type::text)
+ORDER BY tt.score DESC, tt.name
+LIMIT @row_limit::int;
\ No newline at end of file
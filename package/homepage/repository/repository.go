@@ -3,17 +3,31 @@ package homepage
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	db "profiln-be/db/sqlc"
+	"profiln-be/libs"
 	"profiln-be/model"
+	postsRepository "profiln-be/package/posts/repository"
 
 	"strings"
+	"time"
 )
 
 type IHomepageRepository interface {
 	ListNewestPosts(userId int64, offset, limit int32) ([]model.Post, int64, error)
 	ListPostsByFollowing(userId int64, offset, limit int32) ([]model.Post, int64, error)
 	ListPopularPosts(userId int64, offset, limit int32) ([]model.Post, int64, error)
-	GetFollowsRecommendationForUserId(userId int64, offset, limit int32) ([]db.GetFollowsRecommendationForUserIdRow, int64, error)
+	ListNewestPostsByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.Post, *model.PageCursor, error)
+	ListPostsByFollowingByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.Post, *model.PageCursor, error)
+	ListPopularPostsByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.Post, *model.PageCursor, error)
+	ListForYouCandidates(userId int64, limit int32) ([]model.FeedCandidate, error)
+	ListPostsByIds(userId int64, postIds []int64) ([]model.Post, error)
+	ListFollowRecommendationCandidates(userId int64, signalLimit, popularLimit, limit int32) ([]model.FollowCandidate, error)
+	DismissFollowRecommendation(userId, dismissedUserId int64) error
+	RefreshTrending(ctx context.Context, window string, duration time.Duration, limit int32) error
+	ListTrendingPostIds(window string, offset, limit int32) ([]int64, int64, error)
+	ListTrendingPostIdsByCursor(window string, cursor *model.PageCursor, limit int32) ([]int64, *model.PageCursor, error)
+	ListTrendingTopics(window, topicType string, limit int32) ([]model.TrendingTopic, error)
 }
 
 type HomepageRepository struct {
@@ -73,21 +87,63 @@ func (r *HomepageRepository) ListNewestPosts(userId int64, offset, limit int32)
 			RepostCount:  v.RepostCount.Int32,
 			IsRepost:     v.Repost,
 			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
 			UpdatedAt:    v.UpdatedAt.Time,
 		}
 	}
 
+	if err := postsRepository.AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := postsRepository.AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := postsRepository.AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
 	return posts, count, nil
 }
 
 func (r *HomepageRepository) ListPostsByFollowing(userId int64, offset, limit int32) ([]model.Post, int64, error) {
-	arg := db.ListPostsByFollowingParams{
+	data, err := r.query.ListFollowingTimeline(context.Background(), db.ListFollowingTimelineParams{
+		UserID:    userId,
+		RowOffset: offset,
+		RowLimit:  limit,
+	})
+	if err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	// get total rows for pagination
+	count, err := r.query.CountFollowingTimeline(context.Background(), userId)
+	if err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	postIds := make([]int64, len(data))
+	for i, v := range data {
+		postIds[i] = v.PostID
+	}
+
+	posts, err := r.listTimelinePostsByIds(userId, postIds)
+	if err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	return posts, count, nil
+}
+
+func (r *HomepageRepository) ListPopularPosts(userId int64, offset, limit int32) ([]model.Post, int64, error) {
+	arg := db.ListPopularPostsParams{
 		UserID: sql.NullInt64{Int64: userId, Valid: true},
 		Offset: offset,
 		Limit:  limit,
 	}
 
-	data, err := r.query.ListPostsByFollowing(context.Background(), arg)
+	data, err := r.query.ListPopularPosts(context.Background(), arg)
 	if err != nil {
 		return []model.Post{}, 0, err
 	}
@@ -125,31 +181,218 @@ func (r *HomepageRepository) ListPostsByFollowing(userId int64, offset, limit in
 			RepostCount:  v.RepostCount.Int32,
 			IsRepost:     v.Repost,
 			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
 			UpdatedAt:    v.UpdatedAt.Time,
 		}
 	}
 
+	if err := postsRepository.AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := postsRepository.AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := postsRepository.AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
 	return posts, count, nil
 }
 
-func (r *HomepageRepository) ListPopularPosts(userId int64, offset, limit int32) ([]model.Post, int64, error) {
-	arg := db.ListPopularPostsParams{
+func (r *HomepageRepository) ListNewestPostsByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.Post, *model.PageCursor, error) {
+	arg := db.ListNewestPostsByCursorParams{
 		UserID: sql.NullInt64{Int64: userId, Valid: true},
-		Offset: offset,
-		Limit:  limit,
+		Limit:  limit + 1,
 	}
 
-	data, err := r.query.ListPopularPosts(context.Background(), arg)
+	if cursor != nil && cursor.CreatedAt != nil {
+		arg.CursorID = cursor.ID
+		arg.CursorCreatedAt = *cursor.CreatedAt
+	}
+
+	data, err := r.query.ListNewestPostsByCursor(context.Background(), arg)
 	if err != nil {
-		return []model.Post{}, 0, err
+		return []model.Post{}, nil, err
 	}
 
-	// get total rows for pagination
-	var count int64
-	if len(data) > 0 {
-		count = data[0].TotalRows
+	return r.toPostsPage(userId, data, limit, postCreatedAtCursor)
+}
+
+func (r *HomepageRepository) ListPostsByFollowingByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.Post, *model.PageCursor, error) {
+	arg := db.ListFollowingTimelineByCursorParams{
+		UserID:   userId,
+		RowLimit: limit + 1,
+	}
+
+	if cursor != nil && cursor.CreatedAt != nil {
+		arg.CursorID = cursor.ID
+		arg.CursorCreatedAt = *cursor.CreatedAt
+	}
+
+	data, err := r.query.ListFollowingTimelineByCursor(context.Background(), arg)
+	if err != nil {
+		return []model.Post{}, nil, err
+	}
+
+	// The cursor comes from the timeline, so posts dropped while loading them do not end the feed
+	var next *model.PageCursor
+	if len(data) > int(limit) {
+		data = data[:limit]
+		createdAt := data[limit-1].CreatedAt
+		next = &model.PageCursor{CreatedAt: &createdAt, ID: data[limit-1].PostID}
+	}
+
+	postIds := make([]int64, len(data))
+	for i, v := range data {
+		postIds[i] = v.PostID
+	}
+
+	posts, err := r.listTimelinePostsByIds(userId, postIds)
+	if err != nil {
+		return []model.Post{}, nil, err
+	}
+
+	return posts, next, nil
+}
+
+func (r *HomepageRepository) ListPopularPostsByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.Post, *model.PageCursor, error) {
+	arg := db.ListPopularPostsByCursorParams{
+		UserID: sql.NullInt64{Int64: userId, Valid: true},
+		Limit:  limit + 1,
+	}
+
+	if cursor != nil && cursor.CreatedAt != nil {
+		arg.CursorID = cursor.ID
+		arg.CursorCreatedAt = *cursor.CreatedAt
+		arg.CursorScore = cursor.Score
+	}
+
+	data, err := r.query.ListPopularPostsByCursor(context.Background(), arg)
+	if err != nil {
+		return []model.Post{}, nil, err
+	}
+
+	rows := make([]db.ListNewestPostsByCursorRow, len(data))
+	for i, v := range data {
+		rows[i] = db.ListNewestPostsByCursorRow(v)
+	}
+
+	return r.toPostsPage(userId, rows, limit, func(v db.ListNewestPostsByCursorRow) *model.PageCursor {
+		cursor := postCreatedAtCursor(v)
+		cursor.Score = int64(v.LikeCount.Int32) + int64(v.CommentCount.Int32) + int64(v.RepostCount.Int32)
+		return cursor
+	})
+}
+
+func (r *HomepageRepository) ListForYouCandidates(userId int64, limit int32) ([]model.FeedCandidate, error) {
+	data, err := r.query.ListForYouCandidates(context.Background(), db.ListForYouCandidatesParams{
+		UserID:   userId,
+		RowLimit: limit,
+	})
+	if err != nil {
+		return []model.FeedCandidate{}, err
+	}
+
+	candidates := make([]model.FeedCandidate, len(data))
+	for i, v := range data {
+		candidates[i] = model.FeedCandidate{
+			PostID:             v.ID,
+			AuthorID:           v.UserID.Int64,
+			CreatedAt:          v.CreatedAt.Time,
+			LikeCount:          v.LikeCount,
+			CommentCount:       v.CommentCount,
+			RepostCount:        v.RepostCount,
+			IsFollowed:         v.IsFollowed,
+			MutualFollowings:   v.MutualFollowings,
+			SharedSkills:       v.SharedSkills,
+			SharedCompanies:    v.SharedCompanies,
+			AuthorInteractions: v.AuthorInteractions,
+		}
+	}
+
+	return candidates, nil
+}
+
+// ListPostsByIds returns the posts in the order of the given ids, skipping posts that are no longer visible
+func (r *HomepageRepository) ListPostsByIds(userId int64, postIds []int64) ([]model.Post, error) {
+	data, err := r.query.ListPostsByIds(context.Background(), db.ListPostsByIdsParams{
+		UserID:  sql.NullInt64{Int64: userId, Valid: true},
+		PostIds: postIds,
+	})
+	if err != nil {
+		return []model.Post{}, err
+	}
+
+	rowsById := make(map[int64]db.ListNewestPostsByCursorRow, len(data))
+	for _, v := range data {
+		rowsById[v.ID] = db.ListNewestPostsByCursorRow(v)
+	}
+
+	rows := make([]db.ListNewestPostsByCursorRow, 0, len(data))
+	for _, id := range postIds {
+		if v, ok := rowsById[id]; ok {
+			rows = append(rows, v)
+		}
 	}
 
+	return r.toPosts(userId, rows)
+}
+
+// listTimelinePostsByIds returns the posts of the viewer's following feed in the order of the given ids
+func (r *HomepageRepository) listTimelinePostsByIds(userId int64, postIds []int64) ([]model.Post, error) {
+	if len(postIds) == 0 {
+		return []model.Post{}, nil
+	}
+
+	data, err := r.query.ListTimelinePostsByIds(context.Background(), db.ListTimelinePostsByIdsParams{
+		UserID:  sql.NullInt64{Int64: userId, Valid: true},
+		PostIds: postIds,
+	})
+	if err != nil {
+		return []model.Post{}, err
+	}
+
+	rowsById := make(map[int64]db.ListNewestPostsByCursorRow, len(data))
+	for _, v := range data {
+		rowsById[v.ID] = db.ListNewestPostsByCursorRow(v)
+	}
+
+	rows := make([]db.ListNewestPostsByCursorRow, 0, len(data))
+	for _, id := range postIds {
+		if v, ok := rowsById[id]; ok {
+			rows = append(rows, v)
+		}
+	}
+
+	return r.toPosts(userId, rows)
+}
+
+func postCreatedAtCursor(v db.ListNewestPostsByCursorRow) *model.PageCursor {
+	createdAt := v.CreatedAt.Time
+	return &model.PageCursor{CreatedAt: &createdAt, ID: v.ID}
+}
+
+// toPostsPage maps a keyset page fetched with one extra row, which only tells
+// whether there is a next page
+func (r *HomepageRepository) toPostsPage(userId int64, data []db.ListNewestPostsByCursorRow, limit int32, cursorOf func(db.ListNewestPostsByCursorRow) *model.PageCursor) ([]model.Post, *model.PageCursor, error) {
+	var next *model.PageCursor
+	if len(data) > int(limit) {
+		data = data[:limit]
+		next = cursorOf(data[limit-1])
+	}
+
+	posts, err := r.toPosts(userId, data)
+	if err != nil {
+		return []model.Post{}, nil, err
+	}
+
+	return posts, next, nil
+}
+
+// toPosts maps the rows and loads their link previews, reactions and polls
+func (r *HomepageRepository) toPosts(userId int64, data []db.ListNewestPostsByCursorRow) ([]model.Post, error) {
 	posts := make([]model.Post, len(data))
 	for i, v := range data {
 		var imageUrls []string
@@ -177,23 +420,137 @@ func (r *HomepageRepository) ListPopularPosts(userId int64, offset, limit int32)
 			RepostCount:  v.RepostCount.Int32,
 			IsRepost:     v.Repost,
 			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
 			UpdatedAt:    v.UpdatedAt.Time,
 		}
 	}
 
-	return posts, count, nil
+	if err := postsRepository.AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, err
+	}
+
+	if err := postsRepository.AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, err
+	}
+
+	if err := postsRepository.AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, err
+	}
+
+	return posts, nil
 }
 
-func (r *HomepageRepository) GetFollowsRecommendationForUserId(userId int64, offset, limit int32) ([]db.GetFollowsRecommendationForUserIdRow, int64, error) {
-	arg := db.GetFollowsRecommendationForUserIdParams{
-		UserID: sql.NullInt64{Int64: userId, Valid: true},
-		Offset: offset,
-		Limit:  limit,
+func (r *HomepageRepository) ListFollowRecommendationCandidates(userId int64, signalLimit, popularLimit, limit int32) ([]model.FollowCandidate, error) {
+	data, err := r.query.ListFollowRecommendationCandidates(context.Background(), db.ListFollowRecommendationCandidatesParams{
+		SignalLimit:  signalLimit,
+		UserID:       userId,
+		PopularLimit: popularLimit,
+		RowLimit:     limit,
+	})
+	if err != nil {
+		return []model.FollowCandidate{}, err
 	}
 
-	data, err := r.query.GetFollowsRecommendationForUserId(context.Background(), arg)
+	candidates := make([]model.FollowCandidate, len(data))
+	for i, v := range data {
+		candidates[i] = model.FollowCandidate{
+			User: model.User{
+				ID:         v.ID,
+				AvatarUrl:  v.AvatarUrl.String,
+				Fullname:   v.FullName,
+				Bio:        v.Bio.String,
+				OpenToWork: v.OpenToWork.Bool,
+			},
+			FollowersCount:     v.FollowersCount,
+			MutualFollowings:   v.MutualFollowings,
+			SharedCompany:      v.SharedCompany,
+			SharedSchool:       v.SharedSchool,
+			SharedSkills:       v.SharedSkills,
+			SharedJobInterests: v.SharedJobInterests,
+			SharedLocation:     v.SharedLocation,
+		}
+	}
+
+	return candidates, nil
+}
+
+// DismissFollowRecommendation hides the user from the recommendations, it returns sql.ErrNoRows when the user does not exist
+func (r *HomepageRepository) DismissFollowRecommendation(userId, dismissedUserId int64) error {
+	_, err := r.query.InsertDismissedFollowRecommendation(context.Background(), db.InsertDismissedFollowRecommendationParams{
+		UserID:          userId,
+		DismissedUserID: dismissedUserId,
+	})
+
+	return err
+}
+
+// RefreshTrending replaces the trending posts and topics of the window with the engagement within it
+func (r *HomepageRepository) RefreshTrending(ctx context.Context, window string, duration time.Duration, limit int32) error {
+	tx, err := r.dbConn.BeginTx(ctx, nil)
 	if err != nil {
-		return []db.GetFollowsRecommendationForUserIdRow{}, 0, err
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+	windowSeconds := int32(duration.Seconds())
+
+	// The engagement of the window is computed once for the posts, hashtags and skills
+	if err := qtx.CreateTrendingPostEvents(ctx); err != nil {
+		return fmt.Errorf("could not create trending post events: %w", err)
+	}
+
+	if err := qtx.InsertTrendingPostEvents(ctx, windowSeconds); err != nil {
+		return fmt.Errorf("could not insert trending post events: %w", err)
+	}
+
+	if err := qtx.DeleteTrendingPostsByWindow(ctx, window); err != nil {
+		return fmt.Errorf("could not delete trending posts: %w", err)
+	}
+
+	err = qtx.InsertTrendingPosts(ctx, db.InsertTrendingPostsParams{
+		TimeWindow: window,
+		RowLimit:   limit,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert trending posts: %w", err)
+	}
+
+	if err := qtx.DeleteTrendingTopicsByWindow(ctx, window); err != nil {
+		return fmt.Errorf("could not delete trending topics: %w", err)
+	}
+
+	err = qtx.InsertTrendingHashtags(ctx, db.InsertTrendingHashtagsParams{
+		TimeWindow: window,
+		RowLimit:   limit,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert trending hashtags: %w", err)
+	}
+
+	err = qtx.InsertTrendingSkills(ctx, db.InsertTrendingSkillsParams{
+		TimeWindow: window,
+		RowLimit:   limit,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert trending skills: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *HomepageRepository) ListTrendingPostIds(window string, offset, limit int32) ([]int64, int64, error) {
+	data, err := r.query.ListTrendingPosts(context.Background(), db.ListTrendingPostsParams{
+		TimeWindow: window,
+		RowOffset:  offset,
+		RowLimit:   limit,
+	})
+	if err != nil {
+		return []int64{}, 0, err
 	}
 
 	// get total rows for pagination
@@ -202,5 +559,66 @@ func (r *HomepageRepository) GetFollowsRecommendationForUserId(userId int64, off
 		count = data[0].TotalRows
 	}
 
-	return data, count, nil
+	postIds := make([]int64, len(data))
+	for i, v := range data {
+		postIds[i] = v.PostID
+	}
+
+	return postIds, count, nil
+}
+
+func (r *HomepageRepository) ListTrendingPostIdsByCursor(window string, cursor *model.PageCursor, limit int32) ([]int64, *model.PageCursor, error) {
+	arg := db.ListTrendingPostsByCursorParams{
+		TimeWindow: window,
+		RowLimit:   limit + 1,
+	}
+
+	if cursor != nil {
+		arg.CursorID = cursor.ID
+		arg.CursorScore = cursor.Score
+	}
+
+	data, err := r.query.ListTrendingPostsByCursor(context.Background(), arg)
+	if err != nil {
+		return []int64{}, nil, err
+	}
+
+	var next *model.PageCursor
+	if len(data) > int(limit) {
+		data = data[:limit]
+		next = libs.TrendingPageCursor(data[limit-1].PostID, data[limit-1].Score)
+	}
+
+	postIds := make([]int64, len(data))
+	for i, v := range data {
+		postIds[i] = v.PostID
+	}
+
+	return postIds, next, nil
+}
+
+func (r *HomepageRepository) ListTrendingTopics(window, topicType string, limit int32) ([]model.TrendingTopic, error) {
+	data, err := r.query.ListTrendingTopics(context.Background(), db.ListTrendingTopicsParams{
+		TimeWindow: window,
+		TopicType:  topicType,
+		RowLimit:   limit,
+	})
+	if err != nil {
+		return []model.TrendingTopic{}, err
+	}
+
+	topics := make([]model.TrendingTopic, len(data))
+	for i, v := range data {
+		topics[i] = model.TrendingTopic{
+			Type:         v.TopicType,
+			Name:         v.Name,
+			PostCount:    v.PostCount,
+			LikeCount:    v.LikeCount,
+			CommentCount: v.CommentCount,
+			RepostCount:  v.RepostCount,
+			Score:        v.Score,
+		}
+	}
+
+	return topics, nil
 }
@@ -1,32 +1,63 @@
 package homepage
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
 	"profiln-be/libs"
 	"profiln-be/model"
 	repository "profiln-be/package/homepage/repository"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// The for you feed ranks at most this many of the newest posts
+	forYouCandidateLimit = 500
+	// Follow recommendations rank at most this many users, including the most followed ones for new users
+	followCandidateLimit        = 500
+	popularFollowCandidateLimit = 50
+	// Every company, school, skill, job interest, location and followed user of the viewer adds at most this many candidates
+	followSignalLimit = 200
+	// Trending keeps this many posts and topics of each kind per window
+	trendingLimit = 500
+)
+
 type IHomepageUsecase interface {
 	ListPosts(userId int64, pagination model.PaginationRequest) (resp model.Response)
 	ListFollowsRecommendation(userId int64, pagination model.PaginationRequest) (resp model.Response)
+	DismissFollowRecommendation(userId, dismissedUserId int64) (resp model.Response)
+	ListTrendingPosts(userId int64, window string, pagination model.PaginationRequest) (resp model.Response)
+	ListTrendingTopics(window, topicType string, limit int) (resp model.Response)
+	RefreshTrendingEvery(ctx context.Context, interval time.Duration)
 }
 
 type HomepageUsecase struct {
-	repository repository.IHomepageRepository
-	log        *logrus.Logger
+	repository        repository.IHomepageRepository
+	log               *logrus.Logger
+	feedRanker        libs.IFeedRanker
+	followRecommender libs.IFollowRecommender
 }
 
-func NewHomepageUsecase(repository repository.IHomepageRepository, log *logrus.Logger) IHomepageUsecase {
+func NewHomepageUsecase(repository repository.IHomepageRepository, log *logrus.Logger, feedRanker libs.IFeedRanker, followRecommender libs.IFollowRecommender) IHomepageUsecase {
 	return &HomepageUsecase{
 		repository,
 		log,
+		feedRanker,
+		followRecommender,
 	}
 }
 
 func (u *HomepageUsecase) ListPosts(userId int64, pagination model.PaginationRequest) (resp model.Response) {
+	if pagination.OrderBy == model.FeedOrderForYou {
+		return u.listForYouPosts(userId, pagination)
+	}
+
+	if pagination.Page == 0 {
+		return u.listPostsByCursor(userId, pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 	var (
 		posts     []model.Post
@@ -34,7 +65,7 @@ func (u *HomepageUsecase) ListPosts(userId int64, pagination model.PaginationReq
 		err       error
 	)
 
-	if pagination.OrderBy == "newest" {
+	if pagination.OrderBy == model.FeedOrderNewest {
 		posts, totalRows, err = u.repository.ListNewestPosts(userId, int32(offset), int32(pagination.Limit))
 
 		if err != nil {
@@ -44,7 +75,7 @@ func (u *HomepageUsecase) ListPosts(userId int64, pagination model.PaginationReq
 			return
 		}
 
-	} else if pagination.OrderBy == "following" {
+	} else if pagination.OrderBy == model.FeedOrderFollowing {
 		posts, totalRows, err = u.repository.ListPostsByFollowing(userId, int32(offset), int32(pagination.Limit))
 
 		if err != nil {
@@ -53,7 +84,7 @@ func (u *HomepageUsecase) ListPosts(userId int64, pagination model.PaginationReq
 			u.log.Errorf("repository.ListPostsByFollowing: %v", err)
 			return
 		}
-	} else if pagination.OrderBy == "popular" {
+	} else if pagination.OrderBy == model.FeedOrderPopular {
 		posts, totalRows, err = u.repository.ListPopularPosts(userId, int32(offset), int32(pagination.Limit))
 
 		if err != nil {
@@ -71,8 +102,8 @@ func (u *HomepageUsecase) ListPosts(userId int64, pagination model.PaginationReq
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(posts),
 	}
 
@@ -85,35 +116,143 @@ func (u *HomepageUsecase) ListPosts(userId int64, pagination model.PaginationReq
 	return
 }
 
-func (u *HomepageUsecase) ListFollowsRecommendation(userId int64, pagination model.PaginationRequest) (resp model.Response) {
-	offset := (pagination.Page - 1) * pagination.Limit
-	users, totalRows, err :=
-		u.repository.GetFollowsRecommendationForUserId(userId, int32(offset), int32(pagination.Limit))
+func (u *HomepageUsecase) listPostsByCursor(userId int64, pagination model.PaginationRequest) (resp model.Response) {
+	var (
+		posts []model.Post
+		next  *model.PageCursor
+		err   error
+	)
+
+	switch pagination.OrderBy {
+	case model.FeedOrderNewest:
+		posts, next, err = u.repository.ListNewestPostsByCursor(userId, pagination.Cursor, int32(pagination.Limit))
+		if err != nil {
+			resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+			u.log.Errorf("repository.ListNewestPostsByCursor: %v", err)
+			return
+		}
+	case model.FeedOrderFollowing:
+		posts, next, err = u.repository.ListPostsByFollowingByCursor(userId, pagination.Cursor, int32(pagination.Limit))
+		if err != nil {
+			resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+			u.log.Errorf("repository.ListPostsByFollowingByCursor: %v", err)
+			return
+		}
+	case model.FeedOrderPopular:
+		posts, next, err = u.repository.ListPopularPostsByCursor(userId, pagination.Cursor, int32(pagination.Limit))
+		if err != nil {
+			resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+			u.log.Errorf("repository.ListPopularPostsByCursor: %v", err)
+			return
+		}
+	}
+
+	paginate := model.PaginationResponse{
+		CurrentRowsCount: len(posts),
+		NextCursor:       libs.EncodePageCursor(next),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success fetch posts")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       posts,
+	}
+
+	return
+}
+
+// listForYouPosts ranks the candidates on every request, so both offset and
+// cursor pages are taken from the current ranking
+func (u *HomepageUsecase) listForYouPosts(userId int64, pagination model.PaginationRequest) (resp model.Response) {
+	candidates, err := u.repository.ListForYouCandidates(userId, forYouCandidateLimit)
+	if err != nil {
+		resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
 
+		u.log.Errorf("repository.ListForYouCandidates: %v", err)
+		return
+	}
+
+	ranked := u.feedRanker.Rank(candidates, time.Now())
+
+	start := (pagination.Page - 1) * pagination.Limit
+	if pagination.Page == 0 {
+		start = libs.RankedPageStart(ranked, pagination.Cursor)
+	}
+	start = min(start, len(ranked))
+	end := min(start+pagination.Limit, len(ranked))
+	page := ranked[start:end]
+
+	postIds := make([]int64, len(page))
+	for i, v := range page {
+		postIds[i] = v.PostID
+	}
+
+	posts, err := u.repository.ListPostsByIds(userId, postIds)
 	if err != nil {
-		u.log.Errorf("repository.GetFollowsRecommendationForUserId: %v", err)
+		resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+		u.log.Errorf("repository.ListPostsByIds: %v", err)
+		return
+	}
+
+	paginate := model.PaginationResponse{
+		CurrentRowsCount: len(posts),
+	}
+
+	if pagination.Page == 0 {
+		if end < len(ranked) && len(page) > 0 {
+			paginate.NextCursor = libs.EncodePageCursor(libs.RankedPageCursor(page[len(page)-1]))
+		}
+	} else {
+		totalRows := int64(len(ranked))
+		totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+		paginate.Page = pagination.Page
+		paginate.TotalRows = &totalRows
+		paginate.TotalPages = &totalPages
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success fetch posts")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       posts,
+	}
+
+	return
+}
+
+func (u *HomepageUsecase) ListFollowsRecommendation(userId int64, pagination model.PaginationRequest) (resp model.Response) {
+	candidates, err := u.repository.ListFollowRecommendationCandidates(userId, followSignalLimit, popularFollowCandidateLimit, followCandidateLimit)
+	if err != nil {
+		u.log.Errorf("repository.ListFollowRecommendationCandidates: %v", err)
 		return model.Response{
 			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
 		}
 	}
 
-	data := make([]model.User, len(users))
-	for i, v := range users {
-		data[i] = model.User{
-			ID:         v.ID,
-			AvatarUrl:  v.AvatarUrl.String,
-			Fullname:   v.FullName,
-			Bio:        v.Bio.String,
-			OpenToWork: v.OpenToWork.Bool,
-		}
+	recommended := u.followRecommender.Recommend(candidates)
+
+	start := min((pagination.Page-1)*pagination.Limit, len(recommended))
+	end := min(start+pagination.Limit, len(recommended))
+
+	data := make([]model.FollowRecommendation, 0, end-start)
+	for _, v := range recommended[start:end] {
+		data = append(data, model.FollowRecommendation{
+			User:   v.User,
+			Reason: v.Reason,
+		})
 	}
 
+	totalRows := int64(len(recommended))
 	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -124,3 +263,114 @@ func (u *HomepageUsecase) ListFollowsRecommendation(userId int64, pagination mod
 	}
 	return
 }
+
+func (u *HomepageUsecase) DismissFollowRecommendation(userId, dismissedUserId int64) (resp model.Response) {
+	err := u.repository.DismissFollowRecommendation(userId, dismissedUserId)
+	if err != nil && err == sql.ErrNoRows {
+		resp.Status = libs.CustomResponse(http.StatusNotFound, "Data not found")
+		return
+	} else if err != nil {
+		u.log.Errorf("repository.DismissFollowRecommendation: %v", err)
+		resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+		return
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success dismiss follow recommendation")
+	return
+}
+
+func (u *HomepageUsecase) ListTrendingPosts(userId int64, window string, pagination model.PaginationRequest) (resp model.Response) {
+	var (
+		postIds []int64
+		next    *model.PageCursor
+		err     error
+	)
+
+	paginate := model.PaginationResponse{}
+
+	if pagination.Page == 0 {
+		postIds, next, err = u.repository.ListTrendingPostIdsByCursor(window, pagination.Cursor, int32(pagination.Limit))
+		if err != nil {
+			resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+			u.log.Errorf("repository.ListTrendingPostIdsByCursor: %v", err)
+			return
+		}
+
+		paginate.NextCursor = libs.EncodePageCursor(next)
+	} else {
+		offset := (pagination.Page - 1) * pagination.Limit
+
+		var totalRows int64
+		postIds, totalRows, err = u.repository.ListTrendingPostIds(window, int32(offset), int32(pagination.Limit))
+		if err != nil {
+			resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+			u.log.Errorf("repository.ListTrendingPostIds: %v", err)
+			return
+		}
+
+		totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+		paginate.Page = pagination.Page
+		paginate.TotalRows = &totalRows
+		paginate.TotalPages = &totalPages
+	}
+
+	posts, err := u.repository.ListPostsByIds(userId, postIds)
+	if err != nil {
+		resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+		u.log.Errorf("repository.ListPostsByIds: %v", err)
+		return
+	}
+
+	paginate.CurrentRowsCount = len(posts)
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success fetch trending posts")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       posts,
+	}
+
+	return
+}
+
+func (u *HomepageUsecase) ListTrendingTopics(window, topicType string, limit int) (resp model.Response) {
+	topics, err := u.repository.ListTrendingTopics(window, topicType, int32(limit))
+	if err != nil {
+		resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred")
+
+		u.log.Errorf("repository.ListTrendingTopics: %v", err)
+		return
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success fetch trending topics")
+	resp.Data = topics
+
+	return
+}
+
+// RefreshTrendingEvery recomputes every trending window right away and then once per interval,
+// it returns once ctx is done
+func (u *HomepageUsecase) RefreshTrendingEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for window, duration := range libs.TrendingWindows {
+			if err := u.repository.RefreshTrending(ctx, window, duration, trendingLimit); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				u.log.Errorf("repository.RefreshTrending (window %s): %v", window, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,109 @@
+-- name: ListReportedContentSummary :many
+SELECT rp.target_type, rp.target_id,
+    COALESCE(p.id, pc.post_id, pcr_pc.post_id, 0)::bigint AS post_id,
+    COALESCE(p.title, pc.content, pcr.content, '')::text AS preview,
+    u.id, u.full_name, u.avatar_url,
+    COUNT(*) AS reporter_count,
+    MAX(rp.created_at)::timestamp AS last_reported_at,
+    (EXISTS (SELECT 1 FROM hidden_posts hp WHERE rp.target_type = 'post' AND hp.post_id = rp.target_id)
+        OR EXISTS (SELECT 1 FROM hidden_post_comments hpc WHERE rp.target_type = 'post_comment' AND hpc.post_comment_id = rp.target_id)
+        OR EXISTS (SELECT 1 FROM hidden_post_comment_replies hpcr WHERE rp.target_type = 'post_comment_reply' AND hpcr.post_comment_reply_id = rp.target_id)) AS hidden,
+    COUNT(*) OVER () AS total_rows
+FROM reports rp
+LEFT JOIN posts p ON rp.target_type = 'post' AND rp.target_id = p.id
+LEFT JOIN post_comments pc ON rp.target_type = 'post_comment' AND rp.target_id = pc.id
+LEFT JOIN post_comment_replies pcr ON rp.target_type = 'post_comment_reply' AND rp.target_id = pcr.id
+LEFT JOIN post_comments pcr_pc ON pcr.post_comment_id = pcr_pc.id
+JOIN users u ON u.id = COALESCE(p.user_id, pc.user_id, pcr.user_id, CASE WHEN rp.target_type = 'user' THEN rp.target_id END)
+WHERE rp.resolved_at IS NULL
+GROUP BY 
+    rp.target_type, rp.target_id, p.id, pc.id, pcr.id, pcr_pc.id, u.id
+ORDER BY reporter_count DESC, last_reported_at DESC
+OFFSET $1
+LIMIT $2;
+
+-- name: ListOpenReportReasonCountsByTargets :many
+SELECT r.target_type, r.target_id, reason::text AS reason, COUNT(*) AS count
+FROM reports r, UNNEST(r.reasons) AS reason
+WHERE (r.target_type, r.target_id) IN (SELECT * FROM UNNEST(@target_types::text[], @target_ids::bigint[])) AND r.resolved_at IS NULL
+GROUP BY r.target_type, r.target_id, reason;
+
+-- name: ListReportsByPost :many
+SELECT rp.id, rp.reasons, rp.message, rp.created_at, rp.resolved_at,
+    u.id, u.full_name, u.avatar_url
+FROM reports rp
+JOIN users u ON rp.user_id = u.id
+WHERE rp.target_type = 'post' AND rp.target_id = @post_id::bigint
+ORDER BY rp.created_at DESC;
+
+-- name: ResolveReportsByPost :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE target_type = 'post' AND target_id = @post_id::bigint AND resolved_at IS NULL;
+
+-- name: InsertModerationAction :exec
+INSERT INTO moderation_actions (moderator_id, post_id, target_user_id, action, note, created_at)
+VALUES (NULLIF(@moderator_id::bigint, 0), NULLIF(@post_id::bigint, 0), NULLIF(@target_user_id::bigint, 0), @action::text, NULLIF(@note::text, ''), NOW());
+
+-- name: ListModerationActionsByPost :many
+SELECT ma.*, u.full_name AS moderator_full_name
+FROM moderation_actions ma
+LEFT JOIN users u ON ma.moderator_id = u.id
+WHERE ma.post_id = @post_id::bigint
+ORDER BY ma.created_at DESC;
+
+-- name: InsertUserWarning :exec
+INSERT INTO user_warnings (user_id, moderator_id, post_id, message, created_at)
+VALUES (@user_id::bigint, @moderator_id::bigint, NULLIF(@post_id::bigint, 0), @message::text, NOW());
+
+-- name: UpsertUserAccountState :exec
+INSERT INTO user_account_states (user_id, state, reason, expires_at, updated_at, updated_by)
+VALUES (@user_id::bigint, @state::text, NULLIF(@reason::text, ''), sqlc.narg(expires_at)::timestamp, NOW(), NULLIF(@updated_by::bigint, 0))
+ON CONFLICT (user_id) DO UPDATE
+SET state = EXCLUDED.state,
+    reason = EXCLUDED.reason,
+    expires_at = EXCLUDED.expires_at,
+    updated_at = NOW(),
+    updated_by = EXCLUDED.updated_by;
+
+-- name: GetUserAccountState :one
+SELECT state, reason, expires_at
+FROM user_account_states
+WHERE user_id = @user_id::bigint AND state != 'active' AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: ListOpenContentFlags :many
+SELECT cf.id, cf.target_type, cf.target_id, cf.verdict, cf.rules, cf.content, cf.created_at, cf.resolved_at,
+    u.id, u.full_name, u.avatar_url,
+    COUNT(*) OVER () AS total_rows
+FROM content_flags cf
+JOIN users u ON cf.user_id = u.id
+WHERE cf.resolved_at IS NULL
+ORDER BY cf.created_at ASC
+OFFSET $1
+LIMIT $2;
+
+-- name: GetContentFlagById :one
+SELECT cf.id, cf.target_type, cf.target_id, cf.verdict, cf.rules, cf.content, cf.created_at, cf.resolved_at,
+    u.id, u.full_name, u.avatar_url
+FROM content_flags cf
+JOIN users u ON cf.user_id = u.id
+WHERE cf.id = @id::bigint;
+
+-- name: ResolveContentFlag :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE id = @id::bigint AND resolved_at IS NULL;
+
+-- name: DeleteHiddenPostByReason :exec
+DELETE FROM hidden_posts
+WHERE post_id = @post_id::bigint AND reason = @reason::text;
+
+-- name: InsertHiddenPostComment :exec
+INSERT INTO hidden_post_comments (post_comment_id, created_at)
+VALUES (@post_comment_id::bigint, NOW())
+ON CONFLICT (post_comment_id) DO NOTHING;
+
+-- name: InsertHiddenPostCommentReply :exec
+INSERT INTO hidden_post_comment_replies (post_comment_reply_id, created_at)
+VALUES (@post_comment_reply_id::bigint, NOW())
+ON CONFLICT (post_comment_reply_id) DO NOTHING;
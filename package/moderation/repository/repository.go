@@ -0,0 +1,649 @@
+package moderation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	db "profiln-be/db/sqlc"
+	"profiln-be/model"
+	adminRepository "profiln-be/package/admin/repository"
+	postsRepository "profiln-be/package/posts/repository"
+)
+
+type IModerationRepository interface {
+	GetAccountState(userId int64) (model.AccountState, error)
+	SetAccountState(moderatorId, userId int64, state, reason string, expiresAt sql.NullTime, audit *model.AuditEntry) error
+	GetUserById(userId int64) (db.GetUserByIdRow, error)
+	ListReportedContent(offset, limit int32) ([]model.ReportedContentSummary, int64, error)
+	ListReportsByPost(postId int64) ([]model.PostReport, error)
+	ListModerationActionsByPost(postId int64) ([]model.ModerationAction, error)
+	DismissReports(moderatorId, postId int64, note string, audit *model.AuditEntry) error
+	HidePost(moderatorId, postId int64, note string, audit *model.AuditEntry) error
+	WarnUser(moderatorId, postId, userId int64, note string, audit *model.AuditEntry) error
+	SuspendUser(moderatorId, postId, userId int64, note string, expiresAt sql.NullTime, audit *model.AuditEntry) error
+	DeleteReportedPost(moderatorId, postId, userId int64, note string, audit *model.AuditEntry) error
+	ActOnReportedContent(moderatorId int64, content model.ReportedContent, action, note string, expiresAt sql.NullTime, audit *model.AuditEntry) error
+	ListContentFlags(offset, limit int32) ([]model.ContentFlag, int64, error)
+	GetContentFlagById(flagId int64) (model.ContentFlag, error)
+	ResolveContentFlag(moderatorId int64, flag model.ContentFlag, action, note string, audit *model.AuditEntry) error
+}
+
+// Audit trail action recorded for each account state
+var accountStateActions = map[string]string{
+	model.AccountStateActive:     model.ModerationActionRestore,
+	model.AccountStateRestricted: model.ModerationActionRestrict,
+	model.AccountStateSuspended:  model.ModerationActionSuspend,
+	model.AccountStateBanned:     model.ModerationActionBan,
+}
+
+type ModerationRepository struct {
+	dbConn *sql.DB
+	query  *db.Queries
+}
+
+func NewModerationRepository(dbConn *sql.DB) IModerationRepository {
+	return &ModerationRepository{
+		dbConn: dbConn,
+		query:  db.New(dbConn),
+	}
+}
+
+// GetAccountState returns the active state when the user has none or it already expired
+func (r *ModerationRepository) GetAccountState(userId int64) (model.AccountState, error) {
+	data, err := r.query.GetUserAccountState(context.Background(), userId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.AccountState{State: model.AccountStateActive}, nil
+	} else if err != nil {
+		return model.AccountState{}, err
+	}
+
+	state := model.AccountState{
+		State:  data.State,
+		Reason: data.Reason.String,
+	}
+
+	if data.ExpiresAt.Valid {
+		state.ExpiresAt = &data.ExpiresAt.Time
+	}
+
+	return state, nil
+}
+
+// SetAccountState changes the user's account state and records it in the audit trail
+// SetAccountState changes the account state, the optional audit entry is written in the same transaction
+func (r *ModerationRepository) SetAccountState(moderatorId, userId int64, state, reason string, expiresAt sql.NullTime, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	err = qtx.UpsertUserAccountState(ctx, db.UpsertUserAccountStateParams{
+		UserID:    userId,
+		State:     state,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		UpdatedBy: moderatorId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not upsert user account state: %w", err)
+	}
+
+	err = qtx.InsertModerationAction(ctx, db.InsertModerationActionParams{
+		ModeratorID:  moderatorId,
+		TargetUserID: userId,
+		Action:       accountStateActions[state],
+		Note:         reason,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert moderation action: %w", err)
+	}
+
+	if audit != nil {
+		if err = adminRepository.InsertAuditEntry(ctx, qtx, *audit); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) GetUserById(userId int64) (db.GetUserByIdRow, error) {
+	return r.query.GetUserById(context.Background(), userId)
+}
+
+// ListReportedContent groups the open reports by target, posts, comments, replies and users share one queue
+func (r *ModerationRepository) ListReportedContent(offset, limit int32) ([]model.ReportedContentSummary, int64, error) {
+	ctx := context.Background()
+	data, err := r.query.ListReportedContentSummary(ctx, db.ListReportedContentSummaryParams{
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		return []model.ReportedContentSummary{}, 0, err
+	}
+
+	// get total rows for pagination
+	var count int64
+	if len(data) > 0 {
+		count = data[0].TotalRows
+	}
+
+	targetTypes := make([]string, len(data))
+	targetIds := make([]int64, len(data))
+	for i, v := range data {
+		targetTypes[i] = v.TargetType
+		targetIds[i] = v.TargetID
+	}
+
+	reasonCounts, err := r.query.ListOpenReportReasonCountsByTargets(ctx, db.ListOpenReportReasonCountsByTargetsParams{
+		TargetTypes: targetTypes,
+		TargetIds:   targetIds,
+	})
+	if err != nil {
+		return []model.ReportedContentSummary{}, 0, fmt.Errorf("could not list report reason counts: %w", err)
+	}
+
+	type target struct {
+		targetType string
+		targetId   int64
+	}
+
+	reasonCountsByTarget := make(map[target]map[string]int64, len(data))
+	for _, v := range reasonCounts {
+		key := target{v.TargetType, v.TargetID}
+		if reasonCountsByTarget[key] == nil {
+			reasonCountsByTarget[key] = make(map[string]int64)
+		}
+		reasonCountsByTarget[key][v.Reason] = v.Count
+	}
+
+	reportedContent := make([]model.ReportedContentSummary, len(data))
+	for i, v := range data {
+		reportedContent[i] = model.ReportedContentSummary{
+			TargetType: v.TargetType,
+			TargetId:   v.TargetID,
+			PostId:     v.PostID,
+			Preview:    v.Preview,
+			Author: model.User{
+				ID:        v.ID,
+				Fullname:  v.FullName,
+				AvatarUrl: v.AvatarUrl.String,
+			},
+			ReporterCount:  v.ReporterCount,
+			ReasonCounts:   reasonCountsByTarget[target{v.TargetType, v.TargetID}],
+			IsHidden:       v.Hidden,
+			LastReportedAt: v.LastReportedAt,
+		}
+	}
+
+	return reportedContent, count, nil
+}
+
+func (r *ModerationRepository) ListReportsByPost(postId int64) ([]model.PostReport, error) {
+	data, err := r.query.ListReportsByPost(context.Background(), postId)
+	if err != nil {
+		return []model.PostReport{}, err
+	}
+
+	reports := make([]model.PostReport, len(data))
+	for i, v := range data {
+		reports[i] = model.PostReport{
+			ID: v.ID,
+			Reporter: model.User{
+				ID:        v.ID_2,
+				Fullname:  v.FullName,
+				AvatarUrl: v.AvatarUrl.String,
+			},
+			Reasons:   v.Reasons,
+			Message:   v.Message.String,
+			CreatedAt: v.CreatedAt,
+		}
+
+		if v.ResolvedAt.Valid {
+			reports[i].ResolvedAt = &v.ResolvedAt.Time
+		}
+	}
+
+	return reports, nil
+}
+
+func (r *ModerationRepository) ListModerationActionsByPost(postId int64) ([]model.ModerationAction, error) {
+	data, err := r.query.ListModerationActionsByPost(context.Background(), postId)
+	if err != nil {
+		return []model.ModerationAction{}, err
+	}
+
+	actions := make([]model.ModerationAction, len(data))
+	for i, v := range data {
+		actions[i] = model.ModerationAction{
+			ID:                v.ID,
+			ModeratorId:       v.ModeratorID.Int64,
+			ModeratorFullname: v.ModeratorFullName.String,
+			TargetUserId:      v.TargetUserID.Int64,
+			Action:            v.Action,
+			Note:              v.Note.String,
+			CreatedAt:         v.CreatedAt,
+		}
+	}
+
+	return actions, nil
+}
+
+// DismissReports resolves the open reports and restores the post if the reports hid it,
+// hides by moderators or the content policy stay. The optional audit entry is written in the same transaction
+func (r *ModerationRepository) DismissReports(moderatorId, postId int64, note string, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	err = qtx.DeleteHiddenPostByReason(ctx, db.DeleteHiddenPostByReasonParams{
+		PostID: postId,
+		Reason: model.HiddenPostReasonReports,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete hidden post: %w", err)
+	}
+
+	if err = r.resolveReports(qtx, moderatorId, postId, 0, model.ModerationActionDismiss, note, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) HidePost(moderatorId, postId int64, note string, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	if err = insertHiddenPost(ctx, qtx, postId); err != nil {
+		return err
+	}
+
+	if err = r.resolveReports(qtx, moderatorId, postId, 0, model.ModerationActionHide, note, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) WarnUser(moderatorId, postId, userId int64, note string, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	err = qtx.InsertUserWarning(ctx, db.InsertUserWarningParams{
+		UserID:      userId,
+		ModeratorID: moderatorId,
+		PostID:      postId,
+		Message:     note,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert user warning: %w", err)
+	}
+
+	if err = r.resolveReports(qtx, moderatorId, postId, userId, model.ModerationActionWarn, note, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SuspendUser suspends the post author and hides the reported post
+func (r *ModerationRepository) SuspendUser(moderatorId, postId, userId int64, note string, expiresAt sql.NullTime, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	err = qtx.UpsertUserAccountState(ctx, db.UpsertUserAccountStateParams{
+		UserID:    userId,
+		State:     model.AccountStateSuspended,
+		Reason:    note,
+		ExpiresAt: expiresAt,
+		UpdatedBy: moderatorId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not upsert user account state: %w", err)
+	}
+
+	if err = insertHiddenPost(ctx, qtx, postId); err != nil {
+		return err
+	}
+
+	if err = r.resolveReports(qtx, moderatorId, postId, userId, model.ModerationActionSuspend, note, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteReportedPost deletes the post with its reports, the trail keeps the post id without a foreign key
+func (r *ModerationRepository) DeleteReportedPost(moderatorId, postId, userId int64, note string, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	if err = postsRepository.DeletePostWithQueries(ctx, qtx, postId); err != nil {
+		return err
+	}
+
+	if err = r.resolveReports(qtx, moderatorId, postId, userId, model.ModerationActionDelete, note, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ActOnReportedContent applies the action to a reported comment, reply or user, closes the open
+// reports on the target and records the action. Hiding and suspending keep the content visible to its author
+func (r *ModerationRepository) ActOnReportedContent(moderatorId int64, content model.ReportedContent, action, note string, expiresAt sql.NullTime, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	switch action {
+	case model.ModerationActionHide:
+		err = insertHiddenContent(ctx, qtx, content)
+	case model.ModerationActionDelete:
+		if content.TargetType == model.ReportTargetPostComment {
+			err = postsRepository.DeletePostCommentWithQueries(ctx, qtx, content.PostId, content.TargetId)
+		} else if content.TargetType == model.ReportTargetPostCommentReply {
+			err = postsRepository.DeletePostCommentReplyWithQueries(ctx, qtx, content.PostCommentId, content.TargetId)
+		}
+	case model.ModerationActionWarn:
+		err = qtx.InsertUserWarning(ctx, db.InsertUserWarningParams{
+			UserID:      content.AuthorId,
+			ModeratorID: moderatorId,
+			PostID:      content.PostId,
+			Message:     note,
+		})
+		if err != nil {
+			err = fmt.Errorf("could not insert user warning: %w", err)
+		}
+	case model.ModerationActionSuspend:
+		err = qtx.UpsertUserAccountState(ctx, db.UpsertUserAccountStateParams{
+			UserID:    content.AuthorId,
+			State:     model.AccountStateSuspended,
+			Reason:    note,
+			ExpiresAt: expiresAt,
+			UpdatedBy: moderatorId,
+		})
+		if err != nil {
+			return fmt.Errorf("could not upsert user account state: %w", err)
+		}
+
+		err = insertHiddenContent(ctx, qtx, content)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = qtx.ResolveReportsByTarget(ctx, db.ResolveReportsByTargetParams{
+		TargetType: content.TargetType,
+		TargetID:   content.TargetId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not resolve reports: %w", err)
+	}
+
+	err = qtx.InsertModerationAction(ctx, db.InsertModerationActionParams{
+		ModeratorID:  moderatorId,
+		PostID:       content.PostId,
+		TargetUserID: content.AuthorId,
+		Action:       action,
+		Note:         note,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert moderation action: %w", err)
+	}
+
+	if audit != nil {
+		if err = adminRepository.InsertAuditEntry(ctx, qtx, *audit); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) ListContentFlags(offset, limit int32) ([]model.ContentFlag, int64, error) {
+	data, err := r.query.ListOpenContentFlags(context.Background(), db.ListOpenContentFlagsParams{
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		return []model.ContentFlag{}, 0, err
+	}
+
+	var totalRows int64
+	flags := make([]model.ContentFlag, len(data))
+	for i, v := range data {
+		totalRows = v.TotalRows
+		flags[i] = model.ContentFlag{
+			ID: v.ID,
+			Author: model.User{
+				ID:        v.ID_2,
+				Fullname:  v.FullName,
+				AvatarUrl: v.AvatarUrl.String,
+			},
+			TargetType: v.TargetType,
+			Verdict:    v.Verdict,
+			Rules:      v.Rules,
+			Content:    v.Content,
+			CreatedAt:  v.CreatedAt,
+		}
+
+		if v.TargetID.Valid {
+			flags[i].TargetId = &v.TargetID.Int64
+		}
+	}
+
+	return flags, totalRows, nil
+}
+
+func (r *ModerationRepository) GetContentFlagById(flagId int64) (model.ContentFlag, error) {
+	data, err := r.query.GetContentFlagById(context.Background(), flagId)
+	if err != nil {
+		return model.ContentFlag{}, err
+	}
+
+	flag := model.ContentFlag{
+		ID: data.ID,
+		Author: model.User{
+			ID:        data.ID_2,
+			Fullname:  data.FullName,
+			AvatarUrl: data.AvatarUrl.String,
+		},
+		TargetType: data.TargetType,
+		Verdict:    data.Verdict,
+		Rules:      data.Rules,
+		Content:    data.Content,
+		CreatedAt:  data.CreatedAt,
+	}
+
+	if data.TargetID.Valid {
+		flag.TargetId = &data.TargetID.Int64
+	}
+
+	if data.ResolvedAt.Valid {
+		flag.ResolvedAt = &data.ResolvedAt.Time
+	}
+
+	return flag, nil
+}
+
+// ResolveContentFlag closes the flag and records the action, approving a held post makes it visible again.
+// The optional audit entry is written in the same transaction
+func (r *ModerationRepository) ResolveContentFlag(moderatorId int64, flag model.ContentFlag, action, note string, audit *model.AuditEntry) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	if err = qtx.ResolveContentFlag(ctx, flag.ID); err != nil {
+		return fmt.Errorf("could not resolve content flag: %w", err)
+	}
+
+	var postId int64
+	if flag.TargetType == model.ReportTargetPost && flag.TargetId != nil {
+		postId = *flag.TargetId
+	}
+
+	if postId != 0 && action == model.ModerationActionApprove {
+		err = qtx.DeleteHiddenPostByReason(ctx, db.DeleteHiddenPostByReasonParams{
+			PostID: postId,
+			Reason: model.HiddenPostReasonContentPolicy,
+		})
+		if err != nil {
+			return fmt.Errorf("could not delete hidden post: %w", err)
+		}
+	}
+
+	err = qtx.InsertModerationAction(ctx, db.InsertModerationActionParams{
+		ModeratorID:  moderatorId,
+		PostID:       postId,
+		TargetUserID: flag.Author.ID,
+		Action:       action,
+		Note:         note,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert moderation action: %w", err)
+	}
+
+	if audit != nil {
+		if err = adminRepository.InsertAuditEntry(ctx, qtx, *audit); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// resolveReports closes the open reports of the post and records the action in the audit trail
+func (r *ModerationRepository) resolveReports(qtx *db.Queries, moderatorId, postId, targetUserId int64, action, note string, audit *model.AuditEntry) error {
+	ctx := context.Background()
+
+	if err := qtx.ResolveReportsByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not resolve reports: %w", err)
+	}
+
+	err := qtx.InsertModerationAction(ctx, db.InsertModerationActionParams{
+		ModeratorID:  moderatorId,
+		PostID:       postId,
+		TargetUserID: targetUserId,
+		Action:       action,
+		Note:         note,
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert moderation action: %w", err)
+	}
+
+	if audit != nil {
+		return adminRepository.InsertAuditEntry(ctx, qtx, *audit)
+	}
+
+	return nil
+}
+
+func insertHiddenPost(ctx context.Context, qtx *db.Queries, postId int64) error {
+	_, err := qtx.InsertHiddenPost(ctx, db.InsertHiddenPostParams{
+		PostID: postId,
+		Reason: model.HiddenPostReasonModerator,
+	})
+
+	// The post is already hidden
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not insert hidden post: %w", err)
+	}
+
+	return nil
+}
+
+// insertHiddenContent hides a comment or reply, reported users have nothing to hide
+func insertHiddenContent(ctx context.Context, qtx *db.Queries, content model.ReportedContent) error {
+	var err error
+	switch content.TargetType {
+	case model.ReportTargetPostComment:
+		err = qtx.InsertHiddenPostComment(ctx, content.TargetId)
+	case model.ReportTargetPostCommentReply:
+		err = qtx.InsertHiddenPostCommentReply(ctx, content.TargetId)
+	}
+	if err != nil {
+		return fmt.Errorf("could not insert hidden %s: %w", content.TargetType, err)
+	}
+
+	return nil
+}
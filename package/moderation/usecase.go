@@ -0,0 +1,520 @@
+package moderation
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"profiln-be/libs"
+	email "profiln-be/libs/email"
+	"profiln-be/model"
+	repository "profiln-be/package/moderation/repository"
+	postsRepository "profiln-be/package/posts/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type IModerationUsecase interface {
+	ListReportedContent(pagination model.PaginationRequest) (resp model.Response)
+	GetReportedPost(postId int64) (resp model.Response)
+	ActOnReportedPost(moderatorId, postId int64, props *model.ModerationActionRequest, audit *model.AuditEntry) model.Response
+	ActOnReportedContent(moderatorId int64, targetType string, targetId int64, props *model.ModerationActionRequest, audit *model.AuditEntry) model.Response
+	ListContentFlags(pagination model.PaginationRequest) (resp model.Response)
+	ActOnContentFlag(moderatorId, flagId int64, props *model.ContentFlagActionRequest) model.Response
+	SetAccountState(moderatorId, userId int64, props *model.AccountStateRequest) model.Response
+}
+
+type ModerationUsecase struct {
+	repository      repository.IModerationRepository
+	postsRepository postsRepository.IPostsRepository
+	googleBucket    libs.IGoogleBucket
+	email           email.IEmail
+	log             *logrus.Logger
+}
+
+func NewModerationUsecase(repository repository.IModerationRepository, postsRepository postsRepository.IPostsRepository, googleBucket libs.IGoogleBucket, email email.IEmail, log *logrus.Logger) IModerationUsecase {
+	return &ModerationUsecase{
+		repository,
+		postsRepository,
+		googleBucket,
+		email,
+		log,
+	}
+}
+
+func (u *ModerationUsecase) ListReportedContent(pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListReportedContent(int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListReportedContent: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get reported content")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+func (u *ModerationUsecase) GetReportedPost(postId int64) (resp model.Response) {
+	reports, err := u.repository.ListReportsByPost(postId)
+	if err != nil {
+		u.log.Errorf("repository.ListReportsByPost (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	actions, err := u.repository.ListModerationActionsByPost(postId)
+	if err != nil {
+		u.log.Errorf("repository.ListModerationActionsByPost (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	// Deleted posts keep their audit trail, so only 404 when nothing is known about the post
+	if len(reports) == 0 && len(actions) == 0 {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get reported post")
+	resp.Data = map[string]any{
+		"reports": reports,
+		"actions": actions,
+	}
+	return
+}
+
+func (u *ModerationUsecase) ActOnReportedPost(moderatorId, postId int64, props *model.ModerationActionRequest, audit *model.AuditEntry) model.Response {
+	post, err := u.postsRepository.GetPostById(postId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("postsRepository.GetPostById (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	authorId := post.User.ID
+
+	switch props.Action {
+	case model.ModerationActionDismiss:
+		err = u.repository.DismissReports(moderatorId, postId, props.Note, audit)
+	case model.ModerationActionHide:
+		err = u.repository.HidePost(moderatorId, postId, props.Note, audit)
+	case model.ModerationActionWarn:
+		if props.Note == "" {
+			return model.Response{
+				Status: libs.CustomResponse(http.StatusBadRequest, "Note is required to warn a user"),
+			}
+		}
+
+		err = u.repository.WarnUser(moderatorId, postId, authorId, props.Note, audit)
+	case model.ModerationActionSuspend:
+		var expiresAt sql.NullTime
+		if props.SuspendDays > 0 {
+			expiresAt = sql.NullTime{
+				Time:  time.Now().UTC().AddDate(0, 0, props.SuspendDays),
+				Valid: true,
+			}
+		}
+
+		err = u.repository.SuspendUser(moderatorId, postId, authorId, props.Note, expiresAt, audit)
+		if err == nil {
+			go u.notifyAccountState(authorId, model.AccountStateSuspended, props.Note, expiresAt)
+		}
+	case model.ModerationActionDelete:
+		return u.deleteReportedPost(moderatorId, postId, authorId, props.Note, audit)
+	}
+
+	if err != nil {
+		u.log.Errorf("repository %s reported post (post id %d): %v", props.Action, postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success act on reported post"),
+	}
+}
+
+// ActOnReportedContent acts on a reported comment, reply or user,
+// reported users can only be dismissed, warned or suspended
+func (u *ModerationUsecase) ActOnReportedContent(moderatorId int64, targetType string, targetId int64, props *model.ModerationActionRequest, audit *model.AuditEntry) model.Response {
+	content, err := u.getReportedContent(targetType, targetId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("getReportedContent (%s id %d): %v", targetType, targetId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if targetType == model.ReportTargetUser && (props.Action == model.ModerationActionHide || props.Action == model.ModerationActionDelete) {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Only dismiss, warn and suspend are available for a reported user"),
+		}
+	}
+
+	if props.Action == model.ModerationActionWarn && props.Note == "" {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Note is required to warn a user"),
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if props.Action == model.ModerationActionSuspend && props.SuspendDays > 0 {
+		expiresAt = sql.NullTime{
+			Time:  time.Now().UTC().AddDate(0, 0, props.SuspendDays),
+			Valid: true,
+		}
+	}
+
+	var objectUrls []string
+	if props.Action == model.ModerationActionDelete {
+		if targetType == model.ReportTargetPostComment {
+			if objectUrls, err = u.postsRepository.GetPostCommentReplyImageUrls(targetId); err != nil {
+				u.log.Errorf("postsRepository.GetPostCommentReplyImageUrls (comment id %d): %v", targetId, err)
+				return model.Response{
+					Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+				}
+			}
+		}
+
+		if content.ImageUrl != "" {
+			objectUrls = append(objectUrls, content.ImageUrl)
+		}
+	}
+
+	err = u.repository.ActOnReportedContent(moderatorId, content, props.Action, props.Note, expiresAt, audit)
+	if err != nil {
+		u.log.Errorf("repository.ActOnReportedContent (%s id %d): %v", targetType, targetId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if props.Action == model.ModerationActionSuspend {
+		go u.notifyAccountState(content.AuthorId, model.AccountStateSuspended, props.Note, expiresAt)
+	}
+
+	if len(objectUrls) > 0 {
+		if err := u.googleBucket.HandleObjectDeletion(objectUrls...); err != nil {
+			u.log.Errorf("googleBucket.HandleObjectDeletion (%s id %d): %v", targetType, targetId, err)
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success act on reported content"),
+	}
+}
+
+// getReportedContent loads the author and parents of the reported comment, reply or user
+func (u *ModerationUsecase) getReportedContent(targetType string, targetId int64) (model.ReportedContent, error) {
+	content := model.ReportedContent{
+		TargetType: targetType,
+		TargetId:   targetId,
+	}
+
+	switch targetType {
+	case model.ReportTargetPostComment:
+		comment, err := u.postsRepository.GetPostCommentById(targetId)
+		if err != nil {
+			return model.ReportedContent{}, err
+		}
+
+		content.PostId = comment.PostID.Int64
+		content.AuthorId = comment.UserID.Int64
+		content.ImageUrl = comment.ImageUrl.String
+	case model.ReportTargetPostCommentReply:
+		reply, err := u.postsRepository.GetPostCommentReplyById(targetId)
+		if err != nil {
+			return model.ReportedContent{}, err
+		}
+
+		content.PostId = reply.PostID.Int64
+		content.PostCommentId = reply.PostCommentID.Int64
+		content.AuthorId = reply.UserID.Int64
+		content.ImageUrl = reply.ImageUrl.String
+	case model.ReportTargetUser:
+		if _, err := u.repository.GetUserById(targetId); err != nil {
+			return model.ReportedContent{}, err
+		}
+
+		content.AuthorId = targetId
+	default:
+		return model.ReportedContent{}, fmt.Errorf("unknown report target type %s", targetType)
+	}
+
+	return content, nil
+}
+
+func (u *ModerationUsecase) ListContentFlags(pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListContentFlags(int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListContentFlags: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get content flags")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+// ActOnContentFlag approves or removes the flagged content, rejected content was never
+// stored so both actions only close its flag
+func (u *ModerationUsecase) ActOnContentFlag(moderatorId, flagId int64, props *model.ContentFlagActionRequest) model.Response {
+	flag, err := u.repository.GetContentFlagById(flagId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetContentFlagById (flag id %d): %v", flagId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if flag.ResolvedAt != nil {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Content flag already resolved"),
+		}
+	}
+
+	if props.Action == model.ModerationActionRemove && flag.TargetId != nil {
+		if err := u.removeFlaggedContent(flag.TargetType, *flag.TargetId); err != nil {
+			u.log.Errorf("removeFlaggedContent (flag id %d): %v", flagId, err)
+			return model.Response{
+				Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+			}
+		}
+	}
+
+	audit := &model.AuditEntry{
+		ActorId:    moderatorId,
+		Action:     model.AuditActionActOnContentFlag,
+		TargetType: model.AuditTargetContentFlag,
+		TargetId:   flag.ID,
+		Details: map[string]any{
+			"action":      props.Action,
+			"target_type": flag.TargetType,
+			"target_id":   flag.TargetId,
+			"note":        props.Note,
+		},
+	}
+
+	if err := u.repository.ResolveContentFlag(moderatorId, flag, props.Action, props.Note, audit); err != nil {
+		u.log.Errorf("repository.ResolveContentFlag (flag id %d): %v", flagId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success act on content flag"),
+	}
+}
+
+// removeFlaggedContent deletes the held post, comment or reply with its images,
+// content the author already deleted is skipped
+func (u *ModerationUsecase) removeFlaggedContent(targetType string, targetId int64) error {
+	var (
+		objectUrls []string
+		err        error
+	)
+
+	switch targetType {
+	case model.ReportTargetPost:
+		if _, err = u.postsRepository.GetPostById(targetId); err != nil {
+			break
+		}
+
+		if objectUrls, err = u.postsRepository.GetPostImagesUrl(targetId); err != nil {
+			return fmt.Errorf("postsRepository.GetPostImagesUrl: %w", err)
+		}
+
+		err = u.postsRepository.DeletePost(targetId)
+	case model.ReportTargetPostComment:
+		comment, errGet := u.postsRepository.GetPostCommentById(targetId)
+		if errGet != nil {
+			err = errGet
+			break
+		}
+
+		if objectUrls, err = u.postsRepository.GetPostCommentReplyImageUrls(targetId); err != nil {
+			return fmt.Errorf("postsRepository.GetPostCommentReplyImageUrls: %w", err)
+		}
+
+		if comment.ImageUrl.String != "" {
+			objectUrls = append(objectUrls, comment.ImageUrl.String)
+		}
+
+		err = u.postsRepository.DeletePostComment(comment.PostID.Int64, targetId)
+	case model.ReportTargetPostCommentReply:
+		reply, errGet := u.postsRepository.GetPostCommentReplyById(targetId)
+		if errGet != nil {
+			err = errGet
+			break
+		}
+
+		if reply.ImageUrl.String != "" {
+			objectUrls = append(objectUrls, reply.ImageUrl.String)
+		}
+
+		err = u.postsRepository.DeletePostCommentReply(reply.PostCommentID.Int64, targetId)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not delete %s: %w", targetType, err)
+	}
+
+	if len(objectUrls) > 0 {
+		if err := u.googleBucket.HandleObjectDeletion(objectUrls...); err != nil {
+			u.log.Errorf("googleBucket.HandleObjectDeletion (%s id %d): %v", targetType, targetId, err)
+		}
+	}
+
+	return nil
+}
+
+func (u *ModerationUsecase) SetAccountState(moderatorId, userId int64, props *model.AccountStateRequest) model.Response {
+	_, err := u.repository.GetUserById(userId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetUserById (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if props.Days > 0 && props.State != model.AccountStateActive {
+		expiresAt = sql.NullTime{
+			Time:  time.Now().UTC().AddDate(0, 0, props.Days),
+			Valid: true,
+		}
+	}
+
+	audit := &model.AuditEntry{
+		ActorId:    moderatorId,
+		Action:     model.AuditActionSetAccountState,
+		TargetType: model.AuditTargetUser,
+		TargetId:   userId,
+		Details: map[string]any{
+			"state":  props.State,
+			"reason": props.Reason,
+			"days":   props.Days,
+		},
+	}
+
+	err = u.repository.SetAccountState(moderatorId, userId, props.State, props.Reason, expiresAt, audit)
+	if err != nil {
+		u.log.Errorf("repository.SetAccountState (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	go u.notifyAccountState(userId, props.State, props.Reason, expiresAt)
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success set account state"),
+	}
+}
+
+// notifyAccountState emails the user about the new account state, failures are only logged
+func (u *ModerationUsecase) notifyAccountState(userId int64, state, reason string, expiresAt sql.NullTime) {
+	user, err := u.repository.GetUserById(userId)
+	if err != nil {
+		u.log.Errorf("repository.GetUserById (user id %d): %v", userId, err)
+		return
+	}
+
+	data := model.AccountStateEmail{
+		Fullname: user.FullName,
+		State:    state,
+		Reason:   reason,
+	}
+
+	if expiresAt.Valid {
+		data.ExpiresAt = expiresAt.Time.Format("02 January 2006 15:04 MST")
+	}
+
+	subject := "Profiln Account Status Update"
+	if err := u.email.SendAuthEmail(subject, []string{user.Email}, data, "account-state.html"); err != nil {
+		u.log.Errorf("email.SendAuthEmail: %v", err)
+	}
+}
+
+// deleteReportedPost deletes the post with its reports, the trail keeps the post id without a foreign key
+func (u *ModerationUsecase) deleteReportedPost(moderatorId, postId, authorId int64, note string, audit *model.AuditEntry) model.Response {
+	currentObjectUrls, err := u.postsRepository.GetPostImagesUrl(postId)
+	if err != nil {
+		u.log.Errorf("postsRepository.GetPostImagesUrl (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if err = u.repository.DeleteReportedPost(moderatorId, postId, authorId, note, audit); err != nil {
+		u.log.Errorf("repository.DeleteReportedPost (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if len(currentObjectUrls) > 0 {
+		if err := u.googleBucket.HandleObjectDeletion(currentObjectUrls...); err != nil {
+			u.log.Errorf("googleBucket.HandleObjectDeletion (post id %d): %v", postId, err)
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success act on reported post"),
+	}
+}
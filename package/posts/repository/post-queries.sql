@@ -1,11 +1,11 @@
--- name: InsertReportedPost :one
-INSERT INTO reported_posts
-(user_id, post_id, reason, message)
-SELECT @user_id::bigint, @post_id::bigint, UNNEST(@reason::varchar(15)[]), @message::text
+-- name: InsertReport :one
+INSERT INTO reports (user_id, target_type, target_id, reasons, message, created_at)
+VALUES (@user_id::bigint, @target_type::text, @target_id::bigint, @reasons::varchar(50)[], @message::text, NOW())
+ON CONFLICT (user_id, target_type, target_id) DO NOTHING
 RETURNING *;
 
 -- name: GetDetailPost :one
-SELECT p.*, 
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
     pu.id, pu.avatar_url, pu.full_name, pu.bio, pu.open_to_work,
 	ARRAY_AGG(pi.url ORDER BY pi.index ASC) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
     CASE 
@@ -15,15 +15,20 @@ SELECT p.*,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 JOIN users pu ON p.user_id = pu.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = $2
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = $2
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = $2
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE p.id = $1
+WHERE p.id = $1 AND visible_post_for($2, p.id, p.user_id)
 GROUP BY 
-    p.id, pu.id, lp.user_id, rpp.user_id;
+    p.id, pu.id, lp.user_id, rpp.user_id, bp.user_id;
 
 -- name: GetPostComments :many
 SELECT pc.*,
@@ -32,10 +37,30 @@ SELECT pc.*,
 FROM post_comments pc 
 LEFT JOIN users pcu ON pc.user_id = pcu.id
 WHERE pc.post_id = $1
+    AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.target_type = 'post_comment' AND r.target_id = pc.id AND r.user_id = $4)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $4 AND ub.blocked_user_id = pc.user_id) OR (ub.user_id = pc.user_id AND ub.blocked_user_id = $4))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = pc.user_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    AND (pc.user_id = $4 OR NOT EXISTS (SELECT 1 FROM content_flags cf WHERE cf.target_type = 'post_comment' AND cf.target_id = pc.id AND cf.verdict = 'hold' AND cf.resolved_at IS NULL))
+    AND (pc.user_id = $4 OR NOT EXISTS (SELECT 1 FROM hidden_post_comments hpc WHERE hpc.post_comment_id = pc.id))
 ORDER BY pc.created_at DESC
 OFFSET $2
 LIMIT $3;
 
+-- name: GetPostCommentsByCursor :many
+SELECT pc.*,
+    pcu.id, pcu.avatar_url, pcu.full_name, pcu.bio, pcu.open_to_work
+FROM post_comments pc 
+LEFT JOIN users pcu ON pc.user_id = pcu.id
+WHERE pc.post_id = $1
+    AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.target_type = 'post_comment' AND r.target_id = pc.id AND r.user_id = $2)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $2 AND ub.blocked_user_id = pc.user_id) OR (ub.user_id = pc.user_id AND ub.blocked_user_id = $2))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = pc.user_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    AND (pc.user_id = $2 OR NOT EXISTS (SELECT 1 FROM content_flags cf WHERE cf.target_type = 'post_comment' AND cf.target_id = pc.id AND cf.verdict = 'hold' AND cf.resolved_at IS NULL))
+    AND (pc.user_id = $2 OR NOT EXISTS (SELECT 1 FROM hidden_post_comments hpc WHERE hpc.post_comment_id = pc.id))
+    AND (@cursor_id::bigint = 0 OR (pc.created_at, pc.id) < (@cursor_created_at::timestamp, @cursor_id::bigint))
+ORDER BY pc.created_at DESC, pc.id DESC
+LIMIT $3;
+
 -- name: GetPostCommentReplies :many
 SELECT pcr.*, 
     pcr_user.id, pcr_user.avatar_url, pcr_user.full_name, pcr_user.bio, pcr_user.open_to_work,
@@ -44,6 +69,11 @@ FROM post_comment_replies pcr
 LEFT JOIN users pcr_user ON pcr.user_id = pcr_user.id
 LEFT JOIN post_comments pc ON pc.id = pcr.post_comment_id
 WHERE pc.post_id = $1 AND pcr.post_comment_id = $2
+    AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.target_type = 'post_comment_reply' AND r.target_id = pcr.id AND r.user_id = $5)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = $5 AND ub.blocked_user_id = pcr.user_id) OR (ub.user_id = pcr.user_id AND ub.blocked_user_id = $5))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = pcr.user_id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+    AND (pcr.user_id = $5 OR NOT EXISTS (SELECT 1 FROM content_flags cf WHERE cf.target_type = 'post_comment_reply' AND cf.target_id = pcr.id AND cf.verdict = 'hold' AND cf.resolved_at IS NULL))
+    AND (pcr.user_id = $5 OR NOT EXISTS (SELECT 1 FROM hidden_post_comment_replies hpcr WHERE hpcr.post_comment_reply_id = pcr.id))
 ORDER BY pcr.created_at DESC
 OFFSET $3
 LIMIT $4;
@@ -62,7 +92,7 @@ WHERE id = @id::bigint
 RETURNING id, like_count;
 
 -- name: ListNewestPostsByTargetUser :many
-SELECT p.*, 
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
@@ -73,21 +103,26 @@ SELECT p.*,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = @user_id::bigint
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = @user_id::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = @user_id::bigint
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE p.user_id = @target_user_id::bigint
+WHERE p.user_id = @target_user_id::bigint AND visible_post_for(@user_id::bigint, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id
 ORDER BY p.created_at DESC
 OFFSET $1
 LIMIT $2;
 
 -- name: ListLikedPostsByTargetUser :many
-SELECT p.*, 
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
@@ -98,22 +133,27 @@ SELECT p.*,
 	CASE 
     	WHEN rpp.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = @target_user_id::bigint
 LEFT JOIN liked_posts lp2 ON p.id = lp2.post_id AND lp2.user_id = @user_id::bigint
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = @user_id::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = @user_id::bigint
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE lp.user_id = @target_user_id::bigint
+WHERE lp.user_id = @target_user_id::bigint AND visible_post_for(@user_id::bigint, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id, lp2.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, lp2.user_id, bp.user_id
 ORDER BY p.created_at DESC
 OFFSET $1
 LIMIT $2;
 
 -- name: ListRepostedPostsByTargetUser :many
-SELECT p.*, 
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
 	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
 	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
 	COUNT(p.id) OVER () AS total_rows,
@@ -124,16 +164,21 @@ SELECT p.*,
 	CASE 
     	WHEN rpp2.user_id IS NOT NULL THEN TRUE 
     	ELSE FALSE 
-  	END AS repost
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked
 FROM posts p
 LEFT JOIN users u ON p.user_id = u.id
 LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = @user_id::bigint
 LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = @target_user_id::bigint
 LEFT JOIN reposted_posts rpp2 ON p.id = rpp2.post_id AND rpp2.user_id = @user_id::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = @user_id::bigint
 LEFT JOIN post_images pi ON p.id = pi.post_id
-WHERE rpp.user_id = @target_user_id::bigint
+WHERE rpp.user_id = @target_user_id::bigint AND visible_post_for(@user_id::bigint, p.id, p.user_id)
 GROUP BY 
-    p.id, u.id, lp.user_id, rpp.user_id, rpp2.user_id
+    p.id, u.id, lp.user_id, rpp.user_id, rpp2.user_id, bp.user_id
 ORDER BY p.created_at DESC
 OFFSET $1
 LIMIT $2;
@@ -142,10 +187,31 @@ LIMIT $2;
 INSERT INTO posts
 (user_id, title, content, visibility, created_at, updated_at)
 VALUES ($1, $2, $3, $4, NOW(), NOW())
-RETURNING *;
+RETURNING id, user_id, content, like_count, comment_count, repost_count, created_at, updated_at, title, visibility;
+
+-- name: GetUserFollowersCount :one
+SELECT COALESCE(followers_count, 0)::int FROM users
+WHERE id = @user_id::bigint;
+
+-- name: FanOutPost :exec
+INSERT INTO timeline_entries
+(user_id, post_id, author_id, created_at)
+SELECT f.user_id, p.id, p.user_id, COALESCE(p.created_at, NOW())
+FROM posts p
+JOIN followings f ON f.follow_user_id = p.user_id
+WHERE p.id = @post_id::bigint
+ON CONFLICT DO NOTHING;
+
+-- name: InsertTimelinePullPost :exec
+INSERT INTO timeline_pull_posts
+(post_id, author_id, created_at)
+SELECT id, user_id, COALESCE(created_at, NOW())
+FROM posts
+WHERE id = @post_id::bigint
+ON CONFLICT DO NOTHING;
 
 -- name: GetPostById :one
-SELECT * FROM posts
+SELECT id, user_id, content, like_count, comment_count, repost_count, created_at, updated_at, title, visibility FROM posts
 WHERE id = $1
 LIMIT 1;
 
@@ -175,9 +241,18 @@ WHERE post_id = @post_id::bigint;
 DELETE FROM post_images
 WHERE post_id = @post_id::bigint;
 
--- name: BatchDeleteReportedPostsByPost :exec
-DELETE FROM reported_posts
-WHERE post_id = @post_id::bigint;
+-- name: ResolveAllReportsByPost :exec
+-- Reports outlive the deleted content so the report trail stays
+UPDATE reports
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND ((target_type = 'post' AND target_id = @post_id::bigint)
+    OR (target_type = 'post_comment' AND target_id IN (SELECT id FROM post_comments WHERE post_id = @post_id::bigint))
+    OR (target_type = 'post_comment_reply' AND target_id IN (
+        SELECT pcr.id
+        FROM post_comment_replies pcr
+        JOIN post_comments pc ON pcr.post_comment_id = pc.id
+        WHERE pc.post_id = @post_id::bigint
+    )));
 
 -- name: BatchDeleteLikedPostByPost :exec
 DELETE FROM liked_posts
@@ -201,15 +276,15 @@ DELETE FROM post_comment_replies
 WHERE post_comment_id IN (SELECT id FROM post_comments);
 
 -- name: InsertLikedPost :one
-INSERT INTO liked_posts (user_id, post_id)
-VALUES (@user_id::bigint, @post_id::bigint)
+INSERT INTO liked_posts (user_id, post_id, reaction_type, created_at)
+VALUES (@user_id::bigint, @post_id::bigint, @reaction_type::text, NOW())
 ON CONFLICT (user_id, post_id) DO NOTHING
 RETURNING id;
 
 -- name: DeleteLikedPost :one
 DELETE FROM liked_posts
 WHERE user_id = @user_id::bigint AND post_id = @post_id::bigint
-RETURNING id;
+RETURNING id, reaction_type;
 
 -- name: UpdatePostRepostCount :one
 UPDATE posts
@@ -219,8 +294,8 @@ WHERE id = @id::bigint
 RETURNING id, repost_count;
 
 -- name: InsertRepostedPost :one
-INSERT INTO reposted_posts (user_id, post_id)
-VALUES (@user_id::bigint, @post_id::bigint)
+INSERT INTO reposted_posts (user_id, post_id, created_at)
+VALUES (@user_id::bigint, @post_id::bigint, NOW())
 ON CONFLICT (user_id, post_id) DO NOTHING
 RETURNING id;
 
@@ -248,7 +323,7 @@ RETURNING *;
 
 -- name: LockPostCommentForUpdate :one
 SELECT 1
-FROM posts
+FROM post_comments
 WHERE id = $1
 FOR UPDATE;
 
@@ -260,8 +335,8 @@ WHERE id = @id::bigint
 RETURNING id, like_count;
 
 -- name: InsertLikedPostComments :one
-INSERT INTO liked_post_comments (user_id, post_comment_id)
-VALUES (@user_id::bigint, @post_comment_id::bigint)
+INSERT INTO liked_post_comments (user_id, post_comment_id, reaction_type)
+VALUES (@user_id::bigint, @post_comment_id::bigint, @reaction_type::text)
 ON CONFLICT (user_id, post_comment_id) DO NOTHING
 RETURNING id;
 
@@ -284,7 +359,7 @@ RETURNING id, reply_count;
 
 -- name: LockPostCommentReplyForUpdate :one
 SELECT 1
-FROM posts
+FROM post_comment_replies
 WHERE id = $1
 FOR UPDATE;
 
@@ -296,12 +371,485 @@ WHERE id = @id::bigint
 RETURNING id, like_count;
 
 -- name: InsertLikedPostCommentReplies :one
-INSERT INTO liked_post_comment_replies (user_id, post_comment_reply_id)
-VALUES (@user_id::bigint, @post_comment_reply_id::bigint)
+INSERT INTO liked_post_comment_replies (user_id, post_comment_reply_id, reaction_type)
+VALUES (@user_id::bigint, @post_comment_reply_id::bigint, @reaction_type::text)
 ON CONFLICT (user_id, post_comment_reply_id) DO NOTHING
 RETURNING id;
 
 -- name: DeleteLikedPostCommentReplies :one
 DELETE FROM liked_post_comment_replies
 WHERE user_id = @user_id::bigint AND post_comment_reply_id = @post_comment_reply_id::bigint
-RETURNING id;
\ No newline at end of file
+RETURNING id;
+
+-- name: GetLinkPreviewByUrl :one
+SELECT * FROM link_previews
+WHERE url = @url::text
+LIMIT 1;
+
+-- name: UpsertLinkPreview :one
+INSERT INTO link_previews (url, title, description, image_url, site_name, fetched_at)
+VALUES (@url::text, @title::text, @description::text, @image_url::text, @site_name::text, NOW())
+ON CONFLICT (url) DO UPDATE
+SET title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    image_url = EXCLUDED.image_url,
+    site_name = EXCLUDED.site_name,
+    fetched_at = NOW()
+RETURNING *;
+
+-- name: UpsertPostLinkPreview :exec
+INSERT INTO post_link_previews (post_id, link_preview_id)
+VALUES (@post_id::bigint, @link_preview_id::bigint)
+ON CONFLICT (post_id) DO UPDATE
+SET link_preview_id = EXCLUDED.link_preview_id;
+
+-- name: BatchDeletePostLinkPreviewsByPost :exec
+DELETE FROM post_link_previews
+WHERE post_id = @post_id::bigint;
+
+-- name: ListLinkPreviewsByPostIds :many
+SELECT plp.post_id, lp.url, lp.title, lp.description, lp.image_url, lp.site_name
+FROM post_link_previews plp
+JOIN link_previews lp ON plp.link_preview_id = lp.id
+WHERE plp.post_id = ANY(@post_ids::bigint[]);
+
+
+-- name: InsertBookmarkedPost :one
+INSERT INTO bookmarked_posts (user_id, post_id, bookmark_folder_id, created_at)
+VALUES (@user_id::bigint, @post_id::bigint, @bookmark_folder_id, NOW())
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET bookmark_folder_id = EXCLUDED.bookmark_folder_id
+RETURNING id;
+
+-- name: DeleteBookmarkedPost :one
+DELETE FROM bookmarked_posts
+WHERE user_id = @user_id::bigint AND post_id = @post_id::bigint
+RETURNING id;
+
+-- name: BatchDeleteBookmarkedPostsByPost :exec
+DELETE FROM bookmarked_posts
+WHERE post_id = @post_id::bigint;
+
+-- name: ListBookmarkedPosts :many
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+	COUNT(p.id) OVER () AS total_rows,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	bp.bookmark_folder_id
+FROM bookmarked_posts bp
+JOIN posts p ON bp.post_id = p.id
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = @user_id::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = @user_id::bigint
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE bp.user_id = @user_id::bigint AND visible_post_for(@user_id::bigint, p.id, p.user_id)
+	AND (@bookmark_folder_id::bigint = 0 OR bp.bookmark_folder_id = @bookmark_folder_id::bigint)
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.id
+ORDER BY bp.created_at DESC
+OFFSET $1
+LIMIT $2;
+
+-- name: InsertBookmarkFolder :one
+INSERT INTO bookmark_folders (user_id, name, created_at)
+VALUES (@user_id::bigint, @name::text, NOW())
+ON CONFLICT (user_id, name) DO NOTHING
+RETURNING *;
+
+-- name: GetBookmarkFolderById :one
+SELECT * FROM bookmark_folders
+WHERE id = @id::bigint AND user_id = @user_id::bigint
+LIMIT 1;
+
+-- name: ListBookmarkFolders :many
+SELECT bf.*, COUNT(bp.id) AS bookmark_count
+FROM bookmark_folders bf
+LEFT JOIN bookmarked_posts bp ON bf.id = bp.bookmark_folder_id
+WHERE bf.user_id = @user_id::bigint
+GROUP BY bf.id
+ORDER BY bf.name ASC;
+
+-- name: DeleteBookmarkFolder :one
+DELETE FROM bookmark_folders
+WHERE id = @id::bigint AND user_id = @user_id::bigint
+RETURNING id;
+
+
+-- name: GetPostLikeCount :one
+SELECT id, like_count FROM posts
+WHERE id = @id::bigint;
+
+-- name: GetPostReactionByUser :one
+SELECT reaction_type FROM liked_posts
+WHERE user_id = @user_id::bigint AND post_id = @post_id::bigint
+LIMIT 1;
+
+-- name: UpdateLikedPostReactionType :exec
+UPDATE liked_posts
+SET reaction_type = @reaction_type::text
+WHERE user_id = @user_id::bigint AND post_id = @post_id::bigint;
+
+-- name: UpdatePostReactionCount :exec
+INSERT INTO post_reaction_counts (post_id, reaction_type, count)
+VALUES (@post_id::bigint, @reaction_type::text, GREATEST(@value::int, 0))
+ON CONFLICT (post_id, reaction_type) DO UPDATE
+SET count = GREATEST(post_reaction_counts.count + @value::int, 0);
+
+-- name: BatchDeletePostReactionCountsByPost :exec
+DELETE FROM post_reaction_counts
+WHERE post_id = @post_id::bigint;
+
+-- name: ListPostReactionCountsByPostIds :many
+SELECT post_id, reaction_type, count
+FROM post_reaction_counts
+WHERE post_id = ANY(@post_ids::bigint[]) AND count > 0;
+
+-- name: ListUserReactionsByPostIds :many
+SELECT post_id, reaction_type
+FROM liked_posts
+WHERE user_id = @user_id::bigint AND post_id = ANY(@post_ids::bigint[]);
+
+-- name: ListPostReactions :many
+SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work, lp.reaction_type,
+	COUNT(lp.id) OVER () AS total_rows
+FROM liked_posts lp
+JOIN users u ON lp.user_id = u.id
+WHERE lp.post_id = @post_id::bigint
+	AND (@reaction_type::text = '' OR lp.reaction_type = @reaction_type::text)
+ORDER BY lp.id DESC
+OFFSET $1
+LIMIT $2;
+
+-- name: GetPostCommentReactionByUser :one
+SELECT reaction_type FROM liked_post_comments
+WHERE user_id = @user_id::bigint AND post_comment_id = @post_comment_id::bigint
+LIMIT 1;
+
+-- name: UpdateLikedPostCommentReactionType :exec
+UPDATE liked_post_comments
+SET reaction_type = @reaction_type::text
+WHERE user_id = @user_id::bigint AND post_comment_id = @post_comment_id::bigint;
+
+-- name: GetPostCommentReplyReactionByUser :one
+SELECT reaction_type FROM liked_post_comment_replies
+WHERE user_id = @user_id::bigint AND post_comment_reply_id = @post_comment_reply_id::bigint
+LIMIT 1;
+
+-- name: UpdateLikedPostCommentReplyReactionType :exec
+UPDATE liked_post_comment_replies
+SET reaction_type = @reaction_type::text
+WHERE user_id = @user_id::bigint AND post_comment_reply_id = @post_comment_reply_id::bigint;
+
+-- name: GetPostCommentLikeCount :one
+SELECT id, like_count FROM post_comments
+WHERE id = @id::bigint;
+
+-- name: GetPostCommentReplyLikeCount :one
+SELECT id, like_count FROM post_comment_replies
+WHERE id = @id::bigint;
+
+-- name: GetPostCommentById :one
+SELECT pc.*, p.user_id AS post_user_id
+FROM post_comments pc
+LEFT JOIN posts p ON p.id = pc.post_id
+WHERE pc.id = $1;
+
+-- name: UpdatePostComment :one
+UPDATE post_comments
+SET content = @content::text,
+    updated_at = NOW()
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeletePostCommentById :exec
+DELETE FROM post_comments
+WHERE id = @id::bigint;
+
+-- name: BatchDeleteLikedPostCommentsByComment :exec
+DELETE FROM liked_post_comments
+WHERE post_comment_id = @post_comment_id::bigint;
+
+-- name: BatchDeletePostCommentRepliesByComment :exec
+DELETE FROM post_comment_replies
+WHERE post_comment_id = @post_comment_id::bigint;
+
+-- name: BatchDeleteLikedPostCommentRepliesByComment :exec
+DELETE FROM liked_post_comment_replies
+WHERE post_comment_reply_id IN (
+    SELECT id FROM post_comment_replies
+    WHERE post_comment_id = @post_comment_id::bigint
+);
+
+-- name: GetPostCommentReplyImageUrlsByComment :many
+SELECT image_url FROM post_comment_replies
+WHERE post_comment_id = @post_comment_id::bigint;
+
+-- name: GetPostCommentReplyById :one
+SELECT pcr.*, pc.post_id, p.user_id AS post_user_id
+FROM post_comment_replies pcr
+LEFT JOIN post_comments pc ON pc.id = pcr.post_comment_id
+LEFT JOIN posts p ON p.id = pc.post_id
+WHERE pcr.id = $1;
+
+-- name: UpdatePostCommentReply :one
+UPDATE post_comment_replies
+SET content = @content::text,
+    updated_at = NOW()
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: DeletePostCommentReplyById :exec
+DELETE FROM post_comment_replies
+WHERE id = @id::bigint;
+
+-- name: BatchDeleteLikedPostCommentRepliesByReply :exec
+DELETE FROM liked_post_comment_replies
+WHERE post_comment_reply_id = @post_comment_reply_id::bigint;
+
+-- name: BatchDeleteLikedPostCommentsByPost :exec
+DELETE FROM liked_post_comments
+WHERE post_comment_id IN (
+    SELECT id FROM post_comments
+    WHERE post_id = @post_id::bigint
+);
+
+-- name: BatchDeleteLikedPostCommentRepliesByPost :exec
+DELETE FROM liked_post_comment_replies
+WHERE post_comment_reply_id IN (
+    SELECT pcr.id FROM post_comment_replies pcr
+    JOIN post_comments pc ON pc.id = pcr.post_comment_id
+    WHERE pc.post_id = @post_id::bigint
+);
+
+-- name: InsertPoll :one
+INSERT INTO polls (post_id, allow_multiple, ends_at, created_at)
+VALUES (@post_id::bigint, @allow_multiple::boolean, @ends_at::timestamp, NOW())
+RETURNING *;
+
+-- name: BatchInsertPollOptions :many
+INSERT INTO poll_options
+	(poll_id, option_text, position)
+SELECT @poll_id::bigint, UNNEST(@option_text::TEXT[]), UNNEST(@position::smallint[])
+RETURNING *;
+
+-- name: GetPollByPostForUpdate :one
+SELECT * FROM polls
+WHERE post_id = @post_id::bigint
+FOR UPDATE;
+
+-- name: ListPollOptionIdsByPoll :many
+SELECT id FROM poll_options
+WHERE poll_id = @poll_id::bigint;
+
+-- name: CountPollVotesByUser :one
+SELECT COUNT(*) AS count
+FROM poll_votes
+WHERE poll_id = @poll_id::bigint AND user_id = @user_id::bigint;
+
+-- name: BatchInsertPollVotes :exec
+INSERT INTO poll_votes
+	(poll_id, poll_option_id, user_id, created_at)
+SELECT @poll_id::bigint, UNNEST(@poll_option_ids::bigint[]), @user_id::bigint, NOW();
+
+-- name: IncrementPollOptionVoteCounts :exec
+UPDATE poll_options
+SET vote_count = vote_count + 1
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: IncrementPollVoterCount :exec
+UPDATE polls
+SET voter_count = voter_count + 1
+WHERE id = @id::bigint;
+
+-- name: ListPollsByPostIds :many
+SELECT * FROM polls
+WHERE post_id = ANY(@post_ids::bigint[]);
+
+-- name: ListPollOptionsByPostIds :many
+SELECT po.* FROM poll_options po
+JOIN polls p ON p.id = po.poll_id
+WHERE p.post_id = ANY(@post_ids::bigint[])
+ORDER BY po.poll_id, po.position;
+
+-- name: ListUserPollVotesByPostIds :many
+SELECT pv.poll_id, pv.poll_option_id
+FROM poll_votes pv
+JOIN polls p ON p.id = pv.poll_id
+WHERE pv.user_id = @user_id::bigint AND p.post_id = ANY(@post_ids::bigint[]);
+
+-- name: BatchDeletePollVotesByPost :exec
+DELETE FROM poll_votes
+WHERE poll_id IN (SELECT id FROM polls WHERE post_id = @post_id::bigint);
+
+-- name: BatchDeletePollOptionsByPost :exec
+DELETE FROM poll_options
+WHERE poll_id IN (SELECT id FROM polls WHERE post_id = @post_id::bigint);
+
+-- name: DeletePollByPost :exec
+DELETE FROM polls
+WHERE post_id = @post_id::bigint;
+
+-- name: CountOpenPostReporters :one
+SELECT COUNT(*) AS count
+FROM reports
+WHERE target_type = 'post' AND target_id = @post_id::bigint AND resolved_at IS NULL;
+
+-- name: InsertHiddenPost :one
+INSERT INTO hidden_posts (post_id, reason, created_at)
+VALUES (@post_id::bigint, @reason::text, NOW())
+ON CONFLICT (post_id, reason) DO NOTHING
+RETURNING *;
+
+-- name: DeleteHiddenPostByPost :exec
+DELETE FROM hidden_posts
+WHERE post_id = @post_id::bigint;
+
+-- name: ResolveReportsByComment :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND ((target_type = 'post_comment' AND target_id = @post_comment_id::bigint)
+    OR (target_type = 'post_comment_reply' AND target_id IN (SELECT id FROM post_comment_replies WHERE post_comment_id = @post_comment_id::bigint)));
+
+-- name: ResolveReportsByReply :exec
+UPDATE reports
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND target_type = 'post_comment_reply' AND target_id = @post_comment_reply_id::bigint;
+
+-- name: CountUserBlocksForPost :one
+SELECT COUNT(*) AS count
+FROM posts p
+JOIN user_blocks ub ON (ub.user_id = @user_id::bigint AND ub.blocked_user_id = p.user_id)
+    OR (ub.user_id = p.user_id AND ub.blocked_user_id = @user_id::bigint)
+WHERE p.id = @post_id::bigint;
+
+-- name: CountUserBlocksForPostComment :one
+SELECT COUNT(*) AS count
+FROM post_comments pc
+JOIN posts p ON pc.post_id = p.id
+JOIN user_blocks ub ON (ub.user_id = @user_id::bigint AND ub.blocked_user_id IN (pc.user_id, p.user_id))
+    OR (ub.user_id IN (pc.user_id, p.user_id) AND ub.blocked_user_id = @user_id::bigint)
+WHERE pc.id = @post_comment_id::bigint;
+
+-- name: CountUserBlocksForPostCommentReply :one
+SELECT COUNT(*) AS count
+FROM post_comment_replies pcr
+JOIN post_comments pc ON pcr.post_comment_id = pc.id
+JOIN posts p ON pc.post_id = p.id
+JOIN user_blocks ub ON (ub.user_id = @user_id::bigint AND ub.blocked_user_id IN (pcr.user_id, p.user_id))
+    OR (ub.user_id IN (pcr.user_id, p.user_id) AND ub.blocked_user_id = @user_id::bigint)
+WHERE pcr.id = @post_comment_reply_id::bigint;
+
+-- name: ListUserContentCreatedAtSince :many
+SELECT created_at FROM posts WHERE user_id = @user_id::bigint AND created_at >= @since::timestamp
+UNION ALL
+SELECT created_at FROM post_comments WHERE user_id = @user_id::bigint AND created_at >= @since::timestamp
+UNION ALL
+SELECT created_at FROM post_comment_replies WHERE user_id = @user_id::bigint AND created_at >= @since::timestamp;
+
+-- name: InsertContentFlag :exec
+INSERT INTO content_flags (user_id, target_type, target_id, verdict, rules, content, created_at)
+VALUES (@user_id::bigint, @target_type::text, NULLIF(@target_id::bigint, 0), @verdict::text, @rules::text[], @content::text, NOW());
+
+-- name: ResolveContentFlagsByPost :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND (
+    (target_type = 'post' AND target_id = @post_id::bigint)
+    OR (target_type = 'post_comment' AND target_id IN (SELECT id FROM post_comments WHERE post_id = @post_id::bigint))
+    OR (target_type = 'post_comment_reply' AND target_id IN (
+        SELECT pcr.id
+        FROM post_comment_replies pcr
+        JOIN post_comments pc ON pcr.post_comment_id = pc.id
+        WHERE pc.post_id = @post_id::bigint
+    ))
+);
+
+-- name: ResolveContentFlagsByComment :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND (
+    (target_type = 'post_comment' AND target_id = @post_comment_id::bigint)
+    OR (target_type = 'post_comment_reply' AND target_id IN (SELECT id FROM post_comment_replies WHERE post_comment_id = @post_comment_id::bigint))
+);
+
+-- name: ResolveContentFlagsByReply :exec
+UPDATE content_flags
+SET resolved_at = NOW()
+WHERE resolved_at IS NULL AND target_type = 'post_comment_reply' AND target_id = @post_comment_reply_id::bigint;
+
+
+-- name: BatchInsertPostHashtags :exec
+INSERT INTO post_hashtags (post_id, hashtag)
+SELECT @post_id::bigint, UNNEST(@hashtags::varchar(50)[])
+ON CONFLICT DO NOTHING;
+
+-- name: BatchDeletePostHashtagsByPost :exec
+DELETE FROM post_hashtags
+WHERE post_id = @post_id::bigint;
+
+-- name: SearchPosts :many
+-- Visibility, blocks, reports and mutes are filtered exactly like ListNewestPosts
+SELECT p.id, p.user_id, p.content, p.like_count, p.comment_count, p.repost_count, p.created_at, p.updated_at, p.title, p.visibility, 
+	u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+	ARRAY_AGG(pi.url) FILTER (WHERE pi.url IS NOT NULL) AS image_urls,
+	COUNT(p.id) OVER () AS total_rows,
+    CASE 
+    	WHEN lp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS liked,
+	CASE 
+    	WHEN rpp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS repost,
+	CASE 
+    	WHEN bp.user_id IS NOT NULL THEN TRUE 
+    	ELSE FALSE 
+  	END AS bookmarked,
+	-- Titles are stored as plain text, so they are escaped before being highlighted
+	TS_HEADLINE('english', REPLACE(REPLACE(REPLACE(p.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), sq.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+	TS_HEADLINE('english', REGEXP_REPLACE(COALESCE(p.content, ''), '<[^>]*>', ' ', 'g'), sq.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" ... "')::text AS snippet
+FROM posts p
+CROSS JOIN (
+	SELECT WEBSEARCH_TO_TSQUERY('english', @query::text) || WEBSEARCH_TO_TSQUERY('indonesian', @query::text)
+		|| (CASE WHEN @prefix_query::text = '' THEN ''::tsquery ELSE TO_TSQUERY('simple', @prefix_query::text) END) AS query
+) sq
+LEFT JOIN users u ON p.user_id = u.id
+LEFT JOIN reports rp ON rp.target_type = 'post' AND p.id = rp.target_id AND rp.user_id = @user_id::bigint
+LEFT JOIN liked_posts lp ON p.id = lp.post_id AND lp.user_id = @user_id::bigint
+LEFT JOIN reposted_posts rpp ON p.id = rpp.post_id AND rpp.user_id = @user_id::bigint
+LEFT JOIN bookmarked_posts bp ON p.id = bp.post_id AND bp.user_id = @user_id::bigint
+LEFT JOIN post_images pi ON p.id = pi.post_id
+WHERE p.search_vector @@ sq.query
+	AND (@author_id::bigint = 0 OR p.user_id = @author_id::bigint)
+	AND (sqlc.narg('from_date')::timestamp IS NULL OR p.created_at >= sqlc.narg('from_date')::timestamp)
+	AND (sqlc.narg('to_date')::timestamp IS NULL OR p.created_at < sqlc.narg('to_date')::timestamp)
+	AND (@hashtag::text = '' OR EXISTS (SELECT 1 FROM post_hashtags ph WHERE ph.post_id = p.id AND ph.hashtag = @hashtag::text))
+	AND rp.target_id IS NULL AND p.visibility = 'public' AND visible_feed_post_for(@user_id::bigint, p.id, p.user_id)
+GROUP BY 
+    p.id, u.id, lp.user_id, rpp.user_id, bp.user_id, sq.query
+ORDER BY TS_RANK_CD(p.search_vector, sq.query) DESC, p.created_at DESC
+OFFSET $1
+LIMIT $2;
+
+-- name: UpsertPostImpressions :exec
+-- Own and missing posts are skipped
+INSERT INTO post_impressions (user_id, post_id, view_count, first_seen_at, last_seen_at)
+SELECT @user_id::bigint, p.id, 1, NOW(), NOW()
+FROM posts p
+WHERE p.id = ANY(@post_ids::bigint[]) AND p.user_id <> @user_id::bigint
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET view_count = post_impressions.view_count + 1,
+    last_seen_at = NOW();
+
+-- name: GetPostViewCounts :one
+SELECT COALESCE(SUM(view_count), 0)::bigint AS view_count, COUNT(*) AS viewer_count
+FROM post_impressions
+WHERE post_id = @post_id::bigint;
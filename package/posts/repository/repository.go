@@ -6,23 +6,41 @@ import (
 	"errors"
 	"fmt"
 	db "profiln-be/db/sqlc"
+	"profiln-be/libs"
 	"profiln-be/model"
 
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 type IPostsRepository interface {
-	InsertReportedPost(userId int64, props *model.ReportPost) (db.ReportedPost, error)
+	InsertReport(userId int64, props *model.Report) (db.Report, error)
+	GetUserById(userId int64) (db.GetUserByIdRow, error)
+	IsBlockedBetween(userId, targetUserId int64) (bool, error)
+	IsBlockedFromPost(userId, postId int64) (bool, error)
+	IsBlockedFromPostComment(userId, postCommentId int64) (bool, error)
+	IsBlockedFromPostCommentReply(userId, postCommentReplyId int64) (bool, error)
+	CountOpenPostReporters(postId int64) (int64, error)
+	HidePostByReports(postId, reporterCount int64) error
+	ListUserActivitySince(userId int64, since time.Time) ([]time.Time, error)
+	FlagContent(userId int64, targetType string, targetId int64, content string, result model.ContentPolicyResult) error
 	GetDetailPost(postId, userId int64) (model.Post, error)
-	GetPostComments(postId int64, offset, limit int32) ([]db.GetPostCommentsRow, int64, error)
-	GetPostCommentReplies(postId, postCommentId int64, offset, limit int32) ([]db.GetPostCommentRepliesRow, int64, error)
+	GetPostComments(userId, postId int64, offset, limit int32) ([]db.GetPostCommentsRow, int64, error)
+	GetPostCommentsByCursor(userId, postId int64, cursor *model.PageCursor, limit int32) ([]db.GetPostCommentsByCursorRow, *model.PageCursor, error)
+	GetPostCommentReplies(userId, postId, postCommentId int64, offset, limit int32) ([]db.GetPostCommentRepliesRow, int64, error)
 	LikePost(userId, postId int64) (*db.UpdatePostLikeCountRow, error)
 	UnlikePost(userId, postId int64) (*db.UpdatePostLikeCountRow, error)
+	ReactPost(userId, postId int64, reactionType string) (*db.UpdatePostLikeCountRow, error)
+	ListPostReactions(postId int64, reactionType string, offset, limit int32) ([]model.PostReaction, int64, error)
 	ListNewestPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error)
 	ListLikedPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error)
 	ListRepostedPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error)
+	SearchPosts(userId int64, filter model.PostSearchFilter, offset, limit int32) ([]model.PostSearchResult, int64, error)
+	SearchPostsContext(ctx context.Context, userId int64, filter model.PostSearchFilter, offset, limit int32) ([]model.PostSearchResult, int64, error)
 	InsertPost(props *model.CreatePostRequest) (model.Post, error)
+	FanOutPost(postId, authorId int64, fanOutMaxFollowers int32) error
 	UpdatePostById(props *model.UpdatePostRequest) error
 	GetPostById(postId int64) (model.Post, error)
 	GetPostImagesUrl(postId int64) ([]string, error)
@@ -33,12 +51,41 @@ type IPostsRepository interface {
 	CountPostImages(postId int64) (int64, error)
 	InsertPostComment(props *model.AddPostCommentReq) (model.PostComment, error)
 	LikePostComment(userId, postCommentId int64) (*db.UpdatePostCommentsLikeCountRow, error)
+	ReactPostComment(userId, postCommentId int64, reactionType string) (*db.UpdatePostCommentsLikeCountRow, error)
 	UnlikePostComment(userId, postCommentId int64) (*db.UpdatePostCommentsLikeCountRow, error)
 	InsertPostCommentReply(props *model.AddPostCommentReplyReq) (model.PostCommentReply, error)
 	LikePostCommentReply(userId, postCommentReplyId int64) (*db.UpdatePostCommentRepliesLikeCountRow, error)
+	ReactPostCommentReply(userId, postCommentReplyId int64, reactionType string) (*db.UpdatePostCommentRepliesLikeCountRow, error)
 	UnlikePostCommentReply(userId, postCommentReplyId int64) (*db.UpdatePostCommentRepliesLikeCountRow, error)
+	GetPostCommentById(postCommentId int64) (db.GetPostCommentByIdRow, error)
+	UpdatePostComment(props *model.UpdatePostCommentReq) (model.PostComment, error)
+	DeletePostComment(postId, postCommentId int64) error
+	GetPostCommentReplyImageUrls(postCommentId int64) ([]string, error)
+	GetPostCommentReplyById(postCommentReplyId int64) (db.GetPostCommentReplyByIdRow, error)
+	UpdatePostCommentReply(props *model.UpdatePostCommentReplyReq) (model.PostCommentReply, error)
+	DeletePostCommentReply(postCommentId, postCommentReplyId int64) error
+	VotePoll(userId, postId int64, optionIds []int64) error
+	GetLinkPreviewByUrl(url string) (db.LinkPreview, error)
+	SavePostLinkPreview(postId int64, props *model.LinkPreview) error
+	AttachPostLinkPreview(postId, linkPreviewId int64) error
+	DeletePostLinkPreview(postId int64) error
+	BookmarkPost(userId, postId, bookmarkFolderId int64) error
+	UnbookmarkPost(userId, postId int64) error
+	ListBookmarkedPosts(userId, bookmarkFolderId int64, offset, limit int32) ([]model.Post, int64, error)
+	InsertBookmarkFolder(userId int64, name string) (model.BookmarkFolder, error)
+	GetBookmarkFolderById(userId, bookmarkFolderId int64) (model.BookmarkFolder, error)
+	ListBookmarkFolders(userId int64) ([]model.BookmarkFolder, error)
+	DeleteBookmarkFolder(userId, bookmarkFolderId int64) error
+	InsertPostImpressions(userId int64, postIds []int64) error
+	GetPostViewCounts(postId int64) (model.PostViewCounts, error)
 }
 
+var (
+	ErrPollClosed        = errors.New("poll has ended")
+	ErrPollAlreadyVoted  = errors.New("user has already voted")
+	ErrPollInvalidOption = errors.New("invalid poll option")
+)
+
 type PostsRepository struct {
 	dbConn *sql.DB
 	query  *db.Queries
@@ -51,20 +98,192 @@ func NewPostsRepository(dbConn *sql.DB) IPostsRepository {
 	}
 }
 
-func (r *PostsRepository) InsertReportedPost(userId int64, props *model.ReportPost) (db.ReportedPost, error) {
-	arg := db.InsertReportedPostParams{
-		UserID:  userId,
-		PostID:  props.PostId,
-		Reason:  props.Reason,
-		Message: props.Message,
+// InsertReport returns sql.ErrNoRows when the user already reported the target
+func (r *PostsRepository) InsertReport(userId int64, props *model.Report) (db.Report, error) {
+	arg := db.InsertReportParams{
+		UserID:     userId,
+		TargetType: props.TargetType,
+		TargetID:   props.TargetId,
+		Reasons:    props.Reason,
+		Message:    props.Message,
+	}
+
+	report, err := r.query.InsertReport(context.Background(), arg)
+	if err != nil {
+		return db.Report{}, err
+	}
+
+	return report, nil
+}
+
+func (r *PostsRepository) GetUserById(userId int64) (db.GetUserByIdRow, error) {
+	return r.query.GetUserById(context.Background(), userId)
+}
+
+func (r *PostsRepository) IsBlockedBetween(userId, targetUserId int64) (bool, error) {
+	count, err := r.query.CountUserBlocksBetween(context.Background(), db.CountUserBlocksBetweenParams{
+		UserID:       userId,
+		TargetUserID: targetUserId,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not count user blocks (target user id %d): %w", targetUserId, err)
+	}
+
+	return count > 0, nil
+}
+
+// IsBlockedFromPost reports whether the user and the post author blocked each other
+func (r *PostsRepository) IsBlockedFromPost(userId, postId int64) (bool, error) {
+	count, err := r.query.CountUserBlocksForPost(context.Background(), db.CountUserBlocksForPostParams{
+		UserID: userId,
+		PostID: postId,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not count user blocks (post id %d): %w", postId, err)
+	}
+
+	return count > 0, nil
+}
+
+// IsBlockedFromPostComment also checks the author of the post the comment belongs to
+func (r *PostsRepository) IsBlockedFromPostComment(userId, postCommentId int64) (bool, error) {
+	count, err := r.query.CountUserBlocksForPostComment(context.Background(), db.CountUserBlocksForPostCommentParams{
+		UserID:        userId,
+		PostCommentID: postCommentId,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not count user blocks (post comment id %d): %w", postCommentId, err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *PostsRepository) IsBlockedFromPostCommentReply(userId, postCommentReplyId int64) (bool, error) {
+	count, err := r.query.CountUserBlocksForPostCommentReply(context.Background(), db.CountUserBlocksForPostCommentReplyParams{
+		UserID:             userId,
+		PostCommentReplyID: postCommentReplyId,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not count user blocks (post comment reply id %d): %w", postCommentReplyId, err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *PostsRepository) CountOpenPostReporters(postId int64) (int64, error) {
+	return r.query.CountOpenPostReporters(context.Background(), postId)
+}
+
+// HidePostByReports hides the post once enough users reported it and records it in the moderation trail
+func (r *PostsRepository) HidePostByReports(postId, reporterCount int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	_, err = qtx.InsertHiddenPost(ctx, db.InsertHiddenPostParams{
+		PostID: postId,
+		Reason: model.HiddenPostReasonReports,
+	})
+
+	// The post is already hidden
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not insert hidden post: %w", err)
+	}
+
+	err = qtx.InsertModerationAction(ctx, db.InsertModerationActionParams{
+		PostID: postId,
+		Action: model.ModerationActionAutoHide,
+		Note:   fmt.Sprintf("reported by %d users", reporterCount),
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert moderation action: %w", err)
 	}
 
-	reportedPost, err := r.query.InsertReportedPost(context.Background(), arg)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListUserActivitySince returns the creation times of the user's posts, comments and replies
+func (r *PostsRepository) ListUserActivitySince(userId int64, since time.Time) ([]time.Time, error) {
+	data, err := r.query.ListUserContentCreatedAtSince(context.Background(), db.ListUserContentCreatedAtSinceParams{
+		UserID: userId,
+		Since:  since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]time.Time, 0, len(data))
+	for _, createdAt := range data {
+		if createdAt.Valid {
+			activity = append(activity, createdAt.Time)
+		}
+	}
+
+	return activity, nil
+}
+
+// FlagContent records the content policy verdict for moderators, held posts are also hidden
+// from everyone but their author. Rejected content was never stored so targetId is 0
+func (r *PostsRepository) FlagContent(userId int64, targetType string, targetId int64, content string, result model.ContentPolicyResult) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	err = qtx.InsertContentFlag(ctx, db.InsertContentFlagParams{
+		UserID:     userId,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Verdict:    result.Verdict,
+		Rules:      result.Rules,
+		Content:    content,
+	})
 	if err != nil {
-		return db.ReportedPost{}, err
+		return fmt.Errorf("could not insert content flag: %w", err)
+	}
+
+	if result.Verdict == model.ContentVerdictHold && targetType == model.ReportTargetPost {
+		_, err = qtx.InsertHiddenPost(ctx, db.InsertHiddenPostParams{
+			PostID: targetId,
+			Reason: model.HiddenPostReasonContentPolicy,
+		})
+
+		// Already held by the content policy, the flag is still recorded
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("could not insert hidden post: %w", err)
+		}
+
+		err = qtx.InsertModerationAction(ctx, db.InsertModerationActionParams{
+			PostID:       targetId,
+			TargetUserID: userId,
+			Action:       model.ModerationActionAutoHold,
+			Note:         strings.Join(result.Rules, ", "),
+		})
+		if err != nil {
+			return fmt.Errorf("could not insert moderation action: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
 	}
 
-	return reportedPost, nil
+	return nil
 }
 
 func (r *PostsRepository) GetDetailPost(postId, userId int64) (model.Post, error) {
@@ -101,17 +320,32 @@ func (r *PostsRepository) GetDetailPost(postId, userId int64) (model.Post, error
 		RepostCount:  data.RepostCount.Int32,
 		IsRepost:     data.Repost,
 		IsLiked:      data.Liked,
+		IsBookmarked: data.Bookmarked,
 		UpdatedAt:    data.UpdatedAt.Time,
 	}
 
-	return post, nil
+	posts := []model.Post{post}
+	if err := AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return model.Post{}, err
+	}
+
+	return posts[0], nil
 }
 
-func (r *PostsRepository) GetPostComments(postId int64, offset, limit int32) ([]db.GetPostCommentsRow, int64, error) {
+func (r *PostsRepository) GetPostComments(userId, postId int64, offset, limit int32) ([]db.GetPostCommentsRow, int64, error) {
 	arg := db.GetPostCommentsParams{
 		PostID: sql.NullInt64{Int64: postId, Valid: true},
 		Offset: offset,
 		Limit:  limit,
+		UserID: userId,
 	}
 
 	data, err := r.query.GetPostComments(context.Background(), arg)
@@ -127,12 +361,41 @@ func (r *PostsRepository) GetPostComments(postId int64, offset, limit int32) ([]
 	return data, count, nil
 }
 
-func (r *PostsRepository) GetPostCommentReplies(postId, postCommentId int64, offset, limit int32) ([]db.GetPostCommentRepliesRow, int64, error) {
+func (r *PostsRepository) GetPostCommentsByCursor(userId, postId int64, cursor *model.PageCursor, limit int32) ([]db.GetPostCommentsByCursorRow, *model.PageCursor, error) {
+	arg := db.GetPostCommentsByCursorParams{
+		PostID: sql.NullInt64{Int64: postId, Valid: true},
+		UserID: userId,
+		Limit:  limit + 1,
+	}
+
+	if cursor != nil && cursor.CreatedAt != nil {
+		arg.CursorID = cursor.ID
+		arg.CursorCreatedAt = *cursor.CreatedAt
+	}
+
+	data, err := r.query.GetPostCommentsByCursor(context.Background(), arg)
+	if err != nil {
+		return []db.GetPostCommentsByCursorRow{}, nil, err
+	}
+
+	// the extra row only tells whether there is a next page
+	var next *model.PageCursor
+	if len(data) > int(limit) {
+		data = data[:limit]
+		createdAt := data[limit-1].CreatedAt.Time
+		next = &model.PageCursor{CreatedAt: &createdAt, ID: data[limit-1].ID}
+	}
+
+	return data, next, nil
+}
+
+func (r *PostsRepository) GetPostCommentReplies(userId, postId, postCommentId int64, offset, limit int32) ([]db.GetPostCommentRepliesRow, int64, error) {
 	arg := db.GetPostCommentRepliesParams{
 		PostID:        sql.NullInt64{Int64: postId, Valid: true},
 		PostCommentID: sql.NullInt64{Int64: postCommentId, Valid: true},
 		Offset:        offset,
 		Limit:         limit,
+		UserID:        userId,
 	}
 
 	data, err := r.query.GetPostCommentReplies(context.Background(), arg)
@@ -149,6 +412,10 @@ func (r *PostsRepository) GetPostCommentReplies(postId, postCommentId int64, off
 }
 
 func (r *PostsRepository) LikePost(userId, postId int64) (*db.UpdatePostLikeCountRow, error) {
+	return r.ReactPost(userId, postId, model.ReactionLike)
+}
+
+func (r *PostsRepository) UnlikePost(userId, postId int64) (*db.UpdatePostLikeCountRow, error) {
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
 	if err != nil {
@@ -165,54 +432,23 @@ func (r *PostsRepository) LikePost(userId, postId int64) (*db.UpdatePostLikeCoun
 		return nil, fmt.Errorf("could not lock post for update: %w", err)
 	}
 
-	post, err := qtx.UpdatePostLikeCount(ctx, db.UpdatePostLikeCountParams{
-		ID:    postId,
-		Value: 1,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("could not update like count: %w", err)
-	}
-
-	_, err = qtx.InsertLikedPost(ctx, db.InsertLikedPostParams{
+	likedPost, err := qtx.DeleteLikedPost(ctx, db.DeleteLikedPostParams{
 		UserID: userId,
 		PostID: postId,
 	})
 
 	if err != nil {
+		// Nothing to remove, return the current count
 		if errors.Is(err, sql.ErrNoRows) {
-			post = db.UpdatePostLikeCountRow{
-				ID:        post.ID,
-				LikeCount: sql.NullInt32{Int32: post.LikeCount.Int32 - 1, Valid: true},
+			current, err := qtx.GetPostLikeCount(ctx, postId)
+			if err != nil {
+				return nil, fmt.Errorf("could not get like count: %w", err)
 			}
 
-			return &post, nil
+			return &db.UpdatePostLikeCountRow{ID: current.ID, LikeCount: current.LikeCount}, nil
 		}
 
-		return nil, fmt.Errorf("could not insert liked post: %w", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("could not commit transaction: %w", err)
-	}
-
-	return &post, nil
-}
-
-func (r *PostsRepository) UnlikePost(userId, postId int64) (*db.UpdatePostLikeCountRow, error) {
-	ctx := context.Background()
-	tx, err := r.dbConn.Begin()
-	if err != nil {
-		return nil, fmt.Errorf("could not begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	qtx := r.query.WithTx(tx)
-
-	_, err = qtx.LockPostForUpdate(ctx, postId)
-	if err != nil && err == sql.ErrNoRows {
-		return nil, err
-	} else if err != nil {
-		return nil, fmt.Errorf("could not lock post for update: %w", err)
+		return nil, fmt.Errorf("could not delete liked post: %w", err)
 	}
 
 	post, err := qtx.UpdatePostLikeCount(ctx, db.UpdatePostLikeCountParams{
@@ -223,26 +459,13 @@ func (r *PostsRepository) UnlikePost(userId, postId int64) (*db.UpdatePostLikeCo
 		return nil, fmt.Errorf("could not update like count: %w", err)
 	}
 
-	_, err = qtx.DeleteLikedPost(ctx, db.DeleteLikedPostParams{
-		UserID: userId,
-		PostID: postId,
+	err = qtx.UpdatePostReactionCount(ctx, db.UpdatePostReactionCountParams{
+		PostID:       postId,
+		ReactionType: likedPost.ReactionType,
+		Value:        -1,
 	})
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			if post.LikeCount.Int32 > 0 {
-				post.LikeCount.Int32 = post.LikeCount.Int32 + 1
-			}
-
-			post = db.UpdatePostLikeCountRow{
-				ID:        post.ID,
-				LikeCount: sql.NullInt32{Int32: post.LikeCount.Int32, Valid: true},
-			}
-
-			return &post, nil
-		}
-
-		return nil, fmt.Errorf("could not delete liked post: %w", err)
+		return nil, fmt.Errorf("could not update reaction count: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -298,24 +521,56 @@ func (r *PostsRepository) ListNewestPostsByTargetUser(userId, targetUserId int64
 			RepostCount:  v.RepostCount.Int32,
 			IsRepost:     v.Repost,
 			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
 			UpdatedAt:    v.UpdatedAt.Time,
 		}
 	}
 
+	if err := AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
 	return posts, count, nil
 }
 
-func (r *PostsRepository) ListLikedPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error) {
-	arg := db.ListLikedPostsByTargetUserParams{
-		UserID:       userId,
-		Offset:       offset,
-		Limit:        limit,
-		TargetUserID: targetUserId,
+func (r *PostsRepository) SearchPosts(userId int64, filter model.PostSearchFilter, offset, limit int32) ([]model.PostSearchResult, int64, error) {
+	return r.SearchPostsContext(context.Background(), userId, filter, offset, limit)
+}
+
+// SearchPostsContext stops the search query when ctx is done
+func (r *PostsRepository) SearchPostsContext(ctx context.Context, userId int64, filter model.PostSearchFilter, offset, limit int32) ([]model.PostSearchResult, int64, error) {
+	arg := db.SearchPostsParams{
+		Offset:   offset,
+		Limit:    limit,
+		Query:    filter.Query,
+		UserID:   userId,
+		AuthorID: filter.AuthorId,
+		Hashtag:  filter.Hashtag,
 	}
 
-	data, err := r.query.ListLikedPostsByTargetUser(context.Background(), arg)
+	if filter.Prefix {
+		arg.PrefixQuery = libs.PrefixTsQuery(filter.Query)
+	}
+
+	if filter.From != nil {
+		arg.FromDate = sql.NullTime{Time: filter.From.UTC(), Valid: true}
+	}
+
+	if filter.To != nil {
+		arg.ToDate = sql.NullTime{Time: filter.To.UTC(), Valid: true}
+	}
+
+	data, err := r.query.SearchPosts(ctx, arg)
 	if err != nil {
-		return []model.Post{}, 0, err
+		return []model.PostSearchResult{}, 0, err
 	}
 
 	// get total rows for pagination
@@ -351,22 +606,44 @@ func (r *PostsRepository) ListLikedPostsByTargetUser(userId, targetUserId int64,
 			RepostCount:  v.RepostCount.Int32,
 			IsRepost:     v.Repost,
 			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
 			UpdatedAt:    v.UpdatedAt.Time,
 		}
 	}
 
-	return posts, count, nil
+	if err := AttachLinkPreviews(ctx, r.query, posts); err != nil {
+		return []model.PostSearchResult{}, 0, err
+	}
+
+	if err := AttachReactions(ctx, r.query, userId, posts); err != nil {
+		return []model.PostSearchResult{}, 0, err
+	}
+
+	if err := AttachPolls(ctx, r.query, userId, posts); err != nil {
+		return []model.PostSearchResult{}, 0, err
+	}
+
+	results := make([]model.PostSearchResult, len(data))
+	for i, v := range data {
+		results[i] = model.PostSearchResult{
+			Post:           posts[i],
+			TitleHighlight: v.TitleHighlight,
+			Snippet:        v.Snippet,
+		}
+	}
+
+	return results, count, nil
 }
 
-func (r *PostsRepository) ListRepostedPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error) {
-	arg := db.ListRepostedPostsByTargetUserParams{
+func (r *PostsRepository) ListLikedPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error) {
+	arg := db.ListLikedPostsByTargetUserParams{
 		UserID:       userId,
 		Offset:       offset,
 		Limit:        limit,
 		TargetUserID: targetUserId,
 	}
 
-	data, err := r.query.ListRepostedPostsByTargetUser(context.Background(), arg)
+	data, err := r.query.ListLikedPostsByTargetUser(context.Background(), arg)
 	if err != nil {
 		return []model.Post{}, 0, err
 	}
@@ -404,70 +681,254 @@ func (r *PostsRepository) ListRepostedPostsByTargetUser(userId, targetUserId int
 			RepostCount:  v.RepostCount.Int32,
 			IsRepost:     v.Repost,
 			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
 			UpdatedAt:    v.UpdatedAt.Time,
 		}
 	}
 
-	return posts, count, nil
-}
-
-func (r *PostsRepository) InsertPost(props *model.CreatePostRequest) (model.Post, error) {
-	insertPostArg := db.InsertPostParams{
-		UserID:     sql.NullInt64{Int64: props.UserId, Valid: true},
-		Title:      props.Title,
-		Content:    sql.NullString{String: props.Content, Valid: true},
-		Visibility: props.Visibility,
+	if err := AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, 0, err
 	}
 
-	createdPost, err := r.query.InsertPost(context.Background(), insertPostArg)
-	if err != nil {
-		return model.Post{}, err
+	if err := AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
 	}
 
-	data := model.Post{
-		ID: createdPost.ID,
-		User: model.User{
-			ID: createdPost.UserID.Int64,
-		},
-		Title:        createdPost.Title,
-		Content:      createdPost.Content.String,
-		LikeCount:    createdPost.LikeCount.Int32,
-		CommentCount: createdPost.CommentCount.Int32,
-		RepostCount:  createdPost.RepostCount.Int32,
-		IsRepost:     false,
-		IsLiked:      false,
-		UpdatedAt:    createdPost.UpdatedAt.Time,
+	if err := AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
 	}
 
-	return data, nil
+	return posts, count, nil
 }
 
-func (r *PostsRepository) UpdatePostById(props *model.UpdatePostRequest) error {
-	// ctx := context.Background()
-	// tx, err := r.dbConn.Begin()
-	// if err != nil {
-	// 	return fmt.Errorf("could not begin transaction: %w", err)
-	// }
-	// defer tx.Rollback()
-
-	// qtx := r.query.WithTx(tx)
-
-	// err = qtx.BatchDeletePostImagesByPost(ctx, props.ID)
-	// if err != nil {
-	// 	return fmt.Errorf("could not batch delete post images: %w", err)
-	// }
-
-	updatePostArg := db.UpdatePostParams{
-		ID:         props.ID,
-		UserID:     props.UserId,
+func (r *PostsRepository) ListRepostedPostsByTargetUser(userId, targetUserId int64, offset, limit int32) ([]model.Post, int64, error) {
+	arg := db.ListRepostedPostsByTargetUserParams{
+		UserID:       userId,
+		Offset:       offset,
+		Limit:        limit,
+		TargetUserID: targetUserId,
+	}
+
+	data, err := r.query.ListRepostedPostsByTargetUser(context.Background(), arg)
+	if err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	// get total rows for pagination
+	var count int64
+	if len(data) > 0 {
+		count = data[0].TotalRows
+	}
+
+	posts := make([]model.Post, len(data))
+	for i, v := range data {
+		var imageUrls []string
+
+		// Convert to array
+		if v.ImageUrls != nil {
+			imageUrlsString := strings.Trim(string(v.ImageUrls.([]uint8)), "{}")
+			imageUrls = strings.Split(imageUrlsString, ",")
+		}
+
+		posts[i] = model.Post{
+			ID: v.ID,
+			User: model.User{
+				ID:         v.UserID.Int64,
+				AvatarUrl:  v.AvatarUrl.String,
+				Fullname:   v.FullName.String,
+				Bio:        v.Bio.String,
+				OpenToWork: v.OpenToWork.Bool,
+			},
+			Title:        v.Title,
+			Content:      v.Content.String,
+			ImageUrls:    imageUrls,
+			LikeCount:    v.LikeCount.Int32,
+			CommentCount: v.CommentCount.Int32,
+			RepostCount:  v.RepostCount.Int32,
+			IsRepost:     v.Repost,
+			IsLiked:      v.Liked,
+			IsBookmarked: v.Bookmarked,
+			UpdatedAt:    v.UpdatedAt.Time,
+		}
+	}
+
+	if err := AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	return posts, count, nil
+}
+
+func (r *PostsRepository) InsertPost(props *model.CreatePostRequest) (model.Post, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return model.Post{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	insertPostArg := db.InsertPostParams{
+		UserID:     sql.NullInt64{Int64: props.UserId, Valid: true},
+		Title:      props.Title,
+		Content:    sql.NullString{String: props.Content, Valid: true},
+		Visibility: props.Visibility,
+	}
+
+	createdPost, err := qtx.InsertPost(ctx, insertPostArg)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("could not insert post: %w", err)
+	}
+
+	if err := insertPostHashtags(ctx, qtx, createdPost.ID, props.Content); err != nil {
+		return model.Post{}, err
+	}
+
+	data := model.Post{
+		ID: createdPost.ID,
+		User: model.User{
+			ID: createdPost.UserID.Int64,
+		},
+		Title:        createdPost.Title,
+		Content:      createdPost.Content.String,
+		LikeCount:    createdPost.LikeCount.Int32,
+		CommentCount: createdPost.CommentCount.Int32,
+		RepostCount:  createdPost.RepostCount.Int32,
+		IsRepost:     false,
+		IsLiked:      false,
+		UpdatedAt:    createdPost.UpdatedAt.Time,
+	}
+
+	if props.Poll != nil {
+		createdPoll, err := qtx.InsertPoll(ctx, db.InsertPollParams{
+			PostID:        createdPost.ID,
+			AllowMultiple: props.Poll.AllowMultiple,
+			EndsAt:        props.Poll.EndsAt.UTC(),
+		})
+		if err != nil {
+			return model.Post{}, fmt.Errorf("could not insert poll: %w", err)
+		}
+
+		positions := make([]int16, len(props.Poll.Options))
+		for i := range props.Poll.Options {
+			positions[i] = int16(i)
+		}
+
+		createdOptions, err := qtx.BatchInsertPollOptions(ctx, db.BatchInsertPollOptionsParams{
+			PollID:     createdPoll.ID,
+			OptionText: props.Poll.Options,
+			Position:   positions,
+		})
+		if err != nil {
+			return model.Post{}, fmt.Errorf("could not batch insert poll options: %w", err)
+		}
+
+		options := make([]model.PollOption, len(createdOptions))
+		for i, v := range createdOptions {
+			options[i] = model.PollOption{
+				ID:   v.ID,
+				Text: v.OptionText,
+			}
+		}
+
+		data.Poll = &model.Poll{
+			ID:            createdPoll.ID,
+			AllowMultiple: createdPoll.AllowMultiple,
+			EndsAt:        createdPoll.EndsAt,
+			Options:       options,
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Post{}, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// FanOutPost copies the post to the followers' timelines, unless the author has at least
+// fanOutMaxFollowers followers, then the post is merged into their timelines on read
+func (r *PostsRepository) FanOutPost(postId, authorId int64, fanOutMaxFollowers int32) error {
+	ctx := context.Background()
+
+	followersCount, err := r.query.GetUserFollowersCount(ctx, authorId)
+	if err != nil {
+		return fmt.Errorf("could not get user followers count: %w", err)
+	}
+
+	if followersCount >= fanOutMaxFollowers {
+		if err := r.query.InsertTimelinePullPost(ctx, postId); err != nil {
+			return fmt.Errorf("could not insert timeline pull post: %w", err)
+		}
+		return nil
+	}
+
+	if err := r.query.FanOutPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not fan out post: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) UpdatePostById(props *model.UpdatePostRequest) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	updatePostArg := db.UpdatePostParams{
+		ID:         props.ID,
+		UserID:     props.UserId,
 		Title:      props.Title,
 		Content:    props.Content,
 		Visibility: props.Visibility,
 	}
-	if err := r.query.UpdatePost(context.Background(), updatePostArg); err != nil {
+	if err := qtx.UpdatePost(ctx, updatePostArg); err != nil {
 		return fmt.Errorf("could not update post: %w", err)
 	}
 
+	if err := qtx.BatchDeletePostHashtagsByPost(ctx, props.ID); err != nil {
+		return fmt.Errorf("could not batch delete post hashtags: %w", err)
+	}
+
+	if err := insertPostHashtags(ctx, qtx, props.ID, props.Content); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func insertPostHashtags(ctx context.Context, qtx *db.Queries, postId int64, content string) error {
+	hashtags := libs.ExtractHashtags(content)
+	if len(hashtags) == 0 {
+		return nil
+	}
+
+	err := qtx.BatchInsertPostHashtags(ctx, db.BatchInsertPostHashtagsParams{
+		PostID:   postId,
+		Hashtags: hashtags,
+	})
+	if err != nil {
+		return fmt.Errorf("could not batch insert post hashtags: %w", err)
+	}
+
 	return nil
 }
 
@@ -508,11 +969,6 @@ func (r *PostsRepository) GetPostImagesUrl(postId int64) ([]string, error) {
 }
 
 func (r *PostsRepository) DeletePost(postId int64) error {
-	var (
-		errChan = make(chan error, 5)
-		wg      sync.WaitGroup
-	)
-
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
 	if err != nil {
@@ -520,15 +976,55 @@ func (r *PostsRepository) DeletePost(postId int64) error {
 	}
 	defer tx.Rollback()
 
-	qtx := r.query.WithTx(tx)
+	if err = DeletePostWithQueries(ctx, r.query.WithTx(tx), postId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePostWithQueries deletes the post and everything attached to it with the caller's transaction queries
+func DeletePostWithQueries(ctx context.Context, qtx *db.Queries, postId int64) error {
+	var (
+		err     error
+		errChan = make(chan error, 8)
+		wg      sync.WaitGroup
+	)
+
+	// Reports and flags reference comments and replies, so they go before the batch deletes below
+	if err = qtx.ResolveAllReportsByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not resolve reports: %w", err)
+	}
+
+	if err = qtx.ResolveContentFlagsByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not resolve content flags: %w", err)
+	}
+
+	if err = qtx.BatchDeleteLikedPostCommentRepliesByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not batch delete liked post comment replies: %w", err)
+	}
+
+	if err = qtx.BatchDeleteLikedPostCommentsByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not batch delete liked post comments: %w", err)
+	}
+
+	if err = qtx.BatchDeletePollVotesByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not batch delete poll votes: %w", err)
+	}
+
+	if err = qtx.BatchDeletePollOptionsByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not batch delete poll options: %w", err)
+	}
+
+	if err = qtx.DeletePollByPost(ctx, postId); err != nil {
+		return fmt.Errorf("could not delete poll: %w", err)
+	}
 
 	deleteFuncs := []func(int64){
-		func(postId int64) {
-			defer wg.Done()
-			if err := qtx.BatchDeleteReportedPostsByPost(ctx, postId); err != nil {
-				errChan <- fmt.Errorf("could not batch delete reported post: %w", err)
-			}
-		},
 		func(postId int64) {
 			defer wg.Done()
 			if err := qtx.BatchDeleteLikedPostByPost(ctx, postId); err != nil {
@@ -553,6 +1049,30 @@ func (r *PostsRepository) DeletePost(postId int64) error {
 				errChan <- fmt.Errorf("could not batch delete post images: %w", err)
 			}
 		},
+		func(postId int64) {
+			defer wg.Done()
+			if err := qtx.BatchDeletePostLinkPreviewsByPost(ctx, postId); err != nil {
+				errChan <- fmt.Errorf("could not batch delete post link previews: %w", err)
+			}
+		},
+		func(postId int64) {
+			defer wg.Done()
+			if err := qtx.BatchDeleteBookmarkedPostsByPost(ctx, postId); err != nil {
+				errChan <- fmt.Errorf("could not batch delete bookmarked posts: %w", err)
+			}
+		},
+		func(postId int64) {
+			defer wg.Done()
+			if err := qtx.BatchDeletePostReactionCountsByPost(ctx, postId); err != nil {
+				errChan <- fmt.Errorf("could not batch delete post reaction counts: %w", err)
+			}
+		},
+		func(postId int64) {
+			defer wg.Done()
+			if err := qtx.DeleteHiddenPostByPost(ctx, postId); err != nil {
+				errChan <- fmt.Errorf("could not delete hidden post: %w", err)
+			}
+		},
 	}
 
 	for _, deleteFunc := range deleteFuncs {
@@ -577,10 +1097,6 @@ func (r *PostsRepository) DeletePost(postId int64) error {
 		return fmt.Errorf("could not delete post: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -737,6 +1253,13 @@ func (r *PostsRepository) InsertPostComment(props *model.AddPostCommentReq) (mod
 
 	qtx := r.query.WithTx(tx)
 
+	_, err = qtx.LockPostForUpdate(ctx, props.PostId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.PostComment{}, err
+	} else if err != nil {
+		return model.PostComment{}, fmt.Errorf("could not lock post for update: %w", err)
+	}
+
 	arg := db.InsertPostCommentParams{
 		UserID:       props.UserId,
 		PostID:       props.PostId,
@@ -788,6 +1311,10 @@ func (r *PostsRepository) InsertPostComment(props *model.AddPostCommentReq) (mod
 }
 
 func (r *PostsRepository) LikePostComment(userId, postCommentId int64) (*db.UpdatePostCommentsLikeCountRow, error) {
+	return r.ReactPostComment(userId, postCommentId, model.ReactionLike)
+}
+
+func (r *PostsRepository) UnlikePostComment(userId, postCommentId int64) (*db.UpdatePostCommentsLikeCountRow, error) {
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
 	if err != nil {
@@ -806,28 +1333,32 @@ func (r *PostsRepository) LikePostComment(userId, postCommentId int64) (*db.Upda
 
 	postComment, err := qtx.UpdatePostCommentsLikeCount(ctx, db.UpdatePostCommentsLikeCountParams{
 		ID:    postCommentId,
-		Value: 1,
+		Value: -1,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not update like count: %w", err)
 	}
 
-	_, err = qtx.InsertLikedPostComments(ctx, db.InsertLikedPostCommentsParams{
+	_, err = qtx.DeleteLikedPostComment(ctx, db.DeleteLikedPostCommentParams{
 		UserID:        userId,
 		PostCommentID: postCommentId,
 	})
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			if postComment.LikeCount.Int32 > 0 {
+				postComment.LikeCount.Int32 = postComment.LikeCount.Int32 + 1
+			}
+
 			postComment = db.UpdatePostCommentsLikeCountRow{
-				ID:        postComment.ID,
-				LikeCount: sql.NullInt32{Int32: postComment.LikeCount.Int32 - 1, Valid: true},
+				ID:        postCommentId,
+				LikeCount: sql.NullInt32{Int32: postComment.LikeCount.Int32, Valid: true},
 			}
 
 			return &postComment, nil
 		}
 
-		return nil, fmt.Errorf("could not insert liked post comment: %w", err)
+		return nil, fmt.Errorf("could not delete liked post comment: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -837,80 +1368,33 @@ func (r *PostsRepository) LikePostComment(userId, postCommentId int64) (*db.Upda
 	return &postComment, nil
 }
 
-func (r *PostsRepository) UnlikePostComment(userId, postCommentId int64) (*db.UpdatePostCommentsLikeCountRow, error) {
+func (r *PostsRepository) InsertPostCommentReply(props *model.AddPostCommentReplyReq) (model.PostCommentReply, error) {
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("could not begin transaction: %w", err)
+		return model.PostCommentReply{}, fmt.Errorf("could not begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	qtx := r.query.WithTx(tx)
 
-	_, err = qtx.LockPostCommentForUpdate(ctx, postCommentId)
+	_, err = qtx.LockPostCommentForUpdate(ctx, props.PostCommentId)
 	if err != nil && err == sql.ErrNoRows {
-		return nil, err
+		return model.PostCommentReply{}, err
 	} else if err != nil {
-		return nil, fmt.Errorf("could not lock for update: %w", err)
+		return model.PostCommentReply{}, fmt.Errorf("could not lock post comment for update: %w", err)
 	}
 
-	postComment, err := qtx.UpdatePostCommentsLikeCount(ctx, db.UpdatePostCommentsLikeCountParams{
-		ID:    postCommentId,
-		Value: -1,
-	})
+	arg := db.InsertPostCommentReplyParams{
+		UserID:        props.UserId,
+		PostCommentID: props.PostCommentId,
+		Content:       props.Content,
+		ImageUrl:      props.ImageUrl,
+		IsPostAuthor:  props.IsPostAuthor,
+	}
+	createdData, err := qtx.InsertPostCommentReply(ctx, arg)
 	if err != nil {
-		return nil, fmt.Errorf("could not update like count: %w", err)
-	}
-
-	_, err = qtx.DeleteLikedPostComment(ctx, db.DeleteLikedPostCommentParams{
-		UserID:        userId,
-		PostCommentID: postCommentId,
-	})
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			if postComment.LikeCount.Int32 > 0 {
-				postComment.LikeCount.Int32 = postComment.LikeCount.Int32 + 1
-			}
-
-			postComment = db.UpdatePostCommentsLikeCountRow{
-				ID:        postCommentId,
-				LikeCount: sql.NullInt32{Int32: postComment.LikeCount.Int32, Valid: true},
-			}
-
-			return &postComment, nil
-		}
-
-		return nil, fmt.Errorf("could not delete liked post comment: %w", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("could not commit transaction: %w", err)
-	}
-
-	return &postComment, nil
-}
-
-func (r *PostsRepository) InsertPostCommentReply(props *model.AddPostCommentReplyReq) (model.PostCommentReply, error) {
-	ctx := context.Background()
-	tx, err := r.dbConn.Begin()
-	if err != nil {
-		return model.PostCommentReply{}, fmt.Errorf("could not begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	qtx := r.query.WithTx(tx)
-
-	arg := db.InsertPostCommentReplyParams{
-		UserID:        props.UserId,
-		PostCommentID: props.PostCommentId,
-		Content:       props.Content,
-		ImageUrl:      props.ImageUrl,
-		IsPostAuthor:  props.IsPostAuthor,
-	}
-	createdData, err := qtx.InsertPostCommentReply(ctx, arg)
-	if err != nil {
-		return model.PostCommentReply{}, fmt.Errorf("could not insert post comment reply: %w", err)
+		return model.PostCommentReply{}, fmt.Errorf("could not insert post comment reply: %w", err)
 	}
 
 	_, err = qtx.UpdatePostCommentReplyCount(ctx, db.UpdatePostCommentReplyCountParams{
@@ -951,6 +1435,10 @@ func (r *PostsRepository) InsertPostCommentReply(props *model.AddPostCommentRepl
 }
 
 func (r *PostsRepository) LikePostCommentReply(userId, postCommentReplyId int64) (*db.UpdatePostCommentRepliesLikeCountRow, error) {
+	return r.ReactPostCommentReply(userId, postCommentReplyId, model.ReactionLike)
+}
+
+func (r *PostsRepository) UnlikePostCommentReply(userId, postCommentReplyId int64) (*db.UpdatePostCommentRepliesLikeCountRow, error) {
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
 	if err != nil {
@@ -969,28 +1457,32 @@ func (r *PostsRepository) LikePostCommentReply(userId, postCommentReplyId int64)
 
 	postCommentReply, err := qtx.UpdatePostCommentRepliesLikeCount(ctx, db.UpdatePostCommentRepliesLikeCountParams{
 		ID:    postCommentReplyId,
-		Value: 1,
+		Value: -1,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not update like count: %w", err)
 	}
 
-	_, err = qtx.InsertLikedPostCommentReplies(ctx, db.InsertLikedPostCommentRepliesParams{
+	_, err = qtx.DeleteLikedPostCommentReplies(ctx, db.DeleteLikedPostCommentRepliesParams{
 		UserID:             userId,
 		PostCommentReplyID: postCommentReplyId,
 	})
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			if postCommentReply.LikeCount.Int32 > 0 {
+				postCommentReply.LikeCount.Int32 = postCommentReply.LikeCount.Int32 + 1
+			}
+
 			postCommentReply = db.UpdatePostCommentRepliesLikeCountRow{
-				ID:        postCommentReply.ID,
-				LikeCount: sql.NullInt32{Int32: postCommentReply.LikeCount.Int32 - 1, Valid: true},
+				ID:        postCommentReplyId,
+				LikeCount: sql.NullInt32{Int32: postCommentReply.LikeCount.Int32, Valid: true},
 			}
 
 			return &postCommentReply, nil
 		}
 
-		return nil, fmt.Errorf("could not insert liked post comment reply: %w", err)
+		return nil, fmt.Errorf("could not delete liked post comment reply: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -1000,56 +1492,985 @@ func (r *PostsRepository) LikePostCommentReply(userId, postCommentReplyId int64)
 	return &postCommentReply, nil
 }
 
-func (r *PostsRepository) UnlikePostCommentReply(userId, postCommentReplyId int64) (*db.UpdatePostCommentRepliesLikeCountRow, error) {
+func (r *PostsRepository) GetLinkPreviewByUrl(url string) (db.LinkPreview, error) {
+	data, err := r.query.GetLinkPreviewByUrl(context.Background(), url)
+	if err != nil {
+		return db.LinkPreview{}, err
+	}
+
+	return data, nil
+}
+
+func (r *PostsRepository) SavePostLinkPreview(postId int64, props *model.LinkPreview) error {
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("could not begin transaction: %w", err)
+		return fmt.Errorf("could not begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	qtx := r.query.WithTx(tx)
 
-	_, err = qtx.LockPostCommentReplyForUpdate(ctx, postCommentReplyId)
-	if err != nil && err == sql.ErrNoRows {
-		return nil, err
-	} else if err != nil {
-		return nil, fmt.Errorf("could not lock for update: %w", err)
+	linkPreview, err := qtx.UpsertLinkPreview(ctx, db.UpsertLinkPreviewParams{
+		Url:         props.Url,
+		Title:       props.Title,
+		Description: props.Description,
+		ImageUrl:    props.ImageUrl,
+		SiteName:    props.SiteName,
+	})
+	if err != nil {
+		return fmt.Errorf("could not upsert link preview: %w", err)
 	}
 
-	postCommentReply, err := qtx.UpdatePostCommentRepliesLikeCount(ctx, db.UpdatePostCommentRepliesLikeCountParams{
-		ID:    postCommentReplyId,
-		Value: -1,
+	err = qtx.UpsertPostLinkPreview(ctx, db.UpsertPostLinkPreviewParams{
+		PostID:        postId,
+		LinkPreviewID: linkPreview.ID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("could not update like count: %w", err)
+		return fmt.Errorf("could not upsert post link preview: %w", err)
 	}
 
-	_, err = qtx.DeleteLikedPostCommentReplies(ctx, db.DeleteLikedPostCommentRepliesParams{
-		UserID:             userId,
-		PostCommentReplyID: postCommentReplyId,
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) AttachPostLinkPreview(postId, linkPreviewId int64) error {
+	err := r.query.UpsertPostLinkPreview(context.Background(), db.UpsertPostLinkPreviewParams{
+		PostID:        postId,
+		LinkPreviewID: linkPreviewId,
 	})
+	if err != nil {
+		return fmt.Errorf("could not upsert post link preview: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) DeletePostLinkPreview(postId int64) error {
+	if err := r.query.BatchDeletePostLinkPreviewsByPost(context.Background(), postId); err != nil {
+		return fmt.Errorf("could not delete post link preview: %w", err)
+	}
+
+	return nil
+}
+
+// AttachLinkPreviews loads the link preview of every post in a single query,
+// the homepage feeds share it with the posts repository
+func AttachLinkPreviews(ctx context.Context, query *db.Queries, posts []model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIds := make([]int64, len(posts))
+	for i, v := range posts {
+		postIds[i] = v.ID
+	}
 
+	data, err := query.ListLinkPreviewsByPostIds(ctx, postIds)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			if postCommentReply.LikeCount.Int32 > 0 {
-				postCommentReply.LikeCount.Int32 = postCommentReply.LikeCount.Int32 + 1
-			}
+		return fmt.Errorf("could not list link previews: %w", err)
+	}
 
-			postCommentReply = db.UpdatePostCommentRepliesLikeCountRow{
-				ID:        postCommentReplyId,
-				LikeCount: sql.NullInt32{Int32: postCommentReply.LikeCount.Int32, Valid: true},
-			}
+	linkPreviews := make(map[int64]*model.LinkPreview, len(data))
+	for _, v := range data {
+		linkPreviews[v.PostID] = libs.LinkPreviewFromNullable(
+			sql.NullString{String: v.Url, Valid: true}, v.Title, v.Description, v.ImageUrl, v.SiteName,
+		)
+	}
 
-			return &postCommentReply, nil
+	for i := range posts {
+		posts[i].LinkPreview = linkPreviews[posts[i].ID]
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) BookmarkPost(userId, postId, bookmarkFolderId int64) error {
+	_, err := r.query.InsertBookmarkedPost(context.Background(), db.InsertBookmarkedPostParams{
+		UserID:           userId,
+		PostID:           postId,
+		BookmarkFolderID: sql.NullInt64{Int64: bookmarkFolderId, Valid: bookmarkFolderId > 0},
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert bookmarked post: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) UnbookmarkPost(userId, postId int64) error {
+	_, err := r.query.DeleteBookmarkedPost(context.Background(), db.DeleteBookmarkedPostParams{
+		UserID: userId,
+		PostID: postId,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) ListBookmarkedPosts(userId, bookmarkFolderId int64, offset, limit int32) ([]model.Post, int64, error) {
+	arg := db.ListBookmarkedPostsParams{
+		UserID:           userId,
+		BookmarkFolderID: bookmarkFolderId,
+		Offset:           offset,
+		Limit:            limit,
+	}
+
+	data, err := r.query.ListBookmarkedPosts(context.Background(), arg)
+	if err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	// get total rows for pagination
+	var count int64
+	if len(data) > 0 {
+		count = data[0].TotalRows
+	}
+
+	posts := make([]model.Post, len(data))
+	for i, v := range data {
+		var imageUrls []string
+
+		// Convert to array
+		if v.ImageUrls != nil {
+			imageUrlsString := strings.Trim(string(v.ImageUrls.([]uint8)), "{}")
+			imageUrls = strings.Split(imageUrlsString, ",")
 		}
 
-		return nil, fmt.Errorf("could not delete liked post comment reply: %w", err)
+		posts[i] = model.Post{
+			ID: v.ID,
+			User: model.User{
+				ID:         v.UserID.Int64,
+				AvatarUrl:  v.AvatarUrl.String,
+				Fullname:   v.FullName.String,
+				Bio:        v.Bio.String,
+				OpenToWork: v.OpenToWork.Bool,
+			},
+			Title:        v.Title,
+			Content:      v.Content.String,
+			ImageUrls:    imageUrls,
+			LikeCount:    v.LikeCount.Int32,
+			CommentCount: v.CommentCount.Int32,
+			RepostCount:  v.RepostCount.Int32,
+			IsRepost:     v.Repost,
+			IsLiked:      v.Liked,
+			IsBookmarked: true,
+			UpdatedAt:    v.UpdatedAt.Time,
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	if err := AttachLinkPreviews(context.Background(), r.query, posts); err != nil {
+		return []model.Post{}, 0, err
 	}
 
-	return &postCommentReply, nil
+	if err := AttachReactions(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	if err := AttachPolls(context.Background(), r.query, userId, posts); err != nil {
+		return []model.Post{}, 0, err
+	}
+
+	return posts, count, nil
+}
+
+func (r *PostsRepository) InsertBookmarkFolder(userId int64, name string) (model.BookmarkFolder, error) {
+	data, err := r.query.InsertBookmarkFolder(context.Background(), db.InsertBookmarkFolderParams{
+		UserID: userId,
+		Name:   name,
+	})
+	if err != nil {
+		return model.BookmarkFolder{}, err
+	}
+
+	bookmarkFolder := model.BookmarkFolder{
+		ID:        data.ID,
+		Name:      data.Name,
+		CreatedAt: data.CreatedAt,
+	}
+
+	return bookmarkFolder, nil
+}
+
+func (r *PostsRepository) GetBookmarkFolderById(userId, bookmarkFolderId int64) (model.BookmarkFolder, error) {
+	data, err := r.query.GetBookmarkFolderById(context.Background(), db.GetBookmarkFolderByIdParams{
+		ID:     bookmarkFolderId,
+		UserID: userId,
+	})
+	if err != nil {
+		return model.BookmarkFolder{}, err
+	}
+
+	bookmarkFolder := model.BookmarkFolder{
+		ID:        data.ID,
+		Name:      data.Name,
+		CreatedAt: data.CreatedAt,
+	}
+
+	return bookmarkFolder, nil
+}
+
+func (r *PostsRepository) ListBookmarkFolders(userId int64) ([]model.BookmarkFolder, error) {
+	data, err := r.query.ListBookmarkFolders(context.Background(), userId)
+	if err != nil {
+		return []model.BookmarkFolder{}, err
+	}
+
+	bookmarkFolders := make([]model.BookmarkFolder, len(data))
+	for i, v := range data {
+		bookmarkFolders[i] = model.BookmarkFolder{
+			ID:            v.ID,
+			Name:          v.Name,
+			BookmarkCount: v.BookmarkCount,
+			CreatedAt:     v.CreatedAt,
+		}
+	}
+
+	return bookmarkFolders, nil
+}
+
+func (r *PostsRepository) DeleteBookmarkFolder(userId, bookmarkFolderId int64) error {
+	_, err := r.query.DeleteBookmarkFolder(context.Background(), db.DeleteBookmarkFolderParams{
+		ID:     bookmarkFolderId,
+		UserID: userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReactPost adds the user's reaction to the post or changes its type,
+// like_count holds the total of every reaction type
+func (r *PostsRepository) ReactPost(userId, postId int64, reactionType string) (*db.UpdatePostLikeCountRow, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	_, err = qtx.LockPostForUpdate(ctx, postId)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("could not lock post for update: %w", err)
+	}
+
+	currentReactionType, err := qtx.GetPostReactionByUser(ctx, db.GetPostReactionByUserParams{
+		UserID: userId,
+		PostID: postId,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("could not get post reaction: %w", err)
+	}
+
+	var post db.UpdatePostLikeCountRow
+
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = qtx.InsertLikedPost(ctx, db.InsertLikedPostParams{
+			UserID:       userId,
+			PostID:       postId,
+			ReactionType: reactionType,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not insert liked post: %w", err)
+		}
+
+		post, err = qtx.UpdatePostLikeCount(ctx, db.UpdatePostLikeCountParams{
+			ID:    postId,
+			Value: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not update like count: %w", err)
+		}
+	} else {
+		if currentReactionType != reactionType {
+			err = qtx.UpdateLikedPostReactionType(ctx, db.UpdateLikedPostReactionTypeParams{
+				ReactionType: reactionType,
+				UserID:       userId,
+				PostID:       postId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not update liked post reaction type: %w", err)
+			}
+
+			err = qtx.UpdatePostReactionCount(ctx, db.UpdatePostReactionCountParams{
+				PostID:       postId,
+				ReactionType: currentReactionType,
+				Value:        -1,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not update reaction count: %w", err)
+			}
+		}
+
+		current, err := qtx.GetPostLikeCount(ctx, postId)
+		if err != nil {
+			return nil, fmt.Errorf("could not get like count: %w", err)
+		}
+
+		post = db.UpdatePostLikeCountRow{ID: current.ID, LikeCount: current.LikeCount}
+	}
+
+	if currentReactionType != reactionType {
+		err = qtx.UpdatePostReactionCount(ctx, db.UpdatePostReactionCountParams{
+			PostID:       postId,
+			ReactionType: reactionType,
+			Value:        1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not update reaction count: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return &post, nil
+}
+
+func (r *PostsRepository) ListPostReactions(postId int64, reactionType string, offset, limit int32) ([]model.PostReaction, int64, error) {
+	arg := db.ListPostReactionsParams{
+		PostID:       postId,
+		ReactionType: reactionType,
+		Offset:       offset,
+		Limit:        limit,
+	}
+
+	data, err := r.query.ListPostReactions(context.Background(), arg)
+	if err != nil {
+		return []model.PostReaction{}, 0, err
+	}
+
+	// get total rows for pagination
+	var count int64
+	if len(data) > 0 {
+		count = data[0].TotalRows
+	}
+
+	reactions := make([]model.PostReaction, len(data))
+	for i, v := range data {
+		reactions[i] = model.PostReaction{
+			User: model.User{
+				ID:         v.ID,
+				AvatarUrl:  v.AvatarUrl.String,
+				Fullname:   v.FullName,
+				Bio:        v.Bio.String,
+				OpenToWork: v.OpenToWork.Bool,
+			},
+			ReactionType: v.ReactionType,
+		}
+	}
+
+	return reactions, count, nil
+}
+
+func (r *PostsRepository) ReactPostComment(userId, postCommentId int64, reactionType string) (*db.UpdatePostCommentsLikeCountRow, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	_, err = qtx.LockPostCommentForUpdate(ctx, postCommentId)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("could not lock for update: %w", err)
+	}
+
+	currentReactionType, err := qtx.GetPostCommentReactionByUser(ctx, db.GetPostCommentReactionByUserParams{
+		UserID:        userId,
+		PostCommentID: postCommentId,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("could not get post comment reaction: %w", err)
+	}
+
+	var postComment db.UpdatePostCommentsLikeCountRow
+
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = qtx.InsertLikedPostComments(ctx, db.InsertLikedPostCommentsParams{
+			UserID:        userId,
+			PostCommentID: postCommentId,
+			ReactionType:  reactionType,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not insert liked post comment: %w", err)
+		}
+
+		postComment, err = qtx.UpdatePostCommentsLikeCount(ctx, db.UpdatePostCommentsLikeCountParams{
+			ID:    postCommentId,
+			Value: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not update like count: %w", err)
+		}
+	} else {
+		if currentReactionType != reactionType {
+			err = qtx.UpdateLikedPostCommentReactionType(ctx, db.UpdateLikedPostCommentReactionTypeParams{
+				ReactionType:  reactionType,
+				UserID:        userId,
+				PostCommentID: postCommentId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not update liked post comment reaction type: %w", err)
+			}
+		}
+
+		current, err := qtx.GetPostCommentLikeCount(ctx, postCommentId)
+		if err != nil {
+			return nil, fmt.Errorf("could not get like count: %w", err)
+		}
+
+		postComment = db.UpdatePostCommentsLikeCountRow{ID: current.ID, LikeCount: current.LikeCount}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return &postComment, nil
+}
+
+func (r *PostsRepository) ReactPostCommentReply(userId, postCommentReplyId int64, reactionType string) (*db.UpdatePostCommentRepliesLikeCountRow, error) {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	_, err = qtx.LockPostCommentReplyForUpdate(ctx, postCommentReplyId)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("could not lock for update: %w", err)
+	}
+
+	currentReactionType, err := qtx.GetPostCommentReplyReactionByUser(ctx, db.GetPostCommentReplyReactionByUserParams{
+		UserID:             userId,
+		PostCommentReplyID: postCommentReplyId,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("could not get post comment reply reaction: %w", err)
+	}
+
+	var postCommentReply db.UpdatePostCommentRepliesLikeCountRow
+
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = qtx.InsertLikedPostCommentReplies(ctx, db.InsertLikedPostCommentRepliesParams{
+			UserID:             userId,
+			PostCommentReplyID: postCommentReplyId,
+			ReactionType:       reactionType,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not insert liked post comment reply: %w", err)
+		}
+
+		postCommentReply, err = qtx.UpdatePostCommentRepliesLikeCount(ctx, db.UpdatePostCommentRepliesLikeCountParams{
+			ID:    postCommentReplyId,
+			Value: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not update like count: %w", err)
+		}
+	} else {
+		if currentReactionType != reactionType {
+			err = qtx.UpdateLikedPostCommentReplyReactionType(ctx, db.UpdateLikedPostCommentReplyReactionTypeParams{
+				ReactionType:       reactionType,
+				UserID:             userId,
+				PostCommentReplyID: postCommentReplyId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not update liked post comment reply reaction type: %w", err)
+			}
+		}
+
+		current, err := qtx.GetPostCommentReplyLikeCount(ctx, postCommentReplyId)
+		if err != nil {
+			return nil, fmt.Errorf("could not get like count: %w", err)
+		}
+
+		postCommentReply = db.UpdatePostCommentRepliesLikeCountRow{ID: current.ID, LikeCount: current.LikeCount}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return &postCommentReply, nil
+}
+
+// AttachReactions loads the per-type reaction counts and the user's own reaction of every post,
+// the homepage feeds share it with the posts repository
+func AttachReactions(ctx context.Context, query *db.Queries, userId int64, posts []model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIds := make([]int64, len(posts))
+	for i, v := range posts {
+		postIds[i] = v.ID
+	}
+
+	reactionCounts, err := query.ListPostReactionCountsByPostIds(ctx, postIds)
+	if err != nil {
+		return fmt.Errorf("could not list reaction counts: %w", err)
+	}
+
+	userReactions, err := query.ListUserReactionsByPostIds(ctx, db.ListUserReactionsByPostIdsParams{
+		UserID:  userId,
+		PostIds: postIds,
+	})
+	if err != nil {
+		return fmt.Errorf("could not list user reactions: %w", err)
+	}
+
+	countsByPost := make(map[int64]map[string]int32, len(posts))
+	for _, v := range reactionCounts {
+		if countsByPost[v.PostID] == nil {
+			countsByPost[v.PostID] = make(map[string]int32)
+		}
+		countsByPost[v.PostID][v.ReactionType] = v.Count
+	}
+
+	reactionByPost := make(map[int64]string, len(userReactions))
+	for _, v := range userReactions {
+		reactionByPost[v.PostID.Int64] = v.ReactionType
+	}
+
+	for i := range posts {
+		posts[i].ReactionCounts = countsByPost[posts[i].ID]
+		if posts[i].ReactionCounts == nil {
+			posts[i].ReactionCounts = map[string]int32{}
+		}
+		posts[i].MyReaction = reactionByPost[posts[i].ID]
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) GetPostCommentById(postCommentId int64) (db.GetPostCommentByIdRow, error) {
+	return r.query.GetPostCommentById(context.Background(), postCommentId)
+}
+
+func (r *PostsRepository) UpdatePostComment(props *model.UpdatePostCommentReq) (model.PostComment, error) {
+	ctx := context.Background()
+
+	updatedData, err := r.query.UpdatePostComment(ctx, db.UpdatePostCommentParams{
+		Content: props.Content,
+		ID:      props.ID,
+	})
+	if err != nil {
+		return model.PostComment{}, fmt.Errorf("could not update post comment: %w", err)
+	}
+
+	user, err := r.query.GetUserById(ctx, props.UserId)
+	if err != nil {
+		return model.PostComment{}, fmt.Errorf("could not get user: %w", err)
+	}
+
+	postComment := model.PostComment{
+		ID:     updatedData.ID,
+		PostId: updatedData.PostID.Int64,
+		User: model.User{
+			ID:         props.UserId,
+			Fullname:   user.FullName,
+			AvatarUrl:  user.AvatarUrl.String,
+			Bio:        user.Bio.String,
+			OpenToWork: user.OpenToWork.Bool,
+		},
+		Content:      updatedData.Content.String,
+		ImageUrl:     updatedData.ImageUrl.String,
+		LikeCount:    updatedData.LikeCount.Int32,
+		ReplyCount:   updatedData.ReplyCount.Int32,
+		IsPostAuthor: updatedData.IsPostAuthor.Bool,
+		UpdatedAt:    updatedData.UpdatedAt.Time,
+	}
+
+	return postComment, nil
+}
+
+// DeletePostComment deletes the comment along with its replies and likes,
+// the post row is locked so comment_count stays consistent
+func (r *PostsRepository) DeletePostComment(postId, postCommentId int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = DeletePostCommentWithQueries(ctx, r.query.WithTx(tx), postId, postCommentId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePostCommentWithQueries deletes the comment with the caller's transaction queries
+func DeletePostCommentWithQueries(ctx context.Context, qtx *db.Queries, postId, postCommentId int64) error {
+	_, err := qtx.LockPostForUpdate(ctx, postId)
+	if err != nil && err == sql.ErrNoRows {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("could not lock post for update: %w", err)
+	}
+
+	_, err = qtx.LockPostCommentForUpdate(ctx, postCommentId)
+	if err != nil && err == sql.ErrNoRows {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("could not lock post comment for update: %w", err)
+	}
+
+	if err = qtx.ResolveReportsByComment(ctx, postCommentId); err != nil {
+		return fmt.Errorf("could not resolve reports: %w", err)
+	}
+
+	if err = qtx.ResolveContentFlagsByComment(ctx, postCommentId); err != nil {
+		return fmt.Errorf("could not resolve content flags: %w", err)
+	}
+
+	if err = qtx.BatchDeleteLikedPostCommentRepliesByComment(ctx, postCommentId); err != nil {
+		return fmt.Errorf("could not batch delete liked post comment replies: %w", err)
+	}
+
+	if err = qtx.BatchDeletePostCommentRepliesByComment(ctx, postCommentId); err != nil {
+		return fmt.Errorf("could not batch delete post comment replies: %w", err)
+	}
+
+	if err = qtx.BatchDeleteLikedPostCommentsByComment(ctx, postCommentId); err != nil {
+		return fmt.Errorf("could not batch delete liked post comments: %w", err)
+	}
+
+	if err = qtx.DeletePostCommentById(ctx, postCommentId); err != nil {
+		return fmt.Errorf("could not delete post comment: %w", err)
+	}
+
+	_, err = qtx.UpdatePostCommentCount(ctx, db.UpdatePostCommentCountParams{
+		ID:    postId,
+		Value: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update post comment count: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) GetPostCommentReplyImageUrls(postCommentId int64) ([]string, error) {
+	data, err := r.query.GetPostCommentReplyImageUrlsByComment(context.Background(), postCommentId)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	for _, v := range data {
+		if v.String != "" {
+			urls = append(urls, v.String)
+		}
+	}
+
+	return urls, nil
+}
+
+func (r *PostsRepository) GetPostCommentReplyById(postCommentReplyId int64) (db.GetPostCommentReplyByIdRow, error) {
+	return r.query.GetPostCommentReplyById(context.Background(), postCommentReplyId)
+}
+
+func (r *PostsRepository) UpdatePostCommentReply(props *model.UpdatePostCommentReplyReq) (model.PostCommentReply, error) {
+	ctx := context.Background()
+
+	updatedData, err := r.query.UpdatePostCommentReply(ctx, db.UpdatePostCommentReplyParams{
+		Content: props.Content,
+		ID:      props.ID,
+	})
+	if err != nil {
+		return model.PostCommentReply{}, fmt.Errorf("could not update post comment reply: %w", err)
+	}
+
+	user, err := r.query.GetUserById(ctx, props.UserId)
+	if err != nil {
+		return model.PostCommentReply{}, fmt.Errorf("could not get user: %w", err)
+	}
+
+	postCommentReply := model.PostCommentReply{
+		ID:            updatedData.ID,
+		PostCommentId: updatedData.PostCommentID.Int64,
+		User: model.User{
+			ID:         props.UserId,
+			Fullname:   user.FullName,
+			AvatarUrl:  user.AvatarUrl.String,
+			Bio:        user.Bio.String,
+			OpenToWork: user.OpenToWork.Bool,
+		},
+		Content:      updatedData.Content.String,
+		ImageUrl:     updatedData.ImageUrl.String,
+		LikeCount:    updatedData.LikeCount.Int32,
+		IsPostAuthor: updatedData.IsPostAuthor.Bool,
+		UpdatedAt:    updatedData.UpdatedAt.Time,
+	}
+
+	return postCommentReply, nil
+}
+
+// DeletePostCommentReply deletes the reply along with its likes,
+// the parent comment row is locked so reply_count stays consistent
+func (r *PostsRepository) DeletePostCommentReply(postCommentId, postCommentReplyId int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = DeletePostCommentReplyWithQueries(ctx, r.query.WithTx(tx), postCommentId, postCommentReplyId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePostCommentReplyWithQueries deletes the reply with the caller's transaction queries
+func DeletePostCommentReplyWithQueries(ctx context.Context, qtx *db.Queries, postCommentId, postCommentReplyId int64) error {
+	_, err := qtx.LockPostCommentForUpdate(ctx, postCommentId)
+	if err != nil && err == sql.ErrNoRows {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("could not lock post comment for update: %w", err)
+	}
+
+	_, err = qtx.LockPostCommentReplyForUpdate(ctx, postCommentReplyId)
+	if err != nil && err == sql.ErrNoRows {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("could not lock post comment reply for update: %w", err)
+	}
+
+	if err = qtx.ResolveReportsByReply(ctx, postCommentReplyId); err != nil {
+		return fmt.Errorf("could not resolve reports: %w", err)
+	}
+
+	if err = qtx.ResolveContentFlagsByReply(ctx, postCommentReplyId); err != nil {
+		return fmt.Errorf("could not resolve content flags: %w", err)
+	}
+
+	if err = qtx.BatchDeleteLikedPostCommentRepliesByReply(ctx, postCommentReplyId); err != nil {
+		return fmt.Errorf("could not batch delete liked post comment replies: %w", err)
+	}
+
+	if err = qtx.DeletePostCommentReplyById(ctx, postCommentReplyId); err != nil {
+		return fmt.Errorf("could not delete post comment reply: %w", err)
+	}
+
+	_, err = qtx.UpdatePostCommentReplyCount(ctx, db.UpdatePostCommentReplyCountParams{
+		ID:    postCommentId,
+		Value: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update post comment reply count: %w", err)
+	}
+
+	return nil
+}
+
+// VotePoll records the user's vote, the poll row is locked so a user
+// can only vote once and the counts stay consistent
+func (r *PostsRepository) VotePoll(userId, postId int64, optionIds []int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	poll, err := qtx.GetPollByPostForUpdate(ctx, postId)
+	if err != nil && err == sql.ErrNoRows {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("could not get poll for update: %w", err)
+	}
+
+	if !time.Now().UTC().Before(poll.EndsAt) {
+		return ErrPollClosed
+	}
+
+	voteCount, err := qtx.CountPollVotesByUser(ctx, db.CountPollVotesByUserParams{
+		PollID: poll.ID,
+		UserID: userId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not count poll votes: %w", err)
+	}
+
+	if voteCount > 0 {
+		return ErrPollAlreadyVoted
+	}
+
+	if !poll.AllowMultiple && len(optionIds) != 1 {
+		return ErrPollInvalidOption
+	}
+
+	pollOptionIds, err := qtx.ListPollOptionIdsByPoll(ctx, poll.ID)
+	if err != nil {
+		return fmt.Errorf("could not list poll options: %w", err)
+	}
+
+	for i, v := range optionIds {
+		if !slices.Contains(pollOptionIds, v) || slices.Contains(optionIds[:i], v) {
+			return ErrPollInvalidOption
+		}
+	}
+
+	err = qtx.BatchInsertPollVotes(ctx, db.BatchInsertPollVotesParams{
+		PollID:        poll.ID,
+		PollOptionIds: optionIds,
+		UserID:        userId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not batch insert poll votes: %w", err)
+	}
+
+	if err = qtx.IncrementPollOptionVoteCounts(ctx, optionIds); err != nil {
+		return fmt.Errorf("could not update poll option vote counts: %w", err)
+	}
+
+	if err = qtx.IncrementPollVoterCount(ctx, poll.ID); err != nil {
+		return fmt.Errorf("could not update poll voter count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AttachPolls loads the poll of every poll post, hiding the results from
+// users who have not voted yet. The homepage feeds share it with the posts repository
+func AttachPolls(ctx context.Context, query *db.Queries, userId int64, posts []model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIds := make([]int64, len(posts))
+	for i, v := range posts {
+		postIds[i] = v.ID
+	}
+
+	polls, err := query.ListPollsByPostIds(ctx, postIds)
+	if err != nil {
+		return fmt.Errorf("could not list polls: %w", err)
+	}
+
+	if len(polls) == 0 {
+		return nil
+	}
+
+	options, err := query.ListPollOptionsByPostIds(ctx, postIds)
+	if err != nil {
+		return fmt.Errorf("could not list poll options: %w", err)
+	}
+
+	votes, err := query.ListUserPollVotesByPostIds(ctx, db.ListUserPollVotesByPostIdsParams{
+		UserID:  userId,
+		PostIds: postIds,
+	})
+	if err != nil {
+		return fmt.Errorf("could not list user poll votes: %w", err)
+	}
+
+	votedPolls := make(map[int64]bool, len(votes))
+	votedOptions := make(map[int64]bool, len(votes))
+	for _, v := range votes {
+		votedPolls[v.PollID] = true
+		votedOptions[v.PollOptionID] = true
+	}
+
+	optionsByPoll := make(map[int64][]model.PollOption, len(polls))
+	for _, v := range options {
+		voteCount := v.VoteCount
+		optionsByPoll[v.PollID] = append(optionsByPoll[v.PollID], model.PollOption{
+			ID:        v.ID,
+			Text:      v.OptionText,
+			VoteCount: &voteCount,
+			IsVoted:   votedOptions[v.ID],
+		})
+	}
+
+	now := time.Now().UTC()
+	pollByPost := make(map[int64]*model.Poll, len(polls))
+	for _, v := range polls {
+		voterCount := v.VoterCount
+		poll := &model.Poll{
+			ID:            v.ID,
+			AllowMultiple: v.AllowMultiple,
+			EndsAt:        v.EndsAt,
+			HasVoted:      votedPolls[v.ID],
+			VoterCount:    &voterCount,
+			Options:       optionsByPoll[v.ID],
+		}
+		libs.HidePollResults(poll, now)
+
+		pollByPost[v.PostID] = poll
+	}
+
+	for i := range posts {
+		posts[i].Poll = pollByPost[posts[i].ID]
+	}
+
+	return nil
+}
+
+// InsertPostImpressions records a batch of posts seen by the user in a single statement
+func (r *PostsRepository) InsertPostImpressions(userId int64, postIds []int64) error {
+	err := r.query.UpsertPostImpressions(context.Background(), db.UpsertPostImpressionsParams{
+		UserID:  userId,
+		PostIds: postIds,
+	})
+	if err != nil {
+		return fmt.Errorf("could not upsert post impressions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostsRepository) GetPostViewCounts(postId int64) (model.PostViewCounts, error) {
+	data, err := r.query.GetPostViewCounts(context.Background(), postId)
+	if err != nil {
+		return model.PostViewCounts{}, err
+	}
+
+	return model.PostViewCounts{
+		PostID:      postId,
+		ViewCount:   data.ViewCount,
+		ViewerCount: data.ViewerCount,
+	}, nil
 }
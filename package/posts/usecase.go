@@ -2,22 +2,32 @@ package posts
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"profiln-be/libs"
 	"profiln-be/model"
 	repository "profiln-be/package/posts/repository"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 type IPostsUsecase interface {
-	InsertReportedPost(userId int64, props *model.ReportPost) (resp model.Response)
+	ReportPost(userId, postId int64, props *model.Report) model.Response
+	ReportPostComment(userId, postId, postCommentId int64, props *model.Report) model.Response
+	ReportPostCommentReply(userId, postId, postCommentId, postCommentReplyId int64, props *model.Report) model.Response
+	ReportUser(userId, targetUserId int64, props *model.Report) model.Response
 	GetDetailPost(postId, userId int64) (resp model.Response)
-	GetPostComments(postId int64, pagination model.PaginationRequest) (resp model.Response)
-	GetPostCommentReplies(postId, postCommentId int64, pagination model.PaginationRequest) (resp model.Response)
+	GetPostComments(userId, postId int64, pagination model.PaginationRequest) (resp model.Response)
+	GetPostCommentReplies(userId, postId, postCommentId int64, pagination model.PaginationRequest) (resp model.Response)
 	LikePost(userId, postId int64) model.Response
 	UnlikePost(userId, postId int64) model.Response
+	ReactPost(userId, postId int64, props *model.ReactRequest) model.Response
+	ListPostReactions(postId int64, reactionType string, pagination model.PaginationRequest) (resp model.Response)
 	ListNewestPostsByTargetUser(userId, targetUserId int64, pagination model.PaginationRequest) (resp model.Response)
 	ListLikedPostsByTargetUser(userId, targetUserId int64, pagination model.PaginationRequest) (resp model.Response)
 	ListRepostedPostsByTargetUser(userId, targetUserId int64, pagination model.PaginationRequest) (resp model.Response)
@@ -31,38 +41,187 @@ type IPostsUsecase interface {
 	InsertPostComment(imageFileNames []string, props *model.AddPostCommentReq) model.Response
 	LikePostComment(userId, postCommentId int64) model.Response
 	UnlikePostComment(userId, postCommentId int64) model.Response
+	ReactPostComment(userId, postCommentId int64, props *model.ReactRequest) model.Response
 	InsertPostCommentReply(imageFileNames []string, postId int64, props *model.AddPostCommentReplyReq) model.Response
 	LikePostCommentReply(userId, postCommentReplyId int64) model.Response
 	UnlikePostCommentReply(userId, postCommentReplyId int64) model.Response
+	ReactPostCommentReply(userId, postCommentReplyId int64, props *model.ReactRequest) model.Response
+	UpdatePostComment(props *model.UpdatePostCommentReq) model.Response
+	DeletePostComment(userId, postId, postCommentId int64) model.Response
+	UpdatePostCommentReply(props *model.UpdatePostCommentReplyReq) model.Response
+	DeletePostCommentReply(userId, postId, postCommentId, postCommentReplyId int64) model.Response
+	VotePoll(userId, postId int64, props *model.VotePollRequest) model.Response
+	BookmarkPost(userId, postId int64, props *model.BookmarkPostRequest) model.Response
+	UnbookmarkPost(userId, postId int64) model.Response
+	ListBookmarkedPosts(userId, bookmarkFolderId int64, pagination model.PaginationRequest) (resp model.Response)
+	InsertBookmarkFolder(userId int64, props *model.CreateBookmarkFolderRequest) model.Response
+	ListBookmarkFolders(userId int64) model.Response
+	DeleteBookmarkFolder(userId, bookmarkFolderId int64) model.Response
+	RecordPostImpressions(userId int64, props *model.PostImpressionsRequest) model.Response
+	GetPostViewCounts(userId, postId int64) model.Response
 }
 
+// Cached link previews older than this are fetched again
+const linkPreviewCacheTTL = 24 * time.Hour
+
 type PostsUsecase struct {
-	repository   repository.IPostsRepository
-	log          *logrus.Logger
-	googleBucket libs.IGoogleBucket
-	fs           libs.IFileSystem
+	repository          repository.IPostsRepository
+	log                 *logrus.Logger
+	googleBucket        libs.IGoogleBucket
+	fs                  libs.IFileSystem
+	linkPreviewFetcher  libs.ILinkPreviewFetcher
+	reportHideThreshold int
+	contentPolicy       libs.IContentPolicy
+	fanOutMaxFollowers  int
+	accountStates       libs.IAccountStateChecker
+	background          *sync.WaitGroup
 }
 
-func NewPostsUsecase(repository repository.IPostsRepository, log *logrus.Logger, googleBucket libs.IGoogleBucket, fs libs.IFileSystem) IPostsUsecase {
+func NewPostsUsecase(repository repository.IPostsRepository, log *logrus.Logger, googleBucket libs.IGoogleBucket, fs libs.IFileSystem, linkPreviewFetcher libs.ILinkPreviewFetcher, reportHideThreshold int, contentPolicy libs.IContentPolicy, fanOutMaxFollowers int, accountStates libs.IAccountStateChecker, background *sync.WaitGroup) IPostsUsecase {
 	return &PostsUsecase{
 		repository,
 		log,
 		googleBucket,
 		fs,
+		linkPreviewFetcher,
+		reportHideThreshold,
+		contentPolicy,
+		fanOutMaxFollowers,
+		accountStates,
+		background,
 	}
 }
 
-func (u *PostsUsecase) InsertReportedPost(userId int64, props *model.ReportPost) (resp model.Response) {
-	_, err := u.repository.InsertReportedPost(userId, props)
+func (u *PostsUsecase) ReportPost(userId, postId int64, props *model.Report) model.Response {
+	post, err := u.repository.GetPostById(postId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostById (post id: %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	props.TargetType = model.ReportTargetPost
+	props.TargetId = postId
+
+	resp := u.insertReport(userId, post.User.ID, props, "Success report post")
+	if resp.Status.Code != http.StatusOK {
+		return resp
+	}
+
+	// The report itself succeeded, so failing to auto hide is only logged
+	reporterCount, err := u.repository.CountOpenPostReporters(postId)
 	if err != nil {
-		resp.Status = libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured")
-		u.log.Errorf("repository.InsertReportedPost: %v", err)
-		return
+		u.log.Errorf("repository.CountOpenPostReporters (post id %d): %v", postId, err)
+	} else if reporterCount >= int64(u.reportHideThreshold) {
+		if err := u.repository.HidePostByReports(postId, reporterCount); err != nil {
+			u.log.Errorf("repository.HidePostByReports (post id %d): %v", postId, err)
+		}
 	}
 
-	resp.Status = libs.CustomResponse(http.StatusOK, "Success report post")
-	resp.Data = props
-	return
+	return resp
+}
+
+func (u *PostsUsecase) ReportPostComment(userId, postId, postCommentId int64, props *model.Report) model.Response {
+	comment, err := u.repository.GetPostCommentById(postCommentId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostCommentById (post comment id: %d): %v", postCommentId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if comment.PostID.Int64 != postId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	props.TargetType = model.ReportTargetPostComment
+	props.TargetId = postCommentId
+
+	return u.insertReport(userId, comment.UserID.Int64, props, "Success report post comment")
+}
+
+func (u *PostsUsecase) ReportPostCommentReply(userId, postId, postCommentId, postCommentReplyId int64, props *model.Report) model.Response {
+	reply, err := u.repository.GetPostCommentReplyById(postCommentReplyId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostCommentReplyById (post comment reply id: %d): %v", postCommentReplyId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if reply.PostCommentID.Int64 != postCommentId || reply.PostID.Int64 != postId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	props.TargetType = model.ReportTargetPostCommentReply
+	props.TargetId = postCommentReplyId
+
+	return u.insertReport(userId, reply.UserID.Int64, props, "Success report post comment reply")
+}
+
+func (u *PostsUsecase) ReportUser(userId, targetUserId int64, props *model.Report) model.Response {
+	_, err := u.repository.GetUserById(targetUserId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetUserById (user id: %d): %v", targetUserId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	props.TargetType = model.ReportTargetUser
+	props.TargetId = targetUserId
+
+	return u.insertReport(userId, targetUserId, props, "Success report user")
+}
+
+// insertReport stores the report once per user and target, ownerId is the user owning the reported target
+func (u *PostsUsecase) insertReport(userId, ownerId int64, props *model.Report, successMessage string) model.Response {
+	if userId == ownerId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Cannot report your own content"),
+		}
+	}
+
+	slices.Sort(props.Reason)
+	props.Reason = slices.Compact(props.Reason)
+
+	_, err := u.repository.InsertReport(userId, props)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Already reported"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.InsertReport (%s id: %d): %v", props.TargetType, props.TargetId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, successMessage),
+		Data:   props,
+	}
 }
 
 func (u *PostsUsecase) GetDetailPost(postId, userId int64) (resp model.Response) {
@@ -84,9 +243,13 @@ func (u *PostsUsecase) GetDetailPost(postId, userId int64) (resp model.Response)
 	return
 }
 
-func (u *PostsUsecase) GetPostComments(postId int64, pagination model.PaginationRequest) (resp model.Response) {
+func (u *PostsUsecase) GetPostComments(userId, postId int64, pagination model.PaginationRequest) (resp model.Response) {
+	if pagination.Page == 0 {
+		return u.getPostCommentsByCursor(userId, postId, pagination)
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
-	data, totalRows, err := u.repository.GetPostComments(postId, int32(offset), int32(pagination.Limit))
+	data, totalRows, err := u.repository.GetPostComments(userId, postId, int32(offset), int32(pagination.Limit))
 
 	if err != nil {
 		u.log.Errorf("repository.GetPostComments: %v", err)
@@ -120,9 +283,52 @@ func (u *PostsUsecase) GetPostComments(postId int64, pagination model.Pagination
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get post comments")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       postComments,
+	}
+	return
+}
+
+func (u *PostsUsecase) getPostCommentsByCursor(userId, postId int64, pagination model.PaginationRequest) (resp model.Response) {
+	data, next, err := u.repository.GetPostCommentsByCursor(userId, postId, pagination.Cursor, int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.GetPostCommentsByCursor: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	postComments := make([]model.PostComment, len(data))
+	for i, v := range data {
+		postComments[i] = model.PostComment{
+			ID:     v.ID,
+			PostId: v.PostID.Int64,
+			User: model.User{
+				ID:         v.ID_2.Int64,
+				AvatarUrl:  v.AvatarUrl.String,
+				Fullname:   v.FullName.String,
+				Bio:        v.Bio.String,
+				OpenToWork: v.OpenToWork.Bool,
+			},
+			Content:      v.Content.String,
+			ImageUrl:     v.ImageUrl.String,
+			LikeCount:    v.LikeCount.Int32,
+			ReplyCount:   v.ReplyCount.Int32,
+			IsPostAuthor: v.IsPostAuthor.Bool,
+			UpdatedAt:    v.UpdatedAt.Time,
+		}
+	}
+
+	paginate := model.PaginationResponse{
 		CurrentRowsCount: len(data),
+		NextCursor:       libs.EncodePageCursor(next),
 	}
 
 	resp.Status = libs.CustomResponse(http.StatusOK, "Success get post comments")
@@ -133,9 +339,9 @@ func (u *PostsUsecase) GetPostComments(postId int64, pagination model.Pagination
 	return
 }
 
-func (u *PostsUsecase) GetPostCommentReplies(postId, postCommentId int64, pagination model.PaginationRequest) (resp model.Response) {
+func (u *PostsUsecase) GetPostCommentReplies(userId, postId, postCommentId int64, pagination model.PaginationRequest) (resp model.Response) {
 	offset := (pagination.Page - 1) * pagination.Limit
-	data, totalRows, err := u.repository.GetPostCommentReplies(postId, postCommentId, int32(offset), int32(pagination.Limit))
+	data, totalRows, err := u.repository.GetPostCommentReplies(userId, postId, postCommentId, int32(offset), int32(pagination.Limit))
 
 	if err != nil {
 		u.log.Errorf("repository.GetPostCommentReplies: %v", err)
@@ -168,8 +374,8 @@ func (u *PostsUsecase) GetPostCommentReplies(postId, postCommentId int64, pagina
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -182,6 +388,14 @@ func (u *PostsUsecase) GetPostCommentReplies(postId, postCommentId int64, pagina
 }
 
 func (u *PostsUsecase) LikePost(userId, postId int64) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPost(userId, postId)); resp != nil {
+		return *resp
+	}
+
 	data, err := u.repository.LikePost(userId, postId)
 	if err != nil && err == sql.ErrNoRows {
 		return model.Response{
@@ -225,7 +439,70 @@ func (u *PostsUsecase) UnlikePost(userId, postId int64) model.Response {
 	}
 }
 
+func (u *PostsUsecase) ReactPost(userId, postId int64, props *model.ReactRequest) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPost(userId, postId)); resp != nil {
+		return *resp
+	}
+
+	data, err := u.repository.ReactPost(userId, postId, props.Type)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.ReactPost: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success react post"),
+		Data: map[string]any{
+			"id":            data.ID,
+			"like_count":    data.LikeCount.Int32,
+			"reaction_type": props.Type,
+		},
+	}
+}
+
+func (u *PostsUsecase) ListPostReactions(postId int64, reactionType string, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListPostReactions(postId, reactionType, int32(offset), int32(pagination.Limit))
+
+	if err != nil {
+		u.log.Errorf("repository.ListPostReactions (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get post reactions")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
 func (u *PostsUsecase) ListNewestPostsByTargetUser(userId, targetUserId int64, pagination model.PaginationRequest) (resp model.Response) {
+	if resp := u.blockedProfileResponse(userId, targetUserId); resp != nil {
+		return *resp
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 	data, totalRows, err := u.repository.ListNewestPostsByTargetUser(userId, targetUserId, int32(offset), int32(pagination.Limit))
 
@@ -240,8 +517,8 @@ func (u *PostsUsecase) ListNewestPostsByTargetUser(userId, targetUserId int64, p
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -254,6 +531,10 @@ func (u *PostsUsecase) ListNewestPostsByTargetUser(userId, targetUserId int64, p
 }
 
 func (u *PostsUsecase) ListLikedPostsByTargetUser(userId, targetUserId int64, pagination model.PaginationRequest) (resp model.Response) {
+	if resp := u.blockedProfileResponse(userId, targetUserId); resp != nil {
+		return *resp
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 	data, totalRows, err := u.repository.ListLikedPostsByTargetUser(userId, targetUserId, int32(offset), int32(pagination.Limit))
 
@@ -268,8 +549,8 @@ func (u *PostsUsecase) ListLikedPostsByTargetUser(userId, targetUserId int64, pa
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -282,6 +563,10 @@ func (u *PostsUsecase) ListLikedPostsByTargetUser(userId, targetUserId int64, pa
 }
 
 func (u *PostsUsecase) ListRepostedPostsByTargetUser(userId, targetUserId int64, pagination model.PaginationRequest) (resp model.Response) {
+	if resp := u.blockedProfileResponse(userId, targetUserId); resp != nil {
+		return *resp
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 	data, totalRows, err := u.repository.ListRepostedPostsByTargetUser(userId, targetUserId, int32(offset), int32(pagination.Limit))
 
@@ -296,8 +581,8 @@ func (u *PostsUsecase) ListRepostedPostsByTargetUser(userId, targetUserId int64,
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -310,6 +595,22 @@ func (u *PostsUsecase) ListRepostedPostsByTargetUser(userId, targetUserId int64,
 }
 
 func (u *PostsUsecase) InsertPost(props *model.CreatePostRequest) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, props.UserId); resp != nil {
+		return *resp
+	}
+
+	props.Content = libs.SanitizeMarkup(props.Content)
+
+	text := postPolicyText(props.Title, props.Content)
+	if props.Poll != nil {
+		text = strings.Join(append([]string{text}, props.Poll.Options...), "\n")
+	}
+
+	policyResult, resp := u.checkContent(props.UserId, model.ReportTargetPost, text, true)
+	if resp != nil {
+		return *resp
+	}
+
 	data, err := u.repository.InsertPost(props)
 
 	if err != nil {
@@ -320,13 +621,26 @@ func (u *PostsUsecase) InsertPost(props *model.CreatePostRequest) model.Response
 		}
 	}
 
+	message := "Success create post"
+	if u.holdContent(props.UserId, model.ReportTargetPost, data.ID, text, policyResult) {
+		message = "Success create post, it is held for review"
+	}
+
+	// Fetching the preview and copying the post to every follower may be slow, so both run in the background
+	u.runInBackground(func() { u.attachLinkPreview(data.ID, data.Content) })
+	u.runInBackground(func() { u.fanOutPost(data.ID, props.UserId) })
+
 	return model.Response{
-		Status: libs.CustomResponse(http.StatusCreated, "Success create post"),
+		Status: libs.CustomResponse(http.StatusCreated, message),
 		Data:   data,
 	}
 }
 
 func (u *PostsUsecase) UpdatePost(props *model.UpdatePostRequest) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, props.UserId); resp != nil {
+		return *resp
+	}
+
 	var (
 		err error
 	)
@@ -345,6 +659,14 @@ func (u *PostsUsecase) UpdatePost(props *model.UpdatePostRequest) model.Response
 		}
 	}
 
+	props.Content = libs.SanitizeMarkup(props.Content)
+
+	text := postPolicyText(props.Title, props.Content)
+	policyResult, resp := u.checkContent(props.UserId, model.ReportTargetPost, text, false)
+	if resp != nil {
+		return *resp
+	}
+
 	err = u.repository.UpdatePostById(props)
 	if err != nil {
 		u.log.Errorf("repository.UpdatePostById (user id: %d): %v", props.UserId, err)
@@ -354,8 +676,15 @@ func (u *PostsUsecase) UpdatePost(props *model.UpdatePostRequest) model.Response
 		}
 	}
 
+	message := "Success update post"
+	if u.holdContent(props.UserId, model.ReportTargetPost, props.ID, text, policyResult) {
+		message = "Success update post, it is held for review"
+	}
+
+	u.runInBackground(func() { u.attachLinkPreview(props.ID, props.Content) })
+
 	return model.Response{
-		Status: libs.CustomResponse(http.StatusOK, "Success update post"),
+		Status: libs.CustomResponse(http.StatusOK, message),
 		Data:   props,
 	}
 }
@@ -407,6 +736,14 @@ func (u *PostsUsecase) DeletePost(userId, postId int64) model.Response {
 }
 
 func (u *PostsUsecase) RepostPost(userId, postId int64) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPost(userId, postId)); resp != nil {
+		return *resp
+	}
+
 	post, err := u.repository.GetPostById(postId)
 	if err != nil && err == sql.ErrNoRows {
 		return model.Response{
@@ -603,6 +940,14 @@ func (u *PostsUsecase) UploadFileForUpdatePost(userId, postId int64, fileNames [
 }
 
 func (u *PostsUsecase) InsertPostComment(imageFileNames []string, props *model.AddPostCommentReq) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, props.UserId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPost(props.UserId, props.PostId)); resp != nil {
+		return *resp
+	}
+
 	post, err := u.repository.GetPostById(props.PostId)
 	if err != nil && err == sql.ErrNoRows {
 		return model.Response{
@@ -614,6 +959,11 @@ func (u *PostsUsecase) InsertPostComment(imageFileNames []string, props *model.A
 		props.IsPostAuthor = true
 	}
 
+	policyResult, resp := u.checkContent(props.UserId, model.ReportTargetPostComment, props.Content, true)
+	if resp != nil {
+		return *resp
+	}
+
 	if len(imageFileNames) > 0 {
 		objectPath := fmt.Sprintf("users/%d/posts/comments", props.UserId)
 
@@ -653,13 +1003,26 @@ func (u *PostsUsecase) InsertPostComment(imageFileNames []string, props *model.A
 		}
 	}
 
+	message := "Success create post comment"
+	if u.holdContent(props.UserId, model.ReportTargetPostComment, data.ID, props.Content, policyResult) {
+		message = "Success create post comment, it is held for review"
+	}
+
 	return model.Response{
-		Status: libs.CustomResponse(http.StatusCreated, "Success create post comment"),
+		Status: libs.CustomResponse(http.StatusCreated, message),
 		Data:   data,
 	}
 }
 
 func (u *PostsUsecase) LikePostComment(userId, postCommentId int64) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPostComment(userId, postCommentId)); resp != nil {
+		return *resp
+	}
+
 	data, err := u.repository.LikePostComment(userId, postCommentId)
 	if err != nil && err == sql.ErrNoRows {
 		return model.Response{
@@ -703,7 +1066,46 @@ func (u *PostsUsecase) UnlikePostComment(userId, postCommentId int64) model.Resp
 	}
 }
 
+func (u *PostsUsecase) ReactPostComment(userId, postCommentId int64, props *model.ReactRequest) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPostComment(userId, postCommentId)); resp != nil {
+		return *resp
+	}
+
+	data, err := u.repository.ReactPostComment(userId, postCommentId, props.Type)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.ReactPostComment: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success react post comment"),
+		Data: map[string]any{
+			"id":            data.ID,
+			"like_count":    data.LikeCount.Int32,
+			"reaction_type": props.Type,
+		},
+	}
+}
+
 func (u *PostsUsecase) InsertPostCommentReply(imageFileNames []string, postId int64, props *model.AddPostCommentReplyReq) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, props.UserId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPostComment(props.UserId, props.PostCommentId)); resp != nil {
+		return *resp
+	}
+
 	post, err := u.repository.GetPostById(postId)
 	if err != nil && err == sql.ErrNoRows {
 		return model.Response{
@@ -715,6 +1117,11 @@ func (u *PostsUsecase) InsertPostCommentReply(imageFileNames []string, postId in
 		props.IsPostAuthor = true
 	}
 
+	policyResult, resp := u.checkContent(props.UserId, model.ReportTargetPostCommentReply, props.Content, true)
+	if resp != nil {
+		return *resp
+	}
+
 	if len(imageFileNames) > 0 {
 		objectPath := fmt.Sprintf("users/%d/posts/comments/replies", props.UserId)
 
@@ -754,13 +1161,26 @@ func (u *PostsUsecase) InsertPostCommentReply(imageFileNames []string, postId in
 		}
 	}
 
+	message := "Success create post comment reply"
+	if u.holdContent(props.UserId, model.ReportTargetPostCommentReply, data.ID, props.Content, policyResult) {
+		message = "Success create post comment reply, it is held for review"
+	}
+
 	return model.Response{
-		Status: libs.CustomResponse(http.StatusCreated, "Success create post comment reply"),
+		Status: libs.CustomResponse(http.StatusCreated, message),
 		Data:   data,
 	}
 }
 
 func (u *PostsUsecase) LikePostCommentReply(userId, postCommentReplyId int64) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPostCommentReply(userId, postCommentReplyId)); resp != nil {
+		return *resp
+	}
+
 	data, err := u.repository.LikePostCommentReply(userId, postCommentReplyId)
 	if err != nil && err == sql.ErrNoRows {
 		return model.Response{
@@ -803,3 +1223,643 @@ func (u *PostsUsecase) UnlikePostCommentReply(userId, postCommentReplyId int64)
 		},
 	}
 }
+
+func (u *PostsUsecase) ReactPostCommentReply(userId, postCommentReplyId int64, props *model.ReactRequest) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	if resp := u.blockedResponse(u.repository.IsBlockedFromPostCommentReply(userId, postCommentReplyId)); resp != nil {
+		return *resp
+	}
+
+	data, err := u.repository.ReactPostCommentReply(userId, postCommentReplyId, props.Type)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.ReactPostCommentReply: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success react post comment reply"),
+		Data: map[string]any{
+			"id":            data.ID,
+			"like_count":    data.LikeCount.Int32,
+			"reaction_type": props.Type,
+		},
+	}
+}
+
+func (u *PostsUsecase) UpdatePostComment(props *model.UpdatePostCommentReq) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, props.UserId); resp != nil {
+		return *resp
+	}
+
+	currentComment, err := u.repository.GetPostCommentById(props.ID)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostCommentById (post comment id: %d): %v", props.ID, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if currentComment.PostID.Int64 != props.PostId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	if currentComment.UserID.Int64 != props.UserId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusUnauthorized, "Unauthorized"),
+		}
+	}
+
+	policyResult, resp := u.checkContent(props.UserId, model.ReportTargetPostComment, props.Content, false)
+	if resp != nil {
+		return *resp
+	}
+
+	data, err := u.repository.UpdatePostComment(props)
+	if err != nil {
+		u.log.Errorf("repository.UpdatePostComment (user id %d): %v", props.UserId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	message := "Success update post comment"
+	if u.holdContent(props.UserId, model.ReportTargetPostComment, props.ID, props.Content, policyResult) {
+		message = "Success update post comment, it is held for review"
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, message),
+		Data:   data,
+	}
+}
+
+// DeletePostComment can be done by the comment author or the post author
+func (u *PostsUsecase) DeletePostComment(userId, postId, postCommentId int64) model.Response {
+	currentComment, err := u.repository.GetPostCommentById(postCommentId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostCommentById (post comment id: %d): %v", postCommentId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if currentComment.PostID.Int64 != postId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	if currentComment.UserID.Int64 != userId && currentComment.PostUserID.Int64 != userId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusUnauthorized, "Unauthorized"),
+		}
+	}
+
+	// Get the comment and reply image urls before they are deleted
+	objectUrls, err := u.repository.GetPostCommentReplyImageUrls(postCommentId)
+	if err != nil {
+		u.log.Errorf("repository.GetPostCommentReplyImageUrls (post comment id: %d): %v", postCommentId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if currentComment.ImageUrl.String != "" {
+		objectUrls = append(objectUrls, currentComment.ImageUrl.String)
+	}
+
+	err = u.repository.DeletePostComment(postId, postCommentId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.DeletePostComment (post comment id: %d): %v", postCommentId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if len(objectUrls) > 0 {
+		if err := u.googleBucket.HandleObjectDeletion(objectUrls...); err != nil {
+			u.log.Errorf("googleBucket.HandleObjectDeletion (user id: %d): %v", userId, err)
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success delete post comment"),
+	}
+}
+
+func (u *PostsUsecase) UpdatePostCommentReply(props *model.UpdatePostCommentReplyReq) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, props.UserId); resp != nil {
+		return *resp
+	}
+
+	currentReply, err := u.repository.GetPostCommentReplyById(props.ID)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostCommentReplyById (post comment reply id: %d): %v", props.ID, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if currentReply.PostCommentID.Int64 != props.PostCommentId || currentReply.PostID.Int64 != props.PostId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	if currentReply.UserID.Int64 != props.UserId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusUnauthorized, "Unauthorized"),
+		}
+	}
+
+	policyResult, resp := u.checkContent(props.UserId, model.ReportTargetPostCommentReply, props.Content, false)
+	if resp != nil {
+		return *resp
+	}
+
+	data, err := u.repository.UpdatePostCommentReply(props)
+	if err != nil {
+		u.log.Errorf("repository.UpdatePostCommentReply (user id %d): %v", props.UserId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	message := "Success update post comment reply"
+	if u.holdContent(props.UserId, model.ReportTargetPostCommentReply, props.ID, props.Content, policyResult) {
+		message = "Success update post comment reply, it is held for review"
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, message),
+		Data:   data,
+	}
+}
+
+// DeletePostCommentReply can be done by the reply author or the post author
+func (u *PostsUsecase) DeletePostCommentReply(userId, postId, postCommentId, postCommentReplyId int64) model.Response {
+	currentReply, err := u.repository.GetPostCommentReplyById(postCommentReplyId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostCommentReplyById (post comment reply id: %d): %v", postCommentReplyId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if currentReply.PostCommentID.Int64 != postCommentId || currentReply.PostID.Int64 != postId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	if currentReply.UserID.Int64 != userId && currentReply.PostUserID.Int64 != userId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusUnauthorized, "Unauthorized"),
+		}
+	}
+
+	err = u.repository.DeletePostCommentReply(postCommentId, postCommentReplyId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.DeletePostCommentReply (post comment reply id: %d): %v", postCommentReplyId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if currentReply.ImageUrl.String != "" {
+		if err := u.googleBucket.HandleObjectDeletion(currentReply.ImageUrl.String); err != nil {
+			u.log.Errorf("googleBucket.HandleObjectDeletion (user id: %d): %v", userId, err)
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success delete post comment reply"),
+	}
+}
+
+func (u *PostsUsecase) VotePoll(userId, postId int64, props *model.VotePollRequest) model.Response {
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	err := u.repository.VotePoll(userId, postId, props.OptionIds)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	case errors.Is(err, repository.ErrPollClosed):
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Poll has ended"),
+		}
+	case errors.Is(err, repository.ErrPollInvalidOption):
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Invalid poll option"),
+		}
+	case errors.Is(err, repository.ErrPollAlreadyVoted):
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Poll already voted"),
+		}
+	case err != nil:
+		u.log.Errorf("repository.VotePoll (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	// Results are visible once the user has voted
+	post, err := u.repository.GetDetailPost(postId, userId)
+	if err != nil {
+		u.log.Errorf("repository.GetDetailPost (post id %d): %v", postId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success vote poll"),
+		Data:   post.Poll,
+	}
+}
+
+func (u *PostsUsecase) BookmarkPost(userId, postId int64, props *model.BookmarkPostRequest) model.Response {
+	_, err := u.repository.GetPostById(postId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostById: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if props.BookmarkFolderId > 0 {
+		_, err := u.repository.GetBookmarkFolderById(userId, props.BookmarkFolderId)
+		if err != nil && err == sql.ErrNoRows {
+			return model.Response{
+				Status: libs.CustomResponse(http.StatusNotFound, "Bookmark folder not found"),
+			}
+		} else if err != nil {
+			u.log.Errorf("repository.GetBookmarkFolderById: %v", err)
+			return model.Response{
+				Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+			}
+		}
+	}
+
+	if err := u.repository.BookmarkPost(userId, postId, props.BookmarkFolderId); err != nil {
+		u.log.Errorf("repository.BookmarkPost: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success bookmark post"),
+		Data: map[string]any{
+			"id":                 postId,
+			"bookmark_folder_id": props.BookmarkFolderId,
+		},
+	}
+}
+
+func (u *PostsUsecase) UnbookmarkPost(userId, postId int64) model.Response {
+	err := u.repository.UnbookmarkPost(userId, postId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.UnbookmarkPost: %v", err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success unbookmark post"),
+		Data: map[string]any{
+			"id": postId,
+		},
+	}
+}
+
+func (u *PostsUsecase) ListBookmarkedPosts(userId, bookmarkFolderId int64, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.ListBookmarkedPosts(userId, bookmarkFolderId, int32(offset), int32(pagination.Limit))
+
+	if err != nil {
+		u.log.Errorf("repository.ListBookmarkedPosts (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success get bookmarked posts")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+func (u *PostsUsecase) InsertBookmarkFolder(userId int64, props *model.CreateBookmarkFolderRequest) model.Response {
+	data, err := u.repository.InsertBookmarkFolder(userId, props.Name)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Bookmark folder already exists"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.InsertBookmarkFolder (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusCreated, "Success create bookmark folder"),
+		Data:   data,
+	}
+}
+
+func (u *PostsUsecase) ListBookmarkFolders(userId int64) model.Response {
+	data, err := u.repository.ListBookmarkFolders(userId)
+	if err != nil {
+		u.log.Errorf("repository.ListBookmarkFolders (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success get bookmark folders"),
+		Data:   data,
+	}
+}
+
+func (u *PostsUsecase) DeleteBookmarkFolder(userId, bookmarkFolderId int64) model.Response {
+	err := u.repository.DeleteBookmarkFolder(userId, bookmarkFolderId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.DeleteBookmarkFolder (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success delete bookmark folder"),
+	}
+}
+
+// attachLinkPreview links the post to a preview of the first link in its content,
+// reusing the cached preview when it is still fresh
+// runInBackground runs job outside the request, the server waits for it before shutting down
+func (u *PostsUsecase) runInBackground(job func()) {
+	u.background.Add(1)
+	go func() {
+		defer u.background.Done()
+		job()
+	}()
+}
+
+func (u *PostsUsecase) attachLinkPreview(postId int64, content string) {
+	link := libs.ExtractFirstLink(content)
+	if link == "" {
+		if err := u.repository.DeletePostLinkPreview(postId); err != nil {
+			u.log.Errorf("repository.DeletePostLinkPreview (post id: %d): %v", postId, err)
+		}
+		return
+	}
+
+	cachedPreview, err := u.repository.GetLinkPreviewByUrl(link)
+	if err != nil && err != sql.ErrNoRows {
+		u.log.Errorf("repository.GetLinkPreviewByUrl (post id: %d): %v", postId, err)
+		return
+	}
+
+	isCached := err == nil
+	if isCached && time.Since(cachedPreview.FetchedAt) < linkPreviewCacheTTL {
+		if err := u.repository.AttachPostLinkPreview(postId, cachedPreview.ID); err != nil {
+			u.log.Errorf("repository.AttachPostLinkPreview (post id: %d): %v", postId, err)
+		}
+		return
+	}
+
+	preview, err := u.linkPreviewFetcher.Fetch(link)
+	if err != nil {
+		u.log.Warnf("linkPreviewFetcher.Fetch (post id: %d): %v", postId, err)
+
+		// Fall back to the stale preview rather than showing nothing
+		if isCached {
+			if err := u.repository.AttachPostLinkPreview(postId, cachedPreview.ID); err != nil {
+				u.log.Errorf("repository.AttachPostLinkPreview (post id: %d): %v", postId, err)
+			}
+		}
+		return
+	}
+
+	if err := u.repository.SavePostLinkPreview(postId, &preview); err != nil {
+		u.log.Errorf("repository.SavePostLinkPreview (post id: %d): %v", postId, err)
+	}
+}
+
+// fanOutPost adds the saved post to the followers' timelines, a failure only leaves it out of them
+func (u *PostsUsecase) fanOutPost(postId, authorId int64) {
+	if err := u.repository.FanOutPost(postId, authorId, int32(u.fanOutMaxFollowers)); err != nil {
+		u.log.Errorf("repository.FanOutPost (post id: %d): %v", postId, err)
+	}
+}
+
+// checkContent runs the content policy and returns a response when the content must not be stored.
+// Rejected content is flagged right away so moderators can review it
+func (u *PostsUsecase) checkContent(userId int64, targetType, text string, isNew bool) (model.ContentPolicyResult, *model.Response) {
+	input := model.ContentPolicyInput{
+		UserId: userId,
+		Text:   text,
+		Now:    time.Now().UTC(),
+	}
+
+	// Edits do not count towards the velocity rules
+	if lookback := u.contentPolicy.Lookback(); isNew && lookback > 0 {
+		activity, err := u.repository.ListUserActivitySince(userId, input.Now.Add(-lookback))
+		if err != nil {
+			u.log.Errorf("repository.ListUserActivitySince (user id: %d): %v", userId, err)
+			return model.ContentPolicyResult{}, &model.Response{
+				Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+			}
+		}
+
+		input.RecentActivity = activity
+	}
+
+	result := u.contentPolicy.Evaluate(input)
+	if result.Verdict != model.ContentVerdictReject {
+		return result, nil
+	}
+
+	if err := u.repository.FlagContent(userId, targetType, 0, text, result); err != nil {
+		u.log.Errorf("repository.FlagContent (user id: %d): %v", userId, err)
+	}
+
+	return result, &model.Response{
+		Status: libs.CustomResponse(http.StatusUnprocessableEntity, "Content violates community guidelines"),
+		Data: map[string]any{
+			"rules": result.Rules,
+		},
+	}
+}
+
+// holdContent flags the stored content for review when the policy held it and reports whether it did
+func (u *PostsUsecase) holdContent(userId int64, targetType string, targetId int64, text string, result model.ContentPolicyResult) bool {
+	if result.Verdict != model.ContentVerdictHold {
+		return false
+	}
+
+	if err := u.repository.FlagContent(userId, targetType, targetId, text, result); err != nil {
+		u.log.Errorf("repository.FlagContent (%s id: %d): %v", targetType, targetId, err)
+	}
+
+	return true
+}
+
+func postPolicyText(title, content string) string {
+	return title + "\n" + content
+}
+
+// blockedResponse returns the response to send back when the block lookup failed
+// or a block between the users forbids the interaction
+func (u *PostsUsecase) blockedResponse(blocked bool, err error) *model.Response {
+	if err != nil {
+		u.log.Errorf("repository.IsBlocked: %v", err)
+		return &model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if blocked {
+		return &model.Response{
+			Status: libs.CustomResponse(http.StatusForbidden, "Forbidden"),
+		}
+	}
+
+	return nil
+}
+
+// blockedProfileResponse hides the target user's lists when either user blocked the other
+func (u *PostsUsecase) blockedProfileResponse(userId, targetUserId int64) *model.Response {
+	blocked, err := u.repository.IsBlockedBetween(userId, targetUserId)
+	if err != nil {
+		u.log.Errorf("repository.IsBlockedBetween: %v", err)
+		return &model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if blocked {
+		return &model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	}
+
+	return nil
+}
+
+func (u *PostsUsecase) RecordPostImpressions(userId int64, props *model.PostImpressionsRequest) model.Response {
+	if err := u.repository.InsertPostImpressions(userId, props.PostIds); err != nil {
+		u.log.Errorf("repository.InsertPostImpressions (user id %d): %v", userId, err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success record post impressions"),
+	}
+}
+
+// GetPostViewCounts is only available to the author of the post
+func (u *PostsUsecase) GetPostViewCounts(userId, postId int64) model.Response {
+	post, err := u.repository.GetPostById(postId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetPostById (post id: %d): %v", postId, err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if post.User.ID != userId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusUnauthorized, "Unauthorized"),
+		}
+	}
+
+	data, err := u.repository.GetPostViewCounts(postId)
+	if err != nil {
+		u.log.Errorf("repository.GetPostViewCounts (post id: %d): %v", postId, err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success get post view counts"),
+		Data:   data,
+	}
+}
@@ -27,15 +27,17 @@ INSERT INTO educations (
 RETURNING *;
 
 -- name: InsertCompany :one
-INSERT INTO companies (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
+INSERT INTO companies (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
 RETURNING *;
 
--- name: InsertSchool :one 
-INSERT INTO schools (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
+-- name: InsertSchool :one
+INSERT INTO schools (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
 RETURNING *;
 
 -- name: InsertCertificate :one
@@ -47,9 +49,10 @@ INSERT INTO certificates (
 RETURNING *;
 
 -- name: InsertIssuingOrganization :one
-INSERT INTO issuing_organizations (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
+INSERT INTO issuing_organizations (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
 RETURNING *;
 
 -- name: GetUserAbout :one
@@ -72,10 +75,29 @@ ON user_skills.skill_id = skills.id
 WHERE user_skills.main_skill = TRUE AND users.id = $1;
 
 -- name: BatchInsertSkills :exec
-INSERT INTO skills (name)
-SELECT unnest(@names::text[])
+-- Names matching a skill or a synonym resolve to that skill instead
+INSERT INTO skills (name, normalized_name)
+SELECT DISTINCT ON (n.normalized_name) n.name, n.normalized_name
+FROM unnest(@names::text[], @normalized_names::text[]) AS n(name, normalized_name)
+WHERE NOT EXISTS (SELECT 1 FROM skills s WHERE s.normalized_name = n.normalized_name)
+  AND NOT EXISTS (SELECT 1 FROM catalog_aliases ca WHERE ca.catalog = 'skills' AND ca.normalized_name = n.normalized_name)
+ORDER BY n.normalized_name
 ON CONFLICT (name) DO NOTHING;
 
+-- name: GetSkillIdsByNormalizedNames :many
+-- Synonyms win over skills sharing the name, then the oldest skill
+SELECT DISTINCT ON (k.normalized_name) k.skill_id
+FROM (
+    SELECT ca.normalized_name, ca.item_id AS skill_id, 0 AS rank
+    FROM catalog_aliases ca
+    WHERE ca.catalog = 'skills' AND ca.normalized_name = ANY(@normalized_names::text[])
+    UNION ALL
+    SELECT s.normalized_name, s.id, 1
+    FROM skills s
+    WHERE s.normalized_name = ANY(@normalized_names::text[])
+) k
+ORDER BY k.normalized_name, k.rank, k.skill_id;
+
 -- name: UpdateUserMainSkillToFalse :exec
 UPDATE user_skills 
 SET main_skill = false 
@@ -83,45 +105,23 @@ WHERE user_id = @user_id::bigint
 AND main_skill = true;
 
 -- name: BatchInsertUserMainSkills :many
--- start get skills id 
-WITH exist_skills AS (
-    SELECT id, name
-    FROM skills
-    WHERE name = ANY(@names::text[])
-)
--- end get skills id 
--- start insert user skills if not exist
 INSERT INTO user_skills (user_id, skill_id, main_skill)
 SELECT
     @user_id::bigint,
-    es.id,
+    unnest(@skill_ids::bigint[]),
     @is_main_skill::boolean
-FROM exist_skills es
-WHERE es.name = ANY(@names::text[])
 ON CONFLICT (user_id, skill_id) DO UPDATE
 SET main_skill = true
 RETURNING id;
--- end insert user skills if not exist
 
 -- name: BatchInsertUserSkills :many
--- start get skills id 
-WITH exist_skills AS (
-    SELECT id, name
-    FROM skills
-    WHERE name = ANY(@names::text[])
-)
--- end get skills id 
--- start insert user skills if not exist
 INSERT INTO user_skills (user_id, skill_id, main_skill)
 SELECT
     @user_id::bigint,
-    es.id,
+    unnest(@skill_ids::bigint[]),
     @is_main_skill::boolean
-FROM exist_skills es
-WHERE es.name = ANY(@names::text[])
 ON CONFLICT (user_id, skill_id) DO NOTHING
 RETURNING id;
--- end insert user skills if not exist
 
 -- name: UpdateUser :one
 UPDATE users
@@ -273,10 +273,9 @@ WHERE education_id = @education_id::bigint;
 SELECT url FROM work_experience_files
 WHERE work_experience_id = @work_experience_id::bigint;
 
--- name: GetUserSkillIDsByName :many
+-- name: GetUserSkillIDsBySkillIds :many
 SELECT us.id FROM user_skills us
-JOIN skills s ON us.skill_id = s.id
-WHERE s.name = ANY(@name::text[]);
+WHERE us.user_id = @user_id::bigint AND us.skill_id = ANY(@skill_ids::bigint[]);
 
 -- name: GetUserProfile :one
 SELECT 
@@ -290,6 +289,16 @@ FROM users u
 LEFT JOIN user_details ud ON u.id = ud.user_id
 LEFT JOIN followings f ON u.id = f.follow_user_id AND f.user_id = @user_id::bigint
 WHERE u.id = @target_user_id::bigint
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks ub
+        WHERE (ub.user_id = @user_id::bigint AND ub.blocked_user_id = u.id)
+            OR (ub.user_id = u.id AND ub.blocked_user_id = @user_id::bigint)
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_account_states uas
+        WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned')
+            AND (uas.expires_at IS NULL OR uas.expires_at > NOW())
+    )
 LIMIT 1;
 
 -- name: GetUserSocialLinks :many
@@ -361,6 +370,15 @@ WHERE f.user_id = @user_id::bigint
 OFFSET $1
 LIMIT $2;
 
+-- name: GetFollowedUsersByCursor :many
+SELECT 
+  f.id AS following_id, u.id, u.full_name ,u.avatar_url ,u.bio ,u.open_to_work
+FROM followings f 
+LEFT JOIN users u ON f.follow_user_id = u.id 
+WHERE f.user_id = @user_id::bigint AND (@cursor_id::bigint = 0 OR f.id < @cursor_id::bigint)
+ORDER BY f.id DESC
+LIMIT $1;
+
 -- name: UpdateUserOpenToWork :one
 UPDATE users
 SET open_to_work = @open_to_work::boolean,
@@ -369,9 +387,10 @@ WHERE id = @user_id::bigint
 RETURNING id;
 
 -- name: InsertJobPosition :one
-INSERT INTO job_positions (name)
-VALUES ($1)
-ON CONFLICT (name) DO NOTHING
+INSERT INTO job_positions (name, normalized_name)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET normalized_name = EXCLUDED.normalized_name
 RETURNING *;
 
 -- name: BatchInsertUserJobInterests :exec
@@ -439,4 +458,101 @@ RETURNING *;
 -- name: DeleteFollowings :one
 DELETE FROM followings
 WHERE user_id = @user_id::bigint AND follow_user_id = @follow_user_id::bigint
-RETURNING id;
\ No newline at end of file
+RETURNING id;
+
+-- name: BackfillTimeline :exec
+-- Only the newest posts of the author are copied, older ones stay on the author's profile
+INSERT INTO timeline_entries
+(user_id, post_id, author_id, created_at)
+SELECT @user_id::bigint, p.id, p.user_id, COALESCE(p.created_at, NOW())
+FROM posts p
+WHERE p.user_id = @author_id::bigint AND NOT EXISTS (SELECT 1 FROM timeline_pull_posts tp WHERE tp.post_id = p.id)
+ORDER BY p.created_at DESC NULLS LAST, p.id DESC
+LIMIT @row_limit::int
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteTimelineEntriesByAuthor :exec
+DELETE FROM timeline_entries
+WHERE user_id = @user_id::bigint AND author_id = @author_id::bigint;
+
+-- name: CountUserBlocksBetween :one
+SELECT COUNT(*) AS count
+FROM user_blocks
+WHERE (user_id = @user_id::bigint AND blocked_user_id = @target_user_id::bigint)
+    OR (user_id = @target_user_id::bigint AND blocked_user_id = @user_id::bigint);
+
+-- name: InsertUserBlock :one
+INSERT INTO user_blocks (user_id, blocked_user_id, created_at)
+VALUES (@user_id::bigint, @blocked_user_id::bigint, NOW())
+ON CONFLICT (user_id, blocked_user_id) DO NOTHING
+RETURNING *;
+
+-- name: DeleteUserBlock :one
+DELETE FROM user_blocks
+WHERE user_id = @user_id::bigint AND blocked_user_id = @blocked_user_id::bigint
+RETURNING id;
+
+-- name: ListBlockedUsers :many
+SELECT 
+  u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+  COUNT(*) OVER () AS total_rows
+FROM user_blocks ub
+JOIN users u ON ub.blocked_user_id = u.id
+WHERE ub.user_id = @user_id::bigint
+ORDER BY ub.created_at DESC
+OFFSET $1
+LIMIT $2;
+
+-- name: InsertUserMute :one
+INSERT INTO user_mutes (user_id, muted_user_id, created_at)
+VALUES (@user_id::bigint, @muted_user_id::bigint, NOW())
+ON CONFLICT (user_id, muted_user_id) DO NOTHING
+RETURNING *;
+
+-- name: DeleteUserMute :one
+DELETE FROM user_mutes
+WHERE user_id = @user_id::bigint AND muted_user_id = @muted_user_id::bigint
+RETURNING id;
+
+-- name: ListMutedUsers :many
+SELECT 
+  u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work,
+  COUNT(*) OVER () AS total_rows
+FROM user_mutes um
+JOIN users u ON um.muted_user_id = u.id
+WHERE um.user_id = @user_id::bigint
+ORDER BY um.created_at DESC
+OFFSET $1
+LIMIT $2;
+
+-- name: GetCompanyByNormalizedName :one
+SELECT c.*
+FROM companies c
+WHERE c.normalized_name = @normalized_name::text
+  OR c.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'companies' AND ca.normalized_name = @normalized_name::text)
+ORDER BY c.id
+LIMIT 1;
+
+-- name: GetSchoolByNormalizedName :one
+SELECT s.*
+FROM schools s
+WHERE s.normalized_name = @normalized_name::text
+  OR s.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'schools' AND ca.normalized_name = @normalized_name::text)
+ORDER BY s.id
+LIMIT 1;
+
+-- name: GetIssuingOrganizationByNormalizedName :one
+SELECT io.*
+FROM issuing_organizations io
+WHERE io.normalized_name = @normalized_name::text
+  OR io.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'issuing-organizations' AND ca.normalized_name = @normalized_name::text)
+ORDER BY io.id
+LIMIT 1;
+
+-- name: GetJobPositionByNormalizedName :one
+SELECT jp.*
+FROM job_positions jp
+WHERE jp.normalized_name = @normalized_name::text
+  OR jp.id IN (SELECT ca.item_id FROM catalog_aliases ca WHERE ca.catalog = 'job-positions' AND ca.normalized_name = @normalized_name::text)
+ORDER BY jp.id
+LIMIT 1;
\ No newline at end of file
@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"fmt"
 	db "profiln-be/db/sqlc"
+	"profiln-be/libs"
 	"profiln-be/model"
 	"strings"
 	"sync"
 	"time"
 )
 
+// Posts of a newly followed user copied to the follower's timeline
+const timelineBackfillLimit = 50
+
 type IProfileRepository interface {
 	InsertUserPersonalData(props *model.AddProfileRequest) error
 	InsertUserCertificate(props *model.Certificate) (model.Certificate, error)
@@ -35,12 +39,20 @@ type IProfileRepository interface {
 	GetEducationsByUserId(userId int64, offset, limit int32) ([]model.Education, int64, error)
 	GetCertificatesByUserId(userId int64, offset, limit int32) ([]model.Certificate, int64, error)
 	GetFollowedUsersByUserId(userId int64, offset, limit int32) ([]model.User, int64, error)
+	GetFollowedUsersByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.User, *model.PageCursor, error)
 	DeleteUserOpenToWork(userId int64) error
 	DeleteUserWorkExperienceById(userId, workExperienceId int64) error
 	DeleteUserEducationById(userId, educationId int64) error
 	DeleteUserCertificateById(userId, certificateId int64) error
 	FollowUser(userId, targetUserId int64) error
 	UnfollowUser(userId, targetUserId int64) error
+	IsBlockedBetween(userId, targetUserId int64) (bool, error)
+	BlockUser(userId, targetUserId int64) error
+	UnblockUser(userId, targetUserId int64) error
+	ListBlockedUsers(userId int64, offset, limit int32) ([]model.User, int64, error)
+	MuteUser(userId, targetUserId int64) error
+	UnmuteUser(userId, targetUserId int64) error
+	ListMutedUsers(userId int64, offset, limit int32) ([]model.User, int64, error)
 	GetUserByEmail(email string) (model.User, error)
 	BatchInsertUserSkills(userId int64, skills []string) error
 }
@@ -84,7 +96,7 @@ func (r *ProfileRepository) InsertUserWorkExperience(props *model.WorkExperience
 
 	// If the company doesn't exist, insert the company first
 	if props.Company.ID < 1 {
-		company, err := qtx.InsertCompany(ctx, props.Company.Name)
+		company, err := findOrInsertCompany(ctx, qtx, props.Company.Name)
 		if err != nil && err != sql.ErrNoRows {
 			return model.WorkExperience{}, fmt.Errorf("could not insert company: %w", err)
 		}
@@ -169,7 +181,7 @@ func (r *ProfileRepository) InsertUserCertificate(props *model.Certificate) (mod
 
 	if props.IssuingOrganization.ID < 1 {
 		createdIssuingOrganization, err :=
-			qtx.InsertIssuingOrganization(ctx, props.IssuingOrganization.Name)
+			findOrInsertIssuingOrganization(ctx, qtx, props.IssuingOrganization.Name)
 
 		if err != nil && err != sql.ErrNoRows {
 			return model.Certificate{}, fmt.Errorf("could not insert issuing organization: %w", err)
@@ -265,8 +277,9 @@ func (r *ProfileRepository) UpdateProfile(avatar_url string, props *model.Update
 	}
 
 	// insert to skills table (if not exist)
-	if err := qtx.BatchInsertSkills(ctx, props.MainSkills); err != nil {
-		return fmt.Errorf("could not batch insert skills: %w", err)
+	skillIds, err := resolveSkillIds(ctx, qtx, props.MainSkills)
+	if err != nil {
+		return err
 	}
 
 	// change all main skills to false
@@ -276,8 +289,9 @@ func (r *ProfileRepository) UpdateProfile(avatar_url string, props *model.Update
 
 	// insert user main skills
 	_, err = qtx.BatchInsertUserMainSkills(ctx, db.BatchInsertUserMainSkillsParams{
-		UserID: props.UserId,
-		Names:  props.MainSkills,
+		UserID:      props.UserId,
+		SkillIds:    skillIds,
+		IsMainSkill: true,
 	})
 	if err != nil {
 		return fmt.Errorf("could not batch insert user skills: %w", err)
@@ -333,7 +347,7 @@ func (r *ProfileRepository) UpdateUserCertificate(userId int64, props *model.Cer
 
 	if props.IssuingOrganization.ID < 1 {
 		createdIssuingOrganization, err :=
-			qtx.InsertIssuingOrganization(ctx, props.IssuingOrganization.Name)
+			findOrInsertIssuingOrganization(ctx, qtx, props.IssuingOrganization.Name)
 
 		if err != nil {
 			return err
@@ -471,7 +485,7 @@ func (r *ProfileRepository) UpdateUserEducation(props *model.Education) error {
 
 	// If the school doesn't exist, insert the school first
 	if props.School.ID < 1 {
-		school, err := qtx.InsertSchool(ctx, props.School.Name)
+		school, err := findOrInsertSchool(ctx, qtx, props.School.Name)
 		if err != nil && err != sql.ErrNoRows {
 			return fmt.Errorf("could not insert school: %w", err)
 		}
@@ -607,7 +621,7 @@ func (r *ProfileRepository) UpdateUserWorkExperience(props *model.WorkExperience
 
 	// If the company doesn't exist, insert the company first
 	if props.Company.ID < 1 {
-		company, err := qtx.InsertCompany(ctx, props.Company.Name)
+		company, err := findOrInsertCompany(ctx, qtx, props.Company.Name)
 		if err != nil && err != sql.ErrNoRows {
 			return fmt.Errorf("could not insert company: %w", err)
 		}
@@ -948,6 +962,42 @@ func (r *ProfileRepository) GetFollowedUsersByUserId(userId int64, offset, limit
 	return data, count, nil
 }
 
+func (r *ProfileRepository) GetFollowedUsersByCursor(userId int64, cursor *model.PageCursor, limit int32) ([]model.User, *model.PageCursor, error) {
+	arg := db.GetFollowedUsersByCursorParams{
+		Limit:  limit + 1,
+		UserID: userId,
+	}
+
+	if cursor != nil {
+		arg.CursorID = cursor.ID
+	}
+
+	followedUsers, err := r.query.GetFollowedUsersByCursor(context.Background(), arg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the extra row only tells whether there is a next page
+	var next *model.PageCursor
+	if len(followedUsers) > int(limit) {
+		followedUsers = followedUsers[:limit]
+		next = &model.PageCursor{ID: followedUsers[limit-1].FollowingID}
+	}
+
+	data := make([]model.User, len(followedUsers))
+	for i, followedUser := range followedUsers {
+		data[i] = model.User{
+			ID:         followedUser.ID.Int64,
+			Fullname:   followedUser.FullName.String,
+			AvatarUrl:  followedUser.AvatarUrl.String,
+			Bio:        followedUser.Bio.String,
+			OpenToWork: followedUser.OpenToWork.Bool,
+		}
+	}
+
+	return data, next, nil
+}
+
 func (r *ProfileRepository) AddUserOpenToWork(props *model.OpenToWork) error {
 	ctx := context.Background()
 	tx, err := r.dbConn.Begin()
@@ -974,7 +1024,7 @@ func (r *ProfileRepository) AddUserOpenToWork(props *model.OpenToWork) error {
 	var jobPositionIds []int64
 	for i, jobPosition := range props.JobPositions {
 		if jobPosition.ID < 1 {
-			createdJobPosition, err := qtx.InsertJobPosition(ctx, sql.NullString{String: jobPosition.Name, Valid: true})
+			createdJobPosition, err := findOrInsertJobPosition(ctx, qtx, jobPosition.Name)
 			if err != nil && err != sql.ErrNoRows {
 				return fmt.Errorf("could not insert job position: %w", err)
 			}
@@ -1152,6 +1202,15 @@ func (r *ProfileRepository) FollowUser(userId, targetUserId int64) error {
 		return fmt.Errorf("could not insert followings: %w", err)
 	}
 
+	err = qtx.BackfillTimeline(ctx, db.BackfillTimelineParams{
+		UserID:   userId,
+		AuthorID: targetUserId,
+		RowLimit: timelineBackfillLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("could not backfill timeline: %w", err)
+	}
+
 	_, err = qtx.UpdateUserFollowingsCount(ctx, db.UpdateUserFollowingsCountParams{
 		UserID: userId,
 		Value:  1,
@@ -1204,6 +1263,14 @@ func (r *ProfileRepository) UnfollowUser(userId, targetUserId int64) error {
 		return fmt.Errorf("could not delete followings: %w", err)
 	}
 
+	err = qtx.DeleteTimelineEntriesByAuthor(ctx, db.DeleteTimelineEntriesByAuthorParams{
+		UserID:   userId,
+		AuthorID: targetUserId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete timeline entries: %w", err)
+	}
+
 	_, err = qtx.UpdateUserFollowingsCount(ctx, db.UpdateUserFollowingsCountParams{
 		UserID: userId,
 		Value:  -1,
@@ -1231,6 +1298,188 @@ func (r *ProfileRepository) UnfollowUser(userId, targetUserId int64) error {
 	return nil
 }
 
+func (r *ProfileRepository) IsBlockedBetween(userId, targetUserId int64) (bool, error) {
+	count, err := r.query.CountUserBlocksBetween(context.Background(), db.CountUserBlocksBetweenParams{
+		UserID:       userId,
+		TargetUserID: targetUserId,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// BlockUser also removes the follows between both users so they stop seeing each other
+func (r *ProfileRepository) BlockUser(userId, targetUserId int64) error {
+	ctx := context.Background()
+	tx, err := r.dbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := r.query.WithTx(tx)
+
+	_, err = qtx.LockUserForUpdate(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("could not lock user for update: %w", err)
+	}
+
+	_, err = qtx.InsertUserBlock(ctx, db.InsertUserBlockParams{
+		UserID:        userId,
+		BlockedUserID: targetUserId,
+	})
+	if err != nil && err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not insert user block: %w", err)
+	}
+
+	if err = deleteFollowing(ctx, qtx, userId, targetUserId); err != nil {
+		return err
+	}
+
+	if err = deleteFollowing(ctx, qtx, targetUserId, userId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProfileRepository) UnblockUser(userId, targetUserId int64) error {
+	_, err := r.query.DeleteUserBlock(context.Background(), db.DeleteUserBlockParams{
+		UserID:        userId,
+		BlockedUserID: targetUserId,
+	})
+
+	return err
+}
+
+func (r *ProfileRepository) ListBlockedUsers(userId int64, offset, limit int32) ([]model.User, int64, error) {
+	blockedUsers, err := r.query.ListBlockedUsers(context.Background(), db.ListBlockedUsersParams{
+		Offset: offset,
+		Limit:  limit,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	if len(blockedUsers) > 0 {
+		count = blockedUsers[0].TotalRows
+	}
+
+	data := make([]model.User, len(blockedUsers))
+	for i, blockedUser := range blockedUsers {
+		data[i] = model.User{
+			ID:         blockedUser.ID,
+			Fullname:   blockedUser.FullName,
+			AvatarUrl:  blockedUser.AvatarUrl.String,
+			Bio:        blockedUser.Bio.String,
+			OpenToWork: blockedUser.OpenToWork.Bool,
+		}
+	}
+
+	return data, count, nil
+}
+
+func (r *ProfileRepository) MuteUser(userId, targetUserId int64) error {
+	_, err := r.query.InsertUserMute(context.Background(), db.InsertUserMuteParams{
+		UserID:      userId,
+		MutedUserID: targetUserId,
+	})
+
+	// The user is already muted
+	if err != nil && err == sql.ErrNoRows {
+		return nil
+	}
+
+	return err
+}
+
+func (r *ProfileRepository) UnmuteUser(userId, targetUserId int64) error {
+	_, err := r.query.DeleteUserMute(context.Background(), db.DeleteUserMuteParams{
+		UserID:      userId,
+		MutedUserID: targetUserId,
+	})
+
+	return err
+}
+
+func (r *ProfileRepository) ListMutedUsers(userId int64, offset, limit int32) ([]model.User, int64, error) {
+	mutedUsers, err := r.query.ListMutedUsers(context.Background(), db.ListMutedUsersParams{
+		Offset: offset,
+		Limit:  limit,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	if len(mutedUsers) > 0 {
+		count = mutedUsers[0].TotalRows
+	}
+
+	data := make([]model.User, len(mutedUsers))
+	for i, mutedUser := range mutedUsers {
+		data[i] = model.User{
+			ID:         mutedUser.ID,
+			Fullname:   mutedUser.FullName,
+			AvatarUrl:  mutedUser.AvatarUrl.String,
+			Bio:        mutedUser.Bio.String,
+			OpenToWork: mutedUser.OpenToWork.Bool,
+		}
+	}
+
+	return data, count, nil
+}
+
+// deleteFollowing removes the follow if it exists and keeps both users' counters in sync
+func deleteFollowing(ctx context.Context, qtx *db.Queries, userId, followUserId int64) error {
+	_, err := qtx.DeleteFollowings(ctx, db.DeleteFollowingsParams{
+		UserID:       userId,
+		FollowUserID: followUserId,
+	})
+	if err != nil && err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not delete followings: %w", err)
+	}
+
+	err = qtx.DeleteTimelineEntriesByAuthor(ctx, db.DeleteTimelineEntriesByAuthorParams{
+		UserID:   userId,
+		AuthorID: followUserId,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete timeline entries: %w", err)
+	}
+
+	_, err = qtx.UpdateUserFollowingsCount(ctx, db.UpdateUserFollowingsCountParams{
+		UserID: userId,
+		Value:  -1,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update user followings count: %w", err)
+	}
+
+	_, err = qtx.UpdateUserFollowersCount(ctx, db.UpdateUserFollowersCountParams{
+		UserID: followUserId,
+		Value:  -1,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update user followers count: %w", err)
+	}
+
+	return nil
+}
+
 func (r *ProfileRepository) InsertUserEducation(props *model.Education) (model.Education, error) {
 	var (
 		startDate  time.Time
@@ -1248,7 +1497,7 @@ func (r *ProfileRepository) InsertUserEducation(props *model.Education) (model.E
 
 	// If the school doesn't exist, insert the school first
 	if props.School.ID < 1 {
-		school, err := qtx.InsertSchool(ctx, props.School.Name)
+		school, err := findOrInsertSchool(ctx, qtx, props.School.Name)
 		if err != nil && err != sql.ErrNoRows {
 			return model.Education{}, fmt.Errorf("could not insert school: %w", err)
 		}
@@ -1459,14 +1708,15 @@ func (r *ProfileRepository) batchInsertUserSkills(ctx context.Context, qtx *db.Q
 		err          error
 	)
 	// Insert skills
-	if err := qtx.BatchInsertSkills(ctx, skills); err != nil {
-		return nil, fmt.Errorf("could not batch insert skills: %w", err)
+	skillIds, err := resolveSkillIds(ctx, qtx, skills)
+	if err != nil {
+		return nil, err
 	}
 
 	// Insert user skills
 	_, err = qtx.BatchInsertUserSkills(ctx, db.BatchInsertUserSkillsParams{
 		UserID:      userId,
-		Names:       skills,
+		SkillIds:    skillIds,
 		IsMainSkill: false,
 	})
 	if err != nil {
@@ -1474,7 +1724,10 @@ func (r *ProfileRepository) batchInsertUserSkills(ctx context.Context, qtx *db.Q
 	}
 
 	// If no new user skills were inserted, get the existing user skills
-	userSkillIDs, err = qtx.GetUserSkillIDsByName(ctx, skills)
+	userSkillIDs, err = qtx.GetUserSkillIDsBySkillIds(ctx, db.GetUserSkillIDsBySkillIdsParams{
+		UserID:   userId,
+		SkillIds: skillIds,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("could not get user skills: %w", err)
 	}
@@ -1482,6 +1735,53 @@ func (r *ProfileRepository) batchInsertUserSkills(ctx context.Context, qtx *db.Q
 	return userSkillIDs, nil
 }
 
+// resolveSkillIds inserts the unknown skill names and returns their canonical skills without duplicates,
+// so "golang" resolves to "Go" once it's a synonym
+func resolveSkillIds(ctx context.Context, qtx *db.Queries, skills []string) ([]int64, error) {
+	var (
+		names           []string
+		normalizedNames []string
+		seen            = make(map[string]bool)
+	)
+
+	for _, skill := range skills {
+		name := strings.Join(strings.Fields(skill), " ")
+		normalizedName := libs.CatalogMatchKey(model.CatalogSkills, name)
+		if normalizedName == "" || seen[normalizedName] {
+			continue
+		}
+
+		seen[normalizedName] = true
+		names = append(names, name)
+		normalizedNames = append(normalizedNames, normalizedName)
+	}
+
+	err := qtx.BatchInsertSkills(ctx, db.BatchInsertSkillsParams{
+		Names:           names,
+		NormalizedNames: normalizedNames,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not batch insert skills: %w", err)
+	}
+
+	data, err := qtx.GetSkillIdsByNormalizedNames(ctx, normalizedNames)
+	if err != nil {
+		return nil, fmt.Errorf("could not get skill ids: %w", err)
+	}
+
+	// Different names may be synonyms of the same skill, which can only be inserted once
+	skillIds := make([]int64, 0, len(data))
+	seenIds := make(map[int64]bool, len(data))
+	for _, id := range data {
+		if !seenIds[id] {
+			seenIds[id] = true
+			skillIds = append(skillIds, id)
+		}
+	}
+
+	return skillIds, nil
+}
+
 func (r *ProfileRepository) batchInsertEducationFiles(ctx context.Context, qtx *db.Queries, educationId int64, url []string) ([]db.EducationFile, error) {
 	arg := db.BatchInsertEducationFilesParams{
 		EducationID: educationId,
@@ -1528,3 +1828,65 @@ func (r *ProfileRepository) deleteOpenToWorkDataByUserId(ctx context.Context, qt
 
 	return nil
 }
+
+// findOrInsertCompany reuses the company or alias matching the normalized name,
+// so "Google LLC" doesn't create a second "Google"
+func findOrInsertCompany(ctx context.Context, qtx *db.Queries, name string) (db.Company, error) {
+	normalizedName := libs.NormalizeCatalogName(name)
+	if normalizedName != "" {
+		company, err := qtx.GetCompanyByNormalizedName(ctx, normalizedName)
+		if err != sql.ErrNoRows {
+			return company, err
+		}
+	}
+
+	return qtx.InsertCompany(ctx, db.InsertCompanyParams{
+		Name:           name,
+		NormalizedName: sql.NullString{String: normalizedName, Valid: normalizedName != ""},
+	})
+}
+
+func findOrInsertSchool(ctx context.Context, qtx *db.Queries, name string) (db.School, error) {
+	normalizedName := libs.NormalizeCatalogName(name)
+	if normalizedName != "" {
+		school, err := qtx.GetSchoolByNormalizedName(ctx, normalizedName)
+		if err != sql.ErrNoRows {
+			return school, err
+		}
+	}
+
+	return qtx.InsertSchool(ctx, db.InsertSchoolParams{
+		Name:           name,
+		NormalizedName: sql.NullString{String: normalizedName, Valid: normalizedName != ""},
+	})
+}
+
+func findOrInsertIssuingOrganization(ctx context.Context, qtx *db.Queries, name string) (db.IssuingOrganization, error) {
+	normalizedName := libs.NormalizeCatalogName(name)
+	if normalizedName != "" {
+		issuingOrganization, err := qtx.GetIssuingOrganizationByNormalizedName(ctx, normalizedName)
+		if err != sql.ErrNoRows {
+			return issuingOrganization, err
+		}
+	}
+
+	return qtx.InsertIssuingOrganization(ctx, db.InsertIssuingOrganizationParams{
+		Name:           name,
+		NormalizedName: sql.NullString{String: normalizedName, Valid: normalizedName != ""},
+	})
+}
+
+func findOrInsertJobPosition(ctx context.Context, qtx *db.Queries, name string) (db.JobPosition, error) {
+	normalizedName := libs.NormalizeCatalogName(name)
+	if normalizedName != "" {
+		jobPosition, err := qtx.GetJobPositionByNormalizedName(ctx, normalizedName)
+		if err != sql.ErrNoRows {
+			return jobPosition, err
+		}
+	}
+
+	return qtx.InsertJobPosition(ctx, db.InsertJobPositionParams{
+		Name:           sql.NullString{String: name, Valid: true},
+		NormalizedName: sql.NullString{String: normalizedName, Valid: normalizedName != ""},
+	})
+}
@@ -37,21 +37,29 @@ type IProfileUsecase interface {
 	DeleteUserCertificateById(userId, educationId int64) model.Response
 	FollowUser(userId, targetUserId int64) model.Response
 	UnfollowUser(userId, targetUserId int64) model.Response
+	BlockUser(userId, targetUserId int64) model.Response
+	UnblockUser(userId, targetUserId int64) model.Response
+	ListBlockedUsers(userId int64, pagination model.PaginationRequest) model.Response
+	MuteUser(userId, targetUserId int64) model.Response
+	UnmuteUser(userId, targetUserId int64) model.Response
+	ListMutedUsers(userId int64, pagination model.PaginationRequest) model.Response
 }
 
 type ProfileUsecase struct {
-	repository   repository.IProfileRepository
-	log          *logrus.Logger
-	googleBucket libs.IGoogleBucket
-	fs           libs.IFileSystem
+	repository    repository.IProfileRepository
+	log           *logrus.Logger
+	googleBucket  libs.IGoogleBucket
+	fs            libs.IFileSystem
+	accountStates libs.IAccountStateChecker
 }
 
-func NewProfileUsecase(repository repository.IProfileRepository, log *logrus.Logger, googleBucket libs.IGoogleBucket, fs libs.IFileSystem) IProfileUsecase {
+func NewProfileUsecase(repository repository.IProfileRepository, log *logrus.Logger, googleBucket libs.IGoogleBucket, fs libs.IFileSystem, accountStates libs.IAccountStateChecker) IProfileUsecase {
 	return &ProfileUsecase{
 		repository,
 		log,
 		googleBucket,
 		fs,
+		accountStates,
 	}
 }
 
@@ -396,8 +404,8 @@ func (u *ProfileUsecase) GetWorkExperiencesByUserId(userId int64, pagination mod
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -425,8 +433,8 @@ func (u *ProfileUsecase) GetEducationsByUserId(userId int64, pagination model.Pa
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -454,8 +462,8 @@ func (u *ProfileUsecase) GetCertificatesByUserId(userId int64, pagination model.
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -469,6 +477,27 @@ func (u *ProfileUsecase) GetCertificatesByUserId(userId int64, pagination model.
 }
 
 func (u *ProfileUsecase) GetFollowedUsersByUserId(userId int64, pagination model.PaginationRequest) model.Response {
+	if pagination.Page == 0 {
+		data, next, err := u.repository.GetFollowedUsersByCursor(userId, pagination.Cursor, int32(pagination.Limit))
+		if err != nil {
+			u.log.Errorf("repository.GetFollowedUsersByCursor(%d): %v", userId, err)
+			return model.Response{
+				Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+			}
+		}
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusOK, "Success fetch followed users"),
+			Data: map[string]any{
+				"pagination": model.PaginationResponse{
+					CurrentRowsCount: len(data),
+					NextCursor:       libs.EncodePageCursor(next),
+				},
+				"data": data,
+			},
+		}
+	}
+
 	offset := (pagination.Page - 1) * pagination.Limit
 
 	data, totalRows, err := u.repository.GetFollowedUsersByUserId(userId, int32(offset), int32(pagination.Limit))
@@ -483,8 +512,8 @@ func (u *ProfileUsecase) GetFollowedUsersByUserId(userId int64, pagination model
 
 	paginate := model.PaginationResponse{
 		Page:             pagination.Page,
-		TotalRows:        totalRows,
-		TotalPages:       totalPages,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
 		CurrentRowsCount: len(data),
 	}
 
@@ -625,7 +654,26 @@ func (u *ProfileUsecase) DeleteUserCertificateById(userId, certificateId int64)
 }
 
 func (u *ProfileUsecase) FollowUser(userId, targetUserId int64) model.Response {
-	err := u.repository.FollowUser(userId, targetUserId)
+	if resp := libs.RestrictedResponse(u.accountStates, u.log, userId); resp != nil {
+		return *resp
+	}
+
+	blocked, err := u.repository.IsBlockedBetween(userId, targetUserId)
+	if err != nil {
+		u.log.Errorf("repository.IsBlockedBetween: %v", err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	if blocked {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusForbidden, "Forbidden"),
+		}
+	}
+
+	err = u.repository.FollowUser(userId, targetUserId)
 	if err != nil {
 		u.log.Errorf("repository.FollowUser: %v", err)
 
@@ -654,6 +702,164 @@ func (u *ProfileUsecase) UnfollowUser(userId, targetUserId int64) model.Response
 	}
 }
 
+func (u *ProfileUsecase) BlockUser(userId, targetUserId int64) model.Response {
+	if userId == targetUserId {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Can't block yourself"),
+		}
+	}
+
+	_, err := u.repository.GetUserById(targetUserId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetUserById(%d): %v", targetUserId, err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	err = u.repository.BlockUser(userId, targetUserId)
+	if err != nil {
+		u.log.Errorf("repository.BlockUser: %v", err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success block user"),
+	}
+}
+
+func (u *ProfileUsecase) UnblockUser(userId, targetUserId int64) model.Response {
+	err := u.repository.UnblockUser(userId, targetUserId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.UnblockUser: %v", err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success unblock user"),
+	}
+}
+
+func (u *ProfileUsecase) ListBlockedUsers(userId int64, pagination model.PaginationRequest) model.Response {
+	offset := (pagination.Page - 1) * pagination.Limit
+
+	data, totalRows, err := u.repository.ListBlockedUsers(userId, int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListBlockedUsers(%d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success fetch blocked users"),
+		Data: map[string]any{
+			"pagination": paginate,
+			"data":       data,
+		},
+	}
+}
+
+func (u *ProfileUsecase) MuteUser(userId, targetUserId int64) model.Response {
+	_, err := u.repository.GetUserById(targetUserId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetUserById(%d): %v", targetUserId, err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	err = u.repository.MuteUser(userId, targetUserId)
+	if err != nil {
+		u.log.Errorf("repository.MuteUser: %v", err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success mute user"),
+	}
+}
+
+func (u *ProfileUsecase) UnmuteUser(userId, targetUserId int64) model.Response {
+	err := u.repository.UnmuteUser(userId, targetUserId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.UnmuteUser: %v", err)
+
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success unmute user"),
+	}
+}
+
+func (u *ProfileUsecase) ListMutedUsers(userId int64, pagination model.PaginationRequest) model.Response {
+	offset := (pagination.Page - 1) * pagination.Limit
+
+	data, totalRows, err := u.repository.ListMutedUsers(userId, int32(offset), int32(pagination.Limit))
+	if err != nil {
+		u.log.Errorf("repository.ListMutedUsers(%d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occured"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success fetch muted users"),
+		Data: map[string]any{
+			"pagination": paginate,
+			"data":       data,
+		},
+	}
+}
+
 func (u *ProfileUsecase) InsertUserWorkExperience(fileNames []string, props *model.WorkExperience) model.Response {
 	var (
 		err error
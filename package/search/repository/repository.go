@@ -0,0 +1,270 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	db "profiln-be/db/sqlc"
+	"profiln-be/libs"
+	"profiln-be/model"
+)
+
+type ISearchRepository interface {
+	SearchUsers(userId int64, filter model.UserSearchFilter, offset, limit int32) ([]model.UserSearchResult, int64, error)
+	SearchUsersContext(ctx context.Context, userId int64, filter model.UserSearchFilter, offset, limit int32) ([]model.UserSearchResult, int64, error)
+	ListUserSearchFacets(userId int64, filter model.UserSearchFilter) (model.UserSearchFacets, error)
+	SearchTalent(userId int64, filter model.TalentSearchFilter, offset, limit int32) ([]model.TalentSearchResult, int64, error)
+	CountSavedTalentSearches(userId int64) (int64, error)
+	InsertSavedTalentSearch(userId int64, props *model.SaveTalentSearchRequest) (model.SavedTalentSearch, error)
+	ListSavedTalentSearches(userId int64) ([]model.SavedTalentSearch, error)
+	GetSavedTalentSearchById(userId, savedSearchId int64) (model.SavedTalentSearch, error)
+	DeleteSavedTalentSearch(userId, savedSearchId int64) error
+}
+
+type SearchRepository struct {
+	dbConn *sql.DB
+	query  *db.Queries
+}
+
+func NewSearchRepository(dbConn *sql.DB) ISearchRepository {
+	return &SearchRepository{
+		dbConn: dbConn,
+		query:  db.New(dbConn),
+	}
+}
+
+func (r *SearchRepository) SearchUsers(userId int64, filter model.UserSearchFilter, offset, limit int32) ([]model.UserSearchResult, int64, error) {
+	return r.SearchUsersContext(context.Background(), userId, filter, offset, limit)
+}
+
+// SearchUsersContext stops the search query when ctx is done
+func (r *SearchRepository) SearchUsersContext(ctx context.Context, userId int64, filter model.UserSearchFilter, offset, limit int32) ([]model.UserSearchResult, int64, error) {
+	arg := db.SearchUsersParams{
+		Offset:        offset,
+		Limit:         limit,
+		UserID:        userId,
+		Name:          filter.Name,
+		NamePattern:   libs.EscapeLikePattern(filter.Name),
+		SkillID:       filter.SkillId,
+		CompanyID:     filter.CompanyId,
+		SchoolID:      filter.SchoolId,
+		JobPositionID: filter.JobPositionId,
+		Location:      libs.EscapeLikePattern(filter.Location),
+	}
+
+	if filter.OpenToWork != nil {
+		arg.OpenToWork = sql.NullBool{Bool: *filter.OpenToWork, Valid: true}
+	}
+
+	data, err := r.query.SearchUsers(ctx, arg)
+	if err != nil {
+		return []model.UserSearchResult{}, 0, err
+	}
+
+	// get total rows for pagination
+	var count int64
+	if len(data) > 0 {
+		count = data[0].TotalRows
+	}
+
+	users := make([]model.UserSearchResult, len(data))
+	for i, v := range data {
+		users[i] = model.UserSearchResult{
+			ID:          v.ID,
+			Fullname:    v.FullName,
+			AvatarUrl:   v.AvatarUrl.String,
+			Bio:         v.Bio.String,
+			OpenToWork:  v.OpenToWork.Bool,
+			Location:    v.Location.String,
+			IsFollowing: v.IsFollowing,
+		}
+	}
+
+	return users, count, nil
+}
+
+func (r *SearchRepository) ListUserSearchFacets(userId int64, filter model.UserSearchFilter) (model.UserSearchFacets, error) {
+	arg := db.ListUserSearchFacetsParams{
+		Name:          filter.Name,
+		NamePattern:   libs.EscapeLikePattern(filter.Name),
+		SkillID:       filter.SkillId,
+		CompanyID:     filter.CompanyId,
+		SchoolID:      filter.SchoolId,
+		JobPositionID: filter.JobPositionId,
+		Location:      libs.EscapeLikePattern(filter.Location),
+		UserID:        userId,
+	}
+
+	if filter.OpenToWork != nil {
+		arg.OpenToWork = sql.NullBool{Bool: *filter.OpenToWork, Valid: true}
+	}
+
+	data, err := r.query.ListUserSearchFacets(context.Background(), arg)
+	if err != nil {
+		return model.UserSearchFacets{}, err
+	}
+
+	facets := model.UserSearchFacets{
+		Skills:       []model.SearchFacet{},
+		Companies:    []model.SearchFacet{},
+		Schools:      []model.SearchFacet{},
+		JobPositions: []model.SearchFacet{},
+		Locations:    []model.SearchFacet{},
+		OpenToWork:   []model.SearchFacet{},
+	}
+
+	for _, v := range data {
+		facet := model.SearchFacet{
+			ID:    v.ValueID,
+			Value: v.Value,
+			Count: v.Count,
+		}
+
+		switch v.Facet {
+		case "skill":
+			facets.Skills = append(facets.Skills, facet)
+		case "company":
+			facets.Companies = append(facets.Companies, facet)
+		case "school":
+			facets.Schools = append(facets.Schools, facet)
+		case "job_position":
+			facets.JobPositions = append(facets.JobPositions, facet)
+		case "location":
+			facets.Locations = append(facets.Locations, facet)
+		case "open_to_work":
+			facets.OpenToWork = append(facets.OpenToWork, facet)
+		}
+	}
+
+	return facets, nil
+}
+
+func (r *SearchRepository) SearchTalent(userId int64, filter model.TalentSearchFilter, offset, limit int32) ([]model.TalentSearchResult, int64, error) {
+	// Nil slices are sent as NULL, which would make every filter reject the candidate
+	arg := db.SearchTalentParams{
+		Offset:          offset,
+		Limit:           limit,
+		JobPositionIds:  append([]int64{}, filter.JobPositionIds...),
+		SkillIds:        append([]int64{}, filter.SkillIds...),
+		UserID:          userId,
+		LocationTypes:   append([]string{}, filter.LocationTypes...),
+		EmploymentTypes: append([]string{}, filter.EmploymentTypes...),
+	}
+
+	if filter.MinYearsOfExperience != nil {
+		arg.MinYears = sql.NullFloat64{Float64: *filter.MinYearsOfExperience, Valid: true}
+	}
+
+	if filter.MaxYearsOfExperience != nil {
+		arg.MaxYears = sql.NullFloat64{Float64: *filter.MaxYearsOfExperience, Valid: true}
+	}
+
+	data, err := r.query.SearchTalent(context.Background(), arg)
+	if err != nil {
+		return []model.TalentSearchResult{}, 0, err
+	}
+
+	// get total rows for pagination
+	var count int64
+	if len(data) > 0 {
+		count = data[0].TotalRows
+	}
+
+	candidates := make([]model.TalentSearchResult, len(data))
+	for i, v := range data {
+		candidates[i] = model.TalentSearchResult{
+			ID:                v.ID,
+			Fullname:          v.FullName,
+			AvatarUrl:         v.AvatarUrl.String,
+			Bio:               v.Bio.String,
+			Location:          v.Location.String,
+			YearsOfExperience: math.Round(v.YearsOfExperience*10) / 10,
+			JobPositions:      v.JobPositions,
+			LocationTypes:     v.LocationTypes,
+			EmploymentTypes:   v.EmploymentTypes,
+			MatchedSkills:     v.MatchedSkills,
+			Score:             math.Round(v.Score*100) / 100,
+		}
+	}
+
+	return candidates, count, nil
+}
+
+func (r *SearchRepository) CountSavedTalentSearches(userId int64) (int64, error) {
+	return r.query.CountSavedTalentSearches(context.Background(), userId)
+}
+
+func (r *SearchRepository) InsertSavedTalentSearch(userId int64, props *model.SaveTalentSearchRequest) (model.SavedTalentSearch, error) {
+	filters, err := json.Marshal(props.Filters)
+	if err != nil {
+		return model.SavedTalentSearch{}, fmt.Errorf("could not marshal filters: %w", err)
+	}
+
+	data, err := r.query.InsertSavedTalentSearch(context.Background(), db.InsertSavedTalentSearchParams{
+		UserID:  userId,
+		Name:    props.Name,
+		Filters: filters,
+	})
+	if err != nil {
+		return model.SavedTalentSearch{}, err
+	}
+
+	return toSavedTalentSearch(data)
+}
+
+func (r *SearchRepository) ListSavedTalentSearches(userId int64) ([]model.SavedTalentSearch, error) {
+	data, err := r.query.ListSavedTalentSearches(context.Background(), userId)
+	if err != nil {
+		return nil, err
+	}
+
+	savedSearches := make([]model.SavedTalentSearch, len(data))
+	for i, v := range data {
+		savedSearches[i], err = toSavedTalentSearch(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return savedSearches, nil
+}
+
+func (r *SearchRepository) GetSavedTalentSearchById(userId, savedSearchId int64) (model.SavedTalentSearch, error) {
+	data, err := r.query.GetSavedTalentSearchById(context.Background(), db.GetSavedTalentSearchByIdParams{
+		ID:     savedSearchId,
+		UserID: userId,
+	})
+	if err != nil {
+		return model.SavedTalentSearch{}, err
+	}
+
+	return toSavedTalentSearch(data)
+}
+
+func (r *SearchRepository) DeleteSavedTalentSearch(userId, savedSearchId int64) error {
+	_, err := r.query.DeleteSavedTalentSearch(context.Background(), db.DeleteSavedTalentSearchParams{
+		ID:     savedSearchId,
+		UserID: userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toSavedTalentSearch(data db.SavedTalentSearch) (model.SavedTalentSearch, error) {
+	savedSearch := model.SavedTalentSearch{
+		ID:        data.ID,
+		Name:      data.Name,
+		CreatedAt: data.CreatedAt,
+	}
+
+	if err := json.Unmarshal(data.Filters, &savedSearch.Filters); err != nil {
+		return model.SavedTalentSearch{}, fmt.Errorf("could not unmarshal filters: %w", err)
+	}
+
+	return savedSearch, nil
+}
@@ -0,0 +1,160 @@
+-- name: SearchUsers :many
+-- name_pattern and location are escaped with libs.EscapeLikePattern, name is kept as typed for the trigram match
+SELECT u.id, u.full_name, u.avatar_url, u.bio, u.open_to_work, ud.location,
+    CASE
+        WHEN f.user_id IS NOT NULL THEN true
+        ELSE false
+    END AS is_following,
+    COUNT(u.id) OVER () AS total_rows
+FROM users u
+LEFT JOIN user_details ud ON u.id = ud.user_id
+LEFT JOIN followings f ON u.id = f.follow_user_id AND f.user_id = @user_id::bigint
+WHERE (@name::text = '' OR u.full_name ILIKE '%' || @name_pattern::text || '%' OR u.full_name % @name::text)
+    AND (@skill_id::bigint = 0 OR EXISTS (SELECT 1 FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = @skill_id::bigint))
+    AND (@company_id::bigint = 0 OR EXISTS (SELECT 1 FROM work_experiences we WHERE we.user_id = u.id AND we.company_id = @company_id::bigint))
+    AND (@school_id::bigint = 0 OR EXISTS (SELECT 1 FROM educations e WHERE e.user_id = u.id AND e.school_id = @school_id::bigint))
+    AND (@job_position_id::bigint = 0 OR EXISTS (SELECT 1 FROM user_job_interests uji WHERE uji.user_id = u.id AND uji.job_position_id = @job_position_id::bigint))
+    AND (@location::text = '' OR ud.location ILIKE '%' || @location::text || '%')
+    AND (sqlc.narg('open_to_work')::boolean IS NULL OR COALESCE(u.open_to_work, false) = sqlc.narg('open_to_work')::boolean)
+    AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = @user_id::bigint AND ub.blocked_user_id = u.id) OR (ub.user_id = u.id AND ub.blocked_user_id = @user_id::bigint))
+    AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+ORDER BY u.full_name ILIKE @name_pattern::text || '%' DESC, SIMILARITY(u.full_name, @name::text) DESC, u.followers_count DESC NULLS LAST, u.id
+OFFSET $1
+LIMIT $2;
+
+-- name: ListUserSearchFacets :many
+-- Counts the top values of each facet among the users matching the same filters as SearchUsers
+WITH matched_users AS (
+    SELECT u.id, u.open_to_work, ud.location
+    FROM users u
+    LEFT JOIN user_details ud ON u.id = ud.user_id
+    WHERE (@name::text = '' OR u.full_name ILIKE '%' || @name_pattern::text || '%' OR u.full_name % @name::text)
+        AND (@skill_id::bigint = 0 OR EXISTS (SELECT 1 FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = @skill_id::bigint))
+        AND (@company_id::bigint = 0 OR EXISTS (SELECT 1 FROM work_experiences we WHERE we.user_id = u.id AND we.company_id = @company_id::bigint))
+        AND (@school_id::bigint = 0 OR EXISTS (SELECT 1 FROM educations e WHERE e.user_id = u.id AND e.school_id = @school_id::bigint))
+        AND (@job_position_id::bigint = 0 OR EXISTS (SELECT 1 FROM user_job_interests uji WHERE uji.user_id = u.id AND uji.job_position_id = @job_position_id::bigint))
+        AND (@location::text = '' OR ud.location ILIKE '%' || @location::text || '%')
+        AND (sqlc.narg('open_to_work')::boolean IS NULL OR COALESCE(u.open_to_work, false) = sqlc.narg('open_to_work')::boolean)
+        AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = @user_id::bigint AND ub.blocked_user_id = u.id) OR (ub.user_id = u.id AND ub.blocked_user_id = @user_id::bigint))
+        AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+)
+(SELECT 'skill'::text AS facet, s.id::bigint AS value_id, s.name::text AS value, COUNT(DISTINCT mu.id) AS count
+FROM matched_users mu
+JOIN user_skills us ON mu.id = us.user_id
+JOIN skills s ON us.skill_id = s.id
+GROUP BY s.id
+ORDER BY count DESC, s.name
+LIMIT 10)
+UNION ALL
+(SELECT 'company'::text, c.id::bigint, c.name::text, COUNT(DISTINCT mu.id)
+FROM matched_users mu
+JOIN work_experiences we ON mu.id = we.user_id
+JOIN companies c ON we.company_id = c.id
+GROUP BY c.id
+ORDER BY 4 DESC, c.name
+LIMIT 10)
+UNION ALL
+(SELECT 'school'::text, sc.id::bigint, sc.name::text, COUNT(DISTINCT mu.id)
+FROM matched_users mu
+JOIN educations e ON mu.id = e.user_id
+JOIN schools sc ON e.school_id = sc.id
+GROUP BY sc.id
+ORDER BY 4 DESC, sc.name
+LIMIT 10)
+UNION ALL
+(SELECT 'job_position'::text, jp.id::bigint, COALESCE(jp.name, '')::text, COUNT(DISTINCT mu.id)
+FROM matched_users mu
+JOIN user_job_interests uji ON mu.id = uji.user_id
+JOIN job_positions jp ON uji.job_position_id = jp.id
+GROUP BY jp.id
+ORDER BY 4 DESC, jp.name
+LIMIT 10)
+UNION ALL
+(SELECT 'location'::text, 0::bigint, TRIM(mu.location)::text, COUNT(*)
+FROM matched_users mu
+WHERE TRIM(mu.location) <> ''
+GROUP BY TRIM(mu.location)
+ORDER BY 4 DESC, TRIM(mu.location)
+LIMIT 10)
+UNION ALL
+(SELECT 'open_to_work'::text, 0::bigint, COALESCE(mu.open_to_work, false)::text, COUNT(*)
+FROM matched_users mu
+GROUP BY COALESCE(mu.open_to_work, false));
+
+-- name: SearchTalent :many
+-- Only users who turned on open to work are candidates. Overlapping work experiences count once towards the years of experience,
+-- they are only merged for the candidates left after the other filters. RANGE_AGG needs PostgreSQL 14 or newer
+WITH candidates AS (
+    SELECT u.id, u.full_name, u.avatar_url, u.bio, ud.location,
+        (SELECT COUNT(*) FROM user_job_interests uji WHERE uji.user_id = u.id AND uji.job_position_id = ANY(@job_position_ids::bigint[])) AS matched_positions,
+        (SELECT COUNT(*) FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = ANY(@skill_ids::bigint[])) AS matched_skills,
+        (SELECT COUNT(*) FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = ANY(@skill_ids::bigint[]) AND us.main_skill) AS matched_main_skills
+    FROM users u
+    LEFT JOIN user_details ud ON u.id = ud.user_id
+    WHERE u.open_to_work = true AND u.id != @user_id::bigint
+        AND (CARDINALITY(@job_position_ids::bigint[]) = 0 OR EXISTS (SELECT 1 FROM user_job_interests uji WHERE uji.user_id = u.id AND uji.job_position_id = ANY(@job_position_ids::bigint[])))
+        AND (CARDINALITY(@skill_ids::bigint[]) = 0 OR EXISTS (SELECT 1 FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = ANY(@skill_ids::bigint[])))
+        AND (CARDINALITY(@location_types::text[]) = 0 OR EXISTS (SELECT 1 FROM user_location_type_interests ulti WHERE ulti.user_id = u.id AND LOWER(ulti.location_type) = ANY(@location_types::text[])))
+        AND (CARDINALITY(@employment_types::text[]) = 0 OR EXISTS (SELECT 1 FROM user_employment_type_interests ueti WHERE ueti.user_id = u.id AND LOWER(ueti.employment_type) = ANY(@employment_types::text[])))
+        AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.user_id = @user_id::bigint AND ub.blocked_user_id = u.id) OR (ub.user_id = u.id AND ub.blocked_user_id = @user_id::bigint))
+        AND NOT EXISTS (SELECT 1 FROM user_account_states uas WHERE uas.user_id = u.id AND uas.state IN ('suspended', 'banned') AND (uas.expires_at IS NULL OR uas.expires_at > NOW()))
+),
+experience AS (
+    SELECT e.user_id, SUM(UPPER(e.period) - LOWER(e.period)) / 365.25 AS years
+    FROM (
+        SELECT we.user_id, UNNEST(RANGE_AGG(DATERANGE(we.start_date, COALESCE(we.finish_date, CURRENT_DATE)))) AS period
+        FROM work_experiences we
+        JOIN candidates c ON we.user_id = c.id
+        WHERE we.start_date IS NOT NULL AND COALESCE(we.finish_date, CURRENT_DATE) > we.start_date
+        GROUP BY we.user_id
+    ) e
+    GROUP BY e.user_id
+),
+experienced_candidates AS (
+    SELECT c.id, c.full_name, c.avatar_url, c.bio, c.location, c.matched_positions, c.matched_skills, c.matched_main_skills,
+        COALESCE(ex.years, 0)::float8 AS years_of_experience
+    FROM candidates c
+    LEFT JOIN experience ex ON c.id = ex.user_id
+)
+SELECT c.id, c.full_name, c.avatar_url, c.bio, c.location, c.years_of_experience,
+    ARRAY(SELECT jp.name FROM user_job_interests uji JOIN job_positions jp ON uji.job_position_id = jp.id WHERE uji.user_id = c.id AND jp.name IS NOT NULL ORDER BY jp.name)::text[] AS job_positions,
+    ARRAY(SELECT ulti.location_type FROM user_location_type_interests ulti WHERE ulti.user_id = c.id AND ulti.location_type IS NOT NULL ORDER BY ulti.location_type)::text[] AS location_types,
+    ARRAY(SELECT ueti.employment_type FROM user_employment_type_interests ueti WHERE ueti.user_id = c.id AND ueti.employment_type IS NOT NULL ORDER BY ueti.employment_type)::text[] AS employment_types,
+    ARRAY(SELECT s.name FROM user_skills us JOIN skills s ON us.skill_id = s.id WHERE us.user_id = c.id AND us.skill_id = ANY(@skill_ids::bigint[]) ORDER BY us.main_skill DESC, s.name)::text[] AS matched_skills,
+    -- Share of the wanted positions and skills the candidate has, main skills and experience break ties
+    (3.0 * c.matched_positions / GREATEST(CARDINALITY(@job_position_ids::bigint[]), 1)
+        + 2.0 * c.matched_skills / GREATEST(CARDINALITY(@skill_ids::bigint[]), 1)
+        + 0.5 * c.matched_main_skills / GREATEST(CARDINALITY(@skill_ids::bigint[]), 1)
+        + 0.5 * LEAST(c.years_of_experience, 10) / 10)::float8 AS score,
+    COUNT(c.id) OVER () AS total_rows
+FROM experienced_candidates c
+WHERE (sqlc.narg('min_years')::float8 IS NULL OR c.years_of_experience >= sqlc.narg('min_years')::float8)
+    AND (sqlc.narg('max_years')::float8 IS NULL OR c.years_of_experience <= sqlc.narg('max_years')::float8)
+ORDER BY score DESC, c.years_of_experience DESC, c.id
+OFFSET $1
+LIMIT $2;
+
+-- name: InsertSavedTalentSearch :one
+INSERT INTO saved_talent_searches (user_id, name, filters, created_at)
+VALUES (@user_id::bigint, @name::text, @filters::jsonb, NOW())
+ON CONFLICT (user_id, name) DO NOTHING
+RETURNING *;
+
+-- name: ListSavedTalentSearches :many
+SELECT * FROM saved_talent_searches
+WHERE user_id = @user_id::bigint
+ORDER BY created_at DESC;
+
+-- name: GetSavedTalentSearchById :one
+SELECT * FROM saved_talent_searches
+WHERE id = @id::bigint AND user_id = @user_id::bigint
+LIMIT 1;
+
+-- name: DeleteSavedTalentSearch :one
+DELETE FROM saved_talent_searches
+WHERE id = @id::bigint AND user_id = @user_id::bigint
+RETURNING id;
+
+-- name: CountSavedTalentSearches :one
+SELECT COUNT(*) FROM saved_talent_searches
+WHERE user_id = @user_id::bigint;
@@ -0,0 +1,386 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"profiln-be/libs"
+	"profiln-be/model"
+	dataRepository "profiln-be/package/data/repository"
+	postsRepository "profiln-be/package/posts/repository"
+	repository "profiln-be/package/search/repository"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxSavedTalentSearches = 20
+	// Groups still running when the deadline passes are cancelled, returned empty and marked as timed out
+	unifiedSearchTimeout = 800 * time.Millisecond
+	// Catalog groups are continued by fetching past the offset, so they are not paged too far
+	maxCatalogSearchOffset = 100
+)
+
+var searchGroupCatalogs = map[string]string{
+	model.SearchGroupCompanies: model.CatalogCompanies,
+	model.SearchGroupSchools:   model.CatalogSchools,
+	model.SearchGroupSkills:    model.CatalogSkills,
+}
+
+type ISearchUsecase interface {
+	Search(userId int64, q string, limit int) model.Response
+	SearchMore(userId int64, cursor model.SearchCursor) model.Response
+	SearchPosts(userId int64, filter model.PostSearchFilter, pagination model.PaginationRequest) (resp model.Response)
+	SearchUsers(userId int64, filter model.UserSearchFilter, pagination model.PaginationRequest) (resp model.Response)
+	SearchTalent(userId int64, filter *model.TalentSearchFilter, pagination model.PaginationRequest) (resp model.Response)
+	SaveTalentSearch(userId int64, props *model.SaveTalentSearchRequest) model.Response
+	ListSavedTalentSearches(userId int64) model.Response
+	RunSavedTalentSearch(userId, savedSearchId int64, pagination model.PaginationRequest) (resp model.Response)
+	DeleteSavedTalentSearch(userId, savedSearchId int64) model.Response
+}
+
+type SearchUsecase struct {
+	repository      repository.ISearchRepository
+	postsRepository postsRepository.IPostsRepository
+	dataRepository  dataRepository.IDataRepository
+	log             *logrus.Logger
+}
+
+func NewSearchUsecase(repository repository.ISearchRepository, postsRepository postsRepository.IPostsRepository, dataRepository dataRepository.IDataRepository, log *logrus.Logger) ISearchUsecase {
+	return &SearchUsecase{
+		repository,
+		postsRepository,
+		dataRepository,
+		log,
+	}
+}
+
+func (u *SearchUsecase) Search(userId int64, q string, limit int) model.Response {
+	type groupResult struct {
+		index int
+		group model.SearchGroup
+		err   error
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unifiedSearchTimeout)
+	defer cancel()
+
+	var (
+		groups  = make([]model.SearchGroup, len(model.SearchGroups))
+		results = make(chan groupResult, len(model.SearchGroups))
+	)
+
+	for i, group := range model.SearchGroups {
+		groups[i] = model.SearchGroup{Group: group, Items: []any{}, TimedOut: true, Partial: true}
+
+		go func(index int, cursor model.SearchCursor) {
+			group, err := u.searchGroup(ctx, userId, cursor)
+			results <- groupResult{index, group, err}
+		}(i, model.SearchCursor{Group: group, Query: q, Limit: limit})
+	}
+
+	// A failed group is returned empty and marked as partial, the other groups are still returned
+wait:
+	for pending := len(model.SearchGroups); pending > 0; pending-- {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				u.log.Errorf("searchGroup (user id %d): %v", userId, result.err)
+				// The driver reports cancelled queries with its own error, the context tells whether the deadline passed
+				groups[result.index].TimedOut = ctx.Err() != nil
+				continue
+			}
+			groups[result.index] = result.group
+		case <-ctx.Done():
+			u.log.Warnf("search (user id %d): %d groups timed out", userId, pending)
+			break wait
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success search"),
+		Data:   groups,
+	}
+}
+
+func (u *SearchUsecase) SearchMore(userId int64, cursor model.SearchCursor) model.Response {
+	if _, ok := searchGroupCatalogs[cursor.Group]; ok && cursor.Offset > maxCatalogSearchOffset {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Invalid request query"),
+		}
+	}
+
+	group, err := u.searchGroup(context.Background(), userId, cursor)
+	if err != nil {
+		u.log.Errorf("searchGroup (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success search"),
+		Data:   group,
+	}
+}
+
+// searchGroup runs one group of the unified search, the next cursor is empty on the last page
+func (u *SearchUsecase) searchGroup(ctx context.Context, userId int64, cursor model.SearchCursor) (model.SearchGroup, error) {
+	var (
+		items   any
+		count   int
+		hasMore bool
+	)
+
+	switch cursor.Group {
+	case model.SearchGroupPeople:
+		users, totalRows, err := u.repository.SearchUsersContext(ctx, userId, model.UserSearchFilter{Name: cursor.Query}, int32(cursor.Offset), int32(cursor.Limit))
+		if err != nil {
+			return model.SearchGroup{}, fmt.Errorf("repository.SearchUsersContext: %w", err)
+		}
+		items, count, hasMore = users, len(users), int64(cursor.Offset+len(users)) < totalRows
+	case model.SearchGroupPosts:
+		filter := model.PostSearchFilter{Query: cursor.Query, Prefix: true}
+		posts, totalRows, err := u.postsRepository.SearchPostsContext(ctx, userId, filter, int32(cursor.Offset), int32(cursor.Limit))
+		if err != nil {
+			return model.SearchGroup{}, fmt.Errorf("postsRepository.SearchPostsContext: %w", err)
+		}
+		items, count, hasMore = posts, len(posts), int64(cursor.Offset+len(posts)) < totalRows
+	case model.SearchGroupCompanies, model.SearchGroupSchools, model.SearchGroupSkills:
+		catalogItems, err := u.dataRepository.SearchCatalogContext(ctx, searchGroupCatalogs[cursor.Group], cursor.Query, int32(cursor.Offset+cursor.Limit+1))
+		if err != nil {
+			return model.SearchGroup{}, fmt.Errorf("dataRepository.SearchCatalogContext: %w", err)
+		}
+
+		hasMore = len(catalogItems) > cursor.Offset+cursor.Limit
+		catalogItems = catalogItems[min(cursor.Offset, len(catalogItems)):min(cursor.Offset+cursor.Limit, len(catalogItems))]
+		items, count = catalogItems, len(catalogItems)
+	default:
+		return model.SearchGroup{}, fmt.Errorf("unknown search group %q", cursor.Group)
+	}
+
+	group := model.SearchGroup{
+		Group: cursor.Group,
+		Items: items,
+	}
+
+	if hasMore {
+		group.NextCursor = libs.EncodeSearchCursor(model.SearchCursor{
+			Group:  cursor.Group,
+			Query:  cursor.Query,
+			Offset: cursor.Offset + count,
+			Limit:  cursor.Limit,
+		})
+	}
+
+	return group, nil
+}
+
+func (u *SearchUsecase) SearchPosts(userId int64, filter model.PostSearchFilter, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.postsRepository.SearchPosts(userId, filter, int32(offset), int32(pagination.Limit))
+
+	if err != nil {
+		u.log.Errorf("repository.SearchPosts (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success search posts")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+func (u *SearchUsecase) SearchUsers(userId int64, filter model.UserSearchFilter, pagination model.PaginationRequest) (resp model.Response) {
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.SearchUsers(userId, filter, int32(offset), int32(pagination.Limit))
+
+	if err != nil {
+		u.log.Errorf("repository.SearchUsers (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	facets, err := u.repository.ListUserSearchFacets(userId, filter)
+	if err != nil {
+		u.log.Errorf("repository.ListUserSearchFacets (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success search users")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"facets":     facets,
+		"data":       data,
+	}
+	return
+}
+
+func (u *SearchUsecase) SearchTalent(userId int64, filter *model.TalentSearchFilter, pagination model.PaginationRequest) (resp model.Response) {
+	if resp := invalidTalentSearchFilterResponse(filter); resp != nil {
+		return *resp
+	}
+	normalizeTalentSearchFilter(filter)
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	data, totalRows, err := u.repository.SearchTalent(userId, *filter, int32(offset), int32(pagination.Limit))
+
+	if err != nil {
+		u.log.Errorf("repository.SearchTalent (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	totalPages := int((totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginate := model.PaginationResponse{
+		Page:             pagination.Page,
+		TotalRows:        &totalRows,
+		TotalPages:       &totalPages,
+		CurrentRowsCount: len(data),
+	}
+
+	resp.Status = libs.CustomResponse(http.StatusOK, "Success search talent")
+	resp.Data = map[string]any{
+		"pagination": paginate,
+		"data":       data,
+	}
+	return
+}
+
+func (u *SearchUsecase) SaveTalentSearch(userId int64, props *model.SaveTalentSearchRequest) model.Response {
+	if resp := invalidTalentSearchFilterResponse(&props.Filters); resp != nil {
+		return *resp
+	}
+	normalizeTalentSearchFilter(&props.Filters)
+
+	count, err := u.repository.CountSavedTalentSearches(userId)
+	if err != nil {
+		u.log.Errorf("repository.CountSavedTalentSearches (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	if count >= maxSavedTalentSearches {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Saved search limit reached"),
+		}
+	}
+
+	data, err := u.repository.InsertSavedTalentSearch(userId, props)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusConflict, "Saved search already exists"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.InsertSavedTalentSearch (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusCreated, "Success save talent search"),
+		Data:   data,
+	}
+}
+
+func (u *SearchUsecase) ListSavedTalentSearches(userId int64) model.Response {
+	data, err := u.repository.ListSavedTalentSearches(userId)
+	if err != nil {
+		u.log.Errorf("repository.ListSavedTalentSearches (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success get saved talent searches"),
+		Data:   data,
+	}
+}
+
+func (u *SearchUsecase) RunSavedTalentSearch(userId, savedSearchId int64, pagination model.PaginationRequest) (resp model.Response) {
+	savedSearch, err := u.repository.GetSavedTalentSearchById(userId, savedSearchId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.GetSavedTalentSearchById (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return u.SearchTalent(userId, &savedSearch.Filters, pagination)
+}
+
+func (u *SearchUsecase) DeleteSavedTalentSearch(userId, savedSearchId int64) model.Response {
+	err := u.repository.DeleteSavedTalentSearch(userId, savedSearchId)
+	if err != nil && err == sql.ErrNoRows {
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusNotFound, "Data not found"),
+		}
+	} else if err != nil {
+		u.log.Errorf("repository.DeleteSavedTalentSearch (user id %d): %v", userId, err)
+		return model.Response{
+			Status: libs.CustomResponse(http.StatusInternalServerError, "Unexpected error occurred"),
+		}
+	}
+
+	return model.Response{
+		Status: libs.CustomResponse(http.StatusOK, "Success delete saved talent search"),
+	}
+}
+
+func invalidTalentSearchFilterResponse(filter *model.TalentSearchFilter) *model.Response {
+	if filter.MinYearsOfExperience != nil && filter.MaxYearsOfExperience != nil &&
+		*filter.MinYearsOfExperience > *filter.MaxYearsOfExperience {
+		return &model.Response{
+			Status: libs.CustomResponse(http.StatusBadRequest, "Minimum years of experience is greater than the maximum"),
+		}
+	}
+
+	return nil
+}
+
+func normalizeTalentSearchFilter(filter *model.TalentSearchFilter) {
+	for i, v := range filter.LocationTypes {
+		filter.LocationTypes[i] = strings.ToLower(strings.TrimSpace(v))
+	}
+
+	for i, v := range filter.EmploymentTypes {
+		filter.EmploymentTypes[i] = strings.ToLower(strings.TrimSpace(v))
+	}
+}